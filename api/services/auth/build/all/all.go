@@ -4,6 +4,7 @@ package all
 import (
 	"github.com/gradientsearch/pwmanager/app/domain/authapp"
 	"github.com/gradientsearch/pwmanager/app/domain/checkapp"
+	"github.com/gradientsearch/pwmanager/app/domain/oauthapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
 	"github.com/gradientsearch/pwmanager/foundation/web"
 )
@@ -25,7 +26,27 @@ func (add) Add(app *web.App, cfg mux.Config) {
 	})
 
 	authapp.Routes(app, authapp.Config{
-		UserBus: cfg.BusConfig.UserBus,
-		Auth:    cfg.AuthConfig.Auth,
+		Log:        cfg.Log,
+		DB:         cfg.DB,
+		UserBus:    cfg.BusConfig.UserBus,
+		SessionBus: cfg.BusConfig.SessionBus,
+		AuditBus:   cfg.BusConfig.AuditBus,
+		Auth:       cfg.AuthConfig.Auth,
+	})
+
+	oauthapp.Routes(app, oauthapp.Config{
+		Auth:              cfg.AuthConfig.Auth,
+		Log:               cfg.Log,
+		UserBus:           cfg.BusConfig.UserBus,
+		SessionBus:        cfg.BusConfig.SessionBus,
+		AuditBus:          cfg.BusConfig.AuditBus,
+		TokenKey:          cfg.AuthConfig.OAuth.TokenKey,
+		GoogleKey:         cfg.AuthConfig.OAuth.GoogleKey,
+		GoogleSecret:      cfg.AuthConfig.OAuth.GoogleSecret,
+		GoogleUIURL:       cfg.AuthConfig.OAuth.UIURL,
+		GoogleCallBackURL: cfg.AuthConfig.OAuth.CallbackURL,
+		APIHost:           cfg.AuthConfig.OAuth.APIHost,
+		AllowedDomains:    cfg.AuthConfig.OAuth.AllowedDomains,
+		OIDC:              cfg.AuthConfig.OAuth.OIDC,
 	})
 }
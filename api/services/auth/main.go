@@ -17,6 +17,11 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/debug"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
+	"github.com/gradientsearch/pwmanager/app/sdk/oidc"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus/stores/auditdb"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus/stores/sessiondb"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/usercache"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/userdb"
@@ -81,6 +86,22 @@ func run(ctx context.Context, log *logger.Logger) error {
 			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
 			Issuer     string `conf:"default:service project"`
 		}
+		OAuth struct {
+			GoogleKey      string `conf:"mask"`
+			GoogleSecret   string `conf:"mask"`
+			UIURL          string `conf:"default:http://localhost:3000"`
+			CallbackURL    string `conf:"default:http://localhost:6000"`
+			AllowedDomains []string
+		}
+		OIDC struct {
+			Name         string `conf:"default:oidc"`
+			IssuerURL    string
+			ClientID     string
+			ClientSecret string `conf:"mask"`
+			Scopes       []string
+			RoleClaim    string
+			RoleMapping  []string
+		}
 		DB struct {
 			User         string `conf:"default:postgres"`
 			Password     string `conf:"default:postgres,mask"`
@@ -186,6 +207,34 @@ func run(ctx context.Context, log *logger.Logger) error {
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	// -------------------------------------------------------------------------
+	// Initialize OpenID Connect support
+
+	var oidcProvider *oidc.Provider
+
+	if cfg.OIDC.IssuerURL != "" {
+		log.Info(ctx, "startup", "status", "initializing openid connect support", "issuer", cfg.OIDC.IssuerURL)
+
+		roleMapping, err := oidc.ParseRoleMapping(cfg.OIDC.RoleMapping)
+		if err != nil {
+			return fmt.Errorf("parsing oidc role mapping: %w", err)
+		}
+
+		oidcProvider, err = oidc.New(oidc.Config{
+			Name:         cfg.OIDC.Name,
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			CallbackURL:  fmt.Sprintf("%s/api/auth/%s/callback", cfg.OAuth.CallbackURL, cfg.OIDC.Name),
+			Scopes:       cfg.OIDC.Scopes,
+			RoleClaim:    cfg.OIDC.RoleClaim,
+			RoleMapping:  roleMapping,
+		})
+		if err != nil {
+			return fmt.Errorf("constructing oidc provider: %w", err)
+		}
+	}
+
 	// -------------------------------------------------------------------------
 	// Start Tracing Support
 
@@ -213,6 +262,8 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	delegate := delegate.New(log)
 	userBus := userbus.NewBusiness(log, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Minute))
+	sessionBus := sessionbus.NewBusiness(log, delegate, sessiondb.NewStore(log, db))
+	auditBus := auditbus.NewBusiness(log, delegate, auditdb.NewStore(log, db))
 
 	// -------------------------------------------------------------------------
 	// Start Debug Service
@@ -239,10 +290,22 @@ func run(ctx context.Context, log *logger.Logger) error {
 		DB:     db,
 		Tracer: tracer,
 		BusConfig: mux.BusConfig{
-			UserBus: userBus,
+			UserBus:    userBus,
+			SessionBus: sessionBus,
+			AuditBus:   auditBus,
 		},
 		AuthConfig: mux.AuthConfig{
 			Auth: ath,
+			OAuth: mux.OAuthConfig{
+				TokenKey:       cfg.Auth.ActiveKID,
+				GoogleKey:      cfg.OAuth.GoogleKey,
+				GoogleSecret:   cfg.OAuth.GoogleSecret,
+				UIURL:          cfg.OAuth.UIURL,
+				CallbackURL:    cfg.OAuth.CallbackURL,
+				APIHost:        cfg.Web.APIHost,
+				AllowedDomains: cfg.OAuth.AllowedDomains,
+				OIDC:           oidcProvider,
+			},
 		},
 	}
 
@@ -2,12 +2,16 @@
 package all
 
 import (
+	"github.com/gradientsearch/pwmanager/app/domain/auditapp"
 	"github.com/gradientsearch/pwmanager/app/domain/bundleapp"
 	"github.com/gradientsearch/pwmanager/app/domain/checkapp"
 	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
 	"github.com/gradientsearch/pwmanager/app/domain/keyapp"
+	"github.com/gradientsearch/pwmanager/app/domain/memberapp"
 	"github.com/gradientsearch/pwmanager/app/domain/rawapp"
+	"github.com/gradientsearch/pwmanager/app/domain/syncapp"
 	"github.com/gradientsearch/pwmanager/app/domain/userapp"
+	"github.com/gradientsearch/pwmanager/app/domain/vaultapp"
 	"github.com/gradientsearch/pwmanager/app/domain/vbundleapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
 	"github.com/gradientsearch/pwmanager/foundation/web"
@@ -38,38 +42,77 @@ func (add) Add(app *web.App, cfg mux.Config) {
 
 	userapp.Routes(app, userapp.Config{
 		Log:        cfg.Log,
+		DB:         cfg.DB,
 		UserBus:    cfg.BusConfig.UserBus,
+		AuditBus:   cfg.BusConfig.AuditBus,
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
 
 	bundleapp.Routes(app, bundleapp.Config{
-		Log:        cfg.Log,
-		DB:         cfg.DB,
-		UserBus:    cfg.BusConfig.UserBus,
-		KeyBus:     cfg.BusConfig.KeyBus,
-		BundleBus:  cfg.BusConfig.BundleBus,
-		AuthClient: cfg.PwManagerConfig.AuthClient,
+		Log:          cfg.Log,
+		DB:           cfg.DB,
+		UserBus:      cfg.BusConfig.UserBus,
+		KeyBus:       cfg.BusConfig.KeyBus,
+		BundleBus:    cfg.BusConfig.BundleBus,
+		AuditBus:     cfg.BusConfig.AuditBus,
+		AuthClient:   cfg.PwManagerConfig.AuthClient,
+		MaxEntrySize: cfg.PwManagerConfig.MaxEntrySize,
 	})
 
 	keyapp.Routes(app, keyapp.Config{
 		Log:        cfg.Log,
 		KeyBus:     cfg.BusConfig.KeyBus,
+		AuditBus:   cfg.BusConfig.AuditBus,
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
 
-	entryapp.Routes(app, entryapp.Config{
+	memberapp.Routes(app, memberapp.Config{
 		Log:        cfg.Log,
 		DB:         cfg.DB,
 		BundleBus:  cfg.BusConfig.BundleBus,
 		KeyBus:     cfg.BusConfig.KeyBus,
-		EntryBus:   cfg.BusConfig.EntryBus,
+		MemberBus:  cfg.BusConfig.MemberBus,
+		AuditBus:   cfg.BusConfig.AuditBus,
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
 
+	entryapp.Routes(app, entryapp.Config{
+		Log:          cfg.Log,
+		DB:           cfg.DB,
+		BundleBus:    cfg.BusConfig.BundleBus,
+		KeyBus:       cfg.BusConfig.KeyBus,
+		EntryBus:     cfg.BusConfig.EntryBus,
+		AuditBus:     cfg.BusConfig.AuditBus,
+		AuthClient:   cfg.PwManagerConfig.AuthClient,
+		MaxEntrySize: cfg.PwManagerConfig.MaxEntrySize,
+	})
+
 	vbundleapp.Routes(app, vbundleapp.Config{
 		Log:        cfg.Log,
 		UserBus:    cfg.BusConfig.UserBus,
 		VBundleBus: cfg.BusConfig.VBundleBus,
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
+
+	syncapp.Routes(app, syncapp.Config{
+		Log:        cfg.Log,
+		SyncBus:    cfg.BusConfig.SyncBus,
+		AuthClient: cfg.PwManagerConfig.AuthClient,
+	})
+
+	vaultapp.Routes(app, vaultapp.Config{
+		Log:        cfg.Log,
+		DB:         cfg.DB,
+		VaultBus:   cfg.BusConfig.VaultBus,
+		AuditBus:   cfg.BusConfig.AuditBus,
+		AuthClient: cfg.PwManagerConfig.AuthClient,
+	})
+
+	auditapp.Routes(app, auditapp.Config{
+		Log:        cfg.Log,
+		AuditBus:   cfg.BusConfig.AuditBus,
+		BundleBus:  cfg.BusConfig.BundleBus,
+		KeyBus:     cfg.BusConfig.KeyBus,
+		AuthClient: cfg.PwManagerConfig.AuthClient,
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/gradientsearch/pwmanager/app/domain/bundleapp"
 	"github.com/gradientsearch/pwmanager/app/domain/checkapp"
 	"github.com/gradientsearch/pwmanager/app/domain/keyapp"
+	"github.com/gradientsearch/pwmanager/app/domain/memberapp"
 	"github.com/gradientsearch/pwmanager/app/domain/userapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
 	"github.com/gradientsearch/pwmanager/foundation/web"
@@ -40,7 +41,18 @@ func (add) Add(app *web.App, cfg mux.Config) {
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
 
+	memberapp.Routes(app, memberapp.Config{
+		Log:        cfg.Log,
+		DB:         cfg.DB,
+		BundleBus:  cfg.BusConfig.BundleBus,
+		KeyBus:     cfg.BusConfig.KeyBus,
+		MemberBus:  cfg.BusConfig.MemberBus,
+		AuthClient: cfg.PwManagerConfig.AuthClient,
+	})
+
 	userapp.Routes(app, userapp.Config{
+		Log:        cfg.Log,
+		DB:         cfg.DB,
 		UserBus:    cfg.BusConfig.UserBus,
 		AuthClient: cfg.PwManagerConfig.AuthClient,
 	})
@@ -20,21 +20,33 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/debug"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus/stores/auditdb"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus/stores/bundledb"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus/stores/entrydb"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus/stores/keydb"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus/stores/memberdb"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus/stores/sessiondb"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus/stores/syncdb"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/usercache"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/userdb"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus/stores/vaultdb"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus/stores/vbundledb"
 	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/keystore"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/otel"
+	"github.com/gradientsearch/pwmanager/foundation/worker"
 )
 
 /*
@@ -105,6 +117,38 @@ func run(ctx context.Context, log *logger.Logger) error {
 			MaxOpenConns int    `conf:"default:0"`
 			DisableTLS   bool   `conf:"default:true"`
 		}
+		Entries struct {
+			MaxSize int `conf:"default:65536"`
+		}
+		Versions struct {
+			MaxCount      int           `conf:"default:50"`
+			MaxAge        time.Duration `conf:"default:2160h"`
+			PurgeInterval time.Duration `conf:"default:1h"`
+			PurgeTimeout  time.Duration `conf:"default:1m"`
+		}
+		Trash struct {
+			Retention     time.Duration `conf:"default:720h"`
+			PurgeInterval time.Duration `conf:"default:1h"`
+			PurgeTimeout  time.Duration `conf:"default:1m"`
+		}
+		Sync struct {
+			TombstoneRetention time.Duration `conf:"default:2160h"`
+			PurgeInterval      time.Duration `conf:"default:1h"`
+			PurgeTimeout       time.Duration `conf:"default:1m"`
+		}
+		Sessions struct {
+			PurgeInterval time.Duration `conf:"default:1h"`
+			PurgeTimeout  time.Duration `conf:"default:1m"`
+		}
+		Vault struct {
+			SigningKey string `conf:"required,mask"`
+		}
+		Audit struct {
+			KeysFolder         string        `conf:"default:zarf/keys/"`
+			CheckpointKID      string        `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
+			CheckpointInterval time.Duration `conf:"default:1h"`
+			CheckpointTimeout  time.Duration `conf:"default:1m"`
+		}
 		Tempo struct {
 			Host        string  `conf:"default:tempo:4317"`
 			ServiceName string  `conf:"default:pwmanager"`
@@ -200,10 +244,73 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	delegate := delegate.New(log)
 	userBus := userbus.NewBusiness(log, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Minute))
-	bundleBus := bundlebus.NewBusiness(log, userBus, delegate, bundledb.NewStore(log, db))
 	keyBus := keybus.NewBusiness(log, userBus, delegate, keydb.NewStore(log, db))
 	entryBus := entrybus.NewBusiness(log, userBus, delegate, entrydb.NewStore(log, db))
+	bundleBus := bundlebus.NewBusiness(log, userBus, keyBus, entryBus, delegate, bundledb.NewStore(log, db))
 	vbundleBus := vbundlebus.NewBusiness(vbundledb.NewStore(log, db))
+	memberBus := memberbus.NewBusiness(log, userBus, bundleBus, keyBus, memberdb.NewStore(log, db))
+	syncBus := syncbus.NewBusiness(syncdb.NewStore(log, db))
+	vaultBus := vaultbus.NewBusiness(log, bundleBus, keyBus, entryBus, vaultdb.NewStore(log, db), []byte(cfg.Vault.SigningKey))
+	auditBus := auditbus.NewBusiness(log, delegate, auditdb.NewStore(log, db))
+	sessionBus := sessionbus.NewBusiness(log, delegate, sessiondb.NewStore(log, db))
+
+	// -------------------------------------------------------------------------
+	// Start Background Jobs
+
+	log.Info(ctx, "startup", "status", "initializing background jobs")
+
+	// Audit checkpoints are signed with a key from the keystore so the chain
+	// can't be rewritten by someone with access to the database alone.
+	ks := keystore.New()
+	if _, err := ks.LoadByFileSystem(os.DirFS(cfg.Audit.KeysFolder)); err != nil {
+		return fmt.Errorf("loading keys by fs: %w", err)
+	}
+
+	wrk, err := worker.New(4)
+	if err != nil {
+		return fmt.Errorf("constructing worker: %w", err)
+	}
+
+	jobsDone := make(chan struct{})
+
+	vr := entrybus.VersionRetention{
+		MaxCount: cfg.Versions.MaxCount,
+		MaxAge:   cfg.Versions.MaxAge,
+	}
+
+	go schedule(ctx, log, wrk, jobsDone, "purge-entry-versions", cfg.Versions.PurgeInterval, cfg.Versions.PurgeTimeout, func(ctx context.Context) {
+		if err := entryBus.PurgeVersions(ctx, vr); err != nil {
+			log.Error(ctx, "jobs", "job", "purge-entry-versions", "msg", err)
+		}
+	})
+
+	go schedule(ctx, log, wrk, jobsDone, "purge-trash", cfg.Trash.PurgeInterval, cfg.Trash.PurgeTimeout, func(ctx context.Context) {
+		if err := bundleBus.PurgeDeleted(ctx, cfg.Trash.Retention); err != nil {
+			log.Error(ctx, "jobs", "job", "purge-trash", "msg", err)
+		}
+
+		if err := entryBus.PurgeDeleted(ctx, cfg.Trash.Retention); err != nil {
+			log.Error(ctx, "jobs", "job", "purge-trash", "msg", err)
+		}
+	})
+
+	go schedule(ctx, log, wrk, jobsDone, "purge-tombstones", cfg.Sync.PurgeInterval, cfg.Sync.PurgeTimeout, func(ctx context.Context) {
+		if err := syncBus.PurgeTombstones(ctx, cfg.Sync.TombstoneRetention); err != nil {
+			log.Error(ctx, "jobs", "job", "purge-tombstones", "msg", err)
+		}
+	})
+
+	go schedule(ctx, log, wrk, jobsDone, "purge-sessions", cfg.Sessions.PurgeInterval, cfg.Sessions.PurgeTimeout, func(ctx context.Context) {
+		if err := sessionBus.PurgeExpired(ctx); err != nil {
+			log.Error(ctx, "jobs", "job", "purge-sessions", "msg", err)
+		}
+	})
+
+	go schedule(ctx, log, wrk, jobsDone, "audit-checkpoint", cfg.Audit.CheckpointInterval, cfg.Audit.CheckpointTimeout, func(ctx context.Context) {
+		if _, err := auditBus.Checkpoint(ctx, ks, cfg.Audit.CheckpointKID); err != nil && !errors.Is(err, auditbus.ErrNotFound) {
+			log.Error(ctx, "jobs", "job", "audit-checkpoint", "msg", err)
+		}
+	})
 
 	// -------------------------------------------------------------------------
 	// Start Debug Service
@@ -234,10 +341,16 @@ func run(ctx context.Context, log *logger.Logger) error {
 			BundleBus:  bundleBus,
 			KeyBus:     keyBus,
 			EntryBus:   entryBus,
+			MemberBus:  memberBus,
 			VBundleBus: vbundleBus,
+			SyncBus:    syncBus,
+			VaultBus:   vaultBus,
+			AuditBus:   auditBus,
+			SessionBus: sessionBus,
 		},
 		PwManagerConfig: mux.PwManagerConfig{
-			AuthClient: authClient,
+			AuthClient:   authClient,
+			MaxEntrySize: cfg.Entries.MaxSize,
 		},
 	}
 
@@ -278,10 +391,16 @@ func run(ctx context.Context, log *logger.Logger) error {
 		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
 		defer cancel()
 
+		close(jobsDone)
+
 		if err := api.Shutdown(ctx); err != nil {
 			api.Close()
 			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
+
+		if err := wrk.Shutdown(ctx); err != nil {
+			return fmt.Errorf("could not stop background jobs gracefully: %w", err)
+		}
 	}
 
 	return nil
@@ -310,3 +429,24 @@ func buildRoutes() mux.RouteAdder {
 
 	return all.Routes()
 }
+
+// schedule starts the job on the worker every interval until done is closed.
+// Each run of the job is given the timeout to complete.
+func schedule(ctx context.Context, log *logger.Logger, wrk *worker.Worker, done <-chan struct{}, name string, interval time.Duration, timeout time.Duration, jobFn worker.JobFn) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		if _, err := wrk.Start(ctx, jobFn); err != nil {
+			log.Error(ctx, "jobs", "job", name, "status", "not started", "msg", err)
+		}
+		cancel()
+	}
+}
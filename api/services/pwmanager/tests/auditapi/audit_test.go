@@ -0,0 +1,29 @@
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+)
+
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+
+	test := apitest.New(t, "Test_Audit")
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, query200(sd), "query-200")
+	test.Run(t, query400(sd), "query-400")
+	test.Run(t, query401(sd), "query-401")
+	test.Run(t, queryBundle200(sd), "querybundle-200")
+	test.Run(t, queryBundle403(sd), "querybundle-403")
+	test.Run(t, record(sd), "record")
+}
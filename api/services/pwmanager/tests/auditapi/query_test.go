@@ -0,0 +1,219 @@
+package audit_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/auditapp"
+	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+)
+
+// auditIDs returns the ids of the audits in the order they were returned.
+func auditIDs(audits []auditapp.Audit) []string {
+	ids := make([]string, len(audits))
+	for i, a := range audits {
+		ids[i] = a.ID
+	}
+
+	return ids
+}
+
+func cmpAuditIDs(expIDs ...string) func(got any, exp any) string {
+	return func(got any, exp any) string {
+		gotResp, exists := got.(*query.Result[auditapp.Audit])
+		if !exists {
+			return "error occurred"
+		}
+
+		return cmp.Diff(auditIDs(gotResp.Items), expIDs)
+	}
+}
+
+func query200(sd seedData) []apitest.Table {
+	bdl := sd.Users[0].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "action",
+			URL:        fmt.Sprintf("/v1/audits?bundle_id=%s&action=entry.update", bdl.ID),
+			Token:      sd.Admins[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[auditapp.Audit]{},
+			ExpResp:    &query.Result[auditapp.Audit]{},
+			CmpFunc:    cmpAuditIDs(sd.Audits[2].ID.String()),
+		},
+		{
+			Name:       "entry",
+			URL:        fmt.Sprintf("/v1/audits?entry_id=%s", sd.Audits[3].EntryID),
+			Token:      sd.Admins[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[auditapp.Audit]{},
+			ExpResp:    &query.Result[auditapp.Audit]{},
+			CmpFunc:    cmpAuditIDs(sd.Audits[3].ID.String()),
+		},
+		{
+			Name:       "date",
+			URL:        fmt.Sprintf("/v1/audits?bundle_id=%s&end_created_date=%s", bdl.ID, "2000-01-01T00:00:00Z"),
+			Token:      sd.Admins[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[auditapp.Audit]{},
+			ExpResp:    &query.Result[auditapp.Audit]{},
+			CmpFunc:    cmpAuditIDs(),
+		},
+	}
+
+	return table
+}
+
+func query400(sd seedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "actor-id",
+			URL:        "/v1/audits?actor_id=abc",
+			Token:      sd.Admins[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.NewFieldErrors("actor_id", fmt.Errorf("invalid UUID length: 3")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func query401(sd seedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "user",
+			URL:        "/v1/audits",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusUnauthorized,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[[USER]] rule[rule_admin_only]: rego evaluation failed : bindings results[[{[true] map[x:false]}]] ok[true]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryBundle200(sd seedData) []apitest.Table {
+	bdl := sd.Users[0].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "action",
+			URL:        fmt.Sprintf("/v1/bundles/%s/audits?action=entry.create", bdl.ID),
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[auditapp.Audit]{},
+			ExpResp:    &query.Result[auditapp.Audit]{},
+			CmpFunc:    cmpAuditIDs(sd.Audits[0].ID.String()),
+		},
+	}
+
+	return table
+}
+
+func queryBundle403(sd seedData) []apitest.Table {
+	bdl := sd.Users[0].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "reader",
+			URL:        fmt.Sprintf("/v1/bundles/%s/audits", bdl.ID),
+			Token:      sd.Users[1].Token,
+			StatusCode: http.StatusForbidden,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "must have admin perms for bundleID[%s]", bdl.ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// record reads an entry and checks the read was recorded with the actor and
+// targets of the call.
+func record(sd seedData) []apitest.Table {
+	bdl := sd.Users[0].Bundles[0]
+	ent := sd.Users[0].Entries[0]
+	reader := sd.Users[1]
+
+	table := []apitest.Table{
+		{
+			Name:       "read",
+			URL:        fmt.Sprintf("/v1/entries/%s", ent.ID),
+			Token:      reader.Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &entryapp.Entry{},
+			ExpResp:    &entryapp.Entry{ID: ent.ID.String()},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got.(*entryapp.Entry).ID, exp.(*entryapp.Entry).ID)
+			},
+		},
+		{
+			Name:       "recorded",
+			URL:        fmt.Sprintf("/v1/bundles/%s/audits?action=entry.read&entry_id=%s", bdl.ID, ent.ID),
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[auditapp.Audit]{},
+			ExpResp: &query.Result[auditapp.Audit]{
+				Items: []auditapp.Audit{
+					{
+						ActorID:  reader.ID.String(),
+						Action:   "entry.read",
+						BundleID: bdl.ID.String(),
+						EntryID:  ent.ID.String(),
+						Status:   http.StatusOK,
+					},
+				},
+				Total:       1,
+				Page:        1,
+				RowsPerPage: 10,
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[auditapp.Audit])
+				if !exists {
+					return "error occurred"
+				}
+
+				// The id, chain position, address and trace of the call are not
+				// known up front.
+				for i := range gotResp.Items {
+					gotResp.Items[i].ID = ""
+					gotResp.Items[i].Sequence = 0
+					gotResp.Items[i].PrevHash = ""
+					gotResp.Items[i].Hash = ""
+					gotResp.Items[i].IPAddress = ""
+					gotResp.Items[i].UserAgent = ""
+					gotResp.Items[i].TraceID = ""
+					gotResp.Items[i].DateCreated = ""
+				}
+
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,102 @@
+package audit_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/auth"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+// seedData extends the api seed data with the audits recorded up front.
+type seedData struct {
+	apitest.SeedData
+	Audits []auditbus.Audit
+}
+
+func insertSeedData(db *dbtest.Database, ath *auth.Auth) (seedData, error) {
+	ctx := context.Background()
+	busDomain := db.BusDomain
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	bdls, err := bundlebus.TestGenerateSeedShareableBundles(ctx, 1, busDomain.Bundle, usrs[0].ID)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding bundles : %w", err)
+	}
+
+	bids := []uuid.UUID{bdls[0].ID}
+
+	roles := []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write}
+	keys, err := keybus.TestGenerateSeedKeys(ctx, 1, busDomain.Key, usrs[0].ID, bids, roles)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	entries, err := entrybus.TestGenerateSeedEntries(ctx, 1, busDomain.Entry, usrs[0].ID, bids)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding entries : %w", err)
+	}
+
+	audits, err := auditbus.TestGenerateSeedAudits(ctx, 4, busDomain.Audit, usrs[0].ID, bdls[0].ID)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding audits : %w", err)
+	}
+
+	tu1 := apitest.User{
+		User:    usrs[0],
+		Bundles: bdls,
+		Keys:    keys,
+		Entries: entries,
+		Token:   apitest.Token(db.BusDomain.User, ath, usrs[0].Email.Address),
+	}
+
+	// The second user can read the bundle but not administer it.
+	roles = []bundlerole.Role{bundlerole.Read}
+	keys, err = keybus.TestGenerateSeedKeys(ctx, 1, busDomain.Key, usrs[1].ID, bids, roles)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	tu2 := apitest.User{
+		User:  usrs[1],
+		Keys:  keys,
+		Token: apitest.Token(db.BusDomain.User, ath, usrs[1].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	admins, err := userbus.TestSeedUsers(ctx, 1, role.Admin, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding admins : %w", err)
+	}
+
+	ta1 := apitest.User{
+		User:  admins[0],
+		Token: apitest.Token(db.BusDomain.User, ath, admins[0].Email.Address),
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := seedData{
+		SeedData: apitest.SeedData{
+			Users:  []apitest.User{tu1, tu2},
+			Admins: []apitest.User{ta1},
+		},
+		Audits: audits,
+	}
+
+	return sd, nil
+}
@@ -28,9 +28,18 @@ func Test_Bundle(t *testing.T) {
 	test.Run(t, update400(sd), "update-400")
 	test.Run(t, update401(sd), "update-401")
 	test.Run(t, update403(sd), "update-403")
+	test.Run(t, update409(sd), "update-409")
+
+	test.Run(t, rotate400(sd), "rotate-400")
+	test.Run(t, rotate403(sd), "rotate-403")
+	test.Run(t, rotate200(sd), "rotate-200")
+	test.Run(t, rotate409(sd), "rotate-409")
 
 	test.Run(t, delete401(sd), "delete-401")
 	test.Run(t, delete403(sd), "delete-403")
 	test.Run(t, delete200(sd), "delete-200")
 
+	test.Run(t, queryTrash200(sd), "querytrash-200")
+	test.Run(t, undelete403(sd), "undelete-403")
+	test.Run(t, undelete200(sd), "undelete-200")
 }
@@ -38,11 +38,14 @@ func create200(sd apitest.SeedData) []apitest.Table {
 			GotResp: &bundleapp.BundleTx{},
 			ExpResp: &bundleapp.BundleTx{
 				Key: bundleapp.Key{
-					Data: "Guitar",
+					Data:     "Guitar",
+					Revision: 1,
 				},
 				Bundle: bundleapp.Bundle{
-					Type:     "PERSONAL",
-					Metadata: "Bundle Metadata",
+					Type:          "PERSONAL",
+					Metadata:      "Bundle Metadata",
+					KeyGeneration: 1,
+					Revision:      1,
 				},
 			},
 			CmpFunc: func(got any, exp any) string {
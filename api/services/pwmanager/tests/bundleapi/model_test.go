@@ -9,11 +9,13 @@ import (
 
 func toAppBundle(bdl bundlebus.Bundle) bundleapp.Bundle {
 	return bundleapp.Bundle{
-		ID:          bdl.ID.String(),
-		UserID:      bdl.UserID.String(),
-		Type:        bdl.Type.String(),
-		DateCreated: bdl.DateCreated.Format(time.RFC3339),
-		DateUpdated: bdl.DateUpdated.Format(time.RFC3339),
+		ID:            bdl.ID.String(),
+		UserID:        bdl.UserID.String(),
+		Type:          bdl.Type.String(),
+		KeyGeneration: bdl.KeyGeneration,
+		Revision:      bdl.Revision,
+		DateCreated:   bdl.DateCreated.Format(time.RFC3339),
+		DateUpdated:   bdl.DateUpdated.Format(time.RFC3339),
 	}
 }
 
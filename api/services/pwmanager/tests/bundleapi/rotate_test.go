@@ -0,0 +1,143 @@
+package bundle_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/bundleapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+)
+
+func rotateBundle(sd apitest.SeedData) *bundleapp.RotateBundle {
+	return &bundleapp.RotateBundle{
+		KeyGeneration: 1,
+		Metadata:      "ROTATED METADATA",
+		Entries: []bundleapp.RotateEntry{
+			{ID: sd.Users[userB].Entries[1].ID.String(), Data: json.RawMessage(entrybus.TestNewData("RotatedEntry", 2).String())},
+		},
+		Keys: []bundleapp.RotateKey{
+			{UserID: sd.Users[userB].ID.String(), Data: "RotatedKey"},
+		},
+	}
+}
+
+func rotate200(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userB].Bundles[1]
+
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/v1/bundles/%s/rotate", bdl.ID),
+			Token:      sd.Users[userB].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      rotateBundle(sd),
+			GotResp:    &bundleapp.Bundle{},
+			ExpResp: &bundleapp.Bundle{
+				ID:            bdl.ID.String(),
+				UserID:        bdl.UserID.String(),
+				Type:          bdl.Type.String(),
+				Metadata:      "ROTATED METADATA",
+				KeyGeneration: 2,
+				Revision:      2,
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*bundleapp.Bundle)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*bundleapp.Bundle)
+				gotResp.DateUpdated = expResp.DateUpdated
+				gotResp.DateCreated = expResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func rotate400(sd apitest.SeedData) []apitest.Table {
+	incomplete := rotateBundle(sd)
+	incomplete.Entries = nil
+
+	table := []apitest.Table{
+		{
+			Name:       "incomplete",
+			URL:        fmt.Sprintf("/v1/bundles/%s/rotate", sd.Users[userB].Bundles[1].ID),
+			Token:      sd.Users[userB].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      incomplete,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.FailedPrecondition, "expected 1 entries, got 0: %s", bundlebus.ErrIncompleteRotation),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "missing-input",
+			URL:        fmt.Sprintf("/v1/bundles/%s/rotate", sd.Users[userB].Bundles[1].ID),
+			Token:      sd.Users[userB].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &bundleapp.RotateBundle{},
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"keyGeneration\",\"error\":\"keyGeneration is a required field\"},{\"field\":\"metadata\",\"error\":\"metadata is a required field\"},{\"field\":\"keys\",\"error\":\"keys is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func rotate403(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userB].Bundles[1]
+
+	table := []apitest.Table{
+		{
+			Name:       "not-member",
+			URL:        fmt.Sprintf("/v1/bundles/%s/rotate", bdl.ID),
+			Token:      sd.Users[userA].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			Input:      rotateBundle(sd),
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "query: userID[%s] bundleID[%s]: db: key not found", sd.Users[userA].ID, bdl.ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func rotate409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "stale",
+			URL:        fmt.Sprintf("/v1/bundles/%s/rotate", sd.Users[userB].Bundles[1].ID),
+			Token:      sd.Users[userB].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input:      rotateBundle(sd),
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -49,7 +49,7 @@ func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, erro
 	}
 
 	// -------------------------------------------------------------------------
-	// tu2 check cascading deletes
+	// tu2 check deletes of bundles with keys and entries
 
 	bdls, err = bundlebus.TestGenerateSeedBundles(ctx, 2, busDomain.Bundle, usrs[userB].ID)
 	if err != nil {
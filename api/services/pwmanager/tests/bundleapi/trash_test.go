@@ -0,0 +1,102 @@
+package bundle_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/bundleapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+)
+
+func queryTrash200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userA, userKeyMapping[userA]),
+			URL:        "/v1/trash/bundles?page=1&rows=10",
+			Token:      sd.Users[userA].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &query.Result[bundleapp.Bundle]{},
+			ExpResp:    []string{sd.Users[userA].Bundles[0].ID.String()},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[bundleapp.Bundle])
+				if !exists {
+					return "error occurred"
+				}
+
+				var ids []string
+				for _, bdl := range gotResp.Items {
+					if bdl.DateDeleted == "" {
+						return fmt.Sprintf("expected bundle[%s] to be deleted", bdl.ID)
+					}
+					ids = append(ids, bdl.ID)
+				}
+
+				return cmp.Diff(ids, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func undelete403(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userA].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userB, userKeyMapping[userB]),
+			URL:        fmt.Sprintf("/v1/trash/bundles/%s/restore", bdl.ID),
+			Token:      sd.Users[userB].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "only bundle owner can restore bundleID[%s]", bdl.ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func undelete200(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userA].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userA, userKeyMapping[userA]),
+			URL:        fmt.Sprintf("/v1/trash/bundles/%s/restore", bdl.ID),
+			Token:      sd.Users[userA].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &bundleapp.Bundle{},
+			ExpResp: &bundleapp.Bundle{
+				ID:            bdl.ID.String(),
+				UserID:        bdl.UserID.String(),
+				Type:          bdl.Type.String(),
+				Metadata:      bdl.Metadata,
+				KeyGeneration: bdl.KeyGeneration,
+				Revision:      2,
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*bundleapp.Bundle)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*bundleapp.Bundle)
+				gotResp.DateUpdated = expResp.DateUpdated
+				gotResp.DateCreated = expResp.DateCreated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
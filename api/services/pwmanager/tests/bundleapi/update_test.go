@@ -8,6 +8,7 @@ import (
 	"github.com/gradientsearch/pwmanager/app/domain/bundleapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
 )
 
@@ -31,14 +32,17 @@ func update200(sd apitest.SeedData) []apitest.Table {
 			Token:      sd.Users[i.user].Token,
 			Method:     http.MethodPut,
 			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"If-Match": `"1"`},
 			Input: &bundleapp.UpdateBundle{
 				Type: dbtest.StringPointer("PERSONAL"),
 			},
 			GotResp: &bundleapp.Bundle{},
 			ExpResp: &bundleapp.Bundle{
-				ID:     sd.Users[i.user].Bundles[0].ID.String(),
-				UserID: sd.Users[i.user].ID.String(),
-				Type:   "PERSONAL",
+				ID:            sd.Users[i.user].Bundles[0].ID.String(),
+				UserID:        sd.Users[i.user].ID.String(),
+				Type:          "PERSONAL",
+				KeyGeneration: 1,
+				Revision:      2,
 			},
 			CmpFunc: func(got any, exp any) string {
 				gotResp, exists := got.(*bundleapp.Bundle)
@@ -170,3 +174,26 @@ func update403(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func update409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "stale-revision",
+			URL:        fmt.Sprintf("/v1/bundles/%s", sd.Users[userA].Bundles[0].ID),
+			Token:      sd.Users[userA].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusConflict,
+			Headers:    map[string]string{"If-Match": `"1"`},
+			Input: &bundleapp.UpdateBundle{
+				Type: dbtest.StringPointer("SHAREABLE"),
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.Conflict, bundlebus.ErrConflict),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
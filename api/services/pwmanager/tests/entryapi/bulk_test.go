@@ -0,0 +1,185 @@
+package entry_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+)
+
+func bulk200(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+	upd := sd.Users[userBundleAdmin].Entries[3]
+	del := sd.Users[userBundleAdmin].Entries[4]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userReadWrite, userKeyMapping[userReadWrite]),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userReadWrite].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &entryapp.BulkEntries{
+				Operations: []entryapp.BulkOperation{
+					{Action: entrybus.BulkCreate, Type: "LOGIN", SchemaVersion: 1, Data: entryData("BULK LOGIN", 1)},
+					{Action: entrybus.BulkUpdate, ID: upd.ID.String(), Revision: upd.Revision, Type: "SECURE_NOTE", SchemaVersion: 1, Data: entryData("BULK NOTE", 1)},
+					{Action: entrybus.BulkDelete, ID: del.ID.String(), Revision: del.Revision},
+				},
+				Metadata:      "BULK BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &entryapp.BulkResult{},
+			ExpResp: &entryapp.BulkResult{
+				Results: []entryapp.BulkItem{
+					{Index: 0, Action: entrybus.BulkCreate, Revision: 1},
+					{Index: 1, Action: entrybus.BulkUpdate, ID: upd.ID.String(), Revision: upd.Revision + 1},
+					{Index: 2, Action: entrybus.BulkDelete, ID: del.ID.String(), Revision: del.Revision},
+				},
+				Bundle: entryapp.Bundle{
+					Metadata:      "BULK BUNDLE METADATA",
+					Type:          bundletype.Shareable.String(),
+					UserID:        sd.Users[userBundleAdmin].ID.String(),
+					ID:            bdl.ID.String(),
+					KeyGeneration: 1,
+				},
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*entryapp.BulkResult)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*entryapp.BulkResult)
+				if len(gotResp.Results) != len(expResp.Results) {
+					return cmp.Diff(gotResp, expResp)
+				}
+
+				expResp.Results[0].ID = gotResp.Results[0].ID
+				expResp.Bundle.Revision = gotResp.Bundle.Revision
+				expResp.Bundle.DateCreated = gotResp.Bundle.DateCreated
+				expResp.Bundle.DateUpdated = gotResp.Bundle.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func bulk400(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	// The seeded entry has been updated since it was read.
+	stale := sd.Users[userBundleAdmin].Entries[0]
+
+	var failed errs.FieldErrors
+	failed.Add("operations[0]", entrybus.ErrConflict)
+	failed.Add("operations[1]", entrybus.ErrNotFound)
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-missing-operations", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.BulkEntries{
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"operations\",\"error\":\"operations is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-invalid-id", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.BulkEntries{
+				Operations: []entryapp.BulkOperation{
+					{Action: entrybus.BulkDelete, ID: "abc"},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.NewFieldErrors("operations[0].id", fmt.Errorf("invalid UUID length: 3")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-item-type", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.BulkEntries{
+				Operations: []entryapp.BulkOperation{
+					{Action: entrybus.BulkCreate, Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+					{Action: entrybus.BulkCreate, Type: "PASSPORT", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.NewFieldErrors("operations[1].type", fmt.Errorf("invalid item type \"PASSPORT\"")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-key-generation", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.BulkEntries{
+				Operations: []entryapp.BulkOperation{
+					{Action: entrybus.BulkCreate, Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+					{Action: entrybus.BulkCreate, Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 2)},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.NewFieldErrors("operations[1]", entrybus.ErrKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-failed-operations", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/bulk", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.BulkEntries{
+				Operations: []entryapp.BulkOperation{
+					{Action: entrybus.BulkDelete, ID: stale.ID.String(), Revision: stale.Revision},
+					{Action: entrybus.BulkDelete, ID: uuid.NewString()},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: failed.ToError(),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
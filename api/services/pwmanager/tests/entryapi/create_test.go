@@ -8,6 +8,8 @@ import (
 	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 )
 
@@ -32,21 +34,29 @@ func create200(sd apitest.SeedData) []apitest.Table {
 			Method:     http.MethodPost,
 			StatusCode: http.StatusOK,
 			Input: &entryapp.NewEntryTX{
-				Data:     fmt.Sprintf("DATA%d", i.user),
-				Metadata: fmt.Sprintf("METADATA%d", i.user),
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Data:          entryData(fmt.Sprintf("DATA%d", i.user), 1),
+				Metadata:      fmt.Sprintf("METADATA%d", i.user),
+				KeyGeneration: 1,
 			},
 			GotResp: &entryapp.EntryTx{},
 			ExpResp: &entryapp.EntryTx{
 				Entry: entryapp.Entry{
-					Data:     fmt.Sprintf("DATA%d", i.user),
-					UserID:   sd.Users[i.user].ID.String(),
-					BundleID: sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					Type:          "LOGIN",
+					SchemaVersion: 1,
+					Data:          entryData(fmt.Sprintf("DATA%d", i.user), 1),
+					UserID:        sd.Users[i.user].ID.String(),
+					BundleID:      sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					KeyGeneration: 1,
+					Revision:      1,
 				},
 				Bundle: entryapp.Bundle{
-					Metadata: fmt.Sprintf("METADATA%d", i.user),
-					Type:     bundletype.Shareable.String(),
-					UserID:   sd.Users[userBundleAdmin].ID.String(),
-					ID:       sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					Metadata:      fmt.Sprintf("METADATA%d", i.user),
+					Type:          bundletype.Shareable.String(),
+					UserID:        sd.Users[userBundleAdmin].ID.String(),
+					ID:            sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					KeyGeneration: 1,
 				},
 			},
 			CmpFunc: func(got any, exp any) string {
@@ -60,6 +70,7 @@ func create200(sd apitest.SeedData) []apitest.Table {
 				expResp.Entry.DateCreated = gotResp.Entry.DateCreated
 				expResp.Entry.DateUpdated = gotResp.Entry.DateUpdated
 				expResp.Bundle.Type = gotResp.Bundle.Type
+				expResp.Bundle.Revision = gotResp.Bundle.Revision
 				expResp.Bundle.DateCreated = gotResp.Bundle.DateCreated
 				expResp.Bundle.DateUpdated = gotResp.Bundle.DateUpdated
 
@@ -81,7 +92,10 @@ func create400(sd apitest.SeedData) []apitest.Table {
 			Method:     http.MethodPost,
 			StatusCode: http.StatusBadRequest,
 			Input: &entryapp.NewEntryTX{
-				Metadata: "UPDATED BUNDLE METADATA",
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
 			},
 			GotResp: &errs.Error{},
 			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"data\",\"error\":\"data is a required field\"}]"),
@@ -89,6 +103,89 @@ func create400(sd apitest.SeedData) []apitest.Table {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:       fmt.Sprintf("tu%d-key-generation", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries", sd.Users[userBundleAdmin].Bundles[0].ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryTX{
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Data:          entryData("Guitar", 2),
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.InvalidArgument, entrybus.ErrKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-item-type", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries", sd.Users[userBundleAdmin].Bundles[0].ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryTX{
+				Type:          "PASSPORT",
+				SchemaVersion: 1,
+				Data:          entryData("Guitar", 1),
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "parse type: invalid item type \"PASSPORT\""),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-schema-version", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries", sd.Users[userBundleAdmin].Bundles[0].ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryTX{
+				Type:          "CARD",
+				SchemaVersion: 2,
+				Data:          entryData("Guitar", 1),
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.InvalidArgument, entrybus.ErrSchemaVersion),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func create409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-stale-key-generation", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries", sd.Users[userBundleAdmin].Bundles[0].ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &entryapp.NewEntryTX{
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Data:          entryData("Guitar", 2),
+				Metadata:      "STALE BUNDLE METADATA",
+				KeyGeneration: 2,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
@@ -166,8 +263,11 @@ func create403(sd apitest.SeedData) []apitest.Table {
 			Method:     http.MethodPost,
 			StatusCode: http.StatusForbidden,
 			Input: &entryapp.NewEntryTX{
-				Data:     "Guitar",
-				Metadata: "UPDATED BUNDLE METADATA",
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Data:          entryData("Guitar", 1),
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
 			},
 			GotResp: &errs.Error{},
 			ExpResp: errs.Newf(errs.PermissionDenied, ""),
@@ -0,0 +1,154 @@
+package entry_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+)
+
+func createBatch200(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userReadWrite, userKeyMapping[userReadWrite]),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/batch", bdl.ID.String()),
+			Token:      sd.Users[userReadWrite].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &entryapp.NewEntryBatch{
+				Entries: []entryapp.NewBatchEntry{
+					{Type: "LOGIN", SchemaVersion: 1, Data: entryData("IMPORTED LOGIN", 1)},
+					{Type: "SECURE_NOTE", SchemaVersion: 1, Data: entryData("IMPORTED NOTE", 1)},
+				},
+				Metadata:      "IMPORTED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &entryapp.EntryBatch{},
+			ExpResp: &entryapp.EntryBatch{
+				Entries: []entryapp.Entry{
+					{
+						Type:          "LOGIN",
+						SchemaVersion: 1,
+						Data:          entryData("IMPORTED LOGIN", 1),
+						UserID:        sd.Users[userReadWrite].ID.String(),
+						BundleID:      bdl.ID.String(),
+						KeyGeneration: 1,
+						Revision:      1,
+					},
+					{
+						Type:          "SECURE_NOTE",
+						SchemaVersion: 1,
+						Data:          entryData("IMPORTED NOTE", 1),
+						UserID:        sd.Users[userReadWrite].ID.String(),
+						BundleID:      bdl.ID.String(),
+						KeyGeneration: 1,
+						Revision:      1,
+					},
+				},
+				Bundle: entryapp.Bundle{
+					Metadata:      "IMPORTED BUNDLE METADATA",
+					Type:          bundletype.Shareable.String(),
+					UserID:        sd.Users[userBundleAdmin].ID.String(),
+					ID:            bdl.ID.String(),
+					KeyGeneration: 1,
+				},
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*entryapp.EntryBatch)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*entryapp.EntryBatch)
+				if len(gotResp.Entries) != len(expResp.Entries) {
+					return cmp.Diff(gotResp, expResp)
+				}
+
+				for i := range expResp.Entries {
+					expResp.Entries[i].ID = gotResp.Entries[i].ID
+					expResp.Entries[i].DateCreated = gotResp.Entries[i].DateCreated
+					expResp.Entries[i].DateUpdated = gotResp.Entries[i].DateUpdated
+				}
+				expResp.Bundle.Revision = gotResp.Bundle.Revision
+				expResp.Bundle.DateCreated = gotResp.Bundle.DateCreated
+				expResp.Bundle.DateUpdated = gotResp.Bundle.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func createBatch400(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-missing-entries", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/batch", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryBatch{
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"entries\",\"error\":\"entries is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-item-type", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/batch", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryBatch{
+				Entries: []entryapp.NewBatchEntry{
+					{Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+					{Type: "PASSPORT", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "entries[1]: parse type: invalid item type \"PASSPORT\""),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       fmt.Sprintf("tu%d-key-generation", userBundleAdmin),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries/batch", bdl.ID.String()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &entryapp.NewEntryBatch{
+				Entries: []entryapp.NewBatchEntry{
+					{Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 1)},
+					{Type: "LOGIN", SchemaVersion: 1, Data: entryData("Guitar", 2)},
+				},
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.NewFieldErrors("entries[1]", entrybus.ErrKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -29,7 +29,8 @@ func delete200(sd apitest.SeedData) []apitest.Table {
 			URL:   fmt.Sprintf("/v1/entries/%s", sd.Users[userBundleAdmin].Entries[idx].ID),
 			Token: sd.Users[i.user].Token,
 			Input: &entryapp.DeleteEntry{
-				Metadata: fmt.Sprintf("UPDATED BUNDLE METADATA %d", i.user),
+				Metadata:      fmt.Sprintf("UPDATED BUNDLE METADATA %d", i.user),
+				KeyGeneration: 1,
 			},
 			Method:     http.MethodDelete,
 			StatusCode: http.StatusOK,
@@ -125,7 +126,8 @@ func delete403(sd apitest.SeedData) []apitest.Table {
 			StatusCode: http.StatusForbidden,
 			GotResp:    &errs.Error{},
 			Input: &entryapp.DeleteEntry{
-				Metadata: "UPDATED BUNDLE METADATA",
+				Metadata:      "UPDATED BUNDLE METADATA",
+				KeyGeneration: 1,
 			},
 			ExpResp: errs.Newf(errs.PermissionDenied, ""),
 			CmpFunc: func(got any, exp any) string {
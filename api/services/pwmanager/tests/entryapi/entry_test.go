@@ -1,9 +1,12 @@
 package entry_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
 )
 
 func Test_Entry(t *testing.T) {
@@ -24,17 +27,55 @@ func Test_Entry(t *testing.T) {
 	test.Run(t, queryByID401(sd), "querybyid-401")
 	test.Run(t, queryByID403(sd), "querybyid-403")
 
+	test.Run(t, query200(sd), "query-200")
+	test.Run(t, query400(sd), "query-400")
+	test.Run(t, query403(sd), "query-403")
+
 	test.Run(t, create200(sd), "create-200")
 	test.Run(t, create400(sd), "create-400")
 	test.Run(t, create401(sd), "create-401")
+	test.Run(t, create409(sd), "create-409")
 	test.Run(t, create403(sd), "create-403")
 
+	test.Run(t, createBatch200(sd), "createbatch-200")
+	test.Run(t, createBatch400(sd), "createbatch-400")
+
 	test.Run(t, update200(sd), "update-200")
 	test.Run(t, update401(sd), "update-401")
 	test.Run(t, update403(sd), "update-403")
+	test.Run(t, update409(sd), "update-409")
+
+	test.Run(t, queryVersions200(sd), "queryversions-200")
+	test.Run(t, queryVersions403(sd), "queryversions-403")
+
+	v := oldestVersion(t, test, sd.Users[userBundleAdmin].Entries[0])
+
+	test.Run(t, restore404(sd), "restore-404")
+	test.Run(t, restore409(sd, v), "restore-409")
+	test.Run(t, restore200(sd, v), "restore-200")
 
 	test.Run(t, delete200(sd), "delete-200")
 	test.Run(t, delete401(sd), "delete-401")
 	test.Run(t, delete403(sd), "delete-403")
 
+	test.Run(t, queryTrash200(sd), "querytrash-200")
+	test.Run(t, undelete403(sd), "undelete-403")
+	test.Run(t, undelete200(sd), "undelete-200")
+
+	test.Run(t, bulk200(sd), "bulk-200")
+	test.Run(t, bulk400(sd), "bulk-400")
+}
+
+// oldestVersion returns the first version written for the entry.
+func oldestVersion(t *testing.T, test *apitest.Test, e entrybus.Entry) entrybus.Version {
+	vers, err := test.DB.BusDomain.Entry.QueryVersions(context.Background(), e.ID, page.MustParse("1", "10"))
+	if err != nil {
+		t.Fatalf("Should be able to query versions: %s", err)
+	}
+
+	if len(vers) == 0 {
+		t.Fatalf("Should have versions for entry %s", e.ID)
+	}
+
+	return vers[len(vers)-1]
 }
@@ -1,6 +1,7 @@
 package entry_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
@@ -9,12 +10,16 @@ import (
 
 func toAppEntry(e entrybus.Entry) entryapp.Entry {
 	return entryapp.Entry{
-		ID:          e.ID.String(),
-		UserID:      e.UserID.String(),
-		BundleID:    e.BundleID.String(),
-		Data:        e.Data.String(),
-		DateCreated: e.DateCreated.Format(time.RFC3339),
-		DateUpdated: e.DateUpdated.Format(time.RFC3339),
+		ID:            e.ID.String(),
+		UserID:        e.UserID.String(),
+		BundleID:      e.BundleID.String(),
+		Type:          e.Type.String(),
+		SchemaVersion: e.SchemaVersion,
+		Data:          json.RawMessage(e.Data.String()),
+		KeyGeneration: e.KeyGeneration,
+		Revision:      e.Revision,
+		DateCreated:   e.DateCreated.Format(time.RFC3339),
+		DateUpdated:   e.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -31,3 +36,9 @@ func toAppEntries(entries []entrybus.Entry) []entryapp.Entry {
 
 	return items
 }
+
+// entryData returns the encoded envelope of the value encrypted under the
+// bundle key generation.
+func entryData(value string, keyGeneration int) json.RawMessage {
+	return json.RawMessage(entrybus.TestNewData(value, keyGeneration).String())
+}
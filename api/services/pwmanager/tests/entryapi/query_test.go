@@ -3,11 +3,15 @@ package entry_test
 import (
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
 func queryByID200(sd apitest.SeedData) []apitest.Table {
@@ -126,3 +130,134 @@ func queryByID403(sd apitest.SeedData) []apitest.Table {
 	}
 	return table
 }
+
+func query200(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	entries := make([]entrybus.Entry, 0, ENTRIES_PER_BUNDLE)
+	for _, e := range sd.Users[userBundleAdmin].Entries {
+		if e.BundleID == bdl.ID {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID.String() <= entries[j].ID.String()
+	})
+
+	var logins []entrybus.Entry
+	for _, e := range entries {
+		if e.Type == itemtype.Login {
+			logins = append(logins, e)
+		}
+	}
+
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries?page=1&rows=10&orderBy=entry_id,ASC", bdl.ID),
+			Token:      sd.Users[userRead].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[entryapp.Entry]{},
+			ExpResp: &query.Result[entryapp.Entry]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       len(entries),
+				Items:       toAppEntries(entries),
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "type",
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries?page=1&rows=10&orderBy=entry_id,ASC&type=%s", bdl.ID, itemtype.Login),
+			Token:      sd.Users[userRead].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[entryapp.Entry]{},
+			ExpResp: &query.Result[entryapp.Entry]{
+				Page:        1,
+				RowsPerPage: 10,
+				Total:       len(logins),
+				Items:       toAppEntries(logins),
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func query400(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "bad-date",
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries?page=1&rows=10&start_created_date=yesterday", bdl.ID),
+			Token:      sd.Users[userRead].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "[{\"field\":\"start_created_date\",\"error\":\"parsing time \\\"yesterday\\\" as \\\"2006-01-02T15:04:05Z07:00\\\": cannot parse \\\"yesterday\\\" as \\\"2006\\\"\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "bad-orderby",
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries?page=1&rows=10&orderBy=data,ASC", bdl.ID),
+			Token:      sd.Users[userRead].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "[{\"field\":\"order\",\"error\":\"unknown order: data\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func query403(sd apitest.SeedData) []apitest.Table {
+	bdl := sd.Users[userBundleAdmin].Bundles[0]
+
+	inputs := []struct {
+		user       userKey
+		errMessage string
+	}{
+		{
+			userNoRoles,
+			fmt.Sprintf("must have read perms for bundle[%s] to list entries", bdl.ID),
+		},
+		{
+			userNoKey,
+			fmt.Sprintf("query: userID[%s] bundleID[%s]: db: key not found", sd.Users[userNoKey].ID, bdl.ID),
+		},
+	}
+
+	table := []apitest.Table{}
+	for _, i := range inputs {
+		t := apitest.Table{
+			Name:       fmt.Sprintf("tu%d-%s", i.user, userKeyMapping[i.user]),
+			URL:        fmt.Sprintf("/v1/bundles/%s/entries?page=1&rows=10", bdl.ID),
+			Token:      sd.Users[i.user].Token,
+			StatusCode: http.StatusForbidden,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "%s", i.errMessage),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		}
+		table = append(table, t)
+	}
+
+	return table
+}
@@ -52,7 +52,7 @@ func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, erro
 	// -------------------------------------------------------------------------
 	// tu1
 
-	bdls, err := bundlebus.TestGenerateSeedBundles(ctx, NUMBER_OF_BUNDLES, busDomain.Bundle, usrs[userBundleAdmin].ID)
+	bdls, err := bundlebus.TestGenerateSeedShareableBundles(ctx, NUMBER_OF_BUNDLES, busDomain.Bundle, usrs[userBundleAdmin].ID)
 	if err != nil {
 		return apitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
 	}
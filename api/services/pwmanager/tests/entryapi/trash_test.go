@@ -0,0 +1,126 @@
+package entry_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+)
+
+func queryTrash200(sd apitest.SeedData) []apitest.Table {
+	inputs := []struct {
+		user  userKey
+		total int
+	}{
+		{
+			userRead,
+			2,
+		},
+		{
+			userNoKey,
+			0,
+		},
+	}
+
+	table := []apitest.Table{}
+	for _, i := range inputs {
+		t := apitest.Table{
+			Name:       fmt.Sprintf("tu%d-%s", i.user, userKeyMapping[i.user]),
+			URL:        "/v1/trash/entries?page=1&rows=10",
+			Token:      sd.Users[i.user].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &query.Result[entryapp.Entry]{},
+			ExpResp:    i.total,
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[entryapp.Entry])
+				if !exists {
+					return "error occurred"
+				}
+
+				return cmp.Diff(gotResp.Total, exp)
+			},
+		}
+
+		table = append(table, t)
+	}
+
+	return table
+}
+
+func undelete403(sd apitest.SeedData) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:  fmt.Sprintf("tu%d-%s", userRead, userKeyMapping[userRead]),
+			URL:   fmt.Sprintf("/v1/trash/entries/%s/restore", e.ID),
+			Token: sd.Users[userRead].Token,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED FROM TRASH",
+				KeyGeneration: 1,
+			},
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "must have write perms for bundle[%s] to restore an entry", e.BundleID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:  "not-in-trash",
+			URL:   fmt.Sprintf("/v1/trash/entries/%s/restore", uuid.New()),
+			Token: sd.Users[userBundleAdmin].Token,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED FROM TRASH",
+				KeyGeneration: 1,
+			},
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.New(errs.PermissionDenied, entrybus.ErrNotFound),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func undelete200(sd apitest.SeedData) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:  fmt.Sprintf("tu%d-%s", userReadWrite, userKeyMapping[userReadWrite]),
+			URL:   fmt.Sprintf("/v1/trash/entries/%s/restore", e.ID),
+			Token: sd.Users[userReadWrite].Token,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED FROM TRASH",
+				KeyGeneration: 1,
+			},
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &entryapp.EntryTx{},
+			ExpResp:    []string{e.ID.String(), "", "RESTORED FROM TRASH"},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*entryapp.EntryTx)
+				if !exists {
+					return "error occurred"
+				}
+
+				return cmp.Diff([]string{gotResp.Entry.ID, gotResp.Entry.DateDeleted, gotResp.Bundle.Metadata}, exp)
+			},
+		},
+	}
+
+	return table
+}
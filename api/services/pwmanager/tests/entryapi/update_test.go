@@ -9,17 +9,22 @@ import (
 	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 )
 
 func update200(sd apitest.SeedData) []apitest.Table {
+	// Each update moves the entry to the next revision.
 	inputs := []struct {
-		user userKey
+		user     userKey
+		revision int
 	}{
 		{
 			userBundleAdmin,
+			2,
 		},
 		{
 			userReadWrite,
+			3,
 		},
 	}
 
@@ -31,25 +36,34 @@ func update200(sd apitest.SeedData) []apitest.Table {
 			Token:      sd.Users[i.user].Token,
 			Method:     http.MethodPut,
 			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"If-Match": fmt.Sprintf("\"%d\"", i.revision-1)},
 			Input: &entryapp.UpdateEntry{
-				Data:     fmt.Sprintf("%s%d", "Guitar", i.user),
-				Metadata: fmt.Sprintf("%s%d", "Metadata", i.user),
+				Type:          "SECURE_NOTE",
+				SchemaVersion: 1,
+				Data:          entryData(fmt.Sprintf("%s%d", "Guitar", i.user), 1),
+				Metadata:      fmt.Sprintf("%s%d", "Metadata", i.user),
+				KeyGeneration: 1,
 			},
 			GotResp: &entryapp.EntryTx{},
 			ExpResp: &entryapp.EntryTx{
 				Entry: entryapp.Entry{
-					ID:          sd.Users[userBundleAdmin].Entries[0].ID.String(),
-					UserID:      sd.Users[i.user].ID.String(),
-					BundleID:    sd.Users[userBundleAdmin].Bundles[0].ID.String(),
-					Data:        fmt.Sprintf("%s%d", "Guitar", i.user),
-					DateCreated: sd.Users[userBundleAdmin].Entries[0].DateCreated.Format(time.RFC3339),
-					DateUpdated: sd.Users[userBundleAdmin].Entries[0].DateCreated.Format(time.RFC3339),
+					ID:            sd.Users[userBundleAdmin].Entries[0].ID.String(),
+					UserID:        sd.Users[i.user].ID.String(),
+					BundleID:      sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					Type:          "SECURE_NOTE",
+					SchemaVersion: 1,
+					Data:          entryData(fmt.Sprintf("%s%d", "Guitar", i.user), 1),
+					KeyGeneration: 1,
+					Revision:      i.revision,
+					DateCreated:   sd.Users[userBundleAdmin].Entries[0].DateCreated.Format(time.RFC3339),
+					DateUpdated:   sd.Users[userBundleAdmin].Entries[0].DateCreated.Format(time.RFC3339),
 				},
 				Bundle: entryapp.Bundle{
-					ID:       sd.Users[userBundleAdmin].Bundles[0].ID.String(),
-					UserID:   sd.Users[userBundleAdmin].Bundles[0].UserID.String(),
-					Type:     sd.Users[userBundleAdmin].Bundles[0].Type.String(),
-					Metadata: fmt.Sprintf("%s%d", "Metadata", i.user),
+					ID:            sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+					UserID:        sd.Users[userBundleAdmin].Bundles[0].UserID.String(),
+					Type:          sd.Users[userBundleAdmin].Bundles[0].Type.String(),
+					Metadata:      fmt.Sprintf("%s%d", "Metadata", i.user),
+					KeyGeneration: 1,
 
 					DateCreated: sd.Users[userBundleAdmin].Bundles[0].DateCreated.Format(time.RFC3339),
 					DateUpdated: sd.Users[userBundleAdmin].Bundles[0].DateUpdated.Format(time.RFC3339),
@@ -63,6 +77,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 				}
 
 				expResp := exp.(*entryapp.EntryTx)
+				gotResp.Bundle.Revision = expResp.Bundle.Revision
 				gotResp.Bundle.DateUpdated = expResp.Bundle.DateUpdated
 				gotResp.Entry.DateUpdated = expResp.Entry.DateUpdated
 
@@ -152,8 +167,11 @@ func update403(sd apitest.SeedData) []apitest.Table {
 			Method:     http.MethodPut,
 			StatusCode: http.StatusForbidden,
 			Input: &entryapp.UpdateEntry{
-				Data:     "Guitar",
-				Metadata: "NEW METADATA",
+				Type:          "SECURE_NOTE",
+				SchemaVersion: 1,
+				Data:          entryData("Guitar", 1),
+				Metadata:      "NEW METADATA",
+				KeyGeneration: 1,
 			},
 			GotResp: &errs.Error{},
 			ExpResp: errs.Newf(errs.PermissionDenied, ""),
@@ -168,3 +186,30 @@ func update403(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func update409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "stale-revision",
+			URL:        fmt.Sprintf("/v1/entries/%s", sd.Users[userBundleAdmin].Entries[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusConflict,
+			Headers:    map[string]string{"If-Match": `"1"`},
+			Input: &entryapp.UpdateEntry{
+				Type:          "LOGIN",
+				SchemaVersion: 1,
+				Data:          entryData("Stale", 1),
+				Metadata:      "STALE METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.Conflict, entrybus.ErrConflict),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
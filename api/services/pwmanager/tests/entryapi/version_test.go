@@ -0,0 +1,147 @@
+package entry_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/domain/entryapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+)
+
+func queryVersions200(sd apitest.SeedData) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userRead, userKeyMapping[userRead]),
+			URL:        fmt.Sprintf("/v1/entries/%s/versions", e.ID),
+			Token:      sd.Users[userRead].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &query.Result[entryapp.Version]{},
+			ExpResp:    e.Data.String(),
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[entryapp.Version])
+				if !exists {
+					return "error occurred"
+				}
+
+				// Both updates of the entry are versioned, newest first.
+				if gotResp.Total != 2 {
+					return fmt.Sprintf("expected 2 versions, got %d", gotResp.Total)
+				}
+
+				return cmp.Diff(string(gotResp.Items[1].Data), exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryVersions403(sd apitest.SeedData) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userNoKey, userKeyMapping[userNoKey]),
+			URL:        fmt.Sprintf("/v1/entries/%s/versions", e.ID),
+			Token:      sd.Users[userNoKey].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "query: userID[%s] bundleID[%s]: db: key not found", sd.Users[userNoKey].ID, e.BundleID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restore200(sd apitest.SeedData, v entrybus.Version) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:       fmt.Sprintf("tu%d-%s", userReadWrite, userKeyMapping[userReadWrite]),
+			URL:        fmt.Sprintf("/v1/entries/%s/versions/%s/restore", e.ID, v.ID),
+			Token:      sd.Users[userReadWrite].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &entryapp.EntryTx{},
+			ExpResp: v.Data.String(),
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*entryapp.EntryTx)
+				if !exists {
+					return "error occurred"
+				}
+
+				return cmp.Diff(string(gotResp.Entry.Data), exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restore404(sd apitest.SeedData) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "unknown-version",
+			URL:        fmt.Sprintf("/v1/entries/%s/versions/%s/restore", e.ID, uuid.New()),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusNotFound,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED METADATA",
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.NotFound, entrybus.ErrVersionNotFound),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func restore409(sd apitest.SeedData, v entrybus.Version) []apitest.Table {
+	e := sd.Users[userBundleAdmin].Entries[0]
+
+	table := []apitest.Table{
+		{
+			Name:       "stale-key-generation",
+			URL:        fmt.Sprintf("/v1/entries/%s/versions/%s/restore", e.ID, v.ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &entryapp.RestoreEntry{
+				Metadata:      "RESTORED METADATA",
+				KeyGeneration: 2,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -42,6 +42,7 @@ func create200(sd apitest.SeedData) []apitest.Table {
 				UserID:   sd.Users[i.user].ID.String(),
 				BundleID: sd.Users[userBundleAdmin].Bundles[2].ID.String(),
 				Roles:    []string{"ADMIN", "WRITE", "READ"},
+				Revision: 1,
 			},
 			CmpFunc: func(got any, exp any) string {
 				gotResp, exists := got.(*keyapp.Key)
@@ -15,6 +15,7 @@ func toAppKey(k keybus.Key) keyapp.Key {
 		BundleID:    k.BundleID.String(),
 		Data:        k.Data.String(),
 		Roles:       bundlerole.ParseToString(k.Roles),
+		Revision:    k.Revision,
 		DateCreated: k.DateCreated.Format(time.RFC3339),
 		DateUpdated: k.DateUpdated.Format(time.RFC3339),
 	}
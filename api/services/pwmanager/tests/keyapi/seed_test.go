@@ -49,7 +49,7 @@ func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, erro
 	// -------------------------------------------------------------------------
 	// tu1
 
-	bdls, err := bundlebus.TestGenerateSeedBundles(ctx, 3, busDomain.Bundle, usrs[0].ID)
+	bdls, err := bundlebus.TestGenerateSeedShareableBundles(ctx, 3, busDomain.Bundle, usrs[0].ID)
 	if err != nil {
 		return apitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/gradientsearch/pwmanager/app/domain/keyapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 )
@@ -21,6 +22,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 			Token:      sd.Users[userBundleAdmin].Token,
 			Method:     http.MethodPut,
 			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"If-Match": `"1"`},
 			Input: &keyapp.UpdateKey{
 				Data: dbtest.StringPointer("Guitar"),
 			},
@@ -31,6 +33,7 @@ func update200(sd apitest.SeedData) []apitest.Table {
 				BundleID:    sd.Users[userBundleAdmin].Bundles[0].ID.String(),
 				Data:        "Guitar",
 				Roles:       []string{"ADMIN", "READ", "WRITE"},
+				Revision:    2,
 				DateCreated: sd.Users[userBundleAdmin].Keys[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[userBundleAdmin].Keys[0].DateCreated.Format(time.RFC3339),
 			},
@@ -192,3 +195,26 @@ func update403(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func update409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "stale-revision",
+			URL:        fmt.Sprintf("/v1/keys/%s", sd.Users[userBundleAdmin].Keys[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusConflict,
+			Headers:    map[string]string{"If-Match": `"1"`},
+			Input: &keyapp.UpdateKey{
+				Data: dbtest.StringPointer("Stale"),
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.New(errs.Conflict, keybus.ErrConflict),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
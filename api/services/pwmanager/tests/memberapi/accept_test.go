@@ -0,0 +1,78 @@
+package member_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/memberapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+)
+
+func accept200(sd apitest.SeedData, inv memberbus.Invite) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/v1/invites/%s/accept", inv.ID),
+			Token:      sd.Users[userInvitee].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &memberapp.Invite{},
+			ExpResp:    "ACCEPTED",
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*memberapp.Invite)
+				if !exists {
+					return "error occurred"
+				}
+
+				if gotResp.ID != inv.ID.String() {
+					return "unexpected invite"
+				}
+
+				return cmp.Diff(gotResp.Status, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accept400(sd apitest.SeedData, inv memberbus.Invite) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-pending",
+			URL:        fmt.Sprintf("/v1/invites/%s/accept", inv.ID),
+			Token:      sd.Users[userInvitee].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.FailedPrecondition, "invite is not pending"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func accept403(sd apitest.SeedData, inv memberbus.Invite) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-invitee",
+			URL:        fmt.Sprintf("/v1/invites/%s/accept", inv.ID),
+			Token:      sd.Users[userNoKey].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "only the invitee can respond to inviteID[%s]", inv.ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,207 @@
+package member_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/memberapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+)
+
+func invite200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &memberapp.NewInvite{
+				InviteeID:     sd.Users[userInvitee].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ", "WRITE"},
+				KeyGeneration: 1,
+			},
+			GotResp: &memberapp.Invite{},
+			ExpResp: &memberapp.Invite{
+				BundleID:      sd.Users[userBundleAdmin].Bundles[0].ID.String(),
+				InviterID:     sd.Users[userBundleAdmin].ID.String(),
+				InviteeID:     sd.Users[userInvitee].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ", "WRITE"},
+				KeyGeneration: 1,
+				Status:        "PENDING",
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*memberapp.Invite)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*memberapp.Invite)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func invite400(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "missing-input",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &memberapp.NewInvite{},
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"inviteeID\",\"error\":\"inviteeID is a required field\"},{\"field\":\"data\",\"error\":\"data is a required field\"},{\"field\":\"roles\",\"error\":\"roles is a required field\"},{\"field\":\"keyGeneration\",\"error\":\"keyGeneration is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "personal-bundle",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[1].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &memberapp.NewInvite{
+				InviteeID:     sd.Users[userInvitee].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ"},
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.FailedPrecondition, "personal bundles can not be shared"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func invite403(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-bundle-admin",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[0].ID),
+			Token:      sd.Users[userRead].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			Input: &memberapp.NewInvite{
+				InviteeID:     sd.Users[userNoKey].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ"},
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.PermissionDenied, "must have admin perms for bundleID[%s]", sd.Users[userBundleAdmin].Bundles[0].ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func invite409(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "pending",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &memberapp.NewInvite{
+				InviteeID:     sd.Users[userInvitee].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ"},
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.AlreadyExists, "user already has a pending invite for the bundle"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "member",
+			URL:        fmt.Sprintf("/v1/bundles/%s/invites", sd.Users[userBundleAdmin].Bundles[0].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusConflict,
+			Input: &memberapp.NewInvite{
+				InviteeID:     sd.Users[userRead].ID.String(),
+				Data:          "WrappedKey",
+				Roles:         []string{"READ"},
+				KeyGeneration: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.AlreadyExists, "user is already a member of the bundle"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryMyInvites200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "invitee",
+			URL:        "/v1/invites?status=PENDING",
+			Token:      sd.Users[userInvitee].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &query.Result[memberapp.Invite]{},
+			ExpResp:    1,
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[memberapp.Invite])
+				if !exists {
+					return "error occurred"
+				}
+
+				if gotResp.Items[0].InviteeID != sd.Users[userInvitee].ID.String() {
+					return "unexpected invitee"
+				}
+
+				return cmp.Diff(gotResp.Total, exp)
+			},
+		},
+		{
+			Name:       "no-invites",
+			URL:        "/v1/invites",
+			Token:      sd.Users[userNoKey].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &query.Result[memberapp.Invite]{},
+			ExpResp:    0,
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*query.Result[memberapp.Invite])
+				if !exists {
+					return "error occurred"
+				}
+
+				return cmp.Diff(gotResp.Total, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,62 @@
+package member_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+)
+
+func Test_Member(t *testing.T) {
+	t.Parallel()
+
+	test := apitest.New(t, "Test_Member")
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, invite200(sd), "invite-200")
+	test.Run(t, invite400(sd), "invite-400")
+	test.Run(t, invite403(sd), "invite-403")
+	test.Run(t, invite409(sd), "invite-409")
+
+	test.Run(t, queryMyInvites200(sd), "querymyinvites-200")
+
+	inv := pendingInvite(t, test, sd.Users[userInvitee])
+
+	test.Run(t, accept403(sd, inv), "accept-403")
+	test.Run(t, accept200(sd, inv), "accept-200")
+	test.Run(t, accept400(sd, inv), "accept-400")
+
+	test.Run(t, revoke400(sd), "revoke-400")
+	test.Run(t, revoke403(sd), "revoke-403")
+	test.Run(t, revoke200(sd), "revoke-200")
+}
+
+// pendingInvite returns the single pending invite for the user.
+func pendingInvite(t *testing.T, test *apitest.Test, usr apitest.User) memberbus.Invite {
+	filter := memberbus.QueryFilter{
+		InviteeID: &usr.ID,
+		Status:    &invitestatus.Pending,
+	}
+
+	invs, err := test.DB.BusDomain.Member.Query(context.Background(), filter, memberbus.DefaultOrderBy, page.MustParse("1", "10"))
+	if err != nil {
+		t.Fatalf("Should be able to query invites: %s", err)
+	}
+
+	if len(invs) != 1 {
+		t.Fatalf("Should have 1 pending invite: got %d", len(invs))
+	}
+
+	return invs[0]
+}
@@ -0,0 +1,62 @@
+package member_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+)
+
+func revoke200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "invitee",
+			URL:        fmt.Sprintf("/v1/bundles/%s/members/%s", sd.Users[userBundleAdmin].Bundles[0].ID, sd.Users[userInvitee].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusNoContent,
+		},
+	}
+
+	return table
+}
+
+func revoke400(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "owner",
+			URL:        fmt.Sprintf("/v1/bundles/%s/members/%s", sd.Users[userBundleAdmin].Bundles[0].ID, sd.Users[userBundleAdmin].ID),
+			Token:      sd.Users[userBundleAdmin].Token,
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusBadRequest,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.FailedPrecondition, "bundle owner can not be revoked"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func revoke403(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-bundle-admin",
+			URL:        fmt.Sprintf("/v1/bundles/%s/members/%s", sd.Users[userBundleAdmin].Bundles[0].ID, sd.Users[userInvitee].ID),
+			Token:      sd.Users[userRead].Token,
+			Method:     http.MethodDelete,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "must have admin perms for bundleID[%s]", sd.Users[userBundleAdmin].Bundles[0].ID),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
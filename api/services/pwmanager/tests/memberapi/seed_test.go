@@ -0,0 +1,106 @@
+package member_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/auth"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+const (
+	NUMBER_OF_USERS = 4
+)
+
+type userKey int
+
+const (
+	userBundleAdmin userKey = iota
+	userInvitee
+	userRead
+	userNoKey
+)
+
+func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, error) {
+	ctx := context.Background()
+	busDomain := db.BusDomain
+
+	usrs, err := userbus.TestSeedUsers(ctx, NUMBER_OF_USERS, role.User, busDomain.User)
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The bundle admin owns a shareable bundle followed by a personal bundle.
+
+	var bdls []bundlebus.Bundle
+	var keys []keybus.Key
+	for _, typ := range []bundletype.BundleType{bundletype.Shareable, bundletype.Personal} {
+		bdl, err := busDomain.Bundle.Create(ctx, bundlebus.NewBundle{UserID: usrs[userBundleAdmin].ID, Type: typ})
+		if err != nil {
+			return apitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
+		}
+
+		k, err := busDomain.Key.Create(ctx, keybus.NewKey{
+			UserID:   usrs[userBundleAdmin].ID,
+			BundleID: bdl.ID,
+			Data:     key.MustParse("OwnerKey"),
+			Roles:    []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write},
+		})
+		if err != nil {
+			return apitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+		}
+
+		bdls = append(bdls, bdl)
+		keys = append(keys, k)
+	}
+
+	// -------------------------------------------------------------------------
+	// The read user is a member of the shareable bundle without admin perms.
+
+	readKey, err := busDomain.Key.Create(ctx, keybus.NewKey{
+		UserID:   usrs[userRead].ID,
+		BundleID: bdls[0].ID,
+		Data:     key.MustParse("ReadKey"),
+		Roles:    []bundlerole.Role{bundlerole.Read},
+	})
+	if err != nil {
+		return apitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := apitest.SeedData{
+		Users: []apitest.User{
+			{
+				User:    usrs[userBundleAdmin],
+				Keys:    keys,
+				Bundles: bdls,
+				Token:   apitest.Token(db.BusDomain.User, ath, usrs[userBundleAdmin].Email.Address),
+			},
+			{
+				User:  usrs[userInvitee],
+				Token: apitest.Token(db.BusDomain.User, ath, usrs[userInvitee].Email.Address),
+			},
+			{
+				User:  usrs[userRead],
+				Keys:  []keybus.Key{readKey},
+				Token: apitest.Token(db.BusDomain.User, ath, usrs[userRead].Email.Address),
+			},
+			{
+				User:  usrs[userNoKey],
+				Token: apitest.Token(db.BusDomain.User, ath, usrs[userNoKey].Email.Address),
+			},
+		},
+	}
+
+	return sd, nil
+}
@@ -0,0 +1,149 @@
+package sync_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/syncapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+)
+
+// counts holds the number of each kind of change in a response.
+type counts struct {
+	Bundles    int
+	Keys       int
+	Entries    int
+	Tombstones int
+}
+
+func countChanges(c syncapp.Changes) counts {
+	return counts{
+		Bundles:    len(c.Bundles),
+		Keys:       len(c.Keys),
+		Entries:    len(c.Entries),
+		Tombstones: len(c.Tombstones),
+	}
+}
+
+func query200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "owner",
+			URL:        "/v1/sync",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &syncapp.Changes{},
+			ExpResp:    &syncapp.Changes{},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := *(got.(*syncapp.Changes))
+				if gotResp.Cursor == "" || gotResp.Cursor == "0.0" {
+					return "should have returned a cursor"
+				}
+
+				if gotResp.HasMore {
+					return "should have returned every change"
+				}
+
+				return cmp.Diff(countChanges(gotResp), counts{Bundles: 2, Keys: 2, Entries: 4})
+			},
+		},
+		{
+			Name:       "member",
+			URL:        "/v1/sync",
+			Token:      sd.Users[1].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &syncapp.Changes{},
+			ExpResp:    &syncapp.Changes{},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := *(got.(*syncapp.Changes))
+				if len(gotResp.Bundles) == 1 && gotResp.Bundles[0].ID != sd.Users[0].Bundles[0].ID.String() {
+					return "should only have returned the shared bundle"
+				}
+
+				return cmp.Diff(countChanges(gotResp), counts{Bundles: 1, Keys: 1, Entries: 2})
+			},
+		},
+		{
+			Name:       "limit",
+			URL:        "/v1/sync?limit=1",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &syncapp.Changes{},
+			ExpResp:    &syncapp.Changes{},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := *(got.(*syncapp.Changes))
+				if !gotResp.HasMore {
+					return "should have reported more changes"
+				}
+
+				if gotResp.Cursor == "" || gotResp.Cursor == "0.0" {
+					return "should have returned a cursor"
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func query400(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "bad-cursor",
+			URL:        "/v1/sync?since=abc",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "[{\"field\":\"since\",\"error\":\"invalid cursor\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "legacy-cursor",
+			URL:        "/v1/sync?since=42",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "[{\"field\":\"since\",\"error\":\"invalid cursor\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "bad-limit",
+			URL:        "/v1/sync?limit=0",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "[{\"field\":\"limit\",\"error\":\"must be between 1 and 5000\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "future-cursor",
+			URL:        fmt.Sprintf("/v1/sync?since=%d.0", int64(1)<<62),
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.FailedPrecondition, "cursor expired, a full sync is required"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,25 @@
+package sync_test
+
+import (
+	"testing"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+)
+
+func Test_Sync(t *testing.T) {
+	t.Parallel()
+
+	test := apitest.New(t, "Test_Sync")
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, query200(sd), "query-200")
+	test.Run(t, query400(sd), "query-400")
+}
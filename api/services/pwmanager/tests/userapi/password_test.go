@@ -0,0 +1,116 @@
+package user_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/app/domain/userapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/google/go-cmp/cmp"
+)
+
+// changeUUKPassword re-wraps the uuk built by newUUK under a new password.
+func changeUUKPassword(t *testing.T, usr apitest.User, u uuk.UUK, oldPassword string, newPassword string) uuk.UUK {
+	t.Helper()
+
+	if err := u.ChangePassword([]byte(oldPassword), []byte(newPassword), []byte(usr.ID.String()), testSecretKey(), []byte(usr.ID.String())); err != nil {
+		t.Fatalf("Changing uuk password error: %s", err)
+	}
+
+	return u
+}
+
+func updatePassword200(sd apitest.SeedData, changed uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/users/password",
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &userapp.UpdateUserPassword{
+				OldPassword:     "gophers",
+				Password:        "rustaceans",
+				PasswordConfirm: "rustaceans",
+				UUK:             changed,
+			},
+			GotResp: &userapp.User{},
+			ExpResp: &userapp.User{
+				ID:          sd.Users[2].ID.String(),
+				Name:        sd.Users[2].Name.String(),
+				Email:       sd.Users[2].Email.Address,
+				Roles:       []string{"USER"},
+				Department:  sd.Users[2].Department.String(),
+				Enabled:     true,
+				DateCreated: sd.Users[2].DateCreated.Format(time.RFC3339),
+				DateUpdated: sd.Users[2].DateUpdated.Format(time.RFC3339),
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*userapp.User)
+				gotResp.DateUpdated = expResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func updatePassword400(sd apitest.SeedData, other uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "different-keypair",
+			URL:        "/v1/users/password",
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &userapp.UpdateUserPassword{
+				OldPassword:     "rustaceans",
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             other,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "uuk does not protect the existing key pair"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func updatePassword401(sd apitest.SeedData, u uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "wrong-password",
+			URL:        "/v1/users/password",
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusUnauthorized,
+			Input: &userapp.UpdateUserPassword{
+				OldPassword:     "gophers",
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             u,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,200 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gradientsearch/pwmanager/app/domain/userapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/google/go-cmp/cmp"
+)
+
+// addRecoveryKit wraps the symmetric key of the uuk built by newUUK under a
+// new recovery kit.
+func addRecoveryKit(t *testing.T, usr apitest.User, u uuk.UUK, password string) (uuk.UUK, uuk.RecoveryKit) {
+	t.Helper()
+
+	kit := newRecoveryKit(t)
+
+	if err := u.AddRecoveryKit(kit, []byte(password), []byte(usr.ID.String()), testSecretKey(), []byte(usr.ID.String())); err != nil {
+		t.Fatalf("Adding recovery kit error: %s", err)
+	}
+
+	return u, kit
+}
+
+// recoverWithKit re-wraps the symmetric key of the uuk under a new password
+// using the recovery kit.
+func recoverWithKit(t *testing.T, usr apitest.User, u uuk.UUK, kit uuk.RecoveryKit, newPassword string) uuk.UUK {
+	t.Helper()
+
+	if err := u.RecoverWithKit(kit, []byte(newPassword), []byte(usr.ID.String()), testSecretKey(), []byte(usr.ID.String())); err != nil {
+		t.Fatalf("Recovering uuk with kit error: %s", err)
+	}
+
+	return u
+}
+
+func newRecoveryKit(t *testing.T) uuk.RecoveryKit {
+	t.Helper()
+
+	kit, err := uuk.NewRecoveryKit()
+	if err != nil {
+		t.Fatalf("Creating recovery kit error: %s", err)
+	}
+
+	return kit
+}
+
+func verifier(t *testing.T, kit uuk.RecoveryKit) string {
+	t.Helper()
+
+	v, err := kit.Verifier()
+	if err != nil {
+		t.Fatalf("Deriving recovery kit verifier error: %s", err)
+	}
+
+	return v
+}
+
+func enableRecovery200(sd apitest.SeedData, withKit uuk.UUK, kitVerifier string) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/users/recovery",
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusOK,
+			Input: &userapp.EnableRecovery{
+				UUK:      withKit,
+				Verifier: kitVerifier,
+			},
+			GotResp: &userapp.User{},
+			ExpResp: toAppUserPtr(sd.Users[2].User),
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*userapp.User)
+				gotResp.DateUpdated = expResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func recover200(sd apitest.SeedData, recovered uuk.UUK, kitVerifier string) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/recover",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &userapp.RecoverUser{
+				Email:           sd.Users[2].Email.Address,
+				Verifier:        kitVerifier,
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             recovered,
+			},
+			GotResp: &userapp.User{},
+			ExpResp: toAppUserPtr(sd.Users[2].User),
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*userapp.User)
+				gotResp.DateUpdated = expResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func recover401(sd apitest.SeedData, recovered uuk.UUK, otherVerifier string) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "wrong-kit",
+			URL:        "/v1/recover",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: &userapp.RecoverUser{
+				Email:           sd.Users[2].Email.Address,
+				Verifier:        otherVerifier,
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             recovered,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "not-enabled",
+			URL:        "/v1/recover",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: &userapp.RecoverUser{
+				Email:           sd.Users[0].Email.Address,
+				Verifier:        otherVerifier,
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             recovered,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.Unauthenticated, "authentication failed"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryRecoveryEvents200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/v1/users/recovery/events/%s", sd.Users[2].ID),
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &userapp.RecoveryEvents{},
+			ExpResp:    []string{"kit", "enable"},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.RecoveryEvents)
+				if !exists {
+					return "error occurred"
+				}
+
+				var methods []string
+				for _, re := range *gotResp {
+					if re.UserID != sd.Users[2].ID.String() || re.ActorID != sd.Users[2].ID.String() {
+						return "unexpected user or actor"
+					}
+					methods = append(methods, re.Method)
+				}
+
+				return cmp.Diff(methods, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,205 @@
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/app/domain/userapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testSecretKey returns the fixed secret key the tests build UUKs with.
+func testSecretKey() []byte {
+	secretKey := make([]byte, 32)
+	for i := range secretKey {
+		secretKey[i] = byte('A')
+	}
+
+	return secretKey
+}
+
+// newUUK builds a UUK for the user and round trips it through JSON so the
+// public key has the same representation as a UUK read back from storage.
+func newUUK(t *testing.T, usr apitest.User, password string) uuk.UUK {
+	t.Helper()
+
+	var u uuk.UUK
+
+	if err := u.Build([]byte(password), []byte(usr.ID.String()), testSecretKey(), []byte(usr.ID.String())); err != nil {
+		t.Fatalf("Building uuk error: %s", err)
+	}
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("Marshaling uuk error: %s", err)
+	}
+
+	var decoded uuk.UUK
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshaling uuk error: %s", err)
+	}
+
+	return decoded
+}
+
+// checkRegisteredUUK reads the registered user back from the database and
+// checks the UUK sent on registration was stored.
+func checkRegisteredUUK(t *testing.T, test *apitest.Test, usr apitest.User, exp uuk.UUK) {
+	t.Helper()
+
+	got, err := test.DB.BusDomain.User.QueryByID(context.Background(), usr.ID)
+	if err != nil {
+		t.Fatalf("Querying registered user error: %s", err)
+	}
+
+	if diff := cmp.Diff(got.UUK, exp); diff != "" {
+		t.Fatalf("Should store the registered uuk: %s", diff)
+	}
+}
+
+func register200(sd apitest.SeedData, token string, u uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input: &userapp.RegisterUser{
+				Token:           token,
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             u,
+			},
+			GotResp: &userapp.User{},
+			ExpResp: &userapp.User{
+				ID:          sd.Users[2].ID.String(),
+				Name:        sd.Users[2].Name.String(),
+				Email:       sd.Users[2].Email.Address,
+				Roles:       []string{"USER"},
+				Department:  sd.Users[2].Department.String(),
+				Enabled:     true,
+				DateCreated: sd.Users[2].DateCreated.Format(time.RFC3339),
+				DateUpdated: sd.Users[2].DateUpdated.Format(time.RFC3339),
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*userapp.User)
+				gotResp.DateUpdated = expResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func register400(sd apitest.SeedData, u uuk.UUK) []apitest.Table {
+	noPubKey := u
+	noPubKey.PubKey = nil
+
+	noPriKey := u
+	noPriKey.EncPriKey = uuk.EncPriKey{}
+
+	table := []apitest.Table{
+		{
+			Name:       "missing-input",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input:      &userapp.RegisterUser{},
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.InvalidArgument, "validate: [{\"field\":\"token\",\"error\":\"token is a required field\"},{\"field\":\"password\",\"error\":\"password is a required field\"},{\"field\":\"uuk\",\"error\":\"uuk is a required field\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "missing-pubkey",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &userapp.RegisterUser{
+				Token:           "bad-token",
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             noPubKey,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "uuk is invalid"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "missing-enc-pri-key",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &userapp.RegisterUser{
+				Token:           "bad-token",
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             noPriKey,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "uuk is invalid"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func register401(sd apitest.SeedData, token string, u uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "reused-token",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: &userapp.RegisterUser{
+				Token:           token,
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             u,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.Unauthenticated, "registration token is invalid"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "bad-token",
+			URL:        "/v1/register",
+			Method:     http.MethodPost,
+			StatusCode: http.StatusUnauthorized,
+			Input: &userapp.RegisterUser{
+				Token:           "bad-token",
+				Password:        "gophers",
+				PasswordConfirm: "gophers",
+				UUK:             u,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.Unauthenticated, "registration token is invalid"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -1,6 +1,7 @@
 package user_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
@@ -28,6 +29,37 @@ func Test_User(t *testing.T) {
 	test.Run(t, create401(sd), "create-401")
 	test.Run(t, create400(sd), "create-400")
 
+	rt, err := test.DB.BusDomain.User.CreateRegistrationToken(context.Background(), sd.Users[2].User)
+	if err != nil {
+		t.Fatalf("Minting registration token error: %s", err)
+	}
+
+	u := newUUK(t, sd.Users[2], "gophers")
+
+	test.Run(t, register200(sd, rt.Token, u), "register-200")
+	checkRegisteredUUK(t, test, sd.Users[2], u)
+	test.Run(t, register400(sd, u), "register-400")
+	test.Run(t, register401(sd, rt.Token, u), "register-401")
+
+	test.Run(t, queryUUK200(sd, u), "queryuuk-200")
+	test.Run(t, queryUUK404(sd), "queryuuk-404")
+	test.Run(t, queryPublicKey200(sd, u), "querypublickey-200")
+	test.Run(t, queryPublicKey404(sd), "querypublickey-404")
+
+	changed := changeUUKPassword(t, sd.Users[2], u, "gophers", "rustaceans")
+
+	test.Run(t, updatePassword200(sd, changed), "updatepassword-200")
+	test.Run(t, updatePassword400(sd, newUUK(t, sd.Users[2], "gophers")), "updatepassword-400")
+	test.Run(t, updatePassword401(sd, u), "updatepassword-401")
+
+	withKit, kit := addRecoveryKit(t, sd.Users[2], changed, "rustaceans")
+	recovered := recoverWithKit(t, sd.Users[2], withKit, kit, "gophers")
+
+	test.Run(t, enableRecovery200(sd, withKit, verifier(t, kit)), "enablerecovery-200")
+	test.Run(t, recover401(sd, recovered, verifier(t, newRecoveryKit(t))), "recover-401")
+	test.Run(t, recover200(sd, recovered, verifier(t, kit)), "recover-200")
+	test.Run(t, queryRecoveryEvents200(sd), "queryrecoveryevents-200")
+
 	test.Run(t, update200(sd), "update-200")
 	test.Run(t, update401(sd), "update-401")
 	test.Run(t, update400(sd), "update-400")
@@ -0,0 +1,110 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/domain/userapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/google/go-cmp/cmp"
+)
+
+func queryUUK200(sd apitest.SeedData, u uuk.UUK) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/users/uuk",
+			Token:      sd.Users[2].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &userapp.UserUUK{},
+			ExpResp: &userapp.UserUUK{
+				UserID:       sd.Users[2].ID.String(),
+				UUK:          u,
+				NeedsUpgrade: false,
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryUUK404(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-registered",
+			URL:        "/v1/users/uuk",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.NotFound, "user has not completed registration"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryPublicKey200(sd apitest.SeedData, u uuk.UUK) []apitest.Table {
+	exp := &userapp.PublicKey{
+		UserID: sd.Users[2].ID.String(),
+		Email:  sd.Users[2].Email.Address,
+		PubKey: u.PubKey,
+	}
+
+	table := []apitest.Table{
+		{
+			Name:       "byid",
+			URL:        fmt.Sprintf("/v1/users/pubkey/%s", sd.Users[2].ID),
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &userapp.PublicKey{},
+			ExpResp:    exp,
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "byemail",
+			URL:        fmt.Sprintf("/v1/users/pubkey/email/%s", sd.Users[2].Email.Address),
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &userapp.PublicKey{},
+			ExpResp:    exp,
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func queryPublicKey404(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "not-registered",
+			URL:        fmt.Sprintf("/v1/users/pubkey/%s", sd.Users[1].ID),
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.NotFound, "user has not completed registration"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
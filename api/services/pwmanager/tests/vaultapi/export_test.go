@@ -0,0 +1,37 @@
+package vault_test
+
+import (
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+)
+
+// document holds the unsigned fields of an archive.
+type document struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+func export200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/vault/export",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &document{},
+			ExpResp: &document{
+				Format:  vaultbus.Format,
+				Version: vaultbus.Version,
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,118 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/app/domain/vaultapp"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+)
+
+// counts holds the number of bundles and entries an import added and the
+// number of bundles it skipped.
+type counts struct {
+	Bundles int
+	Entries int
+	Skipped int
+}
+
+func countResult(res vaultapp.ImportResult) counts {
+	return counts{
+		Bundles: len(res.Bundles),
+		Entries: len(res.Entries),
+		Skipped: len(res.Skipped),
+	}
+}
+
+func import200(sd apitest.SeedData, archive []byte, copied []byte) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "copy",
+			URL:        "/v1/vault/import",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodPost,
+			Input:      json.RawMessage(copied),
+			GotResp:    &vaultapp.ImportResult{},
+			ExpResp:    &counts{Bundles: 3, Entries: 4},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(countResult(*got.(*vaultapp.ImportResult)), *exp.(*counts))
+			},
+		},
+		{
+			Name:       "duplicate",
+			URL:        "/v1/vault/import",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodPost,
+			Input:      json.RawMessage(archive),
+			GotResp:    &vaultapp.ImportResult{},
+			ExpResp:    &counts{Skipped: 3},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(countResult(*got.(*vaultapp.ImportResult)), *exp.(*counts))
+			},
+		},
+		{
+			Name:       "reimport",
+			URL:        "/v1/vault/import",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodPost,
+			Input:      json.RawMessage(copied),
+			GotResp:    &vaultapp.ImportResult{},
+			ExpResp:    &counts{Skipped: 3},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(countResult(*got.(*vaultapp.ImportResult)), *exp.(*counts))
+			},
+		},
+	}
+
+	return table
+}
+
+func import400(sd apitest.SeedData, archive []byte) []apitest.Table {
+	var doc map[string]any
+	json.Unmarshal(archive, &doc)
+	doc["signature"] = "AAAA"
+
+	table := []apitest.Table{
+		{
+			Name:       "signature",
+			URL:        "/v1/vault/import",
+			Token:      sd.Users[1].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodPost,
+			Input:      doc,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.New(errs.InvalidArgument, vaultbus.ErrInvalidSignature),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func import403(sd apitest.SeedData, archive []byte) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "other-user",
+			URL:        "/v1/vault/import",
+			Token:      sd.Users[1].Token,
+			StatusCode: http.StatusForbidden,
+			Method:     http.MethodPost,
+			Input:      json.RawMessage(archive),
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.New(errs.PermissionDenied, vaultbus.ErrUserMismatch),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,96 @@
+package vault_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+)
+
+func Test_Vault(t *testing.T) {
+	t.Parallel()
+
+	test := apitest.New(t, "Test_Vault")
+
+	// -------------------------------------------------------------------------
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	archive, err := test.DB.BusDomain.Vault.Export(context.Background(), sd.Users[0].ID)
+	if err != nil {
+		t.Fatalf("Exporting error: %s", err)
+	}
+
+	data, err := test.DB.BusDomain.Vault.Seal(archive)
+	if err != nil {
+		t.Fatalf("Sealing error: %s", err)
+	}
+
+	// A copy of the vault under other ids is not a duplicate of what the user
+	// already has.
+	copied := archive
+	copied.Bundles = slices.Clone(archive.Bundles)
+	for i := range copied.Bundles {
+		copied.Bundles[i].ID = uuid.New()
+	}
+
+	copiedData, err := test.DB.BusDomain.Vault.Seal(copied)
+	if err != nil {
+		t.Fatalf("Sealing error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, export200(sd), "export-200")
+	test.Run(t, import200(sd, data, copiedData), "import-200")
+	checkImportedRoles(t, test, sd)
+	test.Run(t, import400(sd, data), "import-400")
+	test.Run(t, import403(sd, data), "import-403")
+}
+
+// checkImportedRoles reads the keys of the user back from the database and
+// checks the user holds the owner roles to every bundle the user owns, the
+// bundles recreated from the member-only bundle included.
+func checkImportedRoles(t *testing.T, test *apitest.Test, sd apitest.SeedData) {
+	t.Helper()
+
+	ctx := context.Background()
+	usr := sd.Users[0]
+
+	keys, err := test.DB.BusDomain.Key.QueryByUserID(ctx, usr.ID)
+	if err != nil {
+		t.Fatalf("Querying keys error: %s", err)
+	}
+
+	exp := []bundlerole.Role{bundlerole.Read, bundlerole.Write, bundlerole.Admin}
+
+	var owned int
+	for _, k := range keys {
+		bdl, err := test.DB.BusDomain.Bundle.QueryByID(ctx, k.BundleID)
+		if err != nil {
+			t.Fatalf("Querying bundle error: %s", err)
+		}
+
+		if bdl.UserID != usr.ID {
+			continue
+		}
+		owned++
+
+		for _, r := range exp {
+			if !slices.ContainsFunc(k.Roles, r.Equal) {
+				t.Fatalf("Should hold the %s role to owned bundle %s: got %v", r, bdl.ID, k.Roles)
+			}
+		}
+	}
+
+	// Two seeded bundles, a copy of each and a copy of the member-only bundle.
+	if owned != 5 {
+		t.Fatalf("Should own 5 bundles: got %d", owned)
+	}
+}
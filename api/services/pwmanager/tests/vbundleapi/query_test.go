@@ -1,81 +1,110 @@
 package vbundle_test
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gradientsearch/pwmanager/app/domain/vbundleapp"
 	"github.com/gradientsearch/pwmanager/app/sdk/apitest"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 )
 
+func toAppBundleUser(usr apitest.User, k keybus.Key) vbundleapp.BundleUser {
+	return vbundleapp.BundleUser{
+		UserID: usr.ID,
+		Name:   usr.Name.String(),
+		Email:  usr.Email.Address,
+		Roles:  bundlerole.ParseToString(k.Roles),
+	}
+}
+
+// toAppUserBundleKey builds the expected view of the bundle for the holder
+// of the key. The dates are taken from the response since they are read back
+// from the database.
+func toAppUserBundleKey(owner apitest.User, bdl bundlebus.Bundle, k keybus.Key, users []vbundleapp.BundleUser, got vbundleapp.UserBundleKey) vbundleapp.UserBundleKey {
+	return vbundleapp.UserBundleKey{
+		KeyID:       k.ID,
+		UserID:      k.UserID,
+		BundleID:    bdl.ID,
+		OwnerID:     owner.ID,
+		OwnerName:   owner.Name.String(),
+		OwnerEmail:  owner.Email.Address,
+		Type:        bdl.Type.String(),
+		Metadata:    bdl.Metadata,
+		DateCreated: got.DateCreated,
+		DateUpdated: got.DateUpdated,
+		KeyData:     k.Data.String(),
+		KeyRoles:    bundlerole.ParseToString(k.Roles),
+		Users:       users,
+	}
+}
+
 func query200(sd apitest.SeedData) []apitest.Table {
+	tu1 := sd.Users[0]
+	tu2 := sd.Users[1]
+
 	table := []apitest.Table{
 		{
 			Name:       "basic",
 			URL:        "/v1/vbundles",
-			Token:      sd.Users[0].Token,
+			Token:      tu1.Token,
 			StatusCode: http.StatusOK,
 			Method:     http.MethodGet,
-			GotResp:    &vbundleapp.UserBundleKeys{},
-			ExpResp:    &vbundleapp.UserBundleKeys{},
+			GotResp:    &query.Result[vbundleapp.UserBundleKey]{},
+			ExpResp:    &query.Result[vbundleapp.UserBundleKey]{},
 			CmpFunc: func(got any, exp any) string {
-				gotResp := *(got.(*vbundleapp.UserBundleKeys))
-				if len(gotResp) != 2 {
+				gotResp := got.(*query.Result[vbundleapp.UserBundleKey])
+				if len(gotResp.Items) != 2 {
 					return "should have returned 2 bundles"
 				}
 
-				tu1 := sd.Users[0]
-				tu2 := sd.Users[1]
+				expResp := &query.Result[vbundleapp.UserBundleKey]{
+					Items: []vbundleapp.UserBundleKey{
+						toAppUserBundleKey(tu1, tu1.Bundles[0], tu1.Keys[0], []vbundleapp.BundleUser{
+							toAppBundleUser(tu1, tu1.Keys[0]),
+							toAppBundleUser(tu2, tu2.Keys[0]),
+						}, gotResp.Items[0]),
+						toAppUserBundleKey(tu1, tu1.Bundles[1], tu1.Keys[1], []vbundleapp.BundleUser{
+							toAppBundleUser(tu1, tu1.Keys[1]),
+						}, gotResp.Items[1]),
+					},
+					Total:       2,
+					Page:        1,
+					RowsPerPage: 10,
+				}
 
-				b1 := gotResp[0]
-				b2 := gotResp[1]
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:       "shared-with-me",
+			URL:        "/v1/vbundles?shared=true",
+			Token:      tu2.Token,
+			StatusCode: http.StatusOK,
+			Method:     http.MethodGet,
+			GotResp:    &query.Result[vbundleapp.UserBundleKey]{},
+			ExpResp:    &query.Result[vbundleapp.UserBundleKey]{},
+			CmpFunc: func(got any, exp any) string {
+				gotResp := got.(*query.Result[vbundleapp.UserBundleKey])
+				if len(gotResp.Items) != 1 {
+					return "should have returned the shared bundle"
+				}
 
-				expResp := vbundleapp.UserBundleKeys{
-					{
-						UserID:      tu1.User.ID,
-						BundleID:    tu1.Bundles[0].ID,
-						Name:        tu1.User.Name.String(),
-						Type:        b1.Type,
-						Metadata:    tu1.Bundles[0].Metadata,
-						DateCreated: b1.DateCreated,
-						DateUpdated: b1.DateUpdated,
-						KeyData:     tu1.Keys[0].Data.String(),
-						KeyRoles:    bundlerole.ParseToString(tu1.Keys[0].Roles),
-						Users: []vbundleapp.BundleUser{
-							{
-								UserID: tu1.User.ID,
-								Name:   tu1.User.Name.String(),
-								Email:  tu1.User.Email.Address,
-								Roles:  bundlerole.ParseToString(tu1.Keys[0].Roles),
-							},
-							{
-								UserID: tu2.User.ID,
-								Name:   tu2.User.Name.String(),
-								Email:  tu2.User.Email.Address,
-								Roles:  bundlerole.ParseToString(tu2.Keys[0].Roles),
-							},
-						},
-					},
-					{
-						UserID:      tu1.User.ID,
-						BundleID:    tu1.Bundles[1].ID,
-						Name:        tu1.User.Name.String(),
-						Type:        b2.Type,
-						Metadata:    tu1.Bundles[1].Metadata,
-						DateCreated: b2.DateCreated,
-						DateUpdated: b2.DateUpdated,
-						KeyData:     tu1.Keys[1].Data.String(),
-						KeyRoles:    bundlerole.ParseToString(tu1.Keys[1].Roles),
-						Users: []vbundleapp.BundleUser{
-							{
-								UserID: tu1.User.ID,
-								Name:   tu1.User.Name.String(),
-								Email:  tu1.User.Email.Address,
-								Roles:  bundlerole.ParseToString(tu1.Keys[1].Roles),
-							},
-						},
+				expResp := &query.Result[vbundleapp.UserBundleKey]{
+					Items: []vbundleapp.UserBundleKey{
+						toAppUserBundleKey(tu1, tu1.Bundles[0], tu2.Keys[0], []vbundleapp.BundleUser{
+							toAppBundleUser(tu1, tu1.Keys[0]),
+							toAppBundleUser(tu2, tu2.Keys[0]),
+						}, gotResp.Items[0]),
 					},
+					Total:       1,
+					Page:        1,
+					RowsPerPage: 10,
 				}
 
 				return cmp.Diff(gotResp, expResp)
@@ -85,3 +114,34 @@ func query200(sd apitest.SeedData) []apitest.Table {
 
 	return table
 }
+
+func query400(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "shared",
+			URL:        "/v1/vbundles?shared=maybe",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.NewFieldErrors("shared", fmt.Errorf("strconv.ParseBool: parsing \"maybe\": invalid syntax")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "order",
+			URL:        "/v1/vbundles?orderBy=cost,ASC",
+			Token:      sd.Users[0].Token,
+			StatusCode: http.StatusBadRequest,
+			Method:     http.MethodGet,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.NewFieldErrors("order", fmt.Errorf("unknown order: cost")),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
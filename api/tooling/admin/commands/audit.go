@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus/stores/auditdb"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/keystore"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+)
+
+// ErrAuditBroken is returned when the audit chain does not verify.
+var ErrAuditBroken = errors.New("audit chain is broken")
+
+// AuditVerify verifies the hash chain of the audit log and the signatures of
+// its checkpoints, reporting the first broken link.
+func AuditVerify(log *logger.Logger, dbConfig sqldb.Config, keysFolder string) error {
+	ks := keystore.New()
+	if _, err := ks.LoadByFileSystem(os.DirFS(keysFolder)); err != nil {
+		return fmt.Errorf("loading keys by fs: %w", err)
+	}
+
+	db, err := sqldb.Open(dbConfig)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	auditBus := auditbus.NewBusiness(log, nil, auditdb.NewStore(log, db))
+
+	v, err := auditBus.Verify(ctx, ks)
+	if err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	fmt.Printf("records: %d unchained: %d checkpoints: %d\n", v.Records, v.Unchained, v.Checkpoints)
+
+	if v.Broken != nil {
+		fmt.Printf("first broken link: sequence %d audit %s: %s\n", v.Broken.Sequence, v.Broken.AuditID, v.Broken.Reason)
+		return ErrAuditBroken
+	}
+
+	fmt.Println("audit chain verified")
+	return nil
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus/stores/bundledb"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus/stores/entrydb"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus/stores/keydb"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/userdb"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus/stores/vaultdb"
+	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// VaultExport writes a signed archive of the bundles of the specified user to
+// the file, or to stdout when no file is specified.
+func VaultExport(log *logger.Logger, dbConfig sqldb.Config, signingKey string, userID uuid.UUID, file string) error {
+	db, err := sqldb.Open(dbConfig)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	vaultBus, err := newVaultBus(log, db, signingKey)
+	if err != nil {
+		return err
+	}
+
+	archive, err := vaultBus.Export(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	data, err := vaultBus.Seal(archive)
+	if err != nil {
+		return fmt.Errorf("seal: %w", err)
+	}
+
+	if file == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	fmt.Printf("exported %d bundles to %s\n", len(archive.Bundles), file)
+	return nil
+}
+
+// VaultImport recreates the bundles of a signed archive for the specified user.
+// The archive is read from the file, or from stdin when no file is specified.
+func VaultImport(log *logger.Logger, dbConfig sqldb.Config, signingKey string, userID uuid.UUID, file string) error {
+	var data []byte
+	var err error
+
+	switch file {
+	case "":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+
+	db, err := sqldb.Open(dbConfig)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	vaultBus, err := newVaultBus(log, db, signingKey)
+	if err != nil {
+		return err
+	}
+
+	archive, err := vaultBus.Open(data)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	tx, err := sqldb.NewBeginner(db).Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	vaultBus, err = vaultBus.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	res, err := vaultBus.Import(ctx, userID, archive)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	fmt.Printf("imported %d bundles and %d entries, skipped %d duplicate bundles\n", len(res.Bundles), len(res.Entries), len(res.Skipped))
+	return nil
+}
+
+func newVaultBus(log *logger.Logger, db *sqlx.DB, signingKey string) (*vaultbus.Business, error) {
+	if signingKey == "" {
+		return nil, errors.New("vault signing key is not configured, set PWMANAGERS_VAULT_SIGNING_KEY")
+	}
+
+	delegate := delegate.New(log)
+	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
+	keyBus := keybus.NewBusiness(log, userBus, delegate, keydb.NewStore(log, db))
+	entryBus := entrybus.NewBusiness(log, userBus, delegate, entrydb.NewStore(log, db))
+	bundleBus := bundlebus.NewBusiness(log, userBus, keyBus, entryBus, delegate, bundledb.NewStore(log, db))
+
+	return vaultbus.NewBusiness(log, bundleBus, keyBus, entryBus, vaultdb.NewStore(log, db), []byte(signingKey)), nil
+}
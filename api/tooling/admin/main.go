@@ -33,6 +33,9 @@ type config struct {
 		KeysFolder string `conf:"default:zarf/keys/"`
 		DefaultKID string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
 	}
+	Vault struct {
+		SigningKey string `conf:"mask"`
+	}
 }
 
 func main() {
@@ -139,6 +142,29 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 			return fmt.Errorf("generating token: %w", err)
 		}
 
+	case "vault-export":
+		userID, err := uuid.Parse(args.Num(1))
+		if err != nil {
+			return fmt.Errorf("exporting vault: %w", err)
+		}
+		if err := commands.VaultExport(log, dbConfig, cfg.Vault.SigningKey, userID, args.Num(2)); err != nil {
+			return fmt.Errorf("exporting vault: %w", err)
+		}
+
+	case "vault-import":
+		userID, err := uuid.Parse(args.Num(1))
+		if err != nil {
+			return fmt.Errorf("importing vault: %w", err)
+		}
+		if err := commands.VaultImport(log, dbConfig, cfg.Vault.SigningKey, userID, args.Num(2)); err != nil {
+			return fmt.Errorf("importing vault: %w", err)
+		}
+
+	case "audit-verify":
+		if err := commands.AuditVerify(log, dbConfig, cfg.Auth.KeysFolder); err != nil {
+			return fmt.Errorf("verifying audit log: %w", err)
+		}
+
 	default:
 		fmt.Println("migrate:    create the schema in the database")
 		fmt.Println("seed:       add data to the database")
@@ -146,6 +172,9 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		fmt.Println("users:      get a list of users from the database")
 		fmt.Println("genkey:     generate a set of private/public key files")
 		fmt.Println("gentoken:   generate a JWT for a user with claims")
+		fmt.Println("vault-export: write a signed archive of a user's vault")
+		fmt.Println("vault-import: recreate a user's vault from a signed archive")
+		fmt.Println("audit-verify: verify the audit log chain and checkpoints")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
 	}
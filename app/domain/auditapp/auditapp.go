@@ -0,0 +1,89 @@
+// Package auditapp maintains the app layer api for the audit domain.
+package auditapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+type app struct {
+	auditBus *auditbus.Business
+}
+
+func newApp(auditBus *auditbus.Business) *app {
+	return &app{
+		auditBus: auditBus,
+	}
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, auditbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	return a.search(ctx, filter, orderBy, page)
+}
+
+func (a *app) queryByBundle(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, auditbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	// Bundle admins can only see the records of the bundle they administer.
+	filter.BundleID = &bdl.ID
+
+	return a.search(ctx, filter, orderBy, page)
+}
+
+func (a *app) search(ctx context.Context, filter auditbus.QueryFilter, orderBy order.By, page page.Page) web.Encoder {
+	audits, err := a.auditBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.auditBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppAudits(audits), total, page)
+}
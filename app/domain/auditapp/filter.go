@@ -0,0 +1,99 @@
+package auditapp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ActorID          string
+	Action           string
+	BundleID         string
+	EntryID          string
+	KeyID            string
+	UserID           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ActorID:          values.Get("actor_id"),
+		Action:           values.Get("action"),
+		BundleID:         values.Get("bundle_id"),
+		EntryID:          values.Get("entry_id"),
+		KeyID:            values.Get("key_id"),
+		UserID:           values.Get("user_id"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (auditbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter auditbus.QueryFilter
+
+	parseID := func(field string, value string) *uuid.UUID {
+		if value == "" {
+			return nil
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			fieldErrors.Add(field, err)
+			return nil
+		}
+
+		return &id
+	}
+
+	filter.ActorID = parseID("actor_id", qp.ActorID)
+	filter.BundleID = parseID("bundle_id", qp.BundleID)
+	filter.EntryID = parseID("entry_id", qp.EntryID)
+	filter.KeyID = parseID("key_id", qp.KeyID)
+	filter.UserID = parseID("user_id", qp.UserID)
+
+	if qp.Action != "" {
+		filter.Action = &qp.Action
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return auditbus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
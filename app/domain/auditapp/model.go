@@ -0,0 +1,72 @@
+package auditapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+)
+
+// Audit represents a record of the audit log. Targets the action does not
+// have are left empty.
+type Audit struct {
+	ID          string `json:"id"`
+	Sequence    int64  `json:"sequence"`
+	PrevHash    string `json:"prevHash"`
+	Hash        string `json:"hash"`
+	ActorID     string `json:"actorID"`
+	Action      string `json:"action"`
+	BundleID    string `json:"bundleID"`
+	EntryID     string `json:"entryID"`
+	KeyID       string `json:"keyID"`
+	UserID      string `json:"userID"`
+	Status      int    `json:"status"`
+	IPAddress   string `json:"ipAddress"`
+	UserAgent   string `json:"userAgent"`
+	TraceID     string `json:"traceID"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Audit) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppAudit(a auditbus.Audit) Audit {
+	return Audit{
+		ID:          a.ID.String(),
+		Sequence:    a.Sequence,
+		PrevHash:    a.PrevHash,
+		Hash:        a.Hash,
+		ActorID:     toAppID(a.ActorID),
+		Action:      a.Action,
+		BundleID:    toAppID(a.BundleID),
+		EntryID:     toAppID(a.EntryID),
+		KeyID:       toAppID(a.KeyID),
+		UserID:      toAppID(a.UserID),
+		Status:      a.Status,
+		IPAddress:   a.IPAddress,
+		UserAgent:   a.UserAgent,
+		TraceID:     a.TraceID,
+		DateCreated: a.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppAudits(audits []auditbus.Audit) []Audit {
+	app := make([]Audit, len(audits))
+	for i, a := range audits {
+		app[i] = toAppAudit(a)
+	}
+
+	return app
+}
+
+func toAppID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
@@ -0,0 +1,12 @@
+package auditapp
+
+import (
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+)
+
+var orderByFields = map[string]string{
+	"audit_id":     auditbus.OrderByAuditID,
+	"actor_id":     auditbus.OrderByActorID,
+	"action":       auditbus.OrderByAction,
+	"date_created": auditbus.OrderByDateCreated,
+}
@@ -0,0 +1,39 @@
+package auditapp
+
+import (
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/auth"
+	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	AuditBus   *auditbus.Business
+	BundleBus  *bundlebus.Business
+	KeyBus     *keybus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+	ruleAuthorizeBundleAdmin := mid.AuthorizeBundleAdmin(cfg.AuthClient, cfg.BundleBus, cfg.KeyBus)
+
+	api := newApp(cfg.AuditBus)
+
+	// Admins can search the whole audit log, bundle admins only the records
+	// of their bundle.
+	app.HandlerFunc(http.MethodGet, version, "/audits", api.query, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodGet, version, "/bundles/{bundle_id}/audits", api.queryByBundle, authen, ruleAuthorizeBundleAdmin)
+}
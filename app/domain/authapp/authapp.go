@@ -5,24 +5,60 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/web"
 )
 
 type app struct {
-	auth *auth.Auth
+	auth       *auth.Auth
+	userBus    *userbus.Business
+	sessionBus *sessionbus.Business
 }
 
-func newApp(ath *auth.Auth) *app {
+func newApp(ath *auth.Auth, userBus *userbus.Business, sessionBus *sessionbus.Business) *app {
 	return &app{
-		auth: ath,
+		auth:       ath,
+		userBus:    userBus,
+		sessionBus: sessionBus,
 	}
 }
 
+// newWithTx constructs a new Handlers value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionBus, err := a.sessionBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		auth:       a.auth,
+		userBus:    userBus,
+		sessionBus: sessionBus,
+	}
+
+	return &app, nil
+}
+
 func (a *app) token(ctx context.Context, r *http.Request) web.Encoder {
 	kid := web.Param(r, "kid")
 	if kid == "" {
@@ -32,12 +68,219 @@ func (a *app) token(ctx context.Context, r *http.Request) web.Encoder {
 	// The BearerBasic middleware function generates the claims.
 	claims := mid.GetClaims(ctx)
 
-	tkn, err := a.auth.GenerateToken(kid, claims)
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	// Users with two-factor authentication post the code as the second step
+	// of the login.
+	var app TokenRequest
+	if r.Method == http.MethodPost {
+		if err := web.Decode(r, &app); err != nil {
+			return errs.New(errs.InvalidArgument, err)
+		}
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if err := a.userBus.AuthenticateMFA(ctx, usr, app.Code); err != nil {
+		if e := toMFAError(err); e != nil {
+			return e
+		}
+		return errs.Newf(errs.Internal, "authenticatemfa: userID[%s]: %s", userID, err)
+	}
+
+	ns := sessionbus.NewSession{
+		UserID:    userID,
+		Device:    r.UserAgent(),
+		IPAddress: mid.RemoteIP(r),
+	}
+
+	sess, refreshToken, err := a.sessionBus.Create(ctx, ns)
+	if err != nil {
+		return errs.Newf(errs.Internal, "create: %s", err)
+	}
+
+	return a.sessionToken(kid, sess, refreshToken, claims.Roles)
+}
+
+func (a *app) queryMFA(ctx context.Context, r *http.Request) web.Encoder {
+	usr, errResp := a.basicUser(ctx)
+	if errResp != nil {
+		return errResp
+	}
+
+	mfa, err := a.userBus.QueryMFA(ctx, usr.ID)
+	if err != nil && !errors.Is(err, userbus.ErrMFANotEnabled) {
+		return errs.Newf(errs.Internal, "querymfa: userID[%s]: %s", usr.ID, err)
+	}
+
+	required, err := a.userBus.MFARequired(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "mfarequired: userID[%s]: %s", usr.ID, err)
+	}
+
+	return MFAStatus{
+		Enabled:  mfa.Enabled,
+		Required: required,
+	}
+}
+
+func (a *app) enrollMFA(ctx context.Context, r *http.Request) web.Encoder {
+	usr, errResp := a.basicUser(ctx)
+	if errResp != nil {
+		return errResp
+	}
+
+	me, err := a.userBus.EnrollMFA(ctx, usr)
+	if err != nil {
+		if errors.Is(err, userbus.ErrMFAEnabled) {
+			return errs.New(errs.FailedPrecondition, userbus.ErrMFAEnabled)
+		}
+		return errs.Newf(errs.Internal, "enrollmfa: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppMFAEnrollment(me)
+}
+
+func (a *app) confirmMFA(ctx context.Context, r *http.Request) web.Encoder {
+	var app MFACode
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, errResp := a.basicUser(ctx)
+	if errResp != nil {
+		return errResp
+	}
+
+	codes, err := a.userBus.ConfirmMFA(ctx, usr, app.Code)
+	if err != nil {
+		if e := toMFAError(err); e != nil {
+			return e
+		}
+		return errs.Newf(errs.Internal, "confirmmfa: userID[%s]: %s", usr.ID, err)
+	}
+
+	return MFARecoveryCodes{Codes: codes}
+}
+
+func (a *app) disableMFA(ctx context.Context, r *http.Request) web.Encoder {
+	var app MFACode
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, errResp := a.basicUser(ctx)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := a.userBus.DisableMFA(ctx, usr, app.Code); err != nil {
+		if e := toMFAError(err); e != nil {
+			return e
+		}
+		return errs.Newf(errs.Internal, "disablemfa: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) refresh(ctx context.Context, r *http.Request) web.Encoder {
+	kid := web.Param(r, "kid")
+	if kid == "" {
+		return errs.NewFieldErrors("kid", errors.New("missing kid"))
+	}
+
+	var app RefreshRequest
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
-	return token{Token: tkn}
+	sess, refreshToken, err := a.sessionBus.Refresh(ctx, app.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, sessionbus.ErrInvalidRefreshToken),
+			errors.Is(err, sessionbus.ErrRefreshTokenReused),
+			errors.Is(err, sessionbus.ErrRevoked),
+			errors.Is(err, sessionbus.ErrExpired):
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "refresh: %s", err)
+	}
+
+	mid.SetAuditActor(ctx, sess.UserID)
+
+	// The roles are read again so role changes apply to the new token, the
+	// roles the identity provider granted at login stay with the session.
+	usr, err := a.userBus.QueryByID(ctx, sess.UserID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", sess.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return errs.Newf(errs.Unauthenticated, "user disabled: userID[%s]", usr.ID)
+	}
+
+	return a.sessionToken(kid, sess, refreshToken, role.ParseToString(role.Merge(usr.Roles, sess.Roles)))
+}
+
+func (a *app) querySessions(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	sessions, err := a.sessionBus.QueryActiveByUserID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryactivebyuserid: userID[%s]: %s", userID, err)
+	}
+
+	return toAppSessions(sessions, mid.GetClaims(ctx).SessionID)
+}
+
+func (a *app) revokeSession(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	sessionID, err := uuid.Parse(web.Param(r, "session_id"))
+	if err != nil {
+		return errs.NewFieldErrors("session_id", err)
+	}
+
+	sess, err := a.sessionBus.QueryByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sessionbus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: sessionID[%s]: %s", sessionID, err)
+	}
+
+	// The sessions of other users are reported as not found so their
+	// existence is not revealed.
+	if sess.UserID != userID {
+		return errs.New(errs.NotFound, sessionbus.ErrNotFound)
+	}
+
+	if _, err := a.sessionBus.Revoke(ctx, sess); err != nil {
+		return errs.Newf(errs.Internal, "revoke: sessionID[%s]: %s", sessionID, err)
+	}
+
+	return nil
 }
 
 func (a *app) authenticate(ctx context.Context, r *http.Request) web.Encoder {
@@ -69,3 +312,65 @@ func (a *app) authorize(ctx context.Context, r *http.Request) web.Encoder {
 
 	return nil
 }
+
+// basicUser returns the user that authenticated with their password. The
+// two-factor settings are managed with the password instead of a token so
+// users whose roles require two-factor authentication can enable it before
+// they are able to get a token.
+func (a *app) basicUser(ctx context.Context) (userbus.User, *errs.Error) {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return userbus.User{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return userbus.User{}, errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	return usr, nil
+}
+
+// toMFAError maps the two-factor authentication errors of the user business
+// to their response, nil is returned for any other error.
+func toMFAError(err error) *errs.Error {
+	switch {
+	case errors.Is(err, userbus.ErrMFACodeRequired):
+		return errs.New(errs.Unauthenticated, userbus.ErrMFACodeRequired)
+
+	case errors.Is(err, userbus.ErrInvalidMFACode):
+		return errs.New(errs.Unauthenticated, userbus.ErrInvalidMFACode)
+
+	case errors.Is(err, userbus.ErrMFALocked):
+		return errs.New(errs.TooManyRequests, userbus.ErrMFALocked)
+
+	case errors.Is(err, userbus.ErrMFARequired):
+		return errs.New(errs.FailedPrecondition, userbus.ErrMFARequired)
+
+	case errors.Is(err, userbus.ErrMFANotEnabled):
+		return errs.New(errs.FailedPrecondition, userbus.ErrMFANotEnabled)
+
+	case errors.Is(err, userbus.ErrMFAEnabled):
+		return errs.New(errs.FailedPrecondition, userbus.ErrMFAEnabled)
+	}
+
+	return nil
+}
+
+// sessionToken signs the access token for the session and returns it with
+// the refresh token.
+func (a *app) sessionToken(kid string, sess sessionbus.Session, refreshToken string, roles []string) web.Encoder {
+	tkn, err := a.auth.GenerateToken(kid, a.auth.SessionClaims(sess, roles))
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	resp := token{
+		Token:        tkn,
+		RefreshToken: refreshToken,
+		SessionID:    sess.ID.String(),
+		ExpiresAt:    sess.AccessExpires.Format(time.RFC3339),
+	}
+
+	return resp
+}
@@ -1,9 +1,20 @@
 package authapp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+)
 
 type token struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	SessionID    string `json:"sessionID"`
+	ExpiresAt    string `json:"expiresAt"`
 }
 
 // Encode implements the encoder interface.
@@ -11,3 +22,149 @@ func (t token) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(t)
 	return data, "application/json", err
 }
+
+// =============================================================================
+
+// TokenRequest contains the second step of a login for users with two-factor
+// authentication, a current TOTP code or one of their recovery codes.
+type TokenRequest struct {
+	Code string `json:"code"`
+}
+
+// Decode implements the decoder interface.
+func (app *TokenRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// =============================================================================
+
+// MFACode contains a TOTP code or a recovery code that proves the user has
+// their authenticator app.
+type MFACode struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *MFACode) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app MFACode) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// MFAStatus reports whether the user enabled two-factor authentication and
+// whether their roles require it.
+type MFAStatus struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app MFAStatus) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// MFAEnrollment contains the secret to add to an authenticator app. URI is
+// the provisioning URI to render as a QR code.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Encode implements the encoder interface.
+func (app MFAEnrollment) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMFAEnrollment(bus userbus.MFAEnrollment) MFAEnrollment {
+	return MFAEnrollment{
+		Secret: bus.Secret,
+		URI:    bus.URI,
+	}
+}
+
+// MFARecoveryCodes contains the recovery codes issued when two-factor
+// authentication is enabled. They are only returned once.
+type MFARecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// Encode implements the encoder interface.
+func (app MFARecoveryCodes) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// RefreshRequest contains the refresh token to exchange for a new access
+// token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *RefreshRequest) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app RefreshRequest) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Session represents an active session of the user. Current marks the
+// session of the token used for the request.
+type Session struct {
+	ID           string `json:"id"`
+	Device       string `json:"device"`
+	IPAddress    string `json:"ipAddress"`
+	Current      bool   `json:"current"`
+	DateCreated  string `json:"dateCreated"`
+	DateLastUsed string `json:"dateLastUsed"`
+	DateExpires  string `json:"dateExpires"`
+}
+
+func toAppSession(bus sessionbus.Session, currentID string) Session {
+	return Session{
+		ID:           bus.ID.String(),
+		Device:       bus.Device,
+		IPAddress:    bus.IPAddress,
+		Current:      bus.ID.String() == currentID,
+		DateCreated:  bus.DateCreated.Format(time.RFC3339),
+		DateLastUsed: bus.DateLastUsed.Format(time.RFC3339),
+		DateExpires:  bus.DateExpires.Format(time.RFC3339),
+	}
+}
+
+// Sessions is a collection wrapper that implements the Encoder interface.
+type Sessions []Session
+
+// Encode implements the encoder interface.
+func (app Sessions) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppSessions(sessions []sessionbus.Session, currentID string) Sessions {
+	app := make(Sessions, len(sessions))
+	for i, sess := range sessions {
+		app[i] = toAppSession(sess, currentID)
+	}
+
+	return app
+}
@@ -5,14 +5,23 @@ import (
 
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
+	"github.com/jmoiron/sqlx"
 )
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
-	UserBus *userbus.Business
-	Auth    *auth.Auth
+	Log        *logger.Logger
+	DB         *sqlx.DB
+	UserBus    *userbus.Business
+	SessionBus *sessionbus.Business
+	AuditBus   *auditbus.Business
+	Auth       *auth.Auth
 }
 
 // Routes adds specific routes for this group.
@@ -21,10 +30,29 @@ func Routes(app *web.App, cfg Config) {
 
 	bearer := mid.Bearer(cfg.Auth)
 	basic := mid.Basic(cfg.Auth, cfg.UserBus)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
 
-	api := newApp(cfg.Auth)
+	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
+
+	api := newApp(cfg.Auth, cfg.UserBus, cfg.SessionBus)
+
+	// Logins are audited ahead of the password check so failed attempts are
+	// recorded as well.
+	app.HandlerFunc(http.MethodGet, version, "/auth/token/{kid}", api.token, audit("auth.token"), basic)
+	app.HandlerFunc(http.MethodPost, version, "/auth/token/{kid}", api.token, audit("auth.token"), basic)
+	app.HandlerFunc(http.MethodPost, version, "/auth/refresh/{kid}", api.refresh, audit("auth.refresh"), transaction)
+	app.HandlerFunc(http.MethodGet, version, "/auth/sessions", api.querySessions, bearer)
+	app.HandlerFunc(http.MethodDelete, version, "/auth/sessions/{session_id}", api.revokeSession, audit("auth.revokesession"), bearer)
+
+	// Two-factor authentication is managed with the password so users can
+	// enable it when their roles require it before they can get a token.
+	app.HandlerFunc(http.MethodGet, version, "/auth/mfa", api.queryMFA, basic)
+	app.HandlerFunc(http.MethodPost, version, "/auth/mfa/enroll", api.enrollMFA, audit("auth.enrollmfa"), basic)
+	app.HandlerFunc(http.MethodPost, version, "/auth/mfa/confirm", api.confirmMFA, audit("auth.confirmmfa"), basic)
+	app.HandlerFunc(http.MethodDelete, version, "/auth/mfa", api.disableMFA, audit("auth.disablemfa"), basic)
 
-	app.HandlerFunc(http.MethodGet, version, "/auth/token/{kid}", api.token, basic)
 	app.HandlerFunc(http.MethodGet, version, "/auth/authenticate", api.authenticate, bearer)
 	app.HandlerFunc(http.MethodPost, version, "/auth/authorize", api.authorize)
 }
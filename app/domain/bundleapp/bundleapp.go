@@ -3,12 +3,15 @@ package bundleapp
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
 	"github.com/gradientsearch/pwmanager/app/sdk/query"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
@@ -17,16 +20,18 @@ import (
 )
 
 type app struct {
-	userBus   *userbus.Business
-	keyBus    *keybus.Business
-	bundleBus *bundlebus.Business
+	userBus      *userbus.Business
+	keyBus       *keybus.Business
+	bundleBus    *bundlebus.Business
+	maxEntrySize int
 }
 
-func newApp(userBus *userbus.Business, keyBus *keybus.Business, bundleBus *bundlebus.Business) *app {
+func newApp(userBus *userbus.Business, keyBus *keybus.Business, bundleBus *bundlebus.Business, maxEntrySize int) *app {
 	return &app{
-		userBus:   userBus,
-		keyBus:    keyBus,
-		bundleBus: bundleBus,
+		userBus:      userBus,
+		keyBus:       keyBus,
+		bundleBus:    bundleBus,
+		maxEntrySize: maxEntrySize,
 	}
 }
 
@@ -54,9 +59,10 @@ func (a *app) newWithTx(ctx context.Context) (*app, error) {
 	}
 
 	app := app{
-		userBus:   userBus,
-		keyBus:    keyBus,
-		bundleBus: bundleBus,
+		userBus:      userBus,
+		keyBus:       keyBus,
+		bundleBus:    bundleBus,
+		maxEntrySize: a.maxEntrySize,
 	}
 
 	return &app, nil
@@ -112,14 +118,67 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
 	}
 
+	if !web.IfMatch(r, strconv.Itoa(bdl.Revision)) {
+		return errs.New(errs.Conflict, bundlebus.ErrConflict)
+	}
+
 	updUsr, err := a.bundleBus.Update(ctx, bdl, uh)
 	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "update: bundleID[%s] uh[%+v]: %s", bdl.ID, uh, err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(updUsr.Revision))
+
 	return toAppBundle(updUsr)
 }
 
+func (a *app) rotate(ctx context.Context, r *http.Request) web.Encoder {
+	var app RotateBundle
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	rb, err := toBusRotateBundle(app, a.maxEntrySize)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	updBdl, err := a.bundleBus.Rotate(ctx, bdl, rb)
+	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrIncompleteRotation):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, entrybus.ErrKeyGeneration):
+			return errs.New(errs.InvalidArgument, entrybus.ErrKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict),
+			errors.Is(err, entrybus.ErrConflict),
+			errors.Is(err, keybus.ErrConflict):
+			return errs.New(errs.Conflict, err)
+		}
+		return errs.Newf(errs.Internal, "rotate: bundleID[%s]: %s", bdl.ID, err)
+	}
+
+	return toAppBundle(updBdl)
+}
+
 func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
 	bdl, err := mid.GetBundle(ctx)
 	if err != nil {
@@ -133,6 +192,61 @@ func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
 	return nil
 }
 
+func (a *app) undelete(ctx context.Context, _ *http.Request) web.Encoder {
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	updBdl, err := a.bundleBus.Undelete(ctx, bdl)
+	if err != nil {
+		return errs.Newf(errs.Internal, "undelete: bundleID[%s]: %s", bdl.ID, err)
+	}
+
+	return toAppBundle(updBdl)
+}
+
+func (a *app) queryTrash(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, bundlebus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	// Users can only list the bundles they own that are in the trash.
+	deleted := true
+	filter.UserID = &userID
+	filter.Deleted = &deleted
+
+	bdls, err := a.bundleBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.bundleBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppBundles(bdls), total, page)
+}
+
 func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
 	qp := parseQueryParams(r)
 
@@ -170,5 +284,7 @@ func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "querybyid: %s", err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(bdl.Revision))
+
 	return toAppBundle(bdl)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
 	"github.com/gradientsearch/pwmanager/business/types/name"
 
 	"github.com/gradientsearch/pwmanager/business/types/key"
@@ -40,8 +41,9 @@ func toAppBundles(bundles []bundlebus.Bundle) []Bundle {
 
 // UpdateBundle defines the data needed to update a bundle.
 type UpdateBundle struct {
-	Type     *string `json:"type"` // TODO may not want to allow updating bundle type 🤔
-	Metadata *string `json:"metadata"`
+	Type          *string `json:"type"` // TODO may not want to allow updating bundle type 🤔
+	Metadata      *string `json:"metadata"`
+	KeyGeneration *int    `json:"keyGeneration" validate:"required_with=Metadata"`
 }
 
 // Decode implements the decoder interface.
@@ -69,8 +71,94 @@ func toBusUpdateBundle(app UpdateBundle) (bundlebus.UpdateBundle, error) {
 	}
 
 	bus := bundlebus.UpdateBundle{
-		Type:     &t,
-		Metadata: app.Metadata,
+		Type:          &t,
+		Metadata:      app.Metadata,
+		KeyGeneration: app.KeyGeneration,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// RotateBundle defines the data needed to rotate a bundle key. Entries must
+// hold every entry in the bundle re-encrypted under the new key and Keys the
+// new key wrapped for every member.
+type RotateBundle struct {
+	KeyGeneration int           `json:"keyGeneration" validate:"required"`
+	Metadata      string        `json:"metadata" validate:"required"`
+	Entries       []RotateEntry `json:"entries" validate:"dive"`
+	Keys          []RotateKey   `json:"keys" validate:"required,dive"`
+}
+
+// RotateEntry is an entry re-encrypted under the new bundle key.
+type RotateEntry struct {
+	ID   string          `json:"id" validate:"required"`
+	Data json.RawMessage `json:"data" validate:"required"`
+}
+
+// RotateKey is the new bundle key wrapped for a member.
+type RotateKey struct {
+	UserID string `json:"userID" validate:"required"`
+	Data   string `json:"data" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *RotateBundle) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app RotateBundle) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusRotateBundle(app RotateBundle, maxEntrySize int) (bundlebus.RotateBundle, error) {
+	entries := make([]bundlebus.RotateEntry, len(app.Entries))
+	for i, re := range app.Entries {
+		id, err := uuid.Parse(re.ID)
+		if err != nil {
+			return bundlebus.RotateBundle{}, fmt.Errorf("parse entry id: %w", err)
+		}
+
+		data, err := entry.ParseLimit(string(re.Data), maxEntrySize)
+		if err != nil {
+			return bundlebus.RotateBundle{}, fmt.Errorf("parse entry data: %w", err)
+		}
+
+		entries[i] = bundlebus.RotateEntry{
+			ID:   id,
+			Data: data,
+		}
+	}
+
+	keys := make([]bundlebus.RotateKey, len(app.Keys))
+	for i, rk := range app.Keys {
+		userID, err := uuid.Parse(rk.UserID)
+		if err != nil {
+			return bundlebus.RotateBundle{}, fmt.Errorf("parse key user id: %w", err)
+		}
+
+		data, err := key.Parse(rk.Data)
+		if err != nil {
+			return bundlebus.RotateBundle{}, fmt.Errorf("parse key data: %w", err)
+		}
+
+		keys[i] = bundlebus.RotateKey{
+			UserID: userID,
+			Data:   data,
+		}
+	}
+
+	bus := bundlebus.RotateBundle{
+		KeyGeneration: app.KeyGeneration,
+		Metadata:      app.Metadata,
+		Entries:       entries,
+		Keys:          keys,
 	}
 
 	return bus, nil
@@ -81,6 +169,7 @@ type Key struct {
 	ID          string `json:"id"`
 	UserID      string `json:"userID"`
 	Data        string `json:"data"`
+	Revision    int    `json:"revision"`
 	DateCreated string `json:"dateCreated"`
 	DateUpdated string `json:"dateUpdated"`
 }
@@ -96,6 +185,7 @@ func toAppKey(k keybus.Key) Key {
 		ID:          k.ID.String(),
 		UserID:      k.UserID.String(),
 		Data:        k.Data.String(),
+		Revision:    k.Revision,
 		DateCreated: k.DateCreated.Format(time.RFC3339),
 		DateUpdated: k.DateUpdated.Format(time.RFC3339),
 	}
@@ -280,21 +370,32 @@ func toBusNewBundle(ctx context.Context, app NewBundle) (bundlebus.NewBundle, er
 // =============================================================================
 // Bundle defines the data needed to add a new bundle.
 type Bundle struct {
-	ID          string `json:"id"`
-	UserID      string `json:"userID"`
-	Type        string `json:"type"`
-	Metadata    string `json:"metadata"`
-	DateCreated string `json:"dateCreated"`
-	DateUpdated string `json:"dateUpdated"`
+	ID            string `json:"id"`
+	UserID        string `json:"userID"`
+	Type          string `json:"type"`
+	Metadata      string `json:"metadata"`
+	KeyGeneration int    `json:"keyGeneration"`
+	Revision      int    `json:"revision"`
+	DateCreated   string `json:"dateCreated"`
+	DateUpdated   string `json:"dateUpdated"`
+	DateDeleted   string `json:"dateDeleted,omitempty"`
 }
 
 func toAppBundle(b bundlebus.Bundle) Bundle {
+	var dateDeleted string
+	if !b.DateDeleted.IsZero() {
+		dateDeleted = b.DateDeleted.Format(time.RFC3339)
+	}
+
 	return Bundle{
-		ID:          b.ID.String(),
-		UserID:      b.UserID.String(),
-		Type:        b.Type.String(),
-		Metadata:    b.Metadata,
-		DateCreated: b.DateCreated.String(),
-		DateUpdated: b.DateUpdated.String(),
+		ID:            b.ID.String(),
+		UserID:        b.UserID.String(),
+		Type:          b.Type.String(),
+		Metadata:      b.Metadata,
+		KeyGeneration: b.KeyGeneration,
+		Revision:      b.Revision,
+		DateCreated:   b.DateCreated.String(),
+		DateUpdated:   b.DateUpdated.String(),
+		DateDeleted:   dateDeleted,
 	}
 }
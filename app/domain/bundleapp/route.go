@@ -5,6 +5,7 @@ import (
 
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
@@ -21,7 +22,11 @@ type Config struct {
 	UserBus    *userbus.Business
 	KeyBus     *keybus.Business
 	BundleBus  *bundlebus.Business
+	AuditBus   *auditbus.Business
 	AuthClient *authclient.Client
+
+	// MaxEntrySize is the limit in bytes of an encoded entry sent by a client.
+	MaxEntrySize int
 }
 
 // Routes adds specific routes for this group.
@@ -29,15 +34,28 @@ func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
 	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
 	ruleAuthorizeBundleModify := mid.AuthorizeBundleModify(cfg.AuthClient, cfg.BundleBus)
+	ruleAuthorizeBundleAdmin := mid.AuthorizeBundleAdmin(cfg.AuthClient, cfg.BundleBus, cfg.KeyBus)
+	ruleAuthorizeBundleUndelete := mid.AuthorizeBundleUndelete(cfg.AuthClient, cfg.BundleBus)
 
 	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
 
-	api := newApp(cfg.UserBus, cfg.KeyBus, cfg.BundleBus)
+	api := newApp(cfg.UserBus, cfg.KeyBus, cfg.BundleBus, cfg.MaxEntrySize)
 
 	// Users can only create bundles for themselves.
-	app.HandlerFunc(http.MethodPost, version, "/bundles", api.create, authen, transaction)
+	app.HandlerFunc(http.MethodPost, version, "/bundles", api.create, audit("bundle.create"), authen, transaction)
+
+	app.HandlerFunc(http.MethodPut, version, "/bundles/{bundle_id}", api.update, audit("bundle.update"), authen, ruleAuthorizeBundleModify)
+	app.HandlerFunc(http.MethodDelete, version, "/bundles/{bundle_id}", api.delete, audit("bundle.delete"), authen, ruleAuthorizeBundleModify)
+
+	// Rotating the bundle key rewrites every entry and member key, so it is
+	// limited to bundle admins.
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/rotate", api.rotate, audit("bundle.rotate"), authen, ruleAuthorizeBundleAdmin, transaction)
 
-	app.HandlerFunc(http.MethodPut, version, "/bundles/{bundle_id}", api.update, authen, ruleAuthorizeBundleModify)
-	app.HandlerFunc(http.MethodDelete, version, "/bundles/{bundle_id}", api.delete, authen, ruleAuthorizeBundleModify)
+	// Deleted bundles stay in the trash until they are purged.
+	app.HandlerFunc(http.MethodGet, version, "/trash/bundles", api.queryTrash, authen)
+	app.HandlerFunc(http.MethodPost, version, "/trash/bundles/{bundle_id}/restore", api.undelete, audit("bundle.undelete"), authen, ruleAuthorizeBundleUndelete)
 }
@@ -3,8 +3,11 @@ package entryapp
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
 	"github.com/gradientsearch/pwmanager/app/sdk/query"
@@ -16,14 +19,16 @@ import (
 )
 
 type app struct {
-	entryBus  *entrybus.Business
-	bundleBus *bundlebus.Business
+	entryBus     *entrybus.Business
+	bundleBus    *bundlebus.Business
+	maxEntrySize int
 }
 
-func newApp(entryBus *entrybus.Business, bundleBus *bundlebus.Business) *app {
+func newApp(entryBus *entrybus.Business, bundleBus *bundlebus.Business, maxEntrySize int) *app {
 	return &app{
-		entryBus:  entryBus,
-		bundleBus: bundleBus,
+		entryBus:     entryBus,
+		bundleBus:    bundleBus,
+		maxEntrySize: maxEntrySize,
 	}
 }
 
@@ -46,8 +51,9 @@ func (a *app) newWithTx(ctx context.Context) (*app, error) {
 	}
 
 	app := app{
-		entryBus:  entryBus,
-		bundleBus: bundleBus,
+		entryBus:     entryBus,
+		bundleBus:    bundleBus,
+		maxEntrySize: a.maxEntrySize,
 	}
 
 	return &app, nil
@@ -67,19 +73,25 @@ func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
 	// =============================================================================
 	// New Entry
 
-	ne, err := toBusNewEntry(ctx, app)
+	ne, err := toBusNewEntry(ctx, app, a.maxEntrySize)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
 	e, err := a.entryBus.Create(ctx, ne)
 	if err != nil {
+		switch {
+		case errors.Is(err, entrybus.ErrKeyGeneration):
+			return errs.New(errs.InvalidArgument, entrybus.ErrKeyGeneration)
+		case errors.Is(err, entrybus.ErrSchemaVersion):
+			return errs.New(errs.InvalidArgument, entrybus.ErrSchemaVersion)
+		}
 		return errs.Newf(errs.Internal, "create: k[%+v]: %s", e, err)
 	}
 
 	// =============================================================================
 	// Bundle update
-	ub, err := toBusUpdateBundle(app.Metadata)
+	ub, err := toBusUpdateBundle(app.Metadata, app.KeyGeneration)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
@@ -91,12 +103,114 @@ func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
 
 	b, err := a.bundleBus.Update(ctx, bdl, ub)
 	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "create: k[%+v]: %s", e, err)
 	}
 
 	return toAppEntryTx(e, b)
 }
 
+func (a *app) createBatch(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewEntryBatch
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ops, err := toBusBatchOperations(app, a.maxEntrySize)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	results, b, err := a.bulk(ctx, ops, app.Metadata, app.KeyGeneration)
+	if err != nil {
+		if errors.Is(err, entrybus.ErrBulkFailed) {
+			return toBulkFieldErrors("entries", results)
+		}
+		return err.(*errs.Error)
+	}
+
+	return toAppEntryBatch(results, b)
+}
+
+func (a *app) bulkWrite(ctx context.Context, r *http.Request) web.Encoder {
+	var app BulkEntries
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ops, err := toBusBulkOperations(app, a.maxEntrySize)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	results, b, err := a.bulk(ctx, ops, app.Metadata, app.KeyGeneration)
+	if err != nil {
+		if errors.Is(err, entrybus.ErrBulkFailed) {
+			return toBulkFieldErrors("operations", results)
+		}
+		return err.(*errs.Error)
+	}
+
+	return toAppBulkResult(results, b)
+}
+
+// bulk applies the operations to the bundle the user was authorized for and
+// updates the bundle metadata once. When operations fail the results are
+// returned with entrybus.ErrBulkFailed, any other error is an *errs.Error.
+func (a *app) bulk(ctx context.Context, ops []entrybus.BulkOperation, metadata string, keyGeneration int) ([]entrybus.BulkResult, bundlebus.Bundle, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return nil, bundlebus.Bundle{}, errs.New(errs.Internal, err)
+	}
+
+	// =============================================================================
+	// Entries
+
+	ne, err := mid.GetEntry(ctx)
+	if err != nil {
+		return nil, bundlebus.Bundle{}, errs.Newf(errs.Internal, "entry missing in context: %s", err)
+	}
+
+	results, err := a.entryBus.Bulk(ctx, ne.UserID, ne.BundleID, keyGeneration, ops)
+	if err != nil {
+		if errors.Is(err, entrybus.ErrBulkFailed) {
+			return results, bundlebus.Bundle{}, err
+		}
+		return nil, bundlebus.Bundle{}, errs.Newf(errs.Internal, "bulk: bundleID[%s]: %s", ne.BundleID, err)
+	}
+
+	// =============================================================================
+	// Bundle update
+
+	ub, err := toBusUpdateBundle(metadata, keyGeneration)
+	if err != nil {
+		return nil, bundlebus.Bundle{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return nil, bundlebus.Bundle{}, errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	b, err := a.bundleBus.Update(ctx, bdl, ub)
+	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return nil, bundlebus.Bundle{}, errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return nil, bundlebus.Bundle{}, errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
+		return nil, bundlebus.Bundle{}, errs.Newf(errs.Internal, "bulk: bundleID[%s]: %s", bdl.ID, err)
+	}
+
+	return results, b, nil
+}
+
 func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 	var app UpdateEntry
 	if err := web.Decode(r, &app); err != nil {
@@ -111,7 +225,7 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 	// =============================================================================
 	// Entry update
 
-	ue, err := toBusUpdateEntry(ctx, app)
+	ue, err := toBusUpdateEntry(ctx, app, a.maxEntrySize)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
@@ -121,15 +235,27 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "entry missing in context: %s", err)
 	}
 
+	if !web.IfMatch(r, strconv.Itoa(e.Revision)) {
+		return errs.New(errs.Conflict, entrybus.ErrConflict)
+	}
+
 	updEntry, err := a.entryBus.Update(ctx, e, ue)
 	if err != nil {
+		switch {
+		case errors.Is(err, entrybus.ErrKeyGeneration):
+			return errs.New(errs.InvalidArgument, entrybus.ErrKeyGeneration)
+		case errors.Is(err, entrybus.ErrSchemaVersion):
+			return errs.New(errs.InvalidArgument, entrybus.ErrSchemaVersion)
+		case errors.Is(err, entrybus.ErrConflict):
+			return errs.New(errs.Conflict, entrybus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "update: entryID[%s] uk[%+v]: %s", e.ID, app, err)
 	}
 
 	// =============================================================================
 	// Bundle update
 
-	ub, err := toBusUpdateBundle(app.Metadata)
+	ub, err := toBusUpdateBundle(app.Metadata, app.KeyGeneration)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
@@ -141,9 +267,17 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 
 	b, err := a.bundleBus.Update(ctx, bdl, ub)
 	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "create: k[%+v]: %s", e, err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(updEntry.Revision))
+
 	return toAppEntryTx(updEntry, b)
 }
 
@@ -173,7 +307,7 @@ func (a *app) delete(ctx context.Context, r *http.Request) web.Encoder {
 	// =============================================================================
 	// Bundle update
 
-	ub, err := toBusUpdateBundle(app.Metadata)
+	ub, err := toBusUpdateBundle(app.Metadata, app.KeyGeneration)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
@@ -185,12 +319,215 @@ func (a *app) delete(ctx context.Context, r *http.Request) web.Encoder {
 
 	b, err := a.bundleBus.Update(ctx, bdl, ub)
 	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "create: k[%+v]: %s", e, err)
 	}
 
 	return toAppEntryTx(e, b)
 }
 
+func (a *app) restore(ctx context.Context, r *http.Request) web.Encoder {
+	var app RestoreEntry
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	versionID, err := uuid.Parse(web.Param(r, "version_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, mid.ErrInvalidID)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	// =============================================================================
+	// Entry restore
+
+	e, err := mid.GetEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "entry missing in context: %s", err)
+	}
+
+	v, err := a.entryBus.QueryVersionByID(ctx, e, versionID)
+	if err != nil {
+		if errors.Is(err, entrybus.ErrVersionNotFound) {
+			return errs.New(errs.NotFound, entrybus.ErrVersionNotFound)
+		}
+		return errs.Newf(errs.Internal, "queryversionbyid: entryID[%s] versionID[%s]: %s", e.ID, versionID, err)
+	}
+
+	// A version encrypted under a rotated bundle key can not be decrypted
+	// by the members anymore.
+	if v.KeyGeneration != app.KeyGeneration {
+		return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	updEntry, err := a.entryBus.Restore(ctx, e, v, userID)
+	if err != nil {
+		if errors.Is(err, entrybus.ErrConflict) {
+			return errs.New(errs.Conflict, entrybus.ErrConflict)
+		}
+		return errs.Newf(errs.Internal, "restore: entryID[%s] versionID[%s]: %s", e.ID, v.ID, err)
+	}
+
+	// =============================================================================
+	// Bundle update
+
+	ub, err := toBusUpdateBundle(app.Metadata, app.KeyGeneration)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	b, err := a.bundleBus.Update(ctx, bdl, ub)
+	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
+		return errs.Newf(errs.Internal, "restore: entryID[%s]: %s", e.ID, err)
+	}
+
+	return toAppEntryTx(updEntry, b)
+}
+
+func (a *app) undelete(ctx context.Context, r *http.Request) web.Encoder {
+	var app RestoreEntry
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	// =============================================================================
+	// Entry undelete
+
+	e, err := mid.GetEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "entry missing in context: %s", err)
+	}
+
+	updEntry, err := a.entryBus.Undelete(ctx, e)
+	if err != nil {
+		return errs.Newf(errs.Internal, "undelete: entryID[%s]: %s", e.ID, err)
+	}
+
+	// =============================================================================
+	// Bundle update
+
+	ub, err := toBusUpdateBundle(app.Metadata, app.KeyGeneration)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	b, err := a.bundleBus.Update(ctx, bdl, ub)
+	if err != nil {
+		switch {
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		case errors.Is(err, bundlebus.ErrConflict):
+			return errs.New(errs.Conflict, bundlebus.ErrConflict)
+		}
+		return errs.Newf(errs.Internal, "undelete: entryID[%s]: %s", e.ID, err)
+	}
+
+	return toAppEntryTx(updEntry, b)
+}
+
+func (a *app) queryTrash(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, entrybus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	// Users can only list the entries in the trash of the bundles they are a
+	// member of.
+	deleted := true
+	filter.MemberID = &userID
+	filter.Deleted = &deleted
+
+	entries, err := a.entryBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.entryBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppEntries(entries), total, page)
+}
+
+func (a *app) queryVersions(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	e, err := mid.GetEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "entry missing in context: %s", err)
+	}
+
+	vers, err := a.entryBus.QueryVersions(ctx, e.ID, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryversions: %s", err)
+	}
+
+	total, err := a.entryBus.CountVersions(ctx, e.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "countversions: %s", err)
+	}
+
+	return query.NewResult(toAppVersions(vers), total, page)
+}
+
 func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
 	qp := parseQueryParams(r)
 
@@ -209,6 +546,14 @@ func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.NewFieldErrors("order", err)
 	}
 
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	// The listing is limited to the bundle the user was authorized for.
+	filter.BundleID = &bdl.ID
+
 	entries, err := a.entryBus.Query(ctx, filter, orderBy, page)
 	if err != nil {
 		return errs.Newf(errs.Internal, "query: %s", err)
@@ -228,5 +573,7 @@ func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "querybyid: %s", err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(e.Revision))
+
 	return toAppEntry(e)
 }
@@ -2,29 +2,41 @@ package entryapp
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
 type queryParams struct {
-	Page    string
-	Rows    string
-	OrderBy string
-	ID      string
-	UserID  string
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	UserID           string
+	Type             string
+	StartCreatedDate string
+	EndCreatedDate   string
+	StartUpdatedDate string
+	EndUpdatedDate   string
 }
 
 func parseQueryParams(r *http.Request) queryParams {
 	values := r.URL.Query()
 
 	filter := queryParams{
-		Page:    values.Get("page"),
-		Rows:    values.Get("rows"),
-		OrderBy: values.Get("orderBy"),
-		ID:      values.Get("entry_id"),
-		UserID:  values.Get("user_id"),
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ID:               values.Get("entry_id"),
+		UserID:           values.Get("user_id"),
+		Type:             values.Get("type"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+		StartUpdatedDate: values.Get("start_updated_date"),
+		EndUpdatedDate:   values.Get("end_updated_date"),
 	}
 
 	return filter
@@ -54,6 +66,56 @@ func parseFilter(qp queryParams) (entrybus.QueryFilter, error) {
 		}
 	}
 
+	if qp.Type != "" {
+		typ, err := itemtype.Parse(qp.Type)
+		switch err {
+		case nil:
+			filter.Type = &typ
+		default:
+			fieldErrors.Add("type", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if qp.StartUpdatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartUpdatedDate)
+		switch err {
+		case nil:
+			filter.StartUpdatedDate = &t
+		default:
+			fieldErrors.Add("start_updated_date", err)
+		}
+	}
+
+	if qp.EndUpdatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndUpdatedDate)
+		switch err {
+		case nil:
+			filter.EndUpdatedDate = &t
+		default:
+			fieldErrors.Add("end_updated_date", err)
+		}
+	}
+
 	if fieldErrors != nil {
 		return entrybus.QueryFilter{}, fieldErrors.ToError()
 	}
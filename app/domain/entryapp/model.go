@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
 // Entry represents information about an individual entry.
@@ -27,23 +29,8 @@ func (app EntryTx) Encode() ([]byte, string, error) {
 
 func toAppEntryTx(e entrybus.Entry, b bundlebus.Bundle) EntryTx {
 	return EntryTx{
-		Entry: Entry{
-			ID:          e.ID.String(),
-			BundleID:    e.BundleID.String(),
-			UserID:      e.UserID.String(),
-			Data:        e.Data.String(),
-			DateCreated: e.DateCreated.Format(time.RFC3339),
-			DateUpdated: e.DateUpdated.Format(time.RFC3339),
-		},
-		Bundle: Bundle{
-			ID:       b.ID.String(),
-			UserID:   b.UserID.String(),
-			Type:     b.Type.String(),
-			Metadata: b.Metadata,
-
-			DateCreated: b.DateCreated.Format(time.RFC3339),
-			DateUpdated: b.DateUpdated.Format(time.RFC3339),
-		},
+		Entry:  toAppEntry(e),
+		Bundle: toAppBundle(b),
 	}
 }
 
@@ -51,12 +38,17 @@ func toAppEntryTx(e entrybus.Entry, b bundlebus.Bundle) EntryTx {
 
 // Entry represents information about an individual entry.
 type Entry struct {
-	ID          string `json:"id"`
-	UserID      string `json:"userID"`
-	BundleID    string `json:"bundleID"`
-	Data        string `json:"data"`
-	DateCreated string `json:"dateCreated"`
-	DateUpdated string `json:"dateUpdated"`
+	ID            string          `json:"id"`
+	UserID        string          `json:"userID"`
+	BundleID      string          `json:"bundleID"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+	KeyGeneration int             `json:"keyGeneration"`
+	Revision      int             `json:"revision"`
+	DateCreated   string          `json:"dateCreated"`
+	DateUpdated   string          `json:"dateUpdated"`
+	DateDeleted   string          `json:"dateDeleted,omitempty"`
 }
 
 // Encode implements the encoder interface.
@@ -66,13 +58,23 @@ func (app Entry) Encode() ([]byte, string, error) {
 }
 
 func toAppEntry(e entrybus.Entry) Entry {
+	var dateDeleted string
+	if !e.DateDeleted.IsZero() {
+		dateDeleted = e.DateDeleted.Format(time.RFC3339)
+	}
+
 	return Entry{
-		ID:          e.ID.String(),
-		BundleID:    e.BundleID.String(),
-		UserID:      e.UserID.String(),
-		Data:        e.Data.String(),
-		DateCreated: e.DateCreated.Format(time.RFC3339),
-		DateUpdated: e.DateUpdated.Format(time.RFC3339),
+		ID:            e.ID.String(),
+		BundleID:      e.BundleID.String(),
+		UserID:        e.UserID.String(),
+		Type:          e.Type.String(),
+		SchemaVersion: e.SchemaVersion,
+		Data:          json.RawMessage(e.Data.String()),
+		KeyGeneration: e.KeyGeneration,
+		Revision:      e.Revision,
+		DateCreated:   e.DateCreated.Format(time.RFC3339),
+		DateUpdated:   e.DateUpdated.Format(time.RFC3339),
+		DateDeleted:   dateDeleted,
 	}
 }
 
@@ -89,8 +91,11 @@ func toAppEntries(entries []entrybus.Entry) []Entry {
 
 // NewEntryTX defines the data needed to add a new entry.
 type NewEntryTX struct {
-	Data     string `json:"data" validate:"required"`
-	Metadata string `json:"metadatadata" validate:"required"`
+	Type          string          `json:"type" validate:"required"`
+	SchemaVersion int             `json:"schemaVersion" validate:"required"`
+	Data          json.RawMessage `json:"data" validate:"required"`
+	Metadata      string          `json:"metadatadata" validate:"required"`
+	KeyGeneration int             `json:"keyGeneration" validate:"required"`
 }
 
 // Decode implements the decoder interface.
@@ -107,21 +112,29 @@ func (app NewEntryTX) Validate() error {
 	return nil
 }
 
-func toBusNewEntry(ctx context.Context, app NewEntryTX) (entrybus.NewEntry, error) {
+func toBusNewEntry(ctx context.Context, app NewEntryTX, maxEntrySize int) (entrybus.NewEntry, error) {
 	ne, err := mid.GetEntry(ctx)
 	if err != nil {
 		return entrybus.NewEntry{}, fmt.Errorf("getentry: %w", err)
 	}
 
-	data, err := entry.Parse(app.Data)
+	typ, err := itemtype.Parse(app.Type)
+	if err != nil {
+		return entrybus.NewEntry{}, fmt.Errorf("parse type: %w", err)
+	}
+
+	data, err := entry.ParseLimit(string(app.Data), maxEntrySize)
 	if err != nil {
 		return entrybus.NewEntry{}, fmt.Errorf("parse data: %w", err)
 	}
 
 	bus := entrybus.NewEntry{
-		UserID:   ne.UserID,
-		BundleID: ne.BundleID,
-		Data:     data,
+		UserID:        ne.UserID,
+		BundleID:      ne.BundleID,
+		Type:          typ,
+		SchemaVersion: app.SchemaVersion,
+		Data:          data,
+		KeyGeneration: app.KeyGeneration,
 	}
 
 	return bus, nil
@@ -129,10 +142,229 @@ func toBusNewEntry(ctx context.Context, app NewEntryTX) (entrybus.NewEntry, erro
 
 // =============================================================================
 
+// NewEntryBatch defines the data needed to add many entries to a bundle at
+// once, e.g. the items of an import. The bundle metadata is updated a single
+// time after all entries are added.
+type NewEntryBatch struct {
+	Entries       []NewBatchEntry `json:"entries" validate:"required,min=1,max=500,dive"`
+	Metadata      string          `json:"metadata" validate:"required"`
+	KeyGeneration int             `json:"keyGeneration" validate:"required"`
+}
+
+// NewBatchEntry defines the data needed to add an entry as part of a batch.
+type NewBatchEntry struct {
+	Type          string          `json:"type" validate:"required"`
+	SchemaVersion int             `json:"schemaVersion" validate:"required"`
+	Data          json.RawMessage `json:"data" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewEntryBatch) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewEntryBatch) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusBatchOperations(app NewEntryBatch, maxEntrySize int) ([]entrybus.BulkOperation, error) {
+	ops := make([]entrybus.BulkOperation, len(app.Entries))
+	for i, e := range app.Entries {
+		typ, err := itemtype.Parse(e.Type)
+		if err != nil {
+			return nil, fmt.Errorf("entries[%d]: parse type: %w", i, err)
+		}
+
+		data, err := entry.ParseLimit(string(e.Data), maxEntrySize)
+		if err != nil {
+			return nil, fmt.Errorf("entries[%d]: parse data: %w", i, err)
+		}
+
+		ops[i] = entrybus.BulkOperation{
+			Action:        entrybus.BulkCreate,
+			Type:          typ,
+			SchemaVersion: e.SchemaVersion,
+			Data:          data,
+		}
+	}
+
+	return ops, nil
+}
+
+// EntryBatch represents the entries added by a batch and the updated bundle.
+type EntryBatch struct {
+	Entries []Entry `json:"entries"`
+	Bundle  Bundle  `json:"bundle"`
+}
+
+// Encode implements the encoder interface.
+func (app EntryBatch) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppEntryBatch(results []entrybus.BulkResult, b bundlebus.Bundle) EntryBatch {
+	entries := make([]Entry, len(results))
+	for i, res := range results {
+		entries[i] = toAppEntry(res.Entry)
+	}
+
+	return EntryBatch{
+		Entries: entries,
+		Bundle:  toAppBundle(b),
+	}
+}
+
+// =============================================================================
+
+// BulkEntries defines the operations of a bulk write to the entries of a
+// bundle. The bundle metadata is updated a single time after all operations
+// are applied.
+type BulkEntries struct {
+	Operations    []BulkOperation `json:"operations" validate:"required,min=1,max=500,dive"`
+	Metadata      string          `json:"metadata" validate:"required"`
+	KeyGeneration int             `json:"keyGeneration" validate:"required"`
+}
+
+// BulkOperation defines a single write of a bulk request. ID and Revision are
+// used by update and delete, Type, SchemaVersion and Data by create and
+// update. A Revision of zero skips the check for concurrent writes.
+type BulkOperation struct {
+	Action        string          `json:"action" validate:"required,oneof=create update delete"`
+	ID            string          `json:"id"`
+	Revision      int             `json:"revision"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Decode implements the decoder interface.
+func (app *BulkEntries) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app BulkEntries) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusBulkOperations(app BulkEntries, maxEntrySize int) ([]entrybus.BulkOperation, error) {
+	var fe errs.FieldErrors
+
+	ops := make([]entrybus.BulkOperation, len(app.Operations))
+	for i, o := range app.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		op := entrybus.BulkOperation{
+			Action:   o.Action,
+			Revision: o.Revision,
+		}
+
+		if o.Action != entrybus.BulkCreate {
+			id, err := uuid.Parse(o.ID)
+			if err != nil {
+				fe.Add(field+".id", err)
+				continue
+			}
+			op.EntryID = id
+		}
+
+		if o.Action != entrybus.BulkDelete {
+			typ, err := itemtype.Parse(o.Type)
+			if err != nil {
+				fe.Add(field+".type", err)
+				continue
+			}
+
+			data, err := entry.ParseLimit(string(o.Data), maxEntrySize)
+			if err != nil {
+				fe.Add(field+".data", err)
+				continue
+			}
+
+			op.Type = typ
+			op.SchemaVersion = o.SchemaVersion
+			op.Data = data
+		}
+
+		ops[i] = op
+	}
+
+	if len(fe) > 0 {
+		return nil, fe
+	}
+
+	return ops, nil
+}
+
+// BulkResult represents the outcome of the operations of a bulk write and the
+// updated bundle.
+type BulkResult struct {
+	Results []BulkItem `json:"results"`
+	Bundle  Bundle     `json:"bundle"`
+}
+
+// BulkItem represents the outcome of a single operation of a bulk write.
+type BulkItem struct {
+	Index    int    `json:"index"`
+	Action   string `json:"action"`
+	ID       string `json:"id"`
+	Revision int    `json:"revision"`
+}
+
+// Encode implements the encoder interface.
+func (app BulkResult) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppBulkResult(results []entrybus.BulkResult, b bundlebus.Bundle) BulkResult {
+	items := make([]BulkItem, len(results))
+	for i, res := range results {
+		items[i] = BulkItem{
+			Index:    i,
+			Action:   res.Action,
+			ID:       res.Entry.ID.String(),
+			Revision: res.Entry.Revision,
+		}
+	}
+
+	return BulkResult{
+		Results: items,
+		Bundle:  toAppBundle(b),
+	}
+}
+
+// toBulkFieldErrors reports the operations that failed, the field of each
+// error is the position of the operation in the request.
+func toBulkFieldErrors(field string, results []entrybus.BulkResult) *errs.Error {
+	var fe errs.FieldErrors
+	for i, res := range results {
+		if res.Err != nil {
+			fe.Add(fmt.Sprintf("%s[%d]", field, i), res.Err)
+		}
+	}
+
+	return fe.ToError()
+}
+
+// =============================================================================
+
 // UpdateEntry defines the data needed to update a entry.
 type UpdateEntry struct {
-	Data     string `json:"data" validate:"required"`
-	Metadata string `json:"metadata" validate:"required"`
+	Type          string          `json:"type" validate:"required"`
+	SchemaVersion int             `json:"schemaVersion" validate:"required"`
+	Data          json.RawMessage `json:"data" validate:"required"`
+	Metadata      string          `json:"metadata" validate:"required"`
+	KeyGeneration int             `json:"keyGeneration" validate:"required"`
 }
 
 // Decode implements the decoder interface.
@@ -149,10 +381,15 @@ func (app UpdateEntry) Validate() error {
 	return nil
 }
 
-func toBusUpdateEntry(ctx context.Context, app UpdateEntry) (entrybus.UpdateEntry, error) {
+func toBusUpdateEntry(ctx context.Context, app UpdateEntry, maxEntrySize int) (entrybus.UpdateEntry, error) {
+	typ, err := itemtype.Parse(app.Type)
+	if err != nil {
+		return entrybus.UpdateEntry{}, fmt.Errorf("parse type: %w", err)
+	}
+
 	var e *entry.Entry
-	if app.Data != "" {
-		k, err := entry.Parse(app.Data)
+	if len(app.Data) > 0 {
+		k, err := entry.ParseLimit(string(app.Data), maxEntrySize)
 		if err != nil {
 			return entrybus.UpdateEntry{}, fmt.Errorf("parse: %w", err)
 		}
@@ -165,8 +402,11 @@ func toBusUpdateEntry(ctx context.Context, app UpdateEntry) (entrybus.UpdateEntr
 	}
 
 	bus := entrybus.UpdateEntry{
-		Data:   e,
-		UserID: &userID,
+		Type:          &typ,
+		SchemaVersion: &app.SchemaVersion,
+		Data:          e,
+		UserID:        &userID,
+		KeyGeneration: &app.KeyGeneration,
 	}
 
 	return bus, nil
@@ -176,7 +416,8 @@ func toBusUpdateEntry(ctx context.Context, app UpdateEntry) (entrybus.UpdateEntr
 
 // DeleteEntry defines the data needed to update a bundle metadata after deleting a password entry.
 type DeleteEntry struct {
-	Metadata string `json:"metadata" validate:"required"`
+	Metadata      string `json:"metadata" validate:"required"`
+	KeyGeneration int    `json:"keyGeneration" validate:"required"`
 }
 
 // Decode implements the decoder interface.
@@ -193,22 +434,107 @@ func (app DeleteEntry) Validate() error {
 	return nil
 }
 
+// =============================================================================
+
+// RestoreEntry defines the data needed to update a bundle metadata after
+// restoring a version of a password entry or restoring it from the trash.
+type RestoreEntry struct {
+	Metadata      string `json:"metadata" validate:"required"`
+	KeyGeneration int    `json:"keyGeneration" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *RestoreEntry) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app RestoreEntry) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Version represents a prior state of an entry.
+type Version struct {
+	ID            string          `json:"id"`
+	EntryID       string          `json:"entryID"`
+	BundleID      string          `json:"bundleID"`
+	UserID        string          `json:"userID"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+	KeyGeneration int             `json:"keyGeneration"`
+	DateCreated   string          `json:"dateCreated"`
+	DateArchived  string          `json:"dateArchived"`
+}
+
+// Encode implements the encoder interface.
+func (app Version) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppVersion(v entrybus.Version) Version {
+	return Version{
+		ID:            v.ID.String(),
+		EntryID:       v.EntryID.String(),
+		BundleID:      v.BundleID.String(),
+		UserID:        v.UserID.String(),
+		Type:          v.Type.String(),
+		SchemaVersion: v.SchemaVersion,
+		Data:          json.RawMessage(v.Data.String()),
+		KeyGeneration: v.KeyGeneration,
+		DateCreated:   v.DateCreated.Format(time.RFC3339),
+		DateArchived:  v.DateArchived.Format(time.RFC3339),
+	}
+}
+
+func toAppVersions(vers []entrybus.Version) []Version {
+	app := make([]Version, len(vers))
+	for i, v := range vers {
+		app[i] = toAppVersion(v)
+	}
+
+	return app
+}
+
 // =============================================================================
 // Bundle
 
 // Bundle represents information about an individual bundle.
 type Bundle struct {
-	ID          string `json:"id"`
-	UserID      string `json:"userID"`
-	Type        string `json:"type"`
-	Metadata    string `json:"metadata"`
-	DateCreated string `json:"dateCreated"`
-	DateUpdated string `json:"dateUpdated"`
+	ID            string `json:"id"`
+	UserID        string `json:"userID"`
+	Type          string `json:"type"`
+	Metadata      string `json:"metadata"`
+	KeyGeneration int    `json:"keyGeneration"`
+	Revision      int    `json:"revision"`
+	DateCreated   string `json:"dateCreated"`
+	DateUpdated   string `json:"dateUpdated"`
+}
+
+func toAppBundle(b bundlebus.Bundle) Bundle {
+	return Bundle{
+		ID:            b.ID.String(),
+		UserID:        b.UserID.String(),
+		Type:          b.Type.String(),
+		Metadata:      b.Metadata,
+		KeyGeneration: b.KeyGeneration,
+		Revision:      b.Revision,
+		DateCreated:   b.DateCreated.Format(time.RFC3339),
+		DateUpdated:   b.DateUpdated.Format(time.RFC3339),
+	}
 }
 
-func toBusUpdateBundle(metadata string) (bundlebus.UpdateBundle, error) {
+func toBusUpdateBundle(metadata string, keyGeneration int) (bundlebus.UpdateBundle, error) {
 	bus := bundlebus.UpdateBundle{
-		Metadata: &metadata,
+		Metadata:      &metadata,
+		KeyGeneration: &keyGeneration,
 	}
 
 	return bus, nil
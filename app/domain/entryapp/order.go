@@ -5,6 +5,9 @@ import (
 )
 
 var orderByFields = map[string]string{
-	"entry_id": entrybus.OrderByEntryID,
-	"user_id":  entrybus.OrderByUserID,
+	"entry_id":     entrybus.OrderByEntryID,
+	"user_id":      entrybus.OrderByUserID,
+	"type":         entrybus.OrderByType,
+	"date_created": entrybus.OrderByDateCreated,
+	"date_updated": entrybus.OrderByDateUpdated,
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
@@ -21,7 +22,11 @@ type Config struct {
 	EntryBus   *entrybus.Business
 	BundleBus  *bundlebus.Business
 	KeyBus     *keybus.Business
+	AuditBus   *auditbus.Business
 	AuthClient *authclient.Client
+
+	// MaxEntrySize is the limit in bytes of an encoded entry sent by a client.
+	MaxEntrySize int
 }
 
 // Routes adds specific routes for this group.
@@ -29,15 +34,35 @@ func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
 	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
+	ruleAuthorizeEntryQuery := mid.AuthorizeEntryQuery(cfg.AuthClient, cfg.KeyBus, cfg.BundleBus)
 	ruleAuthorizeEntryCreate := mid.AuthorizeEntryCreate(cfg.AuthClient, cfg.KeyBus, cfg.EntryBus, cfg.BundleBus)
 	ruleAuthorizeEntryRetrieve := mid.AuthorizeEntryRetrieve(cfg.AuthClient, cfg.KeyBus, cfg.EntryBus, cfg.BundleBus)
 	ruleAuthorizeEntryModify := mid.AuthorizeEntryModify(cfg.AuthClient, cfg.KeyBus, cfg.EntryBus, cfg.BundleBus)
+	ruleAuthorizeEntryUndelete := mid.AuthorizeEntryUndelete(cfg.AuthClient, cfg.KeyBus, cfg.EntryBus, cfg.BundleBus)
 	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
 
-	api := newApp(cfg.EntryBus, cfg.BundleBus)
+	api := newApp(cfg.EntryBus, cfg.BundleBus, cfg.MaxEntrySize)
+
+	app.HandlerFunc(http.MethodGet, version, "/bundles/{bundle_id}/entries", api.query, audit("entry.query"), authen, ruleAuthorizeEntryQuery)
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/entries", api.create, audit("entry.create"), authen, ruleAuthorizeEntryCreate, transaction)
+
+	// Batch and bulk writes are authorized once for the bundle and fail as a
+	// whole. Batch is the create-only form of bulk the importers submit to,
+	// it maps its entries to create operations and runs the same code path.
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/entries/batch", api.createBatch, audit("entry.createbatch"), authen, ruleAuthorizeEntryCreate, transaction)
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/entries/bulk", api.bulkWrite, audit("entry.bulk"), authen, ruleAuthorizeEntryCreate, transaction)
+
+	app.HandlerFunc(http.MethodGet, version, "/entries/{entry_id}", api.queryByID, audit("entry.read"), authen, ruleAuthorizeEntryRetrieve)
+	app.HandlerFunc(http.MethodPut, version, "/entries/{entry_id}", api.update, audit("entry.update"), authen, ruleAuthorizeEntryModify, transaction)
+	app.HandlerFunc(http.MethodDelete, version, "/entries/{entry_id}", api.delete, audit("entry.delete"), authen, ruleAuthorizeEntryModify, transaction)
+
+	app.HandlerFunc(http.MethodGet, version, "/entries/{entry_id}/versions", api.queryVersions, audit("entry.versions"), authen, ruleAuthorizeEntryRetrieve)
+	app.HandlerFunc(http.MethodPost, version, "/entries/{entry_id}/versions/{version_id}/restore", api.restore, audit("entry.restore"), authen, ruleAuthorizeEntryModify, transaction)
 
-	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/entries", api.create, authen, ruleAuthorizeEntryCreate, transaction)
-	app.HandlerFunc(http.MethodGet, version, "/entries/{entry_id}", api.queryByID, authen, ruleAuthorizeEntryRetrieve)
-	app.HandlerFunc(http.MethodPut, version, "/entries/{entry_id}", api.update, authen, ruleAuthorizeEntryModify, transaction)
-	app.HandlerFunc(http.MethodDelete, version, "/entries/{entry_id}", api.delete, authen, ruleAuthorizeEntryModify, transaction)
+	// Deleted entries stay in the trash until they are purged.
+	app.HandlerFunc(http.MethodGet, version, "/trash/entries", api.queryTrash, authen)
+	app.HandlerFunc(http.MethodPost, version, "/trash/entries/{entry_id}/restore", api.undelete, audit("entry.undelete"), authen, ruleAuthorizeEntryUndelete, transaction)
 }
@@ -3,7 +3,9 @@ package keyapp
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
@@ -37,6 +39,12 @@ func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
 
 	k, err := a.keyBus.Create(ctx, nk)
 	if err != nil {
+		switch {
+		case errors.Is(err, keybus.ErrPersonalBundle):
+			return errs.New(errs.FailedPrecondition, keybus.ErrPersonalBundle)
+		case errors.Is(err, keybus.ErrBundleNotFound):
+			return errs.New(errs.NotFound, keybus.ErrBundleNotFound)
+		}
 		return errs.Newf(errs.Internal, "create: k[%+v]: %s", k, err)
 	}
 
@@ -59,11 +67,20 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "key missing in context: %s", err)
 	}
 
+	if !web.IfMatch(r, strconv.Itoa(k.Revision)) {
+		return errs.New(errs.Conflict, keybus.ErrConflict)
+	}
+
 	updKey, err := a.keyBus.Update(ctx, k, uk)
 	if err != nil {
+		if errors.Is(err, keybus.ErrConflict) {
+			return errs.New(errs.Conflict, keybus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "update: keyID[%s] uk[%+v]: %s", k.ID, app, err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(updKey.Revision))
+
 	return toAppKey(updKey)
 }
 
@@ -117,6 +134,8 @@ func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "querybyid: %s", err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(k.Revision))
+
 	return toAppKey(k)
 }
 
@@ -136,10 +155,19 @@ func (a *app) updateRole(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "key missing in context: %s", err)
 	}
 
+	if !web.IfMatch(r, strconv.Itoa(k.Revision)) {
+		return errs.New(errs.Conflict, keybus.ErrConflict)
+	}
+
 	updKey, err := a.keyBus.Update(ctx, k, uu)
 	if err != nil {
+		if errors.Is(err, keybus.ErrConflict) {
+			return errs.New(errs.Conflict, keybus.ErrConflict)
+		}
 		return errs.Newf(errs.Internal, "updaterole: userID[%s] uu[%+v]: %s", k.ID, uu, err)
 	}
 
+	web.SetETag(ctx, strconv.Itoa(updKey.Revision))
+
 	return toAppKey(updKey)
 }
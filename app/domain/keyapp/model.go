@@ -20,6 +20,7 @@ type Key struct {
 	BundleID    string   `json:"bundleID"`
 	Data        string   `json:"data"`
 	Roles       []string `json:"roles"`
+	Revision    int      `json:"revision"`
 	DateCreated string   `json:"dateCreated"`
 	DateUpdated string   `json:"dateUpdated"`
 }
@@ -37,6 +38,7 @@ func toAppKey(k keybus.Key) Key {
 		BundleID:    k.BundleID.String(),
 		Data:        k.Data.String(),
 		Roles:       bundlerole.ParseToString(k.Roles),
+		Revision:    k.Revision,
 		DateCreated: k.DateCreated.Format(time.RFC3339),
 		DateUpdated: k.DateUpdated.Format(time.RFC3339),
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
@@ -14,6 +15,7 @@ import (
 type Config struct {
 	Log        *logger.Logger
 	KeyBus     *keybus.Business
+	AuditBus   *auditbus.Business
 	AuthClient *authclient.Client
 }
 
@@ -22,15 +24,18 @@ func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
 	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
 	ruleAuthorizeKeyCreate := mid.AuthorizeKeyCreate(cfg.AuthClient, cfg.KeyBus)
 	ruleAuthorizeKeyRetrieve := mid.AuthorizeKeyRetrieve(cfg.AuthClient, cfg.KeyBus)
 	ruleAuthorizeKeyModify := mid.AuthorizeKeyModify(cfg.AuthClient, cfg.KeyBus)
 
 	api := newApp(cfg.KeyBus)
 
-	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/keys", api.create, authen, ruleAuthorizeKeyCreate)
-	app.HandlerFunc(http.MethodGet, version, "/keys/{key_id}", api.queryByID, authen, ruleAuthorizeKeyRetrieve)
-	app.HandlerFunc(http.MethodPut, version, "/keys/role/{key_id}", api.updateRole, authen, ruleAuthorizeKeyModify)
-	app.HandlerFunc(http.MethodPut, version, "/keys/{key_id}", api.update, authen, ruleAuthorizeKeyModify)
-	app.HandlerFunc(http.MethodDelete, version, "/keys/{key_id}", api.delete, authen, ruleAuthorizeKeyModify)
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/keys", api.create, audit("key.create"), authen, ruleAuthorizeKeyCreate)
+	app.HandlerFunc(http.MethodGet, version, "/keys/{key_id}", api.queryByID, audit("key.read"), authen, ruleAuthorizeKeyRetrieve)
+	app.HandlerFunc(http.MethodPut, version, "/keys/role/{key_id}", api.updateRole, audit("key.updaterole"), authen, ruleAuthorizeKeyModify)
+	app.HandlerFunc(http.MethodPut, version, "/keys/{key_id}", api.update, audit("key.update"), authen, ruleAuthorizeKeyModify)
+	app.HandlerFunc(http.MethodDelete, version, "/keys/{key_id}", api.delete, audit("key.delete"), authen, ruleAuthorizeKeyModify)
 }
@@ -0,0 +1,63 @@
+package memberapp
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+)
+
+type queryParams struct {
+	Page     string
+	Rows     string
+	OrderBy  string
+	BundleID string
+	Status   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:     values.Get("page"),
+		Rows:     values.Get("rows"),
+		OrderBy:  values.Get("orderBy"),
+		BundleID: values.Get("bundle_id"),
+		Status:   values.Get("status"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (memberbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter memberbus.QueryFilter
+
+	if qp.BundleID != "" {
+		id, err := uuid.Parse(qp.BundleID)
+		switch err {
+		case nil:
+			filter.BundleID = &id
+		default:
+			fieldErrors.Add("bundle_id", err)
+		}
+	}
+
+	if qp.Status != "" {
+		status, err := invitestatus.Parse(qp.Status)
+		switch err {
+		case nil:
+			filter.Status = &status
+		default:
+			fieldErrors.Add("status", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return memberbus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
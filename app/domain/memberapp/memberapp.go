@@ -0,0 +1,202 @@
+// Package memberapp maintains the app layer api for bundle membership.
+package memberapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+type app struct {
+	memberBus *memberbus.Business
+}
+
+func newApp(memberBus *memberbus.Business) *app {
+	return &app{
+		memberBus: memberBus,
+	}
+}
+
+// newWithTx constructs a new Handlers value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberBus, err := a.memberBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		memberBus: memberBus,
+	}
+
+	return &app, nil
+}
+
+func (a *app) invite(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewInvite
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	ni, err := toBusNewInvite(app, bdl.ID, userID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	inv, err := a.memberBus.Invite(ctx, ni)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrNotFound):
+			return errs.New(errs.NotFound, userbus.ErrNotFound)
+		case errors.Is(err, memberbus.ErrNotShareable):
+			return errs.New(errs.FailedPrecondition, memberbus.ErrNotShareable)
+		case errors.Is(err, memberbus.ErrUserDisabled):
+			return errs.New(errs.FailedPrecondition, memberbus.ErrUserDisabled)
+		case errors.Is(err, memberbus.ErrAlreadyMember):
+			return errs.New(errs.AlreadyExists, memberbus.ErrAlreadyMember)
+		case errors.Is(err, memberbus.ErrAlreadyInvited):
+			return errs.New(errs.AlreadyExists, memberbus.ErrAlreadyInvited)
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		}
+		return errs.Newf(errs.Internal, "invite: ni[%+v]: %s", ni, err)
+	}
+
+	return toAppInvite(inv)
+}
+
+func (a *app) revoke(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, mid.ErrInvalidID)
+	}
+
+	bdl, err := mid.GetBundle(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "bundle missing in context: %s", err)
+	}
+
+	if err := a.memberBus.Revoke(ctx, bdl, userID); err != nil {
+		switch {
+		case errors.Is(err, keybus.ErrNotFound):
+			return errs.New(errs.NotFound, keybus.ErrNotFound)
+		case errors.Is(err, memberbus.ErrRevokeOwner):
+			return errs.New(errs.FailedPrecondition, memberbus.ErrRevokeOwner)
+		}
+		return errs.Newf(errs.Internal, "revoke: bundleID[%s] userID[%s]: %s", bdl.ID, userID, err)
+	}
+
+	return nil
+}
+
+func (a *app) queryMyInvites(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, memberbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	// Users can only list the invites sent to them.
+	filter.InviteeID = &userID
+
+	invs, err := a.memberBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.memberBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppInvites(invs), total, page)
+}
+
+func (a *app) accept(ctx context.Context, _ *http.Request) web.Encoder {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	inv, err := mid.GetInvite(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "invite missing in context: %s", err)
+	}
+
+	updInv, err := a.memberBus.Accept(ctx, inv)
+	if err != nil {
+		switch {
+		case errors.Is(err, memberbus.ErrNotPending):
+			return errs.New(errs.FailedPrecondition, memberbus.ErrNotPending)
+		case errors.Is(err, bundlebus.ErrNotFound):
+			return errs.New(errs.NotFound, bundlebus.ErrNotFound)
+		case errors.Is(err, memberbus.ErrNotShareable):
+			return errs.New(errs.FailedPrecondition, memberbus.ErrNotShareable)
+		case errors.Is(err, bundlebus.ErrStaleKeyGeneration):
+			return errs.New(errs.Aborted, bundlebus.ErrStaleKeyGeneration)
+		}
+		return errs.Newf(errs.Internal, "accept: inviteID[%s]: %s", inv.ID, err)
+	}
+
+	return toAppInvite(updInv)
+}
+
+func (a *app) decline(ctx context.Context, _ *http.Request) web.Encoder {
+	inv, err := mid.GetInvite(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "invite missing in context: %s", err)
+	}
+
+	updInv, err := a.memberBus.Decline(ctx, inv)
+	if err != nil {
+		if errors.Is(err, memberbus.ErrNotPending) {
+			return errs.New(errs.FailedPrecondition, memberbus.ErrNotPending)
+		}
+		return errs.Newf(errs.Internal, "decline: inviteID[%s]: %s", inv.ID, err)
+	}
+
+	return toAppInvite(updInv)
+}
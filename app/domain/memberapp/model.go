@@ -0,0 +1,111 @@
+package memberapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+// Invite represents information about an individual bundle invite.
+type Invite struct {
+	ID            string   `json:"id"`
+	BundleID      string   `json:"bundleID"`
+	InviterID     string   `json:"inviterID"`
+	InviteeID     string   `json:"inviteeID"`
+	Data          string   `json:"data"`
+	Roles         []string `json:"roles"`
+	KeyGeneration int      `json:"keyGeneration"`
+	Status        string   `json:"status"`
+	DateCreated   string   `json:"dateCreated"`
+	DateUpdated   string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Invite) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppInvite(inv memberbus.Invite) Invite {
+	return Invite{
+		ID:            inv.ID.String(),
+		BundleID:      inv.BundleID.String(),
+		InviterID:     inv.InviterID.String(),
+		InviteeID:     inv.InviteeID.String(),
+		Data:          inv.Data.String(),
+		Roles:         bundlerole.ParseToString(inv.Roles),
+		KeyGeneration: inv.KeyGeneration,
+		Status:        inv.Status.String(),
+		DateCreated:   inv.DateCreated.Format(time.RFC3339),
+		DateUpdated:   inv.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppInvites(invs []memberbus.Invite) []Invite {
+	app := make([]Invite, len(invs))
+	for i, inv := range invs {
+		app[i] = toAppInvite(inv)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewInvite defines the data needed to invite a user to a bundle. Data is the
+// bundle key wrapped with the invitee's public key and KeyGeneration the
+// bundle key generation it was wrapped from.
+type NewInvite struct {
+	InviteeID     string   `json:"inviteeID" validate:"required"`
+	Data          string   `json:"data" validate:"required"`
+	Roles         []string `json:"roles" validate:"required"`
+	KeyGeneration int      `json:"keyGeneration" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewInvite) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewInvite) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewInvite(app NewInvite, bundleID uuid.UUID, inviterID uuid.UUID) (memberbus.NewInvite, error) {
+	inviteeID, err := uuid.Parse(app.InviteeID)
+	if err != nil {
+		return memberbus.NewInvite{}, fmt.Errorf("parse inviteeid: %w", err)
+	}
+
+	data, err := key.Parse(app.Data)
+	if err != nil {
+		return memberbus.NewInvite{}, fmt.Errorf("parse data: %w", err)
+	}
+
+	roles, err := bundlerole.ParseMany(app.Roles)
+	if err != nil {
+		return memberbus.NewInvite{}, fmt.Errorf("parse roles: %w", err)
+	}
+
+	bus := memberbus.NewInvite{
+		BundleID:      bundleID,
+		InviterID:     inviterID,
+		InviteeID:     inviteeID,
+		Data:          data,
+		Roles:         roles,
+		KeyGeneration: app.KeyGeneration,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,12 @@
+package memberapp
+
+import (
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+)
+
+var orderByFields = map[string]string{
+	"invite_id":    memberbus.OrderByInviteID,
+	"bundle_id":    memberbus.OrderByBundleID,
+	"status":       memberbus.OrderByStatus,
+	"date_created": memberbus.OrderByDateCreated,
+}
@@ -0,0 +1,50 @@
+package memberapp
+
+import (
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	DB         *sqlx.DB
+	BundleBus  *bundlebus.Business
+	KeyBus     *keybus.Business
+	MemberBus  *memberbus.Business
+	AuditBus   *auditbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
+	ruleBundleAdmin := mid.AuthorizeBundleAdmin(cfg.AuthClient, cfg.BundleBus, cfg.KeyBus)
+	ruleInviteRecipient := mid.AuthorizeInviteRecipient(cfg.AuthClient, cfg.MemberBus)
+
+	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
+
+	api := newApp(cfg.MemberBus)
+
+	app.HandlerFunc(http.MethodPost, version, "/bundles/{bundle_id}/invites", api.invite, audit("member.invite"), authen, ruleBundleAdmin)
+	app.HandlerFunc(http.MethodDelete, version, "/bundles/{bundle_id}/members/{user_id}", api.revoke, audit("member.revoke"), authen, ruleBundleAdmin)
+
+	app.HandlerFunc(http.MethodGet, version, "/invites", api.queryMyInvites, authen)
+	app.HandlerFunc(http.MethodPost, version, "/invites/{invite_id}/accept", api.accept, audit("member.accept"), authen, ruleInviteRecipient, transaction)
+	app.HandlerFunc(http.MethodPost, version, "/invites/{invite_id}/decline", api.decline, audit("member.decline"), authen, ruleInviteRecipient)
+}
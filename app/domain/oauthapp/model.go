@@ -0,0 +1,28 @@
+package oauthapp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+)
+
+// LinkCode contains the single-use code the browser is sent to the link
+// endpoint with to start linking an identity.
+type LinkCode struct {
+	Code      string `json:"code"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// Encode implements the encoder interface.
+func (app LinkCode) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppLinkCode(bus userbus.LinkCode) LinkCode {
+	return LinkCode{
+		Code:      bus.Code,
+		ExpiresAt: bus.DateExpires.UTC().Format(time.RFC3339),
+	}
+}
@@ -3,48 +3,138 @@ package oauthapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
+	"net/url"
 	"time"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/oidc"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 	"github.com/markbates/goth/providers/google"
 )
 
+// The link session remembers which logged in user started linking an
+// identity while the user is away at the provider.
+const (
+	linkSessionName = "_pwmanager_link"
+	linkUserKey     = "user_id"
+	linkMaxAge      = 10 * time.Minute
+)
+
 type app struct {
-	log      *logger.Logger
-	auth     *auth.Auth
-	tokenKey string
-	uiURL    string
-	apiHost  string
+	log            *logger.Logger
+	auth           *auth.Auth
+	userBus        *userbus.Business
+	sessionBus     *sessionbus.Business
+	oidc           *oidc.Provider
+	tokenKey       string
+	uiURL          string
+	apiHost        string
+	allowedDomains []string
 }
 
 func newApp(cfg Config) *app {
-	goth.UseProviders(
+	providers := []goth.Provider{
 		google.New(cfg.GoogleKey, cfg.GoogleSecret, fmt.Sprintf("%s/api/auth/google/callback", cfg.GoogleCallBackURL)),
-	)
+	}
+
+	if cfg.OIDC != nil {
+		providers = append(providers, cfg.OIDC.Goth())
+	}
+
+	goth.UseProviders(providers...)
 
 	gothic.GetProviderName = func(r *http.Request) (string, error) {
 		return web.Param(r, "provider"), nil
 	}
 
 	return &app{
-		auth:    cfg.Auth,
-		log:     cfg.Log,
-		uiURL:   cfg.GoogleUIURL,
-		apiHost: cfg.APIHost,
+		auth:           cfg.Auth,
+		log:            cfg.Log,
+		userBus:        cfg.UserBus,
+		sessionBus:     cfg.SessionBus,
+		oidc:           cfg.OIDC,
+		tokenKey:       cfg.TokenKey,
+		uiURL:          cfg.GoogleUIURL,
+		apiHost:        cfg.APIHost,
+		allowedDomains: cfg.AllowedDomains,
 	}
 }
 
 func (a *app) authenticate(ctx context.Context, r *http.Request) web.Encoder {
-	gothic.BeginAuthHandler(web.GetWriter(ctx), r)
+	w := web.GetWriter(ctx)
+
+	// A link flow the user abandoned must not turn this login into a link.
+	if _, err := a.consumeLinkSession(w, r); err != nil {
+		return errs.Newf(errs.Internal, "link session: %s", err)
+	}
+
+	gothic.BeginAuthHandler(w, r)
+
+	return web.NewNoResponse()
+}
+
+// linkCode mints the single-use code the logged in user's browser is sent to
+// the link endpoint with, so the user's token never has to be put in a URL.
+func (a *app) linkCode(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	lc, err := a.userBus.CreateLinkCode(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "createlinkcode: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppLinkCode(lc)
+}
+
+// link starts linking an identity at the provider to the user that minted
+// the link code the browser was sent here with.
+func (a *app) link(ctx context.Context, r *http.Request) web.Encoder {
+	w := web.GetWriter(ctx)
+
+	userID, err := a.userBus.ConsumeLinkCode(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		if errors.Is(err, userbus.ErrInvalidLinkCode) {
+			return errs.New(errs.Unauthenticated, userbus.ErrInvalidLinkCode)
+		}
+		return errs.Newf(errs.Internal, "consumelinkcode: %s", err)
+	}
+
+	mid.SetAuditActor(ctx, userID)
+
+	sess, _ := gothic.Store.New(r, linkSessionName)
+	sess.Values[linkUserKey] = userID.String()
+	sess.Options.MaxAge = int(linkMaxAge.Seconds())
+	sess.Options.HttpOnly = true
+
+	if err := sess.Save(r, w); err != nil {
+		return errs.Newf(errs.Internal, "save link session: %s", err)
+	}
+
+	gothic.BeginAuthHandler(w, r)
 
 	return web.NewNoResponse()
 }
@@ -52,34 +142,162 @@ func (a *app) authenticate(ctx context.Context, r *http.Request) web.Encoder {
 func (a *app) authCallback(ctx context.Context, r *http.Request) web.Encoder {
 	w := web.GetWriter(ctx)
 
-	user, err := gothic.CompleteUserAuth(w, r)
+	gu, err := gothic.CompleteUserAuth(w, r)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
-	clms := auth.Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   user.UserID,
-			Issuer:    a.auth.Issuer(),
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(2 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		},
-		Roles: []string{role.Admin.String()},
+	ei, err := toBusExternalIdentity(gu)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	linkUserID, err := a.consumeLinkSession(w, r)
+	if err != nil {
+		return errs.Newf(errs.Internal, "link session: %s", err)
+	}
+
+	if linkUserID != uuid.Nil {
+		return a.linkCallback(ctx, w, r, linkUserID, ei)
+	}
+
+	usr, err := a.userBus.AuthenticateIdentity(ctx, ei, a.allowedDomains)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrEmailNotVerified),
+			errors.Is(err, userbus.ErrDomainNotAllowed),
+			errors.Is(err, userbus.ErrNotFound):
+			return errs.Newf(errs.Unauthenticated, "identity is not linked to a user: %s", err)
+		}
+		return errs.Newf(errs.Internal, "authenticateidentity: %s", err)
+	}
+
+	mid.SetAuditActor(ctx, usr.ID)
+
+	if !usr.Enabled {
+		return errs.Newf(errs.Unauthenticated, "user disabled: userID[%s]", usr.ID)
+	}
+
+	providerRoles := a.providerRoles(gu)
+
+	// The callback has no step for a two-factor code, so users that enabled
+	// two-factor authentication or whose roles, including the ones granted by
+	// the provider, require it log in with their password instead.
+	mfaUsr := usr
+	mfaUsr.Roles = role.Merge(usr.Roles, providerRoles)
+
+	if err := a.userBus.AuthenticateMFA(ctx, mfaUsr, ""); err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrMFACodeRequired):
+			return errs.New(errs.Unauthenticated, userbus.ErrMFACodeRequired)
+		case errors.Is(err, userbus.ErrMFARequired):
+			return errs.New(errs.FailedPrecondition, userbus.ErrMFARequired)
+		}
+		return errs.Newf(errs.Internal, "authenticatemfa: userID[%s]: %s", usr.ID, err)
+	}
+
+	// The roles granted by the provider are kept with the session so they
+	// apply to the tokens the session is refreshed with as well.
+	ns := sessionbus.NewSession{
+		UserID:    usr.ID,
+		Roles:     providerRoles,
+		Device:    r.UserAgent(),
+		IPAddress: mid.RemoteIP(r),
+	}
+
+	sess, refreshToken, err := a.sessionBus.Create(ctx, ns)
+	if err != nil {
+		return errs.Newf(errs.Internal, "create: %s", err)
 	}
 
-	token, err := a.auth.GenerateToken(a.tokenKey, clms)
+	roles := role.Merge(usr.Roles, sess.Roles)
+
+	token, err := a.auth.GenerateToken(a.tokenKey, a.auth.SessionClaims(sess, role.ParseToString(roles)))
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
-	redirect := fmt.Sprintf("%s/app/admin?token=%s", a.uiURL, token)
-	a.log.Info(r.Context(), "REDIRECT", "redirect", redirect)
+	path := "/app"
+	for _, rl := range roles {
+		if rl == role.Admin {
+			path = "/app/admin"
+		}
+	}
+
+	// The tokens are passed in the fragment, which browsers neither send to
+	// servers nor put in the Referer header, so they stay out of logs.
+	fragment := url.Values{
+		"token":         {token},
+		"refresh_token": {refreshToken},
+	}
+
+	redirect := fmt.Sprintf("%s%s#%s", a.uiURL, path, fragment.Encode())
+	a.log.Info(ctx, "oauth login", "provider", ei.Provider, "userID", usr.ID)
+
+	http.Redirect(w, r, redirect, http.StatusFound)
+
+	return web.NewNoResponse()
+}
+
+// providerRoles returns the roles the OIDC provider's claims for the user
+// map to.
+func (a *app) providerRoles(gu goth.User) []role.Role {
+	if a.oidc == nil || gu.Provider != a.oidc.Name() {
+		return nil
+	}
+
+	return a.oidc.Roles(gu.RawData)
+}
+
+// linkCallback completes linking the identity to the user that started
+// the link flow.
+func (a *app) linkCallback(ctx context.Context, w http.ResponseWriter, r *http.Request, userID uuid.UUID, ei userbus.ExternalIdentity) web.Encoder {
+	mid.SetAuditActor(ctx, userID)
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, err)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if _, err := a.userBus.LinkIdentity(ctx, usr, ei); err != nil {
+		if errors.Is(err, userbus.ErrIdentityLinked) {
+			return errs.New(errs.Aborted, userbus.ErrIdentityLinked)
+		}
+		return errs.Newf(errs.Internal, "linkidentity: userID[%s]: %s", usr.ID, err)
+	}
+
+	redirect := fmt.Sprintf("%s/app?linked=%s", a.uiURL, url.QueryEscape(ei.Provider))
+	a.log.Info(ctx, "oauth link", "provider", ei.Provider, "userID", usr.ID)
 
 	http.Redirect(w, r, redirect, http.StatusFound)
 
 	return web.NewNoResponse()
 }
 
+// consumeLinkSession returns the user that started the link flow and
+// removes the link session. uuid.Nil is returned for a regular login.
+func (a *app) consumeLinkSession(w http.ResponseWriter, r *http.Request) (uuid.UUID, error) {
+	sess, err := gothic.Store.Get(r, linkSessionName)
+	if err != nil {
+		return uuid.Nil, nil
+	}
+
+	value, exists := sess.Values[linkUserKey].(string)
+	if !exists {
+		return uuid.Nil, nil
+	}
+
+	sess.Options.MaxAge = -1
+	if err := sess.Save(r, w); err != nil {
+		return uuid.Nil, fmt.Errorf("delete: %w", err)
+	}
+
+	return uuid.Parse(value)
+}
+
 func (a *app) logout(ctx context.Context, r *http.Request) web.Encoder {
 	w := web.GetWriter(ctx)
 
@@ -92,3 +310,32 @@ func (a *app) logout(ctx context.Context, r *http.Request) web.Encoder {
 
 	return web.NewNoResponse()
 }
+
+// toBusExternalIdentity converts the user returned by the provider. Google
+// reports whether the email is verified in the raw user info.
+func toBusExternalIdentity(gu goth.User) (userbus.ExternalIdentity, error) {
+	if gu.UserID == "" {
+		return userbus.ExternalIdentity{}, errors.New("provider did not return a subject")
+	}
+
+	addr, err := mail.ParseAddress(gu.Email)
+	if err != nil {
+		return userbus.ExternalIdentity{}, fmt.Errorf("parse email: %w", err)
+	}
+
+	var verified bool
+	for _, key := range []string{"verified_email", "email_verified"} {
+		if v, ok := gu.RawData[key].(bool); ok {
+			verified = v
+		}
+	}
+
+	ei := userbus.ExternalIdentity{
+		Provider:      gu.Provider,
+		Subject:       gu.UserID,
+		Email:         *addr,
+		EmailVerified: verified,
+	}
+
+	return ei, nil
+}
@@ -4,27 +4,52 @@ import (
 	"net/http"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/oidc"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
 )
 
-// Config contains all the configuration for the auth app.
+// Config contains all the configuration for the auth app. AllowedDomains
+// lists the email domains whose verified provider emails are matched to
+// existing users on first login, identities outside of them must be linked
+// by a logged in user. OIDC is an optional OpenID Connect provider whose
+// mapped roles are added to the roles of the user in issued tokens.
 type Config struct {
 	Auth              *auth.Auth
 	Log               *logger.Logger
+	UserBus           *userbus.Business
+	SessionBus        *sessionbus.Business
+	AuditBus          *auditbus.Business
 	TokenKey          string
 	GoogleKey         string
 	GoogleSecret      string
 	GoogleUIURL       string
 	GoogleCallBackURL string
 	APIHost           string
+	AllowedDomains    []string
+	OIDC              *oidc.Provider
 }
 
 // Routes adds the routes for the auth app.
 func Routes(app *web.App, cfg Config) {
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
+
+	bearer := mid.Bearer(cfg.Auth)
+
 	api := newApp(cfg)
 
+	// Linking starts with the UI minting a link code with the user's token in
+	// the authorization header, the browser is then sent to the link endpoint
+	// with the code instead of the token.
 	app.HandlerFunc(http.MethodGet, "", "/api/auth/{provider}", api.authenticate)
+	app.HandlerFunc(http.MethodPost, "", "/api/auth/{provider}/link", api.linkCode, audit("auth.linkcode"), bearer)
+	app.HandlerFunc(http.MethodGet, "", "/api/auth/{provider}/link", api.link, audit("auth.linkidentity"))
 	app.HandlerFunc(http.MethodGet, "", "/api/logout/{provider}", api.logout)
-	app.HandlerFunc(http.MethodGet, "", "/api/auth/{provider}/callback", api.authCallback)
+	app.HandlerFunc(http.MethodGet, "", "/api/auth/{provider}/callback", api.authCallback, audit("auth.oauthcallback"))
 }
@@ -0,0 +1,207 @@
+package syncapp
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+)
+
+// Changes represents the changes since a cursor. Cursor is opaque to clients
+// and is passed as the since query parameter on the next sync. HasMore tells
+// the client to sync again right away.
+type Changes struct {
+	Cursor     string      `json:"cursor"`
+	HasMore    bool        `json:"hasMore"`
+	Bundles    []Bundle    `json:"bundles"`
+	Keys       []Key       `json:"keys"`
+	Entries    []Entry     `json:"entries"`
+	Tombstones []Tombstone `json:"tombstones"`
+}
+
+// Encode implements the encoder interface.
+func (app Changes) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppChanges(c syncbus.Changes) Changes {
+	app := Changes{
+		Cursor:     formatCursor(c.Cursor),
+		HasMore:    c.HasMore,
+		Bundles:    make([]Bundle, len(c.Bundles)),
+		Keys:       make([]Key, len(c.Keys)),
+		Entries:    make([]Entry, len(c.Entries)),
+		Tombstones: make([]Tombstone, len(c.Tombstones)),
+	}
+
+	for i, b := range c.Bundles {
+		app.Bundles[i] = toAppBundle(b)
+	}
+
+	for i, k := range c.Keys {
+		app.Keys[i] = toAppKey(k)
+	}
+
+	for i, e := range c.Entries {
+		app.Entries[i] = toAppEntry(e)
+	}
+
+	for i, t := range c.Tombstones {
+		app.Tombstones[i] = toAppTombstone(t)
+	}
+
+	return app
+}
+
+// formatCursor encodes the cursor as the transaction id and the change
+// sequence separated by a dot.
+func formatCursor(c syncbus.Cursor) string {
+	return strconv.FormatInt(c.XID, 10) + "." + strconv.FormatInt(c.Seq, 10)
+}
+
+func parseCursor(v string) (syncbus.Cursor, error) {
+	xid, seq, ok := strings.Cut(v, ".")
+	if !ok {
+		return syncbus.Cursor{}, errors.New("missing change sequence")
+	}
+
+	c := syncbus.Cursor{}
+
+	var err error
+	if c.XID, err = strconv.ParseInt(xid, 10, 64); err != nil || c.XID < 0 {
+		return syncbus.Cursor{}, errors.New("invalid transaction id")
+	}
+
+	if c.Seq, err = strconv.ParseInt(seq, 10, 64); err != nil || c.Seq < 0 {
+		return syncbus.Cursor{}, errors.New("invalid change sequence")
+	}
+
+	return c, nil
+}
+
+// =============================================================================
+
+// Bundle represents a changed bundle.
+type Bundle struct {
+	ID            string `json:"id"`
+	UserID        string `json:"userID"`
+	Type          string `json:"type"`
+	Metadata      string `json:"metadata"`
+	KeyGeneration int    `json:"keyGeneration"`
+	Revision      int    `json:"revision"`
+	DateCreated   string `json:"dateCreated"`
+	DateUpdated   string `json:"dateUpdated"`
+	DateDeleted   string `json:"dateDeleted,omitempty"`
+}
+
+func toAppBundle(b bundlebus.Bundle) Bundle {
+	var dateDeleted string
+	if !b.DateDeleted.IsZero() {
+		dateDeleted = b.DateDeleted.Format(time.RFC3339)
+	}
+
+	return Bundle{
+		ID:            b.ID.String(),
+		UserID:        b.UserID.String(),
+		Type:          b.Type.String(),
+		Metadata:      b.Metadata,
+		KeyGeneration: b.KeyGeneration,
+		Revision:      b.Revision,
+		DateCreated:   b.DateCreated.Format(time.RFC3339),
+		DateUpdated:   b.DateUpdated.Format(time.RFC3339),
+		DateDeleted:   dateDeleted,
+	}
+}
+
+// =============================================================================
+
+// Key represents a changed key of the user.
+type Key struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"userID"`
+	BundleID    string   `json:"bundleID"`
+	Data        string   `json:"data"`
+	Roles       []string `json:"roles"`
+	Revision    int      `json:"revision"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+func toAppKey(k keybus.Key) Key {
+	return Key{
+		ID:          k.ID.String(),
+		UserID:      k.UserID.String(),
+		BundleID:    k.BundleID.String(),
+		Data:        k.Data.String(),
+		Roles:       bundlerole.ParseToString(k.Roles),
+		Revision:    k.Revision,
+		DateCreated: k.DateCreated.Format(time.RFC3339),
+		DateUpdated: k.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// Entry represents a changed entry.
+type Entry struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"userID"`
+	BundleID      string          `json:"bundleID"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+	KeyGeneration int             `json:"keyGeneration"`
+	Revision      int             `json:"revision"`
+	DateCreated   string          `json:"dateCreated"`
+	DateUpdated   string          `json:"dateUpdated"`
+	DateDeleted   string          `json:"dateDeleted,omitempty"`
+}
+
+func toAppEntry(e entrybus.Entry) Entry {
+	var dateDeleted string
+	if !e.DateDeleted.IsZero() {
+		dateDeleted = e.DateDeleted.Format(time.RFC3339)
+	}
+
+	return Entry{
+		ID:            e.ID.String(),
+		UserID:        e.UserID.String(),
+		BundleID:      e.BundleID.String(),
+		Type:          e.Type.String(),
+		SchemaVersion: e.SchemaVersion,
+		Data:          json.RawMessage(e.Data.String()),
+		KeyGeneration: e.KeyGeneration,
+		Revision:      e.Revision,
+		DateCreated:   e.DateCreated.Format(time.RFC3339),
+		DateUpdated:   e.DateUpdated.Format(time.RFC3339),
+		DateDeleted:   dateDeleted,
+	}
+}
+
+// =============================================================================
+
+// Tombstone represents a bundle, key or entry that was permanently removed.
+// A key tombstone means the user no longer has access to the bundle.
+type Tombstone struct {
+	Kind        string `json:"kind"`
+	ID          string `json:"id"`
+	BundleID    string `json:"bundleID"`
+	DateDeleted string `json:"dateDeleted"`
+}
+
+func toAppTombstone(t syncbus.Tombstone) Tombstone {
+	return Tombstone{
+		Kind:        t.Kind.String(),
+		ID:          t.ID.String(),
+		BundleID:    t.BundleID.String(),
+		DateDeleted: t.DateDeleted.Format(time.RFC3339),
+	}
+}
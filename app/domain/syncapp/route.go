@@ -0,0 +1,29 @@
+package syncapp
+
+import (
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	SyncBus    *syncbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+
+	api := newApp(cfg.SyncBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/sync", api.query, authen)
+}
@@ -0,0 +1,72 @@
+// Package syncapp maintains the app layer api for the sync domain.
+package syncapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+// Set of limits on the number of changes returned by a sync.
+const (
+	defaultLimit = 1000
+	maxLimit     = 5000
+)
+
+type app struct {
+	syncBus *syncbus.Business
+}
+
+func newApp(syncBus *syncbus.Business) *app {
+	return &app{
+		syncBus: syncBus,
+	}
+}
+
+// query returns the changes since the cursor in the since query parameter. A
+// missing cursor returns everything the user can access. The limit query
+// parameter bounds the number of changes returned, hasMore is set on the
+// response when the client has to sync again with the returned cursor.
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := r.URL.Query()
+
+	var since syncbus.Cursor
+	if v := qp.Get("since"); v != "" {
+		var err error
+		since, err = parseCursor(v)
+		if err != nil {
+			return errs.NewFieldErrors("since", errors.New("invalid cursor"))
+		}
+	}
+
+	limit := defaultLimit
+	if v := qp.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return errs.NewFieldErrors("limit", fmt.Errorf("must be between 1 and %d", maxLimit))
+		}
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	changes, err := a.syncBus.Query(ctx, userID, since, limit)
+	if err != nil {
+		if errors.Is(err, syncbus.ErrCursorExpired) {
+			return errs.New(errs.FailedPrecondition, syncbus.ErrCursorExpired)
+		}
+		return errs.Newf(errs.Internal, "query: userID[%s]: %s", userID, err)
+	}
+
+	return toAppChanges(changes)
+}
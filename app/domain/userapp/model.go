@@ -6,6 +6,7 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
@@ -55,6 +56,74 @@ func toAppUsers(users []userbus.User) []User {
 	return app
 }
 
+// NewUserResult represents a newly created user along with the one-time
+// registration token the user needs to complete registration.
+type NewUserResult struct {
+	User
+	RegistrationToken   string `json:"registrationToken"`
+	RegistrationExpires string `json:"registrationExpires"`
+}
+
+// Encode implements the encoder interface.
+func (app NewUserResult) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppNewUserResult(bus userbus.User, rt userbus.RegistrationToken) NewUserResult {
+	return NewUserResult{
+		User:                toAppUser(bus),
+		RegistrationToken:   rt.Token,
+		RegistrationExpires: rt.DateExpires.Format(time.RFC3339),
+	}
+}
+
+// UserUUK represents the user unlock key of the authenticated user. The UUK
+// only contains encrypted private key material and the public key.
+// NeedsUpgrade tells the client to re-wrap the UUK with the default key
+// derivation settings on the next unlock.
+type UserUUK struct {
+	UserID       string  `json:"userID"`
+	UUK          uuk.UUK `json:"uuk"`
+	NeedsUpgrade bool    `json:"needsUpgrade"`
+}
+
+// Encode implements the encoder interface.
+func (app UserUUK) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppUserUUK(bus userbus.User) UserUUK {
+	return UserUUK{
+		UserID:       bus.ID.String(),
+		UUK:          bus.UUK,
+		NeedsUpgrade: bus.UUK.NeedsUpgrade(uuk.DefaultKDFParams),
+	}
+}
+
+// PublicKey represents the public key of a user. It is used by bundle admins
+// to wrap a bundle key for another user.
+type PublicKey struct {
+	UserID string `json:"userID"`
+	Email  string `json:"email"`
+	PubKey any    `json:"pubKey"`
+}
+
+// Encode implements the encoder interface.
+func (app PublicKey) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppPublicKey(bus userbus.User) PublicKey {
+	return PublicKey{
+		UserID: bus.ID.String(),
+		Email:  bus.Email.Address,
+		PubKey: bus.UUK.PubKey,
+	}
+}
+
 // =============================================================================
 
 // NewUser defines the data needed to add a new user.
@@ -218,6 +287,44 @@ func toBusUpdateUser(app UpdateUser) (userbus.UpdateUser, error) {
 
 // =============================================================================
 
+// UpdateUserPassword defines the data needed to change a user's password.
+type UpdateUserPassword struct {
+	OldPassword     string  `json:"oldPassword" validate:"required"`
+	Password        string  `json:"password" validate:"required"`
+	PasswordConfirm string  `json:"passwordConfirm" validate:"eqfield=Password"`
+	UUK             uuk.UUK `json:"uuk" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateUserPassword) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateUserPassword) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateUserPassword(app UpdateUserPassword) (userbus.UpdateUserPassword, error) {
+	if err := app.UUK.EncSymKey.ValidateKDF(); err != nil {
+		return userbus.UpdateUserPassword{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := userbus.UpdateUserPassword{
+		OldPassword: app.OldPassword,
+		Password:    app.Password,
+		UUK:         app.UUK,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
 // Decode implements the decoder interface.
 func (app *RegisterUser) Decode(data []byte) error {
 	return json.Unmarshal(data, app)
@@ -234,13 +341,21 @@ func (app RegisterUser) Validate() error {
 
 // RegisterUser defines the data needed to register a user.
 type RegisterUser struct {
-	Token           string  `json:"token"`
+	Token           string  `json:"token" validate:"required"`
 	Password        string  `json:"password" validate:"required"`
 	PasswordConfirm string  `json:"passwordConfirm" validate:"eqfield=Password"`
 	UUK             uuk.UUK `json:"uuk" validate:"required"`
 }
 
 func toBusRegisterUser(app RegisterUser) (userbus.RegisterUser, error) {
+	if app.UUK.UUID == uuid.Nil {
+		return userbus.RegisterUser{}, fmt.Errorf("parse: uuk is missing a uuid")
+	}
+
+	if err := app.UUK.EncSymKey.ValidateKDF(); err != nil {
+		return userbus.RegisterUser{}, fmt.Errorf("parse: %w", err)
+	}
+
 	bus := userbus.RegisterUser{}
 
 	bus.Token = app.Token
@@ -249,3 +364,206 @@ func toBusRegisterUser(app RegisterUser) (userbus.RegisterUser, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+// EnableRecovery defines the data needed to turn on account recovery.
+type EnableRecovery struct {
+	UUK      uuk.UUK `json:"uuk" validate:"required"`
+	Verifier string  `json:"verifier"`
+}
+
+// Decode implements the decoder interface.
+func (app *EnableRecovery) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app EnableRecovery) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusEnableRecovery(app EnableRecovery) userbus.EnableRecovery {
+	return userbus.EnableRecovery{
+		UUK:      app.UUK,
+		Verifier: app.Verifier,
+	}
+}
+
+// =============================================================================
+
+// RecoverUser defines the data needed for a user to recover their account
+// with their recovery kit.
+type RecoverUser struct {
+	Email           string  `json:"email" validate:"required,email"`
+	Verifier        string  `json:"verifier" validate:"required"`
+	Password        string  `json:"password" validate:"required"`
+	PasswordConfirm string  `json:"passwordConfirm" validate:"eqfield=Password"`
+	UUK             uuk.UUK `json:"uuk" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *RecoverUser) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app RecoverUser) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusRecoverUser(app RecoverUser) (userbus.RecoverUser, error) {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return userbus.RecoverUser{}, fmt.Errorf("parse: %w", err)
+	}
+
+	if err := app.UUK.EncSymKey.ValidateKDF(); err != nil {
+		return userbus.RecoverUser{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := userbus.RecoverUser{
+		Email:    *addr,
+		Verifier: app.Verifier,
+		Password: app.Password,
+		UUK:      app.UUK,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// AdminRecoverUser defines the data needed for a recovery admin to recover
+// the account of a user.
+type AdminRecoverUser struct {
+	Password        string  `json:"password" validate:"required"`
+	PasswordConfirm string  `json:"passwordConfirm" validate:"eqfield=Password"`
+	UUK             uuk.UUK `json:"uuk" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *AdminRecoverUser) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AdminRecoverUser) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toBusAdminRecoverUser(app AdminRecoverUser) (userbus.AdminRecoverUser, error) {
+	if err := app.UUK.EncSymKey.ValidateKDF(); err != nil {
+		return userbus.AdminRecoverUser{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := userbus.AdminRecoverUser{
+		Password: app.Password,
+		UUK:      app.UUK,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// RecoveryEvent represents an entry in the recovery audit trail.
+type RecoveryEvent struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userID"`
+	ActorID     string `json:"actorID"`
+	Method      string `json:"method"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// RecoveryEvents is a collection wrapper that implements the Encoder interface.
+type RecoveryEvents []RecoveryEvent
+
+// Encode implements the encoder interface.
+func (app RecoveryEvents) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRecoveryEvents(events []userbus.RecoveryEvent) RecoveryEvents {
+	app := make(RecoveryEvents, len(events))
+	for i, re := range events {
+		app[i] = RecoveryEvent{
+			ID:          re.ID.String(),
+			UserID:      re.UserID.String(),
+			ActorID:     re.ActorID.String(),
+			Method:      re.Method,
+			DateCreated: re.DateCreated.Format(time.RFC3339),
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// Identity represents an external identity linked to a user.
+type Identity struct {
+	ID          string `json:"id"`
+	UserID      string `json:"userID"`
+	Provider    string `json:"provider"`
+	Subject     string `json:"subject"`
+	Email       string `json:"email"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Identities is a collection wrapper that implements the Encoder interface.
+type Identities []Identity
+
+// Encode implements the encoder interface.
+func (app Identities) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppIdentities(idns []userbus.Identity) Identities {
+	app := make(Identities, len(idns))
+	for i, idn := range idns {
+		app[i] = Identity{
+			ID:          idn.ID.String(),
+			UserID:      idn.UserID.String(),
+			Provider:    idn.Provider,
+			Subject:     idn.Subject,
+			Email:       idn.Email.Address,
+			DateCreated: idn.DateCreated.Format(time.RFC3339),
+		}
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// MFARoles lists the roles whose users must use two-factor authentication.
+type MFARoles struct {
+	Roles []string `json:"roles"`
+}
+
+// Encode implements the encoder interface.
+func (app MFARoles) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMFARoles(roles []role.Role) MFARoles {
+	return MFARoles{
+		Roles: role.ParseToString(roles),
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
+	"github.com/jmoiron/sqlx"
 )
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
 	Log        *logger.Logger
+	DB         *sqlx.DB
 	UserBus    *userbus.Business
+	AuditBus   *auditbus.Business
 	AuthClient *authclient.Client
 }
 
@@ -23,16 +28,45 @@ func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
 	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
 	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+	ruleAny := mid.Authorize(cfg.AuthClient, auth.RuleAny)
 	ruleAuthorizeUser := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOrSubject)
 	ruleAuthorizeAdmin := mid.AuthorizeUser(cfg.AuthClient, cfg.UserBus, auth.RuleAdminOnly)
 
+	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
+
 	api := newApp(cfg.UserBus)
 
 	app.HandlerFunc(http.MethodGet, version, "/users", api.query, authen, ruleAdmin)
 	app.HandlerFunc(http.MethodGet, version, "/users/{user_id}", api.queryByID, authen, ruleAuthorizeUser)
-	app.HandlerFunc(http.MethodPost, version, "/users", api.create, authen, ruleAdmin)
-	app.HandlerFunc(http.MethodPut, version, "/users/role/{user_id}", api.updateRole, authen, ruleAuthorizeAdmin)
-	app.HandlerFunc(http.MethodPut, version, "/users/{user_id}", api.update, authen, ruleAuthorizeUser)
-	app.HandlerFunc(http.MethodDelete, version, "/users/{user_id}", api.delete, authen, ruleAuthorizeUser)
+	app.HandlerFunc(http.MethodGet, version, "/users/uuk", api.queryUUK, authen, ruleAny)
+	app.HandlerFunc(http.MethodGet, version, "/users/pubkey/{user_id}", api.queryPublicKeyByID, authen, ruleAny)
+	app.HandlerFunc(http.MethodGet, version, "/users/pubkey/email/{email}", api.queryPublicKeyByEmail, authen, ruleAny)
+	app.HandlerFunc(http.MethodPost, version, "/users", api.create, audit("user.create"), authen, ruleAdmin, transaction)
+
+	// Registration is performed by invited users that don't have a token yet,
+	// the registration token in the payload authenticates the request.
+	app.HandlerFunc(http.MethodPost, version, "/register", api.register, audit("user.register"), transaction)
+	app.HandlerFunc(http.MethodPut, version, "/users/role/{user_id}", api.updateRole, audit("user.updaterole"), authen, ruleAuthorizeAdmin, transaction)
+	app.HandlerFunc(http.MethodPut, version, "/users/password", api.updatePassword, audit("user.updatepassword"), authen, ruleAny)
+	app.HandlerFunc(http.MethodPut, version, "/users/{user_id}", api.update, audit("user.update"), authen, ruleAuthorizeUser, transaction)
+	app.HandlerFunc(http.MethodDelete, version, "/users/{user_id}", api.delete, audit("user.delete"), authen, ruleAuthorizeUser)
+
+	app.HandlerFunc(http.MethodPut, version, "/users/recovery", api.enableRecovery, audit("user.enablerecovery"), authen, ruleAny, transaction)
+	app.HandlerFunc(http.MethodPost, version, "/users/recovery/{user_id}", api.recoverByAdmin, audit("user.recoverbyadmin"), authen, ruleAuthorizeAdmin, transaction)
+	app.HandlerFunc(http.MethodGet, version, "/users/recovery/events/{user_id}", api.queryRecoveryEvents, authen, ruleAuthorizeUser)
+
+	app.HandlerFunc(http.MethodGet, version, "/users/identities", api.queryIdentities, authen, ruleAny)
+	app.HandlerFunc(http.MethodDelete, version, "/users/identities/{identity_id}", api.unlinkIdentity, audit("user.unlinkidentity"), authen, ruleAny)
+
+	app.HandlerFunc(http.MethodGet, version, "/users/mfa/roles", api.queryMFARoles, authen, ruleAdmin)
+	app.HandlerFunc(http.MethodPut, version, "/users/mfa/roles/{role}", api.requireMFARole, audit("user.requiremfarole"), authen, ruleAdmin)
+	app.HandlerFunc(http.MethodDelete, version, "/users/mfa/roles/{role}", api.unrequireMFARole, audit("user.unrequiremfarole"), authen, ruleAdmin)
+
+	// Recovery is performed by users that lost their password, the recovery
+	// kit verifier in the payload authenticates the request.
+	app.HandlerFunc(http.MethodPost, version, "/recover", api.recover, audit("user.recover"), transaction)
 }
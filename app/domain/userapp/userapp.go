@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/mail"
 
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
@@ -12,7 +13,9 @@ import (
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/web"
+	"github.com/google/uuid"
 )
 
 type app struct {
@@ -25,12 +28,37 @@ func newApp(userBus *userbus.Business) *app {
 	}
 }
 
+// newWithTx constructs a new Handlers value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := a.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		userBus: userBus,
+	}
+
+	return &app, nil
+}
+
 func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
 	var app NewUser
 	if err := web.Decode(r, &app); err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	nc, err := toBusNewUser(app)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -44,6 +72,43 @@ func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "create: usr[%+v]: %s", usr, err)
 	}
 
+	rt, err := a.userBus.CreateRegistrationToken(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "createregistrationtoken: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppNewUserResult(usr, rt)
+}
+
+func (a *app) register(ctx context.Context, r *http.Request) web.Encoder {
+	var app RegisterUser
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ru, err := toBusRegisterUser(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userBus.Register(ctx, ru)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrInvalidRegistrationToken):
+			return errs.New(errs.Unauthenticated, userbus.ErrInvalidRegistrationToken)
+		case errors.Is(err, userbus.ErrExpiredRegistrationToken):
+			return errs.New(errs.Unauthenticated, userbus.ErrExpiredRegistrationToken)
+		case errors.Is(err, userbus.ErrInvalidUUK):
+			return errs.New(errs.InvalidArgument, userbus.ErrInvalidUUK)
+		}
+		return errs.Newf(errs.Internal, "register: %s", err)
+	}
+
 	return toAppUser(usr)
 }
 
@@ -58,6 +123,11 @@ func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	usr, err := mid.GetUser(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "user missing in context: %s", err)
@@ -82,6 +152,11 @@ func (a *app) updateRole(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
 	usr, err := mid.GetUser(ctx)
 	if err != nil {
 		return errs.Newf(errs.Internal, "user missing in context: %s", err)
@@ -95,6 +170,43 @@ func (a *app) updateRole(ctx context.Context, r *http.Request) web.Encoder {
 	return toAppUser(updUsr)
 }
 
+func (a *app) updatePassword(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateUserPassword
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	up, err := toBusUpdateUserPassword(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	updUsr, err := a.userBus.UpdatePassword(ctx, usr, up)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrAuthenticationFailure):
+			return errs.New(errs.Unauthenticated, userbus.ErrAuthenticationFailure)
+		case errors.Is(err, userbus.ErrNotRegistered):
+			return errs.New(errs.FailedPrecondition, userbus.ErrNotRegistered)
+		case errors.Is(err, userbus.ErrUUKMismatch):
+			return errs.New(errs.InvalidArgument, userbus.ErrUUKMismatch)
+		}
+		return errs.Newf(errs.Internal, "updatepassword: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppUser(updUsr)
+}
+
 func (a *app) delete(ctx context.Context, _ *http.Request) web.Encoder {
 	usr, err := mid.GetUser(ctx)
 	if err != nil {
@@ -150,3 +262,275 @@ func (a *app) queryByID(ctx context.Context, _ *http.Request) web.Encoder {
 
 	return toAppUser(usr)
 }
+
+func (a *app) queryUUK(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.NotFound, userbus.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if usr.UUK.UUID == uuid.Nil {
+		return errs.New(errs.NotFound, userbus.ErrNotRegistered)
+	}
+
+	return toAppUserUUK(usr)
+}
+
+func (a *app) queryPublicKeyByID(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, mid.ErrInvalidID)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.NotFound, userbus.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if usr.UUK.UUID == uuid.Nil {
+		return errs.New(errs.NotFound, userbus.ErrNotRegistered)
+	}
+
+	return toAppPublicKey(usr)
+}
+
+func (a *app) queryPublicKeyByEmail(ctx context.Context, r *http.Request) web.Encoder {
+	addr, err := mail.ParseAddress(web.Param(r, "email"))
+	if err != nil {
+		return errs.NewFieldErrors("email", err)
+	}
+
+	usr, err := a.userBus.QueryByEmail(ctx, *addr)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return errs.New(errs.NotFound, userbus.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "querybyemail: email[%s]: %s", addr.Address, err)
+	}
+
+	if usr.UUK.UUID == uuid.Nil {
+		return errs.New(errs.NotFound, userbus.ErrNotRegistered)
+	}
+
+	return toAppPublicKey(usr)
+}
+
+func (a *app) enableRecovery(ctx context.Context, r *http.Request) web.Encoder {
+	var app EnableRecovery
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	updUsr, err := a.userBus.EnableRecovery(ctx, usr, toBusEnableRecovery(app))
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrNotRegistered):
+			return errs.New(errs.FailedPrecondition, userbus.ErrNotRegistered)
+		case errors.Is(err, userbus.ErrUUKMismatch):
+			return errs.New(errs.InvalidArgument, userbus.ErrUUKMismatch)
+		case errors.Is(err, userbus.ErrRecoveryNotEnabled):
+			return errs.New(errs.InvalidArgument, userbus.ErrRecoveryNotEnabled)
+		}
+		return errs.Newf(errs.Internal, "enablerecovery: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppUser(updUsr)
+}
+
+func (a *app) recover(ctx context.Context, r *http.Request) web.Encoder {
+	var app RecoverUser
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	rr, err := toBusRecoverUser(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userBus.Recover(ctx, rr)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrAuthenticationFailure):
+			return errs.New(errs.Unauthenticated, userbus.ErrAuthenticationFailure)
+		case errors.Is(err, userbus.ErrUUKMismatch):
+			return errs.New(errs.InvalidArgument, userbus.ErrUUKMismatch)
+		}
+		return errs.Newf(errs.Internal, "recover: %s", err)
+	}
+
+	return toAppUser(usr)
+}
+
+func (a *app) recoverByAdmin(ctx context.Context, r *http.Request) web.Encoder {
+	var app AdminRecoverUser
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ar, err := toBusAdminRecoverUser(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := mid.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	adminID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	admin, err := a.userBus.QueryByID(ctx, adminID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", adminID, err)
+	}
+
+	updUsr, err := a.userBus.RecoverByAdmin(ctx, admin, usr, ar)
+	if err != nil {
+		switch {
+		case errors.Is(err, userbus.ErrRecoveryNotEnabled):
+			return errs.New(errs.PermissionDenied, userbus.ErrRecoveryNotEnabled)
+		case errors.Is(err, userbus.ErrNotRegistered):
+			return errs.New(errs.FailedPrecondition, userbus.ErrNotRegistered)
+		case errors.Is(err, userbus.ErrUUKMismatch):
+			return errs.New(errs.InvalidArgument, userbus.ErrUUKMismatch)
+		}
+		return errs.Newf(errs.Internal, "recoverbyadmin: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppUser(updUsr)
+}
+
+func (a *app) queryRecoveryEvents(ctx context.Context, _ *http.Request) web.Encoder {
+	usr, err := mid.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	events, err := a.userBus.QueryRecoveryEvents(ctx, usr.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryrecoveryevents: userID[%s]: %s", usr.ID, err)
+	}
+
+	return toAppRecoveryEvents(events)
+}
+
+func (a *app) queryIdentities(ctx context.Context, _ *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	idns, err := a.userBus.QueryIdentities(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryidentities: userID[%s]: %s", userID, err)
+	}
+
+	return toAppIdentities(idns)
+}
+
+func (a *app) queryMFARoles(ctx context.Context, _ *http.Request) web.Encoder {
+	roles, err := a.userBus.QueryMFARoles(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querymfaroles: %s", err)
+	}
+
+	return toAppMFARoles(roles)
+}
+
+func (a *app) requireMFARole(ctx context.Context, r *http.Request) web.Encoder {
+	return a.setMFARole(ctx, r, true)
+}
+
+func (a *app) unrequireMFARole(ctx context.Context, r *http.Request) web.Encoder {
+	return a.setMFARole(ctx, r, false)
+}
+
+func (a *app) setMFARole(ctx context.Context, r *http.Request, required bool) web.Encoder {
+	rl, err := role.Parse(web.Param(r, "role"))
+	if err != nil {
+		return errs.NewFieldErrors("role", err)
+	}
+
+	if err := a.userBus.SetMFARole(ctx, rl, required); err != nil {
+		return errs.Newf(errs.Internal, "setmfarole: role[%s]: %s", rl, err)
+	}
+
+	roles, err := a.userBus.QueryMFARoles(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querymfaroles: %s", err)
+	}
+
+	return toAppMFARoles(roles)
+}
+
+func (a *app) unlinkIdentity(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Unauthenticated, "userID not found: %s", err)
+	}
+
+	identityID, err := uuid.Parse(web.Param(r, "identity_id"))
+	if err != nil {
+		return errs.NewFieldErrors("identity_id", err)
+	}
+
+	idn, err := a.userBus.QueryIdentityByID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, userbus.ErrIdentityNotFound) {
+			return errs.New(errs.NotFound, userbus.ErrIdentityNotFound)
+		}
+		return errs.Newf(errs.Internal, "queryidentitybyid: identityID[%s]: %s", identityID, err)
+	}
+
+	// Users can only unlink their own identities, report the identities of
+	// other users as not found so their IDs can't be probed.
+	if idn.UserID != userID {
+		return errs.New(errs.NotFound, userbus.ErrIdentityNotFound)
+	}
+
+	if err := a.userBus.UnlinkIdentity(ctx, idn); err != nil {
+		return errs.Newf(errs.Internal, "unlinkidentity: identityID[%s]: %s", identityID, err)
+	}
+
+	return nil
+}
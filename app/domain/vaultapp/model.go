@@ -0,0 +1,71 @@
+package vaultapp
+
+import (
+	"encoding/json"
+
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+)
+
+// Archive represents a vault archive in its portable form. It is passed
+// through as is, the signature covers the exact bytes of the payload.
+type Archive []byte
+
+// Encode implements the encoder interface.
+func (app Archive) Encode() ([]byte, string, error) {
+	return app, "application/json", nil
+}
+
+// Decode implements the decoder interface.
+func (app *Archive) Decode(data []byte) error {
+	*app = data
+	return nil
+}
+
+// =============================================================================
+
+// Mapping pairs the id of a bundle or entry in the archive with the id it was
+// given when it was imported.
+type Mapping struct {
+	ArchiveID string `json:"archiveID"`
+	ID        string `json:"id"`
+}
+
+// ImportResult represents what an import added to the vault. Skipped holds
+// the archive ids of the bundles the user already had.
+type ImportResult struct {
+	Bundles []Mapping `json:"bundles"`
+	Entries []Mapping `json:"entries"`
+	Skipped []string  `json:"skipped"`
+}
+
+// Encode implements the encoder interface.
+func (app ImportResult) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppImportResult(res vaultbus.Result) ImportResult {
+	app := ImportResult{
+		Bundles: toAppMappings(res.Bundles),
+		Entries: toAppMappings(res.Entries),
+		Skipped: make([]string, len(res.Skipped)),
+	}
+
+	for i, id := range res.Skipped {
+		app.Skipped[i] = id.String()
+	}
+
+	return app
+}
+
+func toAppMappings(mappings []vaultbus.Mapping) []Mapping {
+	app := make([]Mapping, len(mappings))
+	for i, m := range mappings {
+		app[i] = Mapping{
+			ArchiveID: m.ArchiveID.String(),
+			ID:        m.ID.String(),
+		}
+	}
+
+	return app
+}
@@ -0,0 +1,39 @@
+package vaultapp
+
+import (
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	DB         *sqlx.DB
+	VaultBus   *vaultbus.Business
+	AuditBus   *auditbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	audit := func(action string) web.MidFunc {
+		return mid.Audit(cfg.Log, cfg.AuditBus, action)
+	}
+	transaction := mid.BeginCommitRollback(cfg.Log, sqldb.NewBeginner(cfg.DB))
+
+	api := newApp(cfg.VaultBus)
+
+	app.HandlerFunc(http.MethodGet, version, "/vault/export", api.export, audit("vault.export"), authen)
+	app.HandlerFunc(http.MethodPost, version, "/vault/import", api.importArchive, audit("vault.import"), authen, transaction)
+}
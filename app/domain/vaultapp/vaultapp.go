@@ -0,0 +1,111 @@
+// Package vaultapp maintains the app layer api for the vault domain.
+package vaultapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+type app struct {
+	vaultBus *vaultbus.Business
+}
+
+func newApp(vaultBus *vaultbus.Business) *app {
+	return &app{
+		vaultBus: vaultBus,
+	}
+}
+
+// newWithTx constructs a new Handlers value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultBus, err := a.vaultBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		vaultBus: vaultBus,
+	}
+
+	return &app, nil
+}
+
+// export returns a signed archive of the bundles the user has access to.
+func (a *app) export(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	archive, err := a.vaultBus.Export(ctx, userID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: userID[%s]: %s", userID, err)
+	}
+
+	data, err := a.vaultBus.Seal(archive)
+	if err != nil {
+		return errs.Newf(errs.Internal, "seal: userID[%s]: %s", userID, err)
+	}
+
+	filename := fmt.Sprintf("vault-%s.json", archive.DateExported.UTC().Format("20060102T150405Z"))
+	web.GetWriter(ctx).Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	return Archive(data)
+}
+
+// importArchive recreates the bundles of a signed archive for the user.
+func (a *app) importArchive(ctx context.Context, r *http.Request) web.Encoder {
+	var app Archive
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := mid.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	archive, err := a.vaultBus.Open(app)
+	if err != nil {
+		if errors.Is(err, vaultbus.ErrInvalidSignature) {
+			return errs.New(errs.InvalidArgument, vaultbus.ErrInvalidSignature)
+		}
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	res, err := a.vaultBus.Import(ctx, userID, archive)
+	if err != nil {
+		switch {
+		case errors.Is(err, vaultbus.ErrUserMismatch):
+			return errs.New(errs.PermissionDenied, vaultbus.ErrUserMismatch)
+		case errors.Is(err, vaultbus.ErrDuplicateID):
+			return errs.New(errs.InvalidArgument, vaultbus.ErrDuplicateID)
+		case errors.Is(err, vaultbus.ErrKeyGeneration):
+			return errs.New(errs.InvalidArgument, vaultbus.ErrKeyGeneration)
+		case errors.Is(err, entrybus.ErrSchemaVersion):
+			return errs.New(errs.InvalidArgument, entrybus.ErrSchemaVersion)
+		}
+		return errs.Newf(errs.Internal, "import: userID[%s]: %s", userID, err)
+	}
+
+	return toAppImportResult(res)
+}
@@ -0,0 +1,101 @@
+package vbundleapp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+)
+
+type queryParams struct {
+	Page     string
+	Rows     string
+	OrderBy  string
+	BundleID string
+	OwnerID  string
+	Type     string
+	Shared   string
+	Role     string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:     values.Get("page"),
+		Rows:     values.Get("rows"),
+		OrderBy:  values.Get("orderBy"),
+		BundleID: values.Get("bundle_id"),
+		OwnerID:  values.Get("owner_id"),
+		Type:     values.Get("type"),
+		Shared:   values.Get("shared"),
+		Role:     values.Get("role"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (vbundlebus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter vbundlebus.QueryFilter
+
+	if qp.BundleID != "" {
+		id, err := uuid.Parse(qp.BundleID)
+		switch err {
+		case nil:
+			filter.BundleID = &id
+		default:
+			fieldErrors.Add("bundle_id", err)
+		}
+	}
+
+	if qp.OwnerID != "" {
+		id, err := uuid.Parse(qp.OwnerID)
+		switch err {
+		case nil:
+			filter.OwnerID = &id
+		default:
+			fieldErrors.Add("owner_id", err)
+		}
+	}
+
+	if qp.Type != "" {
+		typ, err := bundletype.Parse(qp.Type)
+		switch err {
+		case nil:
+			filter.Type = &typ
+		default:
+			fieldErrors.Add("type", err)
+		}
+	}
+
+	if qp.Shared != "" {
+		shared, err := strconv.ParseBool(qp.Shared)
+		switch err {
+		case nil:
+			filter.Shared = &shared
+		default:
+			fieldErrors.Add("shared", err)
+		}
+	}
+
+	if qp.Role != "" {
+		role, err := bundlerole.Parse(qp.Role)
+		switch err {
+		case nil:
+			filter.Role = &role
+		default:
+			fieldErrors.Add("role", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return vbundlebus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
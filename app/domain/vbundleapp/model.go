@@ -19,27 +19,24 @@ type BundleUser struct {
 
 // Main structure for the query result
 type UserBundleKey struct {
+	KeyID       uuid.UUID    `json:"key_id"`
 	UserID      uuid.UUID    `json:"user_id"`
-	Name        string       `json:"name"`
 	BundleID    uuid.UUID    `json:"bundle_id"`
+	OwnerID     uuid.UUID    `json:"owner_id"`
+	OwnerName   string       `json:"owner_name"`
+	OwnerEmail  string       `json:"owner_email"`
 	Type        string       `json:"type"`
 	Metadata    string       `json:"metadata"`
 	DateCreated time.Time    `json:"date_created"`
 	DateUpdated time.Time    `json:"date_updated"`
 	KeyData     string       `json:"key_data"`
 	KeyRoles    []string     `json:"key_roles"`
+	EntryCount  int          `json:"entry_count"`
 	Users       []BundleUser `json:"users"`
 }
 
-type UserBundleKeys []UserBundleKey
-
-// Decode implements the decoder interface.
-func (app *UserBundleKeys) Decode(data []byte) error {
-	return json.Unmarshal(data, app)
-}
-
 // Encode implements the encoder interface.
-func (app UserBundleKeys) Encode() ([]byte, string, error) {
+func (app UserBundleKey) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(app)
 	return data, "application/json", err
 }
@@ -57,21 +54,25 @@ func toAppBundleUsers(bus []vbundlebus.BundleUser) []BundleUser {
 
 func toAppUserBundleKey(ub vbundlebus.UserBundleKey) UserBundleKey {
 	return UserBundleKey{
+		KeyID:       ub.KeyID,
 		UserID:      ub.UserID,
-		Name:        ub.Name,
 		BundleID:    ub.BundleID,
+		OwnerID:     ub.OwnerID,
+		OwnerName:   ub.OwnerName,
+		OwnerEmail:  ub.OwnerEmail,
 		Type:        ub.Type,
 		Metadata:    ub.Metadata,
 		KeyData:     ub.KeyData,
 		KeyRoles:    bundlerole.ParseToString(ub.KeyRoles),
+		EntryCount:  ub.EntryCount,
 		Users:       toAppBundleUsers(ub.Users),
 		DateCreated: ub.DateCreated,
 		DateUpdated: ub.DateUpdated,
 	}
 }
 
-func toAppUserBundleKeys(keys []vbundlebus.UserBundleKey) UserBundleKeys {
-	app := make(UserBundleKeys, len(keys))
+func toAppUserBundleKeys(keys []vbundlebus.UserBundleKey) []UserBundleKey {
+	app := make([]UserBundleKey, len(keys))
 	for i, k := range keys {
 		app[i] = toAppUserBundleKey(k)
 	}
@@ -0,0 +1,14 @@
+package vbundleapp
+
+import (
+	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+)
+
+var orderByFields = map[string]string{
+	"bundle_id":    vbundlebus.OrderByBundleID,
+	"type":         vbundlebus.OrderByType,
+	"owner_name":   vbundlebus.OrderByOwnerName,
+	"entry_count":  vbundlebus.OrderByEntryCount,
+	"date_created": vbundlebus.OrderByDateCreated,
+	"date_updated": vbundlebus.OrderByDateUpdated,
+}
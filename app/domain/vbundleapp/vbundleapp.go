@@ -7,7 +7,10 @@ import (
 
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/query"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/foundation/web"
 )
 
@@ -22,15 +25,40 @@ func newApp(vbundleBus *vbundlebus.Business) *app {
 }
 
 func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, vbundlebus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
 	userID, err := mid.GetUserID(ctx)
 	if err != nil {
 		return errs.Newf(errs.InvalidArgument, "userID not found: %s", err)
 	}
 
-	bdls, err := a.vbundleBus.QueryByID(ctx, userID)
+	// Users can only list the bundles they hold a key to, owned or shared.
+	filter.UserID = &userID
+
+	bdls, err := a.vbundleBus.Query(ctx, filter, orderBy, page)
 	if err != nil {
 		return errs.Newf(errs.Internal, "query: %s", err)
 	}
 
-	return toAppUserBundleKeys(bdls)
+	total, err := a.vbundleBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppUserBundleKeys(bdls), total, page)
 }
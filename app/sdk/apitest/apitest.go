@@ -42,6 +42,10 @@ func (at *Test) Run(t *testing.T, table []Table, testName string) {
 			}
 
 			r.Header.Set("Authorization", "Bearer "+tt.Token)
+			for k, v := range tt.Headers {
+				r.Header.Set(k, v)
+			}
+
 			at.mux.ServeHTTP(w, r)
 
 			if w.Code != tt.StatusCode {
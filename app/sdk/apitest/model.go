@@ -29,6 +29,7 @@ type Table struct {
 	Token      string
 	Method     string
 	StatusCode int
+	Headers    map[string]string
 	Input      any
 	GotResp    any
 	ExpResp    any
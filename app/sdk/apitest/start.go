@@ -10,6 +10,7 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mux"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
 )
 
 // New initialized the system to run a test.
@@ -33,9 +34,11 @@ func New(t *testing.T, testName string) *Test {
 		Log: db.Log,
 		DB:  db.DB,
 		BusConfig: mux.BusConfig{
-			UserBus:  db.BusDomain.User,
-			KeyBus:   db.BusDomain.Key,
-			EntryBus: db.BusDomain.Entry,
+			UserBus:    db.BusDomain.User,
+			KeyBus:     db.BusDomain.Key,
+			EntryBus:   db.BusDomain.Entry,
+			SessionBus: db.BusDomain.Session,
+			AuditBus:   db.BusDomain.Audit,
 		},
 		AuthConfig: mux.AuthConfig{
 			Auth: auth,
@@ -54,10 +57,15 @@ func New(t *testing.T, testName string) *Test {
 			BundleBus:  db.BusDomain.Bundle,
 			KeyBus:     db.BusDomain.Key,
 			EntryBus:   db.BusDomain.Entry,
+			MemberBus:  db.BusDomain.Member,
 			VBundleBus: db.BusDomain.VBundle,
+			SyncBus:    db.BusDomain.Sync,
+			VaultBus:   db.BusDomain.Vault,
+			AuditBus:   db.BusDomain.Audit,
 		},
 		PwManagerConfig: mux.PwManagerConfig{
-			AuthClient: authClient,
+			AuthClient:   authClient,
+			MaxEntrySize: entry.DefaultMaxSize,
 		},
 	}, pwmanagerbuild.Routes())
 
@@ -10,12 +10,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus/stores/sessiondb"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/usercache"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/userdb"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
-	"github.com/golang-jwt/jwt/v4"
-	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/open-policy-agent/opa/v1/rego"
 )
@@ -23,10 +25,13 @@ import (
 // ErrForbidden is returned when a auth issue is identified.
 var ErrForbidden = errors.New("attempted action is not allowed")
 
-// Claims represents the authorization claims transmitted via a JWT.
+// Claims represents the authorization claims transmitted via a JWT. Tokens
+// issued for a session carry the session ID and use the session's access ID
+// as the token ID.
 type Claims struct {
 	jwt.RegisteredClaims
-	Roles []string `json:"roles"`
+	Roles     []string `json:"roles"`
+	SessionID string   `json:"sid,omitempty"`
 }
 
 // KeyLookup declares a method set of behavior for looking up
@@ -48,31 +53,35 @@ type Config struct {
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
-	log       *logger.Logger
-	keyLookup KeyLookup
-	userBus   *userbus.Business
-	method    jwt.SigningMethod
-	parser    *jwt.Parser
-	issuer    string
+	log        *logger.Logger
+	keyLookup  KeyLookup
+	userBus    *userbus.Business
+	sessionBus *sessionbus.Business
+	method     jwt.SigningMethod
+	parser     *jwt.Parser
+	issuer     string
 }
 
 // New creates an Auth to support authentication/authorization.
 func New(cfg Config) (*Auth, error) {
 
 	// If a database connection is not provided, we won't perform the
-	// user enabled check.
+	// user enabled and revoked token checks.
 	var userBus *userbus.Business
+	var sessionBus *sessionbus.Business
 	if cfg.DB != nil {
 		userBus = userbus.NewBusiness(cfg.Log, nil, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB), 10*time.Minute))
+		sessionBus = sessionbus.NewBusiness(cfg.Log, nil, sessiondb.NewStore(cfg.Log, cfg.DB))
 	}
 
 	a := Auth{
-		log:       cfg.Log,
-		keyLookup: cfg.KeyLookup,
-		userBus:   userBus,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
-		issuer:    cfg.Issuer,
+		log:        cfg.Log,
+		keyLookup:  cfg.KeyLookup,
+		userBus:    userBus,
+		sessionBus: sessionBus,
+		method:     jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
+		parser:     jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
+		issuer:     cfg.Issuer,
 	}
 
 	return &a, nil
@@ -83,6 +92,23 @@ func (a *Auth) Issuer() string {
 	return a.issuer
 }
 
+// SessionClaims constructs the claims of the access token for the session.
+// The token uses the session's access ID as its ID so it can be revoked and
+// expires together with the access ID.
+func (a *Auth) SessionClaims(sess sessionbus.Session, roles []string) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sess.AccessID.String(),
+			Subject:   sess.UserID.String(),
+			Issuer:    a.issuer,
+			ExpiresAt: jwt.NewNumericDate(sess.AccessExpires.UTC()),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Roles:     roles,
+		SessionID: sess.ID.String(),
+	}
+}
+
 // GenerateToken generates a signed JWT token string representing the user Claims.
 func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
 	token := jwt.NewWithClaims(a.method, claims)
@@ -146,6 +172,12 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, fmt.Errorf("authentication failed : %w", err)
 	}
 
+	// Check the database for this token to verify it was not revoked.
+
+	if err := a.isTokenRevoked(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("token revoked : %w", err)
+	}
+
 	// Check the database for this user to verify they are still enabled.
 
 	if err := a.isUserEnabled(ctx, claims); err != nil {
@@ -202,6 +234,26 @@ func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule
 	return nil
 }
 
+// isTokenRevoked hits the database and checks the token was not revoked. If
+// no database connection was provided or the token has no ID, this check is
+// skipped.
+func (a *Auth) isTokenRevoked(ctx context.Context, claims Claims) error {
+	if a.sessionBus == nil || claims.ID == "" {
+		return nil
+	}
+
+	revoked, err := a.sessionBus.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("query token: %w", err)
+	}
+
+	if revoked {
+		return fmt.Errorf("token revoked")
+	}
+
+	return nil
+}
+
 // isUserEnabled hits the database and checks the user is not disabled. If the
 // no database connection was provided, this check is skipped.
 func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
@@ -116,6 +116,12 @@ var (
 	// system has been broken. If you see one of these errors,
 	// something is very broken. The error message is not sent to the client.
 	InternalOnlyLog = ErrCode{value: 19}
+
+	// Conflict indicates the operation was rejected because the resource has
+	// been modified since the caller read it, for example when the If-Match
+	// header of a request no longer matches the revision of the resource. The
+	// caller should read the resource again and merge its changes.
+	Conflict = ErrCode{value: 20}
 )
 
 var codeNumbers = map[string]ErrCode{
@@ -139,6 +145,7 @@ var codeNumbers = map[string]ErrCode{
 	"unauthenticated":     Unauthenticated,
 	"too_many_requests":   TooManyRequests,
 	"internal_only_log":   InternalOnlyLog,
+	"conflict":            Conflict,
 }
 
 var codeNames = map[ErrCode]string{
@@ -162,6 +169,7 @@ var codeNames = map[ErrCode]string{
 	Unauthenticated:    "unauthenticated",
 	TooManyRequests:    "too_many_requests",
 	InternalOnlyLog:    "internal_only_log",
+	Conflict:           "conflict",
 }
 
 var httpStatus = map[ErrCode]int{
@@ -185,4 +193,5 @@ var httpStatus = map[ErrCode]int{
 	Unauthenticated:    http.StatusUnauthorized,
 	TooManyRequests:    http.StatusTooManyRequests,
 	InternalOnlyLog:    http.StatusInternalServerError,
+	Conflict:           http.StatusConflict,
 }
@@ -0,0 +1,94 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+// Audit records the call as the specified action in the audit log. It must
+// be listed before the authentication and authorization middleware so denied
+// calls are recorded as well. The actor and targets are collected as the
+// rest of the middleware sets them in the context, falling back to the ids in
+// the path. Failing to write the record is logged and does not fail the call.
+func Audit(log *logger.Logger, auditBus *auditbus.Business, action string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			na := auditbus.NewAudit{
+				Action:    action,
+				IPAddress: RemoteIP(r),
+				UserAgent: r.UserAgent(),
+				TraceID:   otel.GetTraceID(ctx),
+			}
+
+			resp := next(setAudit(ctx, &na), r)
+
+			na.Status = http.StatusOK
+			if err := isError(resp); err != nil {
+				na.Status = http.StatusInternalServerError
+
+				var v *errs.Error
+				if errors.As(err, &v) {
+					na.Status = v.HTTPStatus()
+				}
+			}
+
+			auditParam(r, "bundle_id", &na.BundleID)
+			auditParam(r, "entry_id", &na.EntryID)
+			auditParam(r, "key_id", &na.KeyID)
+			auditParam(r, "user_id", &na.UserID)
+
+			if _, err := auditBus.Create(ctx, na); err != nil {
+				log.Error(ctx, "audit", "action", action, "ERROR", err)
+			}
+
+			return resp
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// SetAuditActor records the user as the actor of the call for handlers that
+// authenticate the user themselves, e.g. a token refresh or a provider
+// callback. It does nothing when the call is not audited.
+func SetAuditActor(ctx context.Context, userID uuid.UUID) {
+	if na := getAudit(ctx); na != nil {
+		na.ActorID = userID
+	}
+}
+
+// auditParam sets the target from the path parameter when the middleware
+// did not already set it.
+func auditParam(r *http.Request, key string, id *uuid.UUID) {
+	if *id != uuid.Nil {
+		return
+	}
+
+	v, err := uuid.Parse(web.Param(r, key))
+	if err != nil {
+		return
+	}
+
+	*id = v
+}
+
+// RemoteIP returns the address of the client that sent the request.
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
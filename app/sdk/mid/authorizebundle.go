@@ -10,6 +10,8 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/errs"
 
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 	"github.com/gradientsearch/pwmanager/foundation/web"
 )
 
@@ -63,3 +65,122 @@ func AuthorizeBundleModify(client *authclient.Client, bundleBus *bundlebus.Busin
 
 	return m
 }
+
+// AuthorizeBundleUndelete validates the user is the owner of the bundle in the
+// trash prior to moving it out of the trash.
+func AuthorizeBundleUndelete(client *authclient.Client, bundleBus *bundlebus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			// -------------------------------------------------------------------------
+			// Validate Input
+
+			bundleID, err := uuid.Parse(web.Param(r, "bundle_id"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Get bundle
+
+			bdl, err := bundleBus.QueryDeletedByID(ctx, bundleID)
+			if err != nil {
+				switch {
+				case errors.Is(err, bundlebus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querydeletedbyid: bundleID[%s]: %s", bundleID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Authorize
+
+			if userID != bdl.UserID {
+				return errs.Newf(errs.PermissionDenied, "only bundle owner can restore bundleID[%s]", bdl.ID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Set bundle
+
+			ctx = setBundle(ctx, bdl)
+
+			return next(ctx, r)
+		}
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeBundleAdmin validates the user holds the admin role on the bundle
+// prior to managing its members.
+func AuthorizeBundleAdmin(client *authclient.Client, bundleBus *bundlebus.Business, keyBus *keybus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			// -------------------------------------------------------------------------
+			// Validate Input
+
+			bundleID, err := uuid.Parse(web.Param(r, "bundle_id"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Authorize
+
+			k, err := keyBus.QueryByUserIDBundleID(ctx, userID, bundleID)
+			if err != nil {
+				switch {
+				case errors.Is(err, keybus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyuseridbundleid: user_id[%s] bundle_id[%s]: %s", userID, bundleID, err)
+				}
+			}
+
+			isAdmin := false
+			for _, r := range k.Roles {
+				if r.Equal(bundlerole.Admin) {
+					isAdmin = true
+					break
+				}
+			}
+			if !isAdmin {
+				return errs.Newf(errs.PermissionDenied, "must have admin perms for bundleID[%s]", bundleID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Get bundle
+
+			bdl, err := bundleBus.QueryByID(ctx, bundleID)
+			if err != nil {
+				switch {
+				case errors.Is(err, bundlebus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: bundleID[%s]: %s", bundleID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Set bundle
+
+			ctx = setBundle(ctx, bdl)
+
+			return next(ctx, r)
+		}
+		return h
+	}
+
+	return m
+}
@@ -85,6 +85,79 @@ func AuthorizeEntryRetrieve(client *authclient.Client, keyBus *keybus.Business,
 	return m
 }
 
+// AuthorizeEntryQuery validates a user has read permissions for a bundle prior
+// to listing its entries.
+func AuthorizeEntryQuery(client *authclient.Client, keyBus *keybus.Business, bundleBus *bundlebus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			// -------------------------------------------------------------------------
+			// Validate input
+
+			bundleID := web.Param(r, "bundle_id")
+			bID, err := uuid.Parse(bundleID)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Get User Key
+
+			k, err := keyBus.QueryByUserIDBundleID(ctx, userID, bID)
+			if err != nil {
+				switch {
+				case errors.Is(err, keybus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: userID[%s] bundleID[%s]: %s", userID, bID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Authorize
+
+			canRead := false
+			for _, r := range k.Roles {
+				if r.Equal(bundlerole.Read) {
+					canRead = true
+					break
+				}
+			}
+			if !canRead {
+				return errs.New(errs.PermissionDenied, fmt.Errorf("must have read perms for bundle[%s] to list entries", k.BundleID.String()))
+			}
+
+			// -------------------------------------------------------------------------
+			// Get Bundle
+
+			bdl, err := bundleBus.QueryByID(ctx, bID)
+			if err != nil {
+				switch {
+				case errors.Is(err, bundlebus.ErrNotFound):
+					return errs.New(errs.NotFound, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: bundleID[%s] : %s", bID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Set Bundle
+
+			ctx = setBundle(ctx, bdl)
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
 // AuthorizeEntryCreate validates the user is able to create an entry in the bundle.
 func AuthorizeEntryCreate(client *authclient.Client, keyBus *keybus.Business, entryBus *entrybus.Business, bundleBus *bundlebus.Business) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
@@ -245,3 +318,88 @@ func AuthorizeEntryModify(client *authclient.Client, keyBus *keybus.Business, en
 
 	return m
 }
+
+// AuthorizeEntryUndelete validates the user is able to modify the bundle of an
+// entry in the trash prior to moving it out of the trash.
+func AuthorizeEntryUndelete(client *authclient.Client, keyBus *keybus.Business, entryBus *entrybus.Business, bundleBus *bundlebus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			// -------------------------------------------------------------------------
+			// Validate Input
+
+			entryID := web.Param(r, "entry_id")
+			eID, err := uuid.Parse(entryID)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Get Entry
+
+			entry, err := entryBus.QueryDeletedByID(ctx, eID)
+			if err != nil {
+				switch {
+				case errors.Is(err, entrybus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, entrybus.ErrNotFound)
+				default:
+					return errs.Newf(errs.Internal, "querydeletedbyid: entryID[%s] : %s", eID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Authorize
+
+			k, err := keyBus.QueryByUserIDBundleID(ctx, userID, entry.BundleID)
+			if err != nil {
+				switch {
+				case errors.Is(err, keybus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: userID[%s] bundleID[%s]: %s", userID, entry.BundleID, err)
+				}
+			}
+
+			canWrite := false
+			for _, r := range k.Roles {
+				if r.Equal(bundlerole.Write) {
+					canWrite = true
+					break
+				}
+			}
+			if !canWrite {
+				return errs.New(errs.PermissionDenied, fmt.Errorf("must have write perms for bundle[%s] to restore an entry", k.BundleID.String()))
+			}
+
+			// -------------------------------------------------------------------------
+			// Get Bundle
+
+			// An entry can only be restored into a bundle that is not in the trash.
+			bdl, err := bundleBus.QueryByID(ctx, k.BundleID)
+			if err != nil {
+				switch {
+				case errors.Is(err, bundlebus.ErrNotFound):
+					return errs.New(errs.Unauthenticated, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: bundleID[%s] : %s", k.BundleID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Set Entry and Bundle
+
+			ctx = setBundle(ctx, bdl)
+			ctx = setEntry(ctx, entry)
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -0,0 +1,65 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
+	"github.com/gradientsearch/pwmanager/app/sdk/errs"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/foundation/web"
+)
+
+// AuthorizeInviteRecipient validates the user is the invitee prior to
+// accepting or declining the invite.
+func AuthorizeInviteRecipient(client *authclient.Client, memberBus *memberbus.Business) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			// -------------------------------------------------------------------------
+			// Validation
+
+			inviteID, err := uuid.Parse(web.Param(r, "invite_id"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			userID, err := GetUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Get invite
+
+			inv, err := memberBus.QueryByID(ctx, inviteID)
+			if err != nil {
+				switch {
+				case errors.Is(err, memberbus.ErrNotFound):
+					return errs.New(errs.PermissionDenied, err)
+				default:
+					return errs.Newf(errs.Internal, "querybyid: inviteID[%s]: %s", inviteID, err)
+				}
+			}
+
+			// -------------------------------------------------------------------------
+			// Authorize
+
+			if inv.InviteeID != userID {
+				return errs.Newf(errs.PermissionDenied, "only the invitee can respond to inviteID[%s]", inv.ID)
+			}
+
+			// -------------------------------------------------------------------------
+			// Set invite
+
+			ctx = setInvite(ctx, inv)
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
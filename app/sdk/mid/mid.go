@@ -7,9 +7,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
 	"github.com/gradientsearch/pwmanager/foundation/web"
@@ -35,7 +37,9 @@ const (
 	keyKey
 	entryKey
 	bundleKey
+	inviteKey
 	trKey
+	auditKey
 )
 
 func setClaims(ctx context.Context, claims auth.Claims) context.Context {
@@ -52,6 +56,10 @@ func GetClaims(ctx context.Context) auth.Claims {
 }
 
 func setUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	if na := getAudit(ctx); na != nil {
+		na.ActorID = userID
+	}
+
 	return context.WithValue(ctx, userIDKey, userID)
 }
 
@@ -66,6 +74,10 @@ func GetUserID(ctx context.Context) (uuid.UUID, error) {
 }
 
 func setUser(ctx context.Context, usr userbus.User) context.Context {
+	if na := getAudit(ctx); na != nil {
+		na.UserID = usr.ID
+	}
+
 	return context.WithValue(ctx, userKey, usr)
 }
 
@@ -80,6 +92,11 @@ func GetUser(ctx context.Context) (userbus.User, error) {
 }
 
 func setKey(ctx context.Context, k keybus.Key) context.Context {
+	if na := getAudit(ctx); na != nil {
+		na.KeyID = k.ID
+		na.BundleID = k.BundleID
+	}
+
 	return context.WithValue(ctx, keyKey, k)
 }
 
@@ -94,6 +111,13 @@ func GetKey(ctx context.Context) (keybus.Key, error) {
 }
 
 func setEntry(ctx context.Context, k entrybus.Entry) context.Context {
+	if na := getAudit(ctx); na != nil {
+		if k.ID != uuid.Nil {
+			na.EntryID = k.ID
+		}
+		na.BundleID = k.BundleID
+	}
+
 	return context.WithValue(ctx, entryKey, k)
 }
 
@@ -108,6 +132,10 @@ func GetEntry(ctx context.Context) (entrybus.Entry, error) {
 }
 
 func setBundle(ctx context.Context, bdl bundlebus.Bundle) context.Context {
+	if na := getAudit(ctx); na != nil {
+		na.BundleID = bdl.ID
+	}
+
 	return context.WithValue(ctx, bundleKey, bdl)
 }
 
@@ -121,6 +149,20 @@ func GetBundle(ctx context.Context) (bundlebus.Bundle, error) {
 	return v, nil
 }
 
+func setInvite(ctx context.Context, inv memberbus.Invite) context.Context {
+	return context.WithValue(ctx, inviteKey, inv)
+}
+
+// GetInvite returns the invite from the context.
+func GetInvite(ctx context.Context) (memberbus.Invite, error) {
+	v, ok := ctx.Value(inviteKey).(memberbus.Invite)
+	if !ok {
+		return memberbus.Invite{}, errors.New("invite not found in context")
+	}
+
+	return v, nil
+}
+
 func setTran(ctx context.Context, tx sqldb.CommitRollbacker) context.Context {
 	return context.WithValue(ctx, trKey, tx)
 }
@@ -134,3 +176,19 @@ func GetTran(ctx context.Context) (sqldb.CommitRollbacker, error) {
 
 	return v, nil
 }
+
+// setAudit stores the audit record being collected for the call. The record
+// is shared by pointer so the setters above can fill it in as the rest of the
+// middleware runs.
+func setAudit(ctx context.Context, na *auditbus.NewAudit) context.Context {
+	return context.WithValue(ctx, auditKey, na)
+}
+
+func getAudit(ctx context.Context) *auditbus.NewAudit {
+	v, ok := ctx.Value(auditKey).(*auditbus.NewAudit)
+	if !ok {
+		return nil
+	}
+
+	return v
+}
@@ -9,10 +9,16 @@ import (
 	"github.com/gradientsearch/pwmanager/app/sdk/auth"
 	"github.com/gradientsearch/pwmanager/app/sdk/authclient"
 	"github.com/gradientsearch/pwmanager/app/sdk/mid"
+	"github.com/gradientsearch/pwmanager/app/sdk/oidc"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/web"
@@ -55,12 +61,27 @@ func WithFileServer(react bool, static embed.FS, dir string, path string) func(o
 
 // PwManagerConfig contains pwmanager service specific config.
 type PwManagerConfig struct {
-	AuthClient *authclient.Client
+	AuthClient   *authclient.Client
+	MaxEntrySize int
 }
 
 // AuthConfig contains auth service specific config.
 type AuthConfig struct {
-	Auth *auth.Auth
+	Auth  *auth.Auth
+	OAuth OAuthConfig
+}
+
+// OAuthConfig contains the config for logging in through external identity
+// providers.
+type OAuthConfig struct {
+	TokenKey       string
+	GoogleKey      string
+	GoogleSecret   string
+	UIURL          string
+	CallbackURL    string
+	APIHost        string
+	AllowedDomains []string
+	OIDC           *oidc.Provider
 }
 
 type BusConfig struct {
@@ -68,7 +89,12 @@ type BusConfig struct {
 	BundleBus  *bundlebus.Business
 	KeyBus     *keybus.Business
 	EntryBus   *entrybus.Business
+	MemberBus  *memberbus.Business
 	VBundleBus *vbundlebus.Business
+	SyncBus    *syncbus.Business
+	VaultBus   *vaultbus.Business
+	AuditBus   *auditbus.Business
+	SessionBus *sessionbus.Business
 }
 
 // Config contains all the mandatory systems required by handlers.
@@ -0,0 +1,168 @@
+// Package oidc provides support for logging in through a generic OpenID
+// Connect identity provider such as Keycloak or Dex.
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gradientsearch/pwmanager/business/types/role"
+	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/openidConnect"
+)
+
+// DefaultScopes are requested when no scopes are configured. The email scope
+// is required to match identities to users.
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// Config represents the configuration of an OpenID Connect provider. Name is
+// the provider name used in the oauth routes. The provider endpoints are
+// discovered from the IssuerURL. RoleClaim names the claim, using dots for
+// nested claims such as realm_access.roles, whose values are mapped to roles
+// through RoleMapping.
+type Config struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	CallbackURL  string
+	Scopes       []string
+	RoleClaim    string
+	RoleMapping  map[string]role.Role
+}
+
+// Provider represents an OpenID Connect identity provider that can map the
+// claims of a user to roles.
+type Provider struct {
+	goth        *openidConnect.Provider
+	roleClaim   string
+	roleMapping map[string]role.Role
+}
+
+// New constructs a provider by running discovery against the issuer.
+func New(cfg Config) (*Provider, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if cfg.IssuerURL == "" {
+		return nil, errors.New("issuer url is required")
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	issuer := strings.TrimSuffix(cfg.IssuerURL, "/")
+	discoveryURL := issuer + "/.well-known/openid-configuration"
+
+	p, err := openidConnect.New(cfg.ClientID, cfg.ClientSecret, cfg.CallbackURL, discoveryURL, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+
+	// The issuer in the discovery document must match the configured issuer
+	// so a document served from elsewhere can't redirect the login.
+	if strings.TrimSuffix(p.OpenIDConfig.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovered issuer %q does not match %q", p.OpenIDConfig.Issuer, cfg.IssuerURL)
+	}
+
+	p.SetName(cfg.Name)
+
+	prv := Provider{
+		goth:        p,
+		roleClaim:   cfg.RoleClaim,
+		roleMapping: cfg.RoleMapping,
+	}
+
+	return &prv, nil
+}
+
+// Name returns the name of the provider.
+func (p *Provider) Name() string {
+	return p.goth.Name()
+}
+
+// Goth returns the provider to register with goth. The goth sessions of the
+// provider only work with the provider goth constructed.
+func (p *Provider) Goth() goth.Provider {
+	return p.goth
+}
+
+// Roles returns the roles the claims of a user map to. No roles are returned
+// when no role claim is configured or none of its values are mapped.
+func (p *Provider) Roles(claims map[string]any) []role.Role {
+	if p.roleClaim == "" {
+		return nil
+	}
+
+	var roles []role.Role
+	seen := make(map[role.Role]bool)
+
+	for _, value := range claimValues(claims, p.roleClaim) {
+		r, exists := p.roleMapping[value]
+		if !exists || seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		roles = append(roles, r)
+	}
+
+	return roles
+}
+
+// ParseRoleMapping parses role mappings of the form value:ROLE, such as
+// pwmanager-admins:ADMIN.
+func ParseRoleMapping(mappings []string) (map[string]role.Role, error) {
+	m := make(map[string]role.Role, len(mappings))
+
+	for _, mapping := range mappings {
+		i := strings.LastIndex(mapping, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid role mapping %q", mapping)
+		}
+
+		r, err := role.Parse(mapping[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("role mapping %q: %w", mapping, err)
+		}
+
+		m[mapping[:i]] = r
+	}
+
+	return m, nil
+}
+
+// claimValues returns the string values of the claim at the dotted path.
+// A claim can hold a single string or a list of strings.
+func claimValues(claims map[string]any, path string) []string {
+	var value any = claims
+
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		value = obj[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package oidc_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/app/sdk/oidc"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	clientID     = "pwmanager"
+	clientSecret = "secret"
+	code         = "auth-code"
+	accessToken  = "access-token"
+	subject      = "0a6c7c2a-9f43-4d2c-8e8a-1f3c5d3b1e11"
+)
+
+func Test_OIDC(t *testing.T) {
+	iss := newIssuer(t)
+
+	t.Run("login", login(iss))
+	t.Run("issuer-mismatch", issuerMismatch(iss))
+	t.Run("roles", roles(iss))
+	t.Run("role-mapping", roleMapping())
+}
+
+func login(iss *issuer) func(t *testing.T) {
+	f := func(t *testing.T) {
+		prv := iss.provider(t, "realm_access.roles")
+
+		if prv.Name() != "keycloak" {
+			t.Fatalf("Should use the configured name: got %q", prv.Name())
+		}
+
+		sess, err := prv.Goth().BeginAuth("state")
+		if err != nil {
+			t.Fatalf("Should be able to begin auth: %s", err)
+		}
+
+		authURL, err := sess.GetAuthURL()
+		if err != nil {
+			t.Fatalf("Should be able to get the auth url: %s", err)
+		}
+
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatalf("Should be able to parse the auth url: %s", err)
+		}
+
+		if got := u.Scheme + "://" + u.Host + u.Path; got != iss.URL+"/authorize" {
+			t.Errorf("Should use the discovered authorization endpoint: got %q", got)
+		}
+
+		if got := u.Query().Get("client_id"); got != clientID {
+			t.Errorf("Should send the client id: got %q", got)
+		}
+
+		if got := u.Query().Get("scope"); got != "openid email profile" {
+			t.Errorf("Should request the default scopes: got %q", got)
+		}
+
+		if _, err := sess.Authorize(prv.Goth(), url.Values{"code": {code}}); err != nil {
+			t.Fatalf("Should be able to exchange the code: %s", err)
+		}
+
+		usr, err := prv.Goth().FetchUser(sess)
+		if err != nil {
+			t.Fatalf("Should be able to fetch the user: %s", err)
+		}
+
+		if usr.UserID != subject {
+			t.Errorf("Should use the subject as the user id: got %q", usr.UserID)
+		}
+
+		if usr.Email != "bill@example.com" {
+			t.Errorf("Should merge the email from userinfo: got %q", usr.Email)
+		}
+
+		if usr.Provider != "keycloak" {
+			t.Errorf("Should report the configured provider name: got %q", usr.Provider)
+		}
+
+		if verified, _ := usr.RawData["email_verified"].(bool); !verified {
+			t.Errorf("Should report the email as verified: got %v", usr.RawData["email_verified"])
+		}
+
+		exp := []role.Role{role.Admin}
+		if diff := cmp.Diff(prv.Roles(usr.RawData), exp); diff != "" {
+			t.Errorf("Should map the role claim:\n%s", diff)
+		}
+	}
+
+	return f
+}
+
+func issuerMismatch(iss *issuer) func(t *testing.T) {
+	f := func(t *testing.T) {
+		_, err := oidc.New(oidc.Config{
+			Name:      "keycloak",
+			IssuerURL: iss.URL + "/realms/other",
+			ClientID:  clientID,
+		})
+		if err == nil {
+			t.Fatalf("Should not accept discovery that names another issuer")
+		}
+	}
+
+	return f
+}
+
+func roles(iss *issuer) func(t *testing.T) {
+	f := func(t *testing.T) {
+		claims := map[string]any{
+			"groups": []any{"staff", "pwmanager-admins", "staff"},
+			"realm_access": map[string]any{
+				"roles": "pwmanager-users",
+			},
+		}
+
+		table := []struct {
+			name  string
+			claim string
+			exp   []role.Role
+		}{
+			{name: "list", claim: "groups", exp: []role.Role{role.User, role.Admin}},
+			{name: "nested", claim: "realm_access.roles", exp: []role.Role{role.User}},
+			{name: "missing", claim: "departments", exp: nil},
+			{name: "none", claim: "", exp: nil},
+		}
+
+		for _, tt := range table {
+			prv := iss.provider(t, tt.claim)
+
+			if diff := cmp.Diff(prv.Roles(claims), tt.exp); diff != "" {
+				t.Errorf("%s: Should map the claim values to roles:\n%s", tt.name, diff)
+			}
+		}
+	}
+
+	return f
+}
+
+func roleMapping() func(t *testing.T) {
+	f := func(t *testing.T) {
+		m, err := oidc.ParseRoleMapping([]string{"pwmanager-admins:ADMIN", "urn:team:staff:USER"})
+		if err != nil {
+			t.Fatalf("Should be able to parse the role mapping: %s", err)
+		}
+
+		exp := map[string]role.Role{
+			"pwmanager-admins": role.Admin,
+			"urn:team:staff":   role.User,
+		}
+
+		if diff := cmp.Diff(m, exp); diff != "" {
+			t.Errorf("Should parse the role mapping:\n%s", diff)
+		}
+
+		for _, bad := range []string{"ADMIN", ":ADMIN", "admins:OWNER"} {
+			if _, err := oidc.ParseRoleMapping([]string{bad}); err == nil {
+				t.Errorf("Should not parse the role mapping %q", bad)
+			}
+		}
+	}
+
+	return f
+}
+
+// =============================================================================
+
+// issuer is an in-process fake OpenID Connect issuer that serves discovery,
+// the token endpoint and the userinfo endpoint for a single user. The
+// discovery document served under /realms/other names the root issuer.
+type issuer struct {
+	*httptest.Server
+}
+
+func newIssuer(t *testing.T) *issuer {
+	iss := issuer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", iss.discovery)
+	mux.HandleFunc("GET /realms/other/.well-known/openid-configuration", iss.discovery)
+	mux.HandleFunc("POST /token", iss.token)
+	mux.HandleFunc("GET /userinfo", iss.userinfo)
+
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+
+	return &iss
+}
+
+func (iss *issuer) provider(t *testing.T, roleClaim string) *oidc.Provider {
+	prv, err := oidc.New(oidc.Config{
+		Name:         "keycloak",
+		IssuerURL:    iss.URL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		CallbackURL:  "http://localhost:6000/api/auth/keycloak/callback",
+		RoleClaim:    roleClaim,
+		RoleMapping: map[string]role.Role{
+			"pwmanager-admins": role.Admin,
+			"pwmanager-users":  role.User,
+			"staff":            role.User,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to construct the provider: %s", err)
+	}
+
+	return prv
+}
+
+func (iss *issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"issuer":                 iss.URL,
+		"authorization_endpoint": iss.URL + "/authorize",
+		"token_endpoint":         iss.URL + "/token",
+		"userinfo_endpoint":      iss.URL + "/userinfo",
+	})
+}
+
+func (iss *issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != code {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	id, pass, _ := r.BasicAuth()
+	if id != clientID || pass != clientSecret {
+		id, pass = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	if id != clientID || pass != clientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss": iss.URL,
+		"sub": subject,
+		"aud": clientID,
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"iat": time.Now().Unix(),
+		"realm_access": map[string]any{
+			"roles": []string{"offline_access", "pwmanager-admins"},
+		},
+	}
+
+	idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(clientSecret))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (iss *issuer) userinfo(w http.ResponseWriter, r *http.Request) {
+	if strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != accessToken {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"sub":            subject,
+		"email":          "bill@example.com",
+		"email_verified": true,
+		"name":           "Bill Kennedy",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
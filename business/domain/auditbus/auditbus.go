@@ -0,0 +1,137 @@
+// Package auditbus provides business access to the audit log, the record of
+// who read, created, shared or deleted what.
+package auditbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound      = errors.New("audit not found")
+	ErrChainConflict = errors.New("audit chain extended concurrently")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Append(ctx context.Context, link func(last Audit) Audit) (Audit, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryLast(ctx context.Context) (Audit, error)
+	QueryChainStart(ctx context.Context) (int64, error)
+	QueryChain(ctx context.Context, afterSequence int64, limit int) ([]Audit, error)
+	CreateCheckpoint(ctx context.Context, cp Checkpoint) error
+	QueryLastCheckpoint(ctx context.Context) (Checkpoint, error)
+	QueryCheckpoints(ctx context.Context) ([]Checkpoint, error)
+}
+
+// Business manages the set of APIs for audit access.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+}
+
+// NewBusiness constructs an audit business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		delegate: b.delegate,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Create records an action in the audit log, linking it to the last record
+// of the chain. The store appends one record at a time so the chain never
+// forks.
+func (b *Business) Create(ctx context.Context, na NewAudit) (Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.create")
+	defer span.End()
+
+	// The database stores timestamps with microsecond precision, the time is
+	// truncated so the hash can be recomputed from the stored record.
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	a := Audit{
+		ID:          uuid.New(),
+		ActorID:     na.ActorID,
+		Action:      na.Action,
+		BundleID:    na.BundleID,
+		EntryID:     na.EntryID,
+		KeyID:       na.KeyID,
+		UserID:      na.UserID,
+		Status:      na.Status,
+		IPAddress:   na.IPAddress,
+		UserAgent:   na.UserAgent,
+		TraceID:     na.TraceID,
+		DateCreated: now,
+	}
+
+	a, err := b.storer.Append(ctx, func(last Audit) Audit {
+		a.Sequence = last.Sequence + 1
+		a.PrevHash = last.Hash
+		a.Hash = hashAudit(a)
+
+		return a
+	})
+	if err != nil {
+		return Audit{}, fmt.Errorf("append: %w", err)
+	}
+
+	return a, nil
+}
+
+// Query retrieves a list of recorded actions.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.query")
+	defer span.End()
+
+	audits, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return audits, nil
+}
+
+// Count returns the total number of recorded actions.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
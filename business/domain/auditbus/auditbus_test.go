@@ -0,0 +1,523 @@
+package auditbus_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Audit")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, event(db, sd), "event")
+	unitest.Run(t, chain(db, newKeyStore(t)), "chain")
+}
+
+// =============================================================================
+
+type seedData struct {
+	unitest.SeedData
+	Audits [][]auditbus.Audit
+}
+
+func insertSeedData(busDomain dbtest.BusDomain) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := seedData{
+		Audits: make([][]auditbus.Audit, len(usrs)),
+	}
+
+	for i, usr := range usrs {
+		bdls, err := bundlebus.TestGenerateSeedBundles(ctx, 1, busDomain.Bundle, usr.ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding bundles : %w", err)
+		}
+
+		audits, err := auditbus.TestGenerateSeedAudits(ctx, 4, busDomain.Audit, usr.ID, bdls[0].ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding audits : %w", err)
+		}
+
+		tu := unitest.User{
+			User:    usr,
+			Bundles: bdls,
+		}
+
+		sd.Users = append(sd.Users, tu)
+		sd.Audits[i] = audits
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+// auditIDs returns the ids of the audits sorted the way the query orders
+// them by id.
+func auditIDs(audits []auditbus.Audit) []uuid.UUID {
+	ids := make([]uuid.UUID, len(audits))
+	for i, a := range audits {
+		ids[i] = a.ID
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	return ids
+}
+
+func cmpIDs(got any, exp any) string {
+	gotResp, exists := got.([]uuid.UUID)
+	if !exists {
+		return fmt.Sprintf("error occurred: %v", got)
+	}
+
+	return cmp.Diff(gotResp, exp)
+}
+
+func query(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	orderBy := order.NewBy(auditbus.OrderByAuditID, order.ASC)
+	pg := page.MustParse("1", "10")
+
+	search := func(ctx context.Context, filter auditbus.QueryFilter) any {
+		resp, err := busDomain.Audit.Query(ctx, filter, orderBy, pg)
+		if err != nil {
+			return err
+		}
+
+		return auditIDs(resp)
+	}
+
+	action := "entry.read"
+	past := time.Now().Add(-time.Hour)
+
+	table := []unitest.Table{
+		{
+			Name:    "bundle",
+			ExpResp: auditIDs(sd.Audits[0]),
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, auditbus.QueryFilter{BundleID: &sd.Users[0].Bundles[0].ID})
+			},
+			CmpFunc: cmpIDs,
+		},
+		{
+			Name:    "actor",
+			ExpResp: auditIDs(sd.Audits[1]),
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, auditbus.QueryFilter{ActorID: &sd.Users[1].ID})
+			},
+			CmpFunc: cmpIDs,
+		},
+		{
+			Name:    "action",
+			ExpResp: auditIDs([]auditbus.Audit{sd.Audits[0][1]}),
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, auditbus.QueryFilter{ActorID: &sd.Users[0].ID, Action: &action})
+			},
+			CmpFunc: cmpIDs,
+		},
+		{
+			Name:    "entry",
+			ExpResp: auditIDs([]auditbus.Audit{sd.Audits[1][2]}),
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, auditbus.QueryFilter{EntryID: &sd.Audits[1][2].EntryID})
+			},
+			CmpFunc: cmpIDs,
+		},
+		{
+			Name:    "date",
+			ExpResp: auditIDs(nil),
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, auditbus.QueryFilter{BundleID: &sd.Users[0].Bundles[0].ID, EndCreatedDate: &past})
+			},
+			CmpFunc: cmpIDs,
+		},
+		{
+			Name:    "count",
+			ExpResp: len(sd.Audits[0]),
+			ExcFunc: func(ctx context.Context) any {
+				count, err := busDomain.Audit.Count(ctx, auditbus.QueryFilter{BundleID: &sd.Users[0].Bundles[0].ID})
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func event(db *dbtest.Database, sd seedData) []unitest.Table {
+	busDomain := db.BusDomain
+	usr := sd.Users[1]
+
+	type recorded struct {
+		Action  string
+		ActorID uuid.UUID
+		UserID  uuid.UUID
+	}
+
+	queryRecorded := func(ctx context.Context) ([]recorded, error) {
+		filter := auditbus.QueryFilter{UserID: &usr.ID}
+
+		resp, err := busDomain.Audit.Query(ctx, filter, auditbus.DefaultOrderBy, page.MustParse("1", "10"))
+		if err != nil {
+			return nil, err
+		}
+
+		got := make([]recorded, len(resp))
+		for i, a := range resp {
+			got[i] = recorded{
+				Action:  a.Action,
+				ActorID: a.ActorID,
+				UserID:  a.UserID,
+			}
+		}
+
+		return got, nil
+	}
+
+	cmpRecorded := func(got any, exp any) string {
+		gotResp, exists := got.([]recorded)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "user-disabled",
+			ExpResp: []recorded{
+				{
+					Action: auditbus.ActionUserDisabled,
+					UserID: usr.ID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				enabled := false
+				if _, err := busDomain.User.Update(ctx, usr.User, userbus.UpdateUser{Enabled: &enabled}); err != nil {
+					return err
+				}
+
+				got, err := queryRecorded(ctx)
+				if err != nil {
+					return err
+				}
+
+				return got
+			},
+			CmpFunc: cmpRecorded,
+		},
+		{
+			Name: "rolled-back",
+			ExpResp: []recorded{
+				{
+					Action: auditbus.ActionUserDisabled,
+					UserID: usr.ID,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				tx, err := sqldb.NewBeginner(db.DB).Begin()
+				if err != nil {
+					return err
+				}
+
+				bus, err := busDomain.User.NewWithTx(tx)
+				if err != nil {
+					tx.Rollback()
+					return err
+				}
+
+				// The record is written in the transaction of the update and
+				// goes away with it.
+				enabled := true
+				if _, err := bus.Update(ctx, usr.User, userbus.UpdateUser{Enabled: &enabled}); err != nil {
+					tx.Rollback()
+					return err
+				}
+
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				got, err := queryRecorded(ctx)
+				if err != nil {
+					return err
+				}
+
+				return got
+			},
+			CmpFunc: cmpRecorded,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+// keyStore implements auditbus.KeyLookup with a single generated key.
+type keyStore struct {
+	privatePEM string
+	publicPEM  string
+}
+
+func newKeyStore(t *testing.T) *keyStore {
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(&pk.PublicKey)
+	if err != nil {
+		t.Fatalf("marshaling public key: %s", err)
+	}
+
+	ks := keyStore{
+		privatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(pk)})),
+		publicPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})),
+	}
+
+	return &ks
+}
+
+func (ks *keyStore) PrivateKey(kid string) (string, error) {
+	return ks.privatePEM, nil
+}
+
+func (ks *keyStore) PublicKey(kid string) (string, error) {
+	return ks.publicPEM, nil
+}
+
+func chain(db *dbtest.Database, ks *keyStore) []unitest.Table {
+	const kid = "test-kid"
+
+	cmpVerification := func(got any, exp any) string {
+		gotResp, exists := got.(auditbus.Verification)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp, exp)
+	}
+
+	// Every test before this one appended to the chain, the last record is
+	// read so the expectations can be built from it.
+	lastAudit := func(ctx context.Context) (auditbus.Audit, error) {
+		resp, err := db.BusDomain.Audit.Query(ctx, auditbus.QueryFilter{}, order.NewBy(auditbus.OrderByDateCreated, order.DESC), page.MustParse("1", "1"))
+		if err != nil {
+			return auditbus.Audit{}, err
+		}
+
+		return resp[0], nil
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "unsigned",
+			ExpResp: auditbus.Verification{
+				Records: 9,
+				Broken: &auditbus.Break{
+					Sequence: 1,
+					Reason:   "no checkpoint signs the chain",
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := db.BusDomain.Audit.Verify(ctx, ks)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpVerification,
+		},
+		{
+			Name:    "checkpoint",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				last, err := lastAudit(ctx)
+				if err != nil {
+					return err
+				}
+
+				cp, err := db.BusDomain.Audit.Checkpoint(ctx, ks, kid)
+				if err != nil {
+					return err
+				}
+
+				return cp.Sequence == last.Sequence && cp.Hash == last.Hash && cp.KID == kid
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "verify",
+			ExpResp: auditbus.Verification{
+				Records:     9,
+				Checkpoints: 1,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := db.BusDomain.Audit.Verify(ctx, ks)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpVerification,
+		},
+		{
+			Name: "tampered",
+			ExpResp: auditbus.Verification{
+				Records:     2,
+				Checkpoints: 1,
+				Broken: &auditbus.Break{
+					Sequence: 2,
+					Reason:   "hash does not match the record",
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				const q = `UPDATE audits SET status = status + 1 WHERE seq = 2`
+				if _, err := db.DB.ExecContext(ctx, q); err != nil {
+					return err
+				}
+
+				resp, err := db.BusDomain.Audit.Verify(ctx, ks)
+				if err != nil {
+					return err
+				}
+
+				const r = `UPDATE audits SET status = status - 1 WHERE seq = 2`
+				if _, err := db.DB.ExecContext(ctx, r); err != nil {
+					return err
+				}
+
+				if resp.Broken == nil {
+					return resp
+				}
+
+				resp.Broken.AuditID = uuid.Nil
+
+				return resp
+			},
+			CmpFunc: cmpVerification,
+		},
+		{
+			Name: "unchained",
+			ExpResp: auditbus.Verification{
+				Records:     3,
+				Checkpoints: 1,
+				Broken: &auditbus.Break{
+					Sequence: 3,
+					Reason:   "record is not chained",
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				var prevHash, hash string
+				const q = `SELECT prev_hash, hash FROM audits WHERE seq = 3`
+				if err := db.DB.QueryRowContext(ctx, q).Scan(&prevHash, &hash); err != nil {
+					return err
+				}
+
+				const u = `UPDATE audits SET prev_hash = '', hash = '' WHERE seq = 3`
+				if _, err := db.DB.ExecContext(ctx, u); err != nil {
+					return err
+				}
+
+				resp, err := db.BusDomain.Audit.Verify(ctx, ks)
+				if err != nil {
+					return err
+				}
+
+				const r = `UPDATE audits SET prev_hash = $1, hash = $2 WHERE seq = 3`
+				if _, err := db.DB.ExecContext(ctx, r, prevHash, hash); err != nil {
+					return err
+				}
+
+				if resp.Broken == nil {
+					return resp
+				}
+
+				resp.Broken.AuditID = uuid.Nil
+
+				return resp
+			},
+			CmpFunc: cmpVerification,
+		},
+		{
+			Name: "truncated",
+			ExpResp: auditbus.Verification{
+				Records:     8,
+				Checkpoints: 1,
+				Broken: &auditbus.Break{
+					Sequence: 9,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				const q = `DELETE FROM audits WHERE seq = 9`
+				if _, err := db.DB.ExecContext(ctx, q); err != nil {
+					return err
+				}
+
+				resp, err := db.BusDomain.Audit.Verify(ctx, ks)
+				if err != nil {
+					return err
+				}
+
+				if resp.Broken == nil {
+					return resp
+				}
+
+				resp.Broken.Reason = ""
+
+				return resp
+			},
+			CmpFunc: cmpVerification,
+		},
+	}
+
+	return table
+}
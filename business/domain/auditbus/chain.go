@@ -0,0 +1,288 @@
+package auditbus
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// KeyLookup declares the behavior needed to look up the keys checkpoints are
+// signed with. It is implemented by foundation/keystore.
+type KeyLookup interface {
+	PrivateKey(kid string) (key string, err error)
+	PublicKey(kid string) (key string, err error)
+}
+
+// verifyBatchSize is how many records are read at a time while verifying the
+// chain.
+const verifyBatchSize = 1000
+
+// hashAudit returns the hex encoded SHA-256 hash of the record. Every field
+// other than the hash itself is written on its own line, starting with the
+// hash of the previous record so changing any record changes the hash of
+// every record after it.
+func hashAudit(a Audit) string {
+	fields := []string{
+		a.PrevHash,
+		strconv.FormatInt(a.Sequence, 10),
+		a.ID.String(),
+		a.ActorID.String(),
+		a.Action,
+		a.BundleID.String(),
+		a.EntryID.String(),
+		a.KeyID.String(),
+		a.UserID.String(),
+		strconv.Itoa(a.Status),
+		a.IPAddress,
+		a.UserAgent,
+		a.TraceID,
+		a.DateCreated.UTC().Format(time.RFC3339Nano),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkpointMessage returns the data a checkpoint signature covers.
+func checkpointMessage(cp Checkpoint) []byte {
+	return fmt.Appendf(nil, "%d\n%s\n%s\n%s", cp.Sequence, cp.Hash, cp.KID, cp.DateCreated.UTC().Format(time.RFC3339Nano))
+}
+
+// Checkpoint signs the hash of the last record of the chain with the
+// specified key. When no record was added since the last checkpoint, the last
+// checkpoint is returned.
+func (b *Business) Checkpoint(ctx context.Context, keys KeyLookup, kid string) (Checkpoint, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.checkpoint")
+	defer span.End()
+
+	last, err := b.storer.QueryLast(ctx)
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("querylast: %w", err)
+	}
+
+	if last.Hash == "" {
+		return Checkpoint{}, fmt.Errorf("querylast: %w", ErrNotFound)
+	}
+
+	lastCP, err := b.storer.QueryLastCheckpoint(ctx)
+	switch {
+	case err == nil:
+		if lastCP.Sequence == last.Sequence {
+			return lastCP, nil
+		}
+	case !errors.Is(err, ErrNotFound):
+		return Checkpoint{}, fmt.Errorf("querylastcheckpoint: %w", err)
+	}
+
+	cp := Checkpoint{
+		ID:          uuid.New(),
+		Sequence:    last.Sequence,
+		Hash:        last.Hash,
+		KID:         kid,
+		DateCreated: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	sig, err := sign(keys, kid, checkpointMessage(cp))
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("sign: %w", err)
+	}
+	cp.Signature = sig
+
+	if err := b.storer.CreateCheckpoint(ctx, cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("createcheckpoint: %w", err)
+	}
+
+	return cp, nil
+}
+
+// Verify walks the chain from the first record, recomputing the hash of every
+// record and checking the signatures of the checkpoints. Records before the
+// recorded start of the chain must be unchained and every record from it on
+// must be chained. A chain without a checkpoint is broken as nothing stops it
+// from being rewritten. It stops at the first broken link. An error is only
+// returned when the chain could not be read.
+func (b *Business) Verify(ctx context.Context, keys KeyLookup) (Verification, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.verify")
+	defer span.End()
+
+	cps, err := b.storer.QueryCheckpoints(ctx)
+	if err != nil {
+		return Verification{}, fmt.Errorf("querycheckpoints: %w", err)
+	}
+
+	checkpoints := make(map[int64][]Checkpoint)
+	for _, cp := range cps {
+		checkpoints[cp.Sequence] = append(checkpoints[cp.Sequence], cp)
+	}
+
+	v := Verification{
+		Checkpoints: len(cps),
+	}
+
+	start, err := b.storer.QueryChainStart(ctx)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		v.Broken = &Break{
+			Reason: "the start of the chain is not recorded",
+		}
+		return v, nil
+	case err != nil:
+		return Verification{}, fmt.Errorf("querychainstart: %w", err)
+	}
+
+	var seq int64
+	var prevHash string
+
+	for {
+		audits, err := b.storer.QueryChain(ctx, seq, verifyBatchSize)
+		if err != nil {
+			return Verification{}, fmt.Errorf("querychain: %w", err)
+		}
+
+		if len(audits) == 0 {
+			break
+		}
+
+		for _, a := range audits {
+			v.Records++
+
+			brk := func(format string, args ...any) (Verification, error) {
+				v.Broken = &Break{
+					Sequence: a.Sequence,
+					AuditID:  a.ID,
+					Reason:   fmt.Sprintf(format, args...),
+				}
+				return v, nil
+			}
+
+			switch {
+			case a.Sequence != seq+1:
+				return brk("expected sequence %d, records are missing", seq+1)
+
+			// Records written before the chain was introduced have no hash and
+			// can only precede the start of the chain.
+			case a.Sequence < start:
+				if a.Hash != "" || a.PrevHash != "" {
+					return brk("chained record before the start of the chain at sequence %d", start)
+				}
+				v.Unchained++
+
+			case a.Hash == "":
+				return brk("record is not chained")
+
+			case a.PrevHash != prevHash:
+				return brk("previous hash does not match the record before it")
+
+			case hashAudit(a) != a.Hash:
+				return brk("hash does not match the record")
+			}
+
+			for _, cp := range checkpoints[a.Sequence] {
+				if cp.Hash != a.Hash {
+					return brk("hash does not match checkpoint %s", cp.ID)
+				}
+
+				if err := verify(keys, cp.KID, checkpointMessage(cp), cp.Signature); err != nil {
+					return brk("checkpoint %s: %s", cp.ID, err)
+				}
+			}
+
+			seq = a.Sequence
+			prevHash = a.Hash
+		}
+	}
+
+	// A checkpoint past the end of the chain means records were removed from
+	// the end.
+	for _, cp := range cps {
+		if cp.Sequence > seq {
+			v.Broken = &Break{
+				Sequence: seq + 1,
+				Reason:   fmt.Sprintf("checkpoint %s is at sequence %d, records are missing", cp.ID, cp.Sequence),
+			}
+			return v, nil
+		}
+	}
+
+	if len(cps) == 0 && v.Records > v.Unchained {
+		v.Broken = &Break{
+			Sequence: start,
+			Reason:   "no checkpoint signs the chain",
+		}
+		return v, nil
+	}
+
+	return v, nil
+}
+
+// =============================================================================
+
+func sign(keys KeyLookup, kid string, msg []byte) ([]byte, error) {
+	privatePEM, err := keys.PrivateKey(kid)
+	if err != nil {
+		return nil, fmt.Errorf("private key: kid[%s]: %w", kid, err)
+	}
+
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, errors.New("invalid key: key must be a PEM encoded PKCS1 or PKCS8 key")
+	}
+
+	var parsedKey any
+	parsedKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key: %w", err)
+		}
+	}
+
+	pk, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not a valid RSA private key")
+	}
+
+	sum := sha256.Sum256(msg)
+	return rsa.SignPKCS1v15(nil, pk, crypto.SHA256, sum[:])
+}
+
+func verify(keys KeyLookup, kid string, msg []byte, sig []byte) error {
+	publicPEM, err := keys.PublicKey(kid)
+	if err != nil {
+		return fmt.Errorf("public key: kid[%s]: %w", kid, err)
+	}
+
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return errors.New("invalid key: key must be a PEM encoded PKIX public key")
+	}
+
+	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("parse public key: %w", err)
+	}
+
+	pk, ok := parsedKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("key is not a valid RSA public key")
+	}
+
+	sum := sha256.Sum256(msg)
+	if err := rsa.VerifyPKCS1v15(pk, crypto.SHA256, sum[:], sig); err != nil {
+		return errors.New("signature does not verify")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package auditbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of actions recorded from the events of other domains.
+const (
+	ActionUserEnabled  = "user.enabled"
+	ActionUserDisabled = "user.disabled"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system. If the business was constructed for query only, there won't be a
+// delegate provided.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is
+// updated. Enabling and disabling a user is recorded with the trace id of the
+// call so it can be matched with the call that made the change. The record is
+// written in the transaction of the update so it only exists when the change
+// does.
+func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionUpdatedParms
+	err := json.Unmarshal(data.RawParams, &params)
+	if err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	if params.Enabled == nil {
+		return nil
+	}
+
+	action := ActionUserDisabled
+	if *params.Enabled {
+		action = ActionUserEnabled
+	}
+
+	na := NewAudit{
+		Action:  action,
+		UserID:  params.UserID,
+		TraceID: otel.GetTraceID(ctx),
+	}
+
+	bus := b
+	if data.Tx != nil {
+		bus, err = b.NewWithTx(data.Tx)
+		if err != nil {
+			return fmt.Errorf("newwithtx: %w", err)
+		}
+	}
+
+	if _, err := bus.Create(ctx, na); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
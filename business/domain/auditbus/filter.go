@@ -0,0 +1,20 @@
+package auditbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ActorID          *uuid.UUID
+	Action           *string
+	BundleID         *uuid.UUID
+	EntryID          *uuid.UUID
+	KeyID            *uuid.UUID
+	UserID           *uuid.UUID
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
@@ -0,0 +1,76 @@
+package auditbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit represents a security relevant action taken in the system. Action
+// names the call in the form <domain>.<verb>, e.g. entry.read. ActorID is the
+// authenticated user making the call and is uuid.Nil for unauthenticated calls
+// and actions taken by the system. BundleID, EntryID, KeyID and UserID are the
+// targets of the action, uuid.Nil when the action has no such target. Status
+// is the HTTP status code the call completed with. Sequence is the position of
+// the record in the chain, PrevHash the hash of the record before it and Hash
+// the hash of the record itself, see hashAudit.
+type Audit struct {
+	ID          uuid.UUID
+	Sequence    int64
+	PrevHash    string
+	Hash        string
+	ActorID     uuid.UUID
+	Action      string
+	BundleID    uuid.UUID
+	EntryID     uuid.UUID
+	KeyID       uuid.UUID
+	UserID      uuid.UUID
+	Status      int
+	IPAddress   string
+	UserAgent   string
+	TraceID     string
+	DateCreated time.Time
+}
+
+// NewAudit is what we require to record an action.
+type NewAudit struct {
+	ActorID   uuid.UUID
+	Action    string
+	BundleID  uuid.UUID
+	EntryID   uuid.UUID
+	KeyID     uuid.UUID
+	UserID    uuid.UUID
+	Status    int
+	IPAddress string
+	UserAgent string
+	TraceID   string
+}
+
+// Checkpoint is a signed statement of the hash of the chain at a sequence.
+// Records can be rewritten by someone with access to the database along with
+// the hashes that follow them, the signature can not.
+type Checkpoint struct {
+	ID          uuid.UUID
+	Sequence    int64
+	Hash        string
+	KID         string
+	Signature   []byte
+	DateCreated time.Time
+}
+
+// Break describes the first record where the chain does not verify.
+type Break struct {
+	Sequence int64
+	AuditID  uuid.UUID
+	Reason   string
+}
+
+// Verification is the result of verifying the chain. Unchained counts the
+// records written before the chain was introduced. Broken is nil when the
+// whole chain verified.
+type Verification struct {
+	Records     int64
+	Unchained   int64
+	Checkpoints int
+	Broken      *Break
+}
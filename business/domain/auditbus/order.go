@@ -0,0 +1,14 @@
+package auditbus
+
+import "github.com/gradientsearch/pwmanager/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort, newest first.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByAuditID     = "audit_id"
+	OrderByActorID     = "actor_id"
+	OrderByAction      = "action"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,302 @@
+// Package auditdb contains audit related CRUD functionality.
+package auditdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for audit database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (auditbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Append adds the record link returns for the last record of the chain, the
+// zero value when the chain is empty. The chain is locked first and the lock
+// is held until the transaction ends, so records are appended one transaction
+// at a time across every instance and never linked to a record that is rolled
+// back. Outside of a transaction the append runs in one of its own.
+func (s *Store) Append(ctx context.Context, link func(last auditbus.Audit) auditbus.Audit) (auditbus.Audit, error) {
+	db, ok := s.db.(*sqlx.DB)
+	if !ok {
+		return s.append(ctx, link)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return auditbus.Audit{}, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	store := Store{
+		log: s.log,
+		db:  tx,
+	}
+
+	a, err := store.append(ctx, link)
+	if err != nil {
+		return auditbus.Audit{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return auditbus.Audit{}, fmt.Errorf("commit: %w", err)
+	}
+
+	return a, nil
+}
+
+func (s *Store) append(ctx context.Context, link func(last auditbus.Audit) auditbus.Audit) (auditbus.Audit, error) {
+	const q = `
+	SELECT
+		start_seq
+	FROM
+		audit_chain
+	FOR UPDATE`
+
+	var dest chainStart
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dest); err != nil {
+		return auditbus.Audit{}, fmt.Errorf("lock: %w", err)
+	}
+
+	last, err := s.QueryLast(ctx)
+	if err != nil && !errors.Is(err, auditbus.ErrNotFound) {
+		return auditbus.Audit{}, fmt.Errorf("querylast: %w", err)
+	}
+
+	a := link(last)
+	if err := s.create(ctx, a); err != nil {
+		return auditbus.Audit{}, fmt.Errorf("create: %w", err)
+	}
+
+	return a, nil
+}
+
+// create adds an Audit to the sqldb.
+func (s *Store) create(ctx context.Context, a auditbus.Audit) error {
+	const q = `
+	INSERT INTO audits
+		(audit_id, seq, prev_hash, hash, actor_id, action, bundle_id, entry_id, key_id, user_id, status, ip_address, user_agent, trace_id, date_created)
+	VALUES
+		(:audit_id, :seq, :prev_hash, :hash, :actor_id, :action, :bundle_id, :entry_id, :key_id, :user_id, :status, :ip_address, :user_agent, :trace_id, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBAudit(a)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", auditbus.ErrChainConflict)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query gets all Audits from the database.
+func (s *Store) Query(ctx context.Context, filter auditbus.QueryFilter, orderBy order.By, page page.Page) ([]auditbus.Audit, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		audit_id, seq, prev_hash, hash, actor_id, action, bundle_id, entry_id, key_id, user_id, status, ip_address, user_agent, trace_id, date_created
+	FROM
+		audits`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbAudits []audit
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbAudits); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAudits(dbAudits), nil
+}
+
+// Count returns the total number of audits in the DB.
+func (s *Store) Count(ctx context.Context, filter auditbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		audits`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryLast gets the last record of the chain.
+func (s *Store) QueryLast(ctx context.Context) (auditbus.Audit, error) {
+	const q = `
+	SELECT
+		audit_id, seq, prev_hash, hash, actor_id, action, bundle_id, entry_id, key_id, user_id, status, ip_address, user_agent, trace_id, date_created
+	FROM
+		audits
+	ORDER BY
+		seq DESC
+	LIMIT 1`
+
+	var dbAudit audit
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dbAudit); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return auditbus.Audit{}, fmt.Errorf("db: %w", auditbus.ErrNotFound)
+		}
+		return auditbus.Audit{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusAudit(dbAudit), nil
+}
+
+// QueryChainStart gets the sequence of the first chained record.
+func (s *Store) QueryChainStart(ctx context.Context) (int64, error) {
+	const q = `
+	SELECT
+		start_seq
+	FROM
+		audit_chain`
+
+	var dest chainStart
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return 0, fmt.Errorf("db: %w", auditbus.ErrNotFound)
+		}
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return dest.StartSeq, nil
+}
+
+// QueryChain gets up to limit records of the chain that follow the specified
+// sequence, in the order of the chain.
+func (s *Store) QueryChain(ctx context.Context, afterSequence int64, limit int) ([]auditbus.Audit, error) {
+	data := map[string]any{
+		"after_seq": afterSequence,
+		"limit":     limit,
+	}
+
+	const q = `
+	SELECT
+		audit_id, seq, prev_hash, hash, actor_id, action, bundle_id, entry_id, key_id, user_id, status, ip_address, user_agent, trace_id, date_created
+	FROM
+		audits
+	WHERE
+		seq > :after_seq
+	ORDER BY
+		seq
+	LIMIT :limit`
+
+	var dbAudits []audit
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbAudits); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAudits(dbAudits), nil
+}
+
+// CreateCheckpoint adds a Checkpoint to the sqldb.
+func (s *Store) CreateCheckpoint(ctx context.Context, cp auditbus.Checkpoint) error {
+	const q = `
+	INSERT INTO audit_checkpoints
+		(checkpoint_id, seq, hash, kid, signature, date_created)
+	VALUES
+		(:checkpoint_id, :seq, :hash, :kid, :signature, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBCheckpoint(cp)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryLastCheckpoint gets the checkpoint furthest along the chain.
+func (s *Store) QueryLastCheckpoint(ctx context.Context) (auditbus.Checkpoint, error) {
+	const q = `
+	SELECT
+		checkpoint_id, seq, hash, kid, signature, date_created
+	FROM
+		audit_checkpoints
+	ORDER BY
+		seq DESC, date_created DESC
+	LIMIT 1`
+
+	var dbCP checkpoint
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dbCP); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return auditbus.Checkpoint{}, fmt.Errorf("db: %w", auditbus.ErrNotFound)
+		}
+		return auditbus.Checkpoint{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusCheckpoint(dbCP), nil
+}
+
+// QueryCheckpoints gets all checkpoints in the order of the chain.
+func (s *Store) QueryCheckpoints(ctx context.Context) ([]auditbus.Checkpoint, error) {
+	const q = `
+	SELECT
+		checkpoint_id, seq, hash, kid, signature, date_created
+	FROM
+		audit_checkpoints
+	ORDER BY
+		seq`
+
+	var dbCPs []checkpoint
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, map[string]any{}, &dbCPs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusCheckpoints(dbCPs), nil
+}
@@ -0,0 +1,57 @@
+package auditdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+)
+
+func (s *Store) applyFilter(filter auditbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ActorID != nil {
+		data["actor_id"] = *filter.ActorID
+		wc = append(wc, "actor_id = :actor_id")
+	}
+
+	if filter.Action != nil {
+		data["action"] = *filter.Action
+		wc = append(wc, "action = :action")
+	}
+
+	if filter.BundleID != nil {
+		data["bundle_id"] = *filter.BundleID
+		wc = append(wc, "bundle_id = :bundle_id")
+	}
+
+	if filter.EntryID != nil {
+		data["entry_id"] = *filter.EntryID
+		wc = append(wc, "entry_id = :entry_id")
+	}
+
+	if filter.KeyID != nil {
+		data["key_id"] = *filter.KeyID
+		wc = append(wc, "key_id = :key_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,140 @@
+package auditdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+)
+
+type audit struct {
+	ID          uuid.UUID     `db:"audit_id"`
+	Sequence    int64         `db:"seq"`
+	PrevHash    string        `db:"prev_hash"`
+	Hash        string        `db:"hash"`
+	ActorID     uuid.NullUUID `db:"actor_id"`
+	Action      string        `db:"action"`
+	BundleID    uuid.NullUUID `db:"bundle_id"`
+	EntryID     uuid.NullUUID `db:"entry_id"`
+	KeyID       uuid.NullUUID `db:"key_id"`
+	UserID      uuid.NullUUID `db:"user_id"`
+	Status      int           `db:"status"`
+	IPAddress   string        `db:"ip_address"`
+	UserAgent   string        `db:"user_agent"`
+	TraceID     string        `db:"trace_id"`
+	DateCreated time.Time     `db:"date_created"`
+}
+
+func toDBAudit(bus auditbus.Audit) audit {
+	db := audit{
+		ID:          bus.ID,
+		Sequence:    bus.Sequence,
+		PrevHash:    bus.PrevHash,
+		Hash:        bus.Hash,
+		ActorID:     toNullUUID(bus.ActorID),
+		Action:      bus.Action,
+		BundleID:    toNullUUID(bus.BundleID),
+		EntryID:     toNullUUID(bus.EntryID),
+		KeyID:       toNullUUID(bus.KeyID),
+		UserID:      toNullUUID(bus.UserID),
+		Status:      bus.Status,
+		IPAddress:   bus.IPAddress,
+		UserAgent:   bus.UserAgent,
+		TraceID:     bus.TraceID,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusAudit(db audit) auditbus.Audit {
+	bus := auditbus.Audit{
+		ID:          db.ID,
+		Sequence:    db.Sequence,
+		PrevHash:    db.PrevHash,
+		Hash:        db.Hash,
+		ActorID:     db.ActorID.UUID,
+		Action:      db.Action,
+		BundleID:    db.BundleID.UUID,
+		EntryID:     db.EntryID.UUID,
+		KeyID:       db.KeyID.UUID,
+		UserID:      db.UserID.UUID,
+		Status:      db.Status,
+		IPAddress:   db.IPAddress,
+		UserAgent:   db.UserAgent,
+		TraceID:     db.TraceID,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusAudits(dbs []audit) []auditbus.Audit {
+	bus := make([]auditbus.Audit, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusAudit(db)
+	}
+
+	return bus
+}
+
+// toNullUUID stores uuid.Nil as NULL, targets an action does not have are
+// left empty.
+func toNullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{
+		UUID:  id,
+		Valid: id != uuid.Nil,
+	}
+}
+
+// =============================================================================
+
+type checkpoint struct {
+	ID          uuid.UUID `db:"checkpoint_id"`
+	Sequence    int64     `db:"seq"`
+	Hash        string    `db:"hash"`
+	KID         string    `db:"kid"`
+	Signature   []byte    `db:"signature"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBCheckpoint(bus auditbus.Checkpoint) checkpoint {
+	db := checkpoint{
+		ID:          bus.ID,
+		Sequence:    bus.Sequence,
+		Hash:        bus.Hash,
+		KID:         bus.KID,
+		Signature:   bus.Signature,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusCheckpoint(db checkpoint) auditbus.Checkpoint {
+	bus := auditbus.Checkpoint{
+		ID:          db.ID,
+		Sequence:    db.Sequence,
+		Hash:        db.Hash,
+		KID:         db.KID,
+		Signature:   db.Signature,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusCheckpoints(dbs []checkpoint) []auditbus.Checkpoint {
+	bus := make([]auditbus.Checkpoint, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusCheckpoint(db)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type chainStart struct {
+	StartSeq int64 `db:"start_seq"`
+}
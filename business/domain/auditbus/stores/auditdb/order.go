@@ -0,0 +1,24 @@
+package auditdb
+
+import (
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	auditbus.OrderByAuditID:     "audit_id",
+	auditbus.OrderByActorID:     "actor_id",
+	auditbus.OrderByAction:      "action",
+	auditbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,55 @@
+package auditbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// testActions is the set of actions the generated audits cycle through.
+var testActions = []string{
+	"entry.create",
+	"entry.read",
+	"entry.update",
+	"entry.delete",
+}
+
+// TestGenerateNewAudits is a helper method for testing.
+func TestGenerateNewAudits(n int, actorID uuid.UUID, bundleID uuid.UUID) []NewAudit {
+	newAudits := make([]NewAudit, n)
+
+	for i := range n {
+		na := NewAudit{
+			ActorID:   actorID,
+			Action:    testActions[i%len(testActions)],
+			BundleID:  bundleID,
+			EntryID:   uuid.New(),
+			Status:    200,
+			IPAddress: "127.0.0.1",
+			UserAgent: "pwmanager-test",
+			TraceID:   fmt.Sprintf("trace-%d", i),
+		}
+
+		newAudits[i] = na
+	}
+
+	return newAudits
+}
+
+// TestGenerateSeedAudits is a helper method for testing.
+func TestGenerateSeedAudits(ctx context.Context, n int, api *Business, actorID uuid.UUID, bundleID uuid.UUID) ([]Audit, error) {
+	newAudits := TestGenerateNewAudits(n, actorID, bundleID)
+
+	audits := make([]Audit, len(newAudits))
+	for i, na := range newAudits {
+		a, err := api.Create(ctx, na)
+		if err != nil {
+			return nil, fmt.Errorf("seeding audit: idx: %d : %w", i, err)
+		}
+
+		audits[i] = a
+	}
+
+	return audits, nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
@@ -19,8 +21,11 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound     = errors.New("bundle not found")
-	ErrUserDisabled = errors.New("user disabled")
+	ErrNotFound           = errors.New("bundle not found")
+	ErrUserDisabled       = errors.New("user disabled")
+	ErrStaleKeyGeneration = errors.New("bundle key generation is stale")
+	ErrIncompleteRotation = errors.New("bundle key rotation is incomplete")
+	ErrConflict           = errors.New("bundle has been modified since it was read")
 )
 
 // Storer interface declares the behaviour this package needs to persist and
@@ -29,7 +34,9 @@ type Storer interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, bdl Bundle) error
 	Update(ctx context.Context, bdl Bundle) error
-	Delete(ctx context.Context, bdl Bundle) error
+	Rotate(ctx context.Context, bdl Bundle) error
+	SetDeleted(ctx context.Context, bdl Bundle) error
+	Purge(ctx context.Context, before time.Time) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Bundle, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, bundleID uuid.UUID) (Bundle, error)
@@ -40,15 +47,19 @@ type Storer interface {
 type Business struct {
 	log      *logger.Logger
 	userBus  *userbus.Business
+	keyBus   *keybus.Business
+	entryBus *entrybus.Business
 	delegate *delegate.Delegate
 	storer   Storer
 }
 
 // NewBusiness constructs a bundle business API for use.
-func NewBusiness(log *logger.Logger, userBus *userbus.Business, delegate *delegate.Delegate, storer Storer) *Business {
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, keyBus *keybus.Business, entryBus *entrybus.Business, delegate *delegate.Delegate, storer Storer) *Business {
 	return &Business{
 		log:      log,
 		userBus:  userBus,
+		keyBus:   keyBus,
+		entryBus: entryBus,
 		delegate: delegate,
 		storer:   storer,
 	}
@@ -67,9 +78,21 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		return nil, err
 	}
 
+	keyBus, err := b.keyBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	entryBus, err := b.entryBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	bus := Business{
 		log:      b.log,
 		userBus:  userBus,
+		keyBus:   keyBus,
+		entryBus: entryBus,
 		delegate: b.delegate,
 		storer:   storer,
 	}
@@ -91,15 +114,22 @@ func (b *Business) Create(ctx context.Context, nb NewBundle) (Bundle, error) {
 		return Bundle{}, ErrUserDisabled
 	}
 
+	keyGeneration := nb.KeyGeneration
+	if keyGeneration == 0 {
+		keyGeneration = 1
+	}
+
 	now := time.Now()
 
 	bdl := Bundle{
-		ID:          uuid.New(),
-		Type:        nb.Type,
-		UserID:      nb.UserID,
-		Metadata:    nb.Metadata,
-		DateCreated: now,
-		DateUpdated: now,
+		ID:            uuid.New(),
+		Type:          nb.Type,
+		UserID:        nb.UserID,
+		Metadata:      nb.Metadata,
+		KeyGeneration: keyGeneration,
+		Revision:      1,
+		DateCreated:   now,
+		DateUpdated:   now,
 	}
 
 	if err := b.storer.Create(ctx, bdl); err != nil {
@@ -109,11 +139,18 @@ func (b *Business) Create(ctx context.Context, nb NewBundle) (Bundle, error) {
 	return bdl, nil
 }
 
-// Update modifies information about a bundle.
+// Update modifies information about a bundle. If the update specifies the key
+// generation its metadata is encrypted under, the update is rejected unless it
+// matches the bundle's current key generation. The update is also rejected if
+// the bundle has been written since the specified bundle was read.
 func (b *Business) Update(ctx context.Context, bdl Bundle, uh UpdateBundle) (Bundle, error) {
 	ctx, span := otel.AddSpan(ctx, "business.bundlebus.update")
 	defer span.End()
 
+	if uh.KeyGeneration != nil && *uh.KeyGeneration != bdl.KeyGeneration {
+		return Bundle{}, ErrStaleKeyGeneration
+	}
+
 	if uh.Type != nil {
 		bdl.Type = *uh.Type
 
@@ -123,28 +160,165 @@ func (b *Business) Update(ctx context.Context, bdl Bundle, uh UpdateBundle) (Bun
 		bdl.Metadata = *uh.Metadata
 	}
 
+	bdl.Revision++
 	bdl.DateUpdated = time.Now()
 
 	if err := b.storer.Update(ctx, bdl); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return Bundle{}, fmt.Errorf("update: %w", b.conflict(ctx, bdl.ID, bdl.KeyGeneration))
+		}
 		return Bundle{}, fmt.Errorf("update: %w", err)
 	}
 
 	return bdl, nil
 }
 
-// Delete removes the specified bundle.
+// Delete moves the specified bundle to the trash. The bundle along with its
+// entries and keys is kept until it is purged.
 func (b *Business) Delete(ctx context.Context, bdl Bundle) error {
 	ctx, span := otel.AddSpan(ctx, "business.bundlebus.delete")
 	defer span.End()
 
-	if err := b.storer.Delete(ctx, bdl); err != nil {
-		return fmt.Errorf("delete: %w", err)
+	bdl.DateDeleted = time.Now()
+
+	if err := b.storer.SetDeleted(ctx, bdl); err != nil {
+		return fmt.Errorf("setdeleted: %w", err)
 	}
 
 	return nil
 }
 
-// Query retrieves a list of existing bundles.
+// Undelete moves the specified bundle out of the trash.
+func (b *Business) Undelete(ctx context.Context, bdl Bundle) (Bundle, error) {
+	ctx, span := otel.AddSpan(ctx, "business.bundlebus.undelete")
+	defer span.End()
+
+	bdl.DateDeleted = time.Time{}
+
+	if err := b.storer.SetDeleted(ctx, bdl); err != nil {
+		return Bundle{}, fmt.Errorf("setdeleted: %w", err)
+	}
+
+	return bdl, nil
+}
+
+// PurgeDeleted permanently removes the bundles that have been in the trash
+// for longer than the retention period, along with their entries and keys.
+func (b *Business) PurgeDeleted(ctx context.Context, retention time.Duration) error {
+	ctx, span := otel.AddSpan(ctx, "business.bundlebus.purgedeleted")
+	defer span.End()
+
+	if err := b.storer.Purge(ctx, time.Now().Add(-retention)); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	return nil
+}
+
+// Rotate replaces the bundle key. Every entry in the bundle must be provided
+// re-encrypted under the new key along with the new key wrapped for every
+// remaining member, otherwise nothing is changed. The bundle moves to the next
+// key generation so writes encrypted under the old key are rejected.
+func (b *Business) Rotate(ctx context.Context, bdl Bundle, rb RotateBundle) (Bundle, error) {
+	ctx, span := otel.AddSpan(ctx, "business.bundlebus.rotate")
+	defer span.End()
+
+	if rb.KeyGeneration != bdl.KeyGeneration {
+		return Bundle{}, ErrStaleKeyGeneration
+	}
+
+	bdl.Metadata = rb.Metadata
+	bdl.KeyGeneration++
+	bdl.Revision++
+	bdl.DateUpdated = time.Now()
+
+	// Rotating the bundle row first locks it, so concurrent writes wait for
+	// this rotation and are then rejected as stale.
+	if err := b.storer.Rotate(ctx, bdl); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return Bundle{}, fmt.Errorf("rotate: %w", b.conflict(ctx, bdl.ID, rb.KeyGeneration))
+		}
+		return Bundle{}, fmt.Errorf("rotate: %w", err)
+	}
+
+	entries, err := b.entryBus.QueryByBundleID(ctx, bdl.ID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("entry.querybybundleid: %w", err)
+	}
+
+	entriesByID := make(map[uuid.UUID]entrybus.Entry, len(entries))
+	for _, e := range entries {
+		entriesByID[e.ID] = e
+	}
+
+	keys, err := b.keyBus.QueryByBundleID(ctx, bdl.ID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("key.querybybundleid: %w", err)
+	}
+
+	keysByUserID := make(map[uuid.UUID]keybus.Key, len(keys))
+	for _, k := range keys {
+		keysByUserID[k.UserID] = k
+	}
+
+	if len(rb.Entries) != len(entriesByID) {
+		return Bundle{}, fmt.Errorf("expected %d entries, got %d: %w", len(entriesByID), len(rb.Entries), ErrIncompleteRotation)
+	}
+
+	if len(rb.Keys) != len(keysByUserID) {
+		return Bundle{}, fmt.Errorf("expected %d keys, got %d: %w", len(keysByUserID), len(rb.Keys), ErrIncompleteRotation)
+	}
+
+	for _, re := range rb.Entries {
+		e, exists := entriesByID[re.ID]
+		if !exists {
+			return Bundle{}, fmt.Errorf("entryID[%s] is not in the bundle or is duplicated: %w", re.ID, ErrIncompleteRotation)
+		}
+		delete(entriesByID, re.ID)
+
+		ue := entrybus.UpdateEntry{
+			Data:          &re.Data,
+			KeyGeneration: &bdl.KeyGeneration,
+		}
+
+		if _, err := b.entryBus.Update(ctx, e, ue); err != nil {
+			return Bundle{}, fmt.Errorf("entry.update: entryID[%s]: %w", e.ID, err)
+		}
+	}
+
+	for _, rk := range rb.Keys {
+		k, exists := keysByUserID[rk.UserID]
+		if !exists {
+			return Bundle{}, fmt.Errorf("userID[%s] is not a member or is duplicated: %w", rk.UserID, ErrIncompleteRotation)
+		}
+		delete(keysByUserID, rk.UserID)
+
+		uk := keybus.UpdateKey{
+			Data: &rk.Data,
+		}
+
+		if _, err := b.keyBus.Update(ctx, k, uk); err != nil {
+			return Bundle{}, fmt.Errorf("key.update: keyID[%s]: %w", k.ID, err)
+		}
+	}
+
+	return bdl, nil
+}
+
+// conflict reports why a write to the bundle was rejected by the store. A
+// rotation moves both the key generation and the revision of the bundle, so a
+// write that raced a rotation is reported with the more specific error.
+func (b *Business) conflict(ctx context.Context, bundleID uuid.UUID, keyGeneration int) error {
+	cur, err := b.storer.QueryByID(ctx, bundleID)
+	if err == nil && cur.KeyGeneration != keyGeneration {
+		return ErrStaleKeyGeneration
+	}
+
+	return ErrConflict
+}
+
+// Query retrieves a list of existing bundles. Bundles in the trash are only
+// returned when the filter asks for them.
 func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Bundle, error) {
 	ctx, span := otel.AddSpan(ctx, "business.bundlebus.query")
 	defer span.End()
@@ -165,7 +339,8 @@ func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
-// QueryByID finds the bundle by the specified ID.
+// QueryByID finds the bundle by the specified ID. A bundle in the trash is
+// treated as not existing.
 func (b *Business) QueryByID(ctx context.Context, bundleID uuid.UUID) (Bundle, error) {
 	ctx, span := otel.AddSpan(ctx, "business.bundlebus.querybyid")
 	defer span.End()
@@ -175,10 +350,32 @@ func (b *Business) QueryByID(ctx context.Context, bundleID uuid.UUID) (Bundle, e
 		return Bundle{}, fmt.Errorf("query: bundleID[%s]: %w", bundleID, err)
 	}
 
+	if !bdl.DateDeleted.IsZero() {
+		return Bundle{}, fmt.Errorf("query: bundleID[%s]: %w", bundleID, ErrNotFound)
+	}
+
+	return bdl, nil
+}
+
+// QueryDeletedByID finds the bundle in the trash by the specified ID.
+func (b *Business) QueryDeletedByID(ctx context.Context, bundleID uuid.UUID) (Bundle, error) {
+	ctx, span := otel.AddSpan(ctx, "business.bundlebus.querydeletedbyid")
+	defer span.End()
+
+	bdl, err := b.storer.QueryByID(ctx, bundleID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("query: bundleID[%s]: %w", bundleID, err)
+	}
+
+	if bdl.DateDeleted.IsZero() {
+		return Bundle{}, fmt.Errorf("query: bundleID[%s]: %w", bundleID, ErrNotFound)
+	}
+
 	return bdl, nil
 }
 
-// QueryByUserID finds the bundles by a specified User ID.
+// QueryByUserID finds the bundles by a specified User ID, excluding the
+// bundles in the trash.
 func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Bundle, error) {
 	ctx, span := otel.AddSpan(ctx, "business.bundlebus.querybyuserid")
 	defer span.End()
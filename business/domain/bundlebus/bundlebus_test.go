@@ -2,18 +2,26 @@ package bundlebus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
 	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/key"
 	"github.com/gradientsearch/pwmanager/business/types/role"
 )
 
@@ -33,6 +41,8 @@ func Test_Bundle(t *testing.T) {
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+	unitest.Run(t, trash(db.BusDomain, sd), "trash")
+	unitest.Run(t, rotate(db, sd), "rotate")
 }
 
 // =============================================================================
@@ -78,9 +88,22 @@ func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 		return unitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
 	}
 
+	// The first bundle gets the keys and entries needed to rotate it.
+	keys, err := keybus.TestGenerateSeedKeys(ctx, 1, busDomain.Key, usrs[0].ID, []uuid.UUID{bdls[0].ID}, []bundlerole.Role{bundlerole.Admin})
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	entries, err := entrybus.TestGenerateSeedEntries(ctx, 2, busDomain.Entry, usrs[0].ID, []uuid.UUID{bdls[0].ID})
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding entries : %w", err)
+	}
+
 	tu3 := unitest.User{
 		User:    usrs[0],
 		Bundles: bdls,
+		Keys:    keys,
+		Entries: entries,
 	}
 
 	// -------------------------------------------------------------------------
@@ -188,9 +211,11 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: bundlebus.Bundle{
-				UserID:   sd.Users[0].ID,
-				Type:     bundletype.Personal,
-				Metadata: "BUNDLE METADATA",
+				UserID:        sd.Users[0].ID,
+				Type:          bundletype.Personal,
+				Metadata:      "BUNDLE METADATA",
+				KeyGeneration: 1,
+				Revision:      1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nh := bundlebus.NewBundle{
@@ -231,11 +256,13 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: bundlebus.Bundle{
-				ID:          sd.Users[0].Bundles[0].ID,
-				UserID:      sd.Users[0].ID,
-				Type:        bundletype.Personal,
-				DateCreated: sd.Users[0].Bundles[0].DateCreated,
-				DateUpdated: sd.Users[0].Bundles[0].DateCreated,
+				ID:            sd.Users[0].Bundles[0].ID,
+				UserID:        sd.Users[0].ID,
+				Type:          bundletype.Personal,
+				KeyGeneration: 1,
+				Revision:      2,
+				DateCreated:   sd.Users[0].Bundles[0].DateCreated,
+				DateUpdated:   sd.Users[0].Bundles[0].DateCreated,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				uh := bundlebus.UpdateBundle{
@@ -303,3 +330,258 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func trash(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "excluded",
+			ExpResp: bundlebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Bundle.QueryByID(ctx, sd.Users[0].Bundles[1].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "count",
+			ExpResp: []int{1, 1},
+			ExcFunc: func(ctx context.Context) any {
+				deleted := true
+				filter := bundlebus.QueryFilter{UserID: &sd.Users[0].ID}
+
+				active, err := busDomain.Bundle.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				filter.Deleted = &deleted
+
+				trashed, err := busDomain.Bundle.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return []int{active, trashed}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "undelete",
+			ExpResp: sd.Users[0].Bundles[1].ID,
+			ExcFunc: func(ctx context.Context) any {
+				bdl, err := busDomain.Bundle.QueryDeletedByID(ctx, sd.Users[0].Bundles[1].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Bundle.Undelete(ctx, bdl); err != nil {
+					return err
+				}
+
+				bdl, err = busDomain.Bundle.QueryByID(ctx, sd.Users[0].Bundles[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return bdl.ID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: bundlebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Bundle.PurgeDeleted(ctx, 0); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Bundle.QueryDeletedByID(ctx, sd.Admins[0].Bundles[1].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func rotate(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	adm := sd.Admins[0]
+	bdl := adm.Bundles[0]
+
+	rb := bundlebus.RotateBundle{
+		KeyGeneration: 1,
+		Metadata:      "ROTATED METADATA",
+		Entries: []bundlebus.RotateEntry{
+			{ID: adm.Entries[0].ID, Data: entrybus.TestNewData("Rotated0", 2)},
+			{ID: adm.Entries[1].ID, Data: entrybus.TestNewData("Rotated1", 2)},
+		},
+		Keys: []bundlebus.RotateKey{
+			{UserID: adm.ID, Data: key.MustParse("RotatedKey")},
+		},
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "stale",
+			ExpResp: bundlebus.ErrStaleKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				stale := rb
+				stale.KeyGeneration = 2
+
+				_, err := rotateTx(ctx, db, bdl, stale)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "incomplete",
+			ExpResp: bundlebus.ErrIncompleteRotation,
+			ExcFunc: func(ctx context.Context) any {
+				incomplete := rb
+				incomplete.Entries = rb.Entries[:1]
+
+				_, err := rotateTx(ctx, db, bdl, incomplete)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "duplicated",
+			ExpResp: bundlebus.ErrIncompleteRotation,
+			ExcFunc: func(ctx context.Context) any {
+				duplicated := rb
+				duplicated.Entries = []bundlebus.RotateEntry{rb.Entries[0], rb.Entries[0]}
+
+				_, err := rotateTx(ctx, db, bdl, duplicated)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "key-generation",
+			ExpResp: entrybus.ErrKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				unrotated := rb
+				unrotated.Entries = []bundlebus.RotateEntry{
+					{ID: adm.Entries[0].ID, Data: entrybus.TestNewData("Rotated0", 1)},
+					rb.Entries[1],
+				}
+
+				_, err := rotateTx(ctx, db, bdl, unrotated)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name: "basic",
+			ExpResp: bundlebus.Bundle{
+				ID:            bdl.ID,
+				UserID:        bdl.UserID,
+				Type:          bdl.Type,
+				Metadata:      "ROTATED METADATA",
+				KeyGeneration: 2,
+				Revision:      bdl.Revision + 1,
+				DateCreated:   bdl.DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := rotateTx(ctx, db, bdl, rb)
+				if err != nil {
+					return err
+				}
+
+				entries, err := db.BusDomain.Entry.QueryByBundleID(ctx, bdl.ID)
+				if err != nil {
+					return err
+				}
+
+				for _, e := range entries {
+					if e.KeyGeneration != 2 || !strings.HasPrefix(e.Data.String(), "Rotated") {
+						return fmt.Errorf("entry %s was not rotated", e.ID)
+					}
+				}
+
+				k, err := db.BusDomain.Key.QueryByUserIDBundleID(ctx, adm.ID, bdl.ID)
+				if err != nil {
+					return err
+				}
+
+				if k.Data.String() != "RotatedKey" {
+					return fmt.Errorf("key %s was not rotated", k.ID)
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(bundlebus.Bundle)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(bundlebus.Bundle)
+
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "stale-write",
+			ExpResp: bundlebus.ErrStaleKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				// The seeded bundle still reports the old generation so the
+				// write is only caught by the store.
+				uh := bundlebus.UpdateBundle{
+					Metadata:      dbtest.StringPointer("STALE METADATA"),
+					KeyGeneration: &bdl.KeyGeneration,
+				}
+
+				_, err := db.BusDomain.Bundle.Update(ctx, bdl, uh)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+// rotateTx rotates the bundle inside a transaction the same way the app
+// layer does, so a failed rotation leaves the bundle untouched.
+func rotateTx(ctx context.Context, db *dbtest.Database, bdl bundlebus.Bundle, rb bundlebus.RotateBundle) (bundlebus.Bundle, error) {
+	tx, err := sqldb.NewBeginner(db.DB).Begin()
+	if err != nil {
+		return bundlebus.Bundle{}, err
+	}
+
+	bus, err := db.BusDomain.Bundle.NewWithTx(tx)
+	if err != nil {
+		tx.Rollback()
+		return bundlebus.Bundle{}, err
+	}
+
+	resp, err := bus.Rotate(ctx, bdl, rb)
+	if err != nil {
+		tx.Rollback()
+		return bundlebus.Bundle{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return bundlebus.Bundle{}, err
+	}
+
+	return resp, nil
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
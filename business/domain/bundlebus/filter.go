@@ -9,10 +9,13 @@ import (
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// Bundles in the trash are excluded unless Deleted is true, in which case only
+// bundles in the trash are included.
 type QueryFilter struct {
 	ID               *uuid.UUID
 	UserID           *uuid.UUID
 	Type             *bundletype.BundleType
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
+	Deleted          *bool
 }
@@ -5,23 +5,34 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/key"
 )
 
-// Bundle represents an individual bundle.
+// Bundle represents an individual bundle. KeyGeneration counts how many times
+// the symmetric bundle key has been rotated, starting at 1. Revision counts the
+// writes to the bundle, starting at 1, and is used to detect concurrent
+// updates. DateDeleted is set while the bundle is in the trash.
 type Bundle struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Type        bundletype.BundleType
-	Metadata    string
-	DateCreated time.Time
-	DateUpdated time.Time
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Type          bundletype.BundleType
+	Metadata      string
+	KeyGeneration int
+	Revision      int
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	DateDeleted   time.Time
 }
 
-// NewBundle is what we require from clients when adding a Bundle.
+// NewBundle is what we require from clients when adding a Bundle. The key
+// generation is only set when a bundle is recreated with an existing key, a
+// new bundle starts at the first generation.
 type NewBundle struct {
-	UserID   uuid.UUID
-	Type     bundletype.BundleType
-	Metadata string
+	UserID        uuid.UUID
+	Type          bundletype.BundleType
+	Metadata      string
+	KeyGeneration int
 }
 
 // UpdateBundle defines what information may be provided to modify an existing
@@ -31,6 +42,30 @@ type NewBundle struct {
 // we do not want to use pointers to basic types but we make exception around
 // marshalling/unmarshalling.
 type UpdateBundle struct {
-	Type     *bundletype.BundleType
-	Metadata *string
+	Type          *bundletype.BundleType
+	Metadata      *string
+	KeyGeneration *int
+}
+
+// RotateBundle is what we require from clients when rotating the bundle key.
+// It must contain every entry in the bundle re-encrypted under the new key and
+// the new key wrapped for every remaining member. KeyGeneration is the
+// generation the client rotated from.
+type RotateBundle struct {
+	KeyGeneration int
+	Metadata      string
+	Entries       []RotateEntry
+	Keys          []RotateKey
+}
+
+// RotateEntry is an entry re-encrypted under the new bundle key.
+type RotateEntry struct {
+	ID   uuid.UUID
+	Data entry.Entry
+}
+
+// RotateKey is the new bundle key wrapped for a remaining member.
+type RotateKey struct {
+	UserID uuid.UUID
+	Data   key.Key
 }
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
@@ -50,9 +51,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (bundlebus.Storer, error) {
 func (s *Store) Create(ctx context.Context, bdl bundlebus.Bundle) error {
 	const q = `
     INSERT INTO bundles
-        (bundle_id, user_id, type, metadata, date_created, date_updated)
+        (bundle_id, user_id, type, metadata, key_generation, revision, date_created, date_updated)
     VALUES
-        (:bundle_id, :user_id, :type, :metadata, :date_created, :date_updated)`
+        (:bundle_id, :user_id, :type, :metadata, :key_generation, :revision, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBBundle(bdl)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -61,19 +62,37 @@ func (s *Store) Create(ctx context.Context, bdl bundlebus.Bundle) error {
 	return nil
 }
 
-// Delete removes a bundle from the database.
-func (s *Store) Delete(ctx context.Context, bdl bundlebus.Bundle) error {
+// SetDeleted moves a bundle in or out of the trash.
+func (s *Store) SetDeleted(ctx context.Context, bdl bundlebus.Bundle) error {
+	const q = `
+    UPDATE
+        bundles
+    SET
+        "date_deleted" = :date_deleted
+    WHERE
+        bundle_id = :bundle_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBBundle(bdl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Purge removes the bundles moved to the trash before the specified time from
+// the database. Their entries, keys and invites are removed by cascade.
+func (s *Store) Purge(ctx context.Context, before time.Time) error {
 	data := struct {
-		ID string `db:"bundle_id"`
+		Before time.Time `db:"before"`
 	}{
-		ID: bdl.ID.String(),
+		Before: before.UTC(),
 	}
 
 	const q = `
     DELETE FROM
 	    bundles
 	WHERE
-	  	bundle_id = :bundle_id`
+	  	date_deleted < :before`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -82,7 +101,10 @@ func (s *Store) Delete(ctx context.Context, bdl bundlebus.Bundle) error {
 	return nil
 }
 
-// Update replaces a bundle document in the database.
+// Update replaces a bundle document in the database. The update only applies
+// while the bundle is still at the key generation and the revision before the
+// one of the specified bundle, so metadata encrypted under a rotated key and
+// writes based on an outdated read are rejected.
 func (s *Store) Update(ctx context.Context, bdl bundlebus.Bundle) error {
 	const q = `
     UPDATE
@@ -90,12 +112,55 @@ func (s *Store) Update(ctx context.Context, bdl bundlebus.Bundle) error {
     SET
         "type"          = :type,
 		"metadata"      = :metadata,
+        "revision"      = :revision,
         "date_updated"  = :date_updated
     WHERE
-        bundle_id = :bundle_id`
+        bundle_id = :bundle_id
+        AND key_generation = :key_generation
+        AND revision = :revision - 1
+    RETURNING
+        bundle_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"bundle_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBBundle(bdl), &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", bundlebus.ErrConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBBundle(bdl)); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	return nil
+}
+
+// Rotate moves the bundle to the next key generation. It fails if the bundle
+// has been written since the specified bundle was read, which includes another
+// rotation moving the bundle past the previous generation.
+func (s *Store) Rotate(ctx context.Context, bdl bundlebus.Bundle) error {
+	const q = `
+    UPDATE
+        bundles
+    SET
+		"metadata"       = :metadata,
+        "key_generation" = :key_generation,
+        "revision"       = :revision,
+        "date_updated"   = :date_updated
+    WHERE
+        bundle_id = :bundle_id
+        AND key_generation = :key_generation - 1
+        AND revision = :revision - 1
+    RETURNING
+        bundle_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"bundle_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBBundle(bdl), &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", bundlebus.ErrConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 
 	return nil
@@ -110,7 +175,7 @@ func (s *Store) Query(ctx context.Context, filter bundlebus.QueryFilter, orderBy
 
 	const q = `
     SELECT
-	    bundle_id, user_id, type, metadata, date_created, date_updated
+	    bundle_id, user_id, type, metadata, key_generation, revision, date_created, date_updated, date_deleted
 	FROM
 	  	bundles`
 
@@ -171,7 +236,7 @@ func (s *Store) QueryByID(ctx context.Context, bundleID uuid.UUID) (bundlebus.Bu
 
 	const q = `
     SELECT
-	  	bundle_id, user_id, type, metadata, date_created, date_updated
+	  	bundle_id, user_id, type, metadata, key_generation, revision, date_created, date_updated, date_deleted
     FROM
         bundles
     WHERE
@@ -198,11 +263,11 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]bundlebu
 
 	const q = `
 	SELECT
-	    bundle_id, user_id, type, metadata, date_created, date_updated
+	    bundle_id, user_id, type, metadata, key_generation, revision, date_created, date_updated, date_deleted
 	FROM
 		bundles
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND date_deleted IS NULL`
 
 	var dbBdls []bundle
 	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbBdls); err != nil {
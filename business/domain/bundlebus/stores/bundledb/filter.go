@@ -35,8 +35,13 @@ func (s *Store) applyFilter(filter bundlebus.QueryFilter, data map[string]any, b
 		wc = append(wc, "date_created <= :end_date_created")
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	switch {
+	case filter.Deleted != nil && *filter.Deleted:
+		wc = append(wc, "date_deleted IS NOT NULL")
+	default:
+		wc = append(wc, "date_deleted IS NULL")
 	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
 }
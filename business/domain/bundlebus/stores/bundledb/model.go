@@ -1,6 +1,7 @@
 package bundledb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -10,22 +11,31 @@ import (
 )
 
 type bundle struct {
-	ID          uuid.UUID `db:"bundle_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Type        string    `db:"type"`
-	Metadata    string    `db:"metadata"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID            uuid.UUID    `db:"bundle_id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	Type          string       `db:"type"`
+	Metadata      string       `db:"metadata"`
+	KeyGeneration int          `db:"key_generation"`
+	Revision      int          `db:"revision"`
+	DateCreated   time.Time    `db:"date_created"`
+	DateUpdated   time.Time    `db:"date_updated"`
+	DateDeleted   sql.NullTime `db:"date_deleted"`
 }
 
 func toDBBundle(bus bundlebus.Bundle) bundle {
 	db := bundle{
-		ID:          bus.ID,
-		UserID:      bus.UserID,
-		Type:        bus.Type.String(),
-		Metadata:    bus.Metadata, // TODO make metadata a type
-		DateCreated: bus.DateCreated.UTC(),
-		DateUpdated: bus.DateUpdated.UTC(),
+		ID:            bus.ID,
+		UserID:        bus.UserID,
+		Type:          bus.Type.String(),
+		Metadata:      bus.Metadata, // TODO make metadata a type
+		KeyGeneration: bus.KeyGeneration,
+		Revision:      bus.Revision,
+		DateCreated:   bus.DateCreated.UTC(),
+		DateUpdated:   bus.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  bus.DateDeleted.UTC(),
+			Valid: !bus.DateDeleted.IsZero(),
+		},
 	}
 
 	return db
@@ -38,12 +48,18 @@ func toBusBundle(db bundle) (bundlebus.Bundle, error) {
 	}
 
 	bus := bundlebus.Bundle{
-		ID:          db.ID,
-		UserID:      db.UserID,
-		Type:        typ,
-		Metadata:    db.Metadata,
-		DateCreated: db.DateCreated.In(time.Local),
-		DateUpdated: db.DateUpdated.In(time.Local),
+		ID:            db.ID,
+		UserID:        db.UserID,
+		Type:          typ,
+		Metadata:      db.Metadata,
+		KeyGeneration: db.KeyGeneration,
+		Revision:      db.Revision,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
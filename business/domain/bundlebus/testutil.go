@@ -49,3 +49,23 @@ func TestGenerateSeedBundles(ctx context.Context, n int, api *Business, userID u
 
 	return bdls, nil
 }
+
+// TestGenerateSeedShareableBundles is a helper method for testing bundles
+// that keys for other users are seeded into.
+func TestGenerateSeedShareableBundles(ctx context.Context, n int, api *Business, userID uuid.UUID) ([]Bundle, error) {
+	newBdls := TestGenerateNewBundles(n, userID)
+
+	bdls := make([]Bundle, len(newBdls))
+	for i, nh := range newBdls {
+		nh.Type = bundletype.Shareable
+
+		bdl, err := api.Create(ctx, nh)
+		if err != nil {
+			return nil, fmt.Errorf("seeding bundle: idx: %d : %w", i, err)
+		}
+
+		bdls[i] = bdl
+	}
+
+	return bdls, nil
+}
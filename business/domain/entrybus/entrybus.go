@@ -19,8 +19,15 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound     = errors.New("entry not found")
-	ErrUserDisabled = errors.New("user disabled")
+	ErrNotFound        = errors.New("entry not found")
+	ErrUserDisabled    = errors.New("user disabled")
+	ErrVersionNotFound = errors.New("entry version not found")
+	ErrKeyGeneration   = errors.New("entry data is not encrypted under the key generation")
+	ErrSchemaVersion   = errors.New("schema version is not supported by the item type")
+	ErrConflict        = errors.New("entry has been modified since it was read")
+	ErrBulkFailed      = errors.New("one or more bulk operations failed")
+	ErrBulkAction      = errors.New("bulk action is not supported")
+	ErrBulkDuplicate   = errors.New("entry appears more than once in the bulk request")
 )
 
 // Storer interface declares the behavior this package needs to persist and
@@ -28,12 +35,22 @@ var (
 type Storer interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, k Entry) error
+	CreateMany(ctx context.Context, entries []Entry) error
 	Update(ctx context.Context, k Entry) error
-	Delete(ctx context.Context, k Entry) error
+	SetDeleted(ctx context.Context, k Entry) error
+	Purge(ctx context.Context, before time.Time) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Entry, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error)
+	QueryByIDs(ctx context.Context, entryIDs []uuid.UUID) ([]Entry, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Entry, error)
+	QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]Entry, error)
+	CreateVersion(ctx context.Context, v Version) error
+	CreateVersions(ctx context.Context, vers []Version) error
+	QueryVersions(ctx context.Context, entryID uuid.UUID, page page.Page) ([]Version, error)
+	CountVersions(ctx context.Context, entryID uuid.UUID) (int, error)
+	QueryVersionByID(ctx context.Context, versionID uuid.UUID) (Version, error)
+	DeleteVersions(ctx context.Context, maxCount int, before time.Time) error
 }
 
 // Business manages the set of APIs for entry access.
@@ -81,7 +98,9 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 	return &bus, nil
 }
 
-// Create adds a new entry to the system.
+// Create adds a new entry to the system. The data must be encrypted under the
+// key generation of the entry and the schema version must be supported by the
+// item type.
 func (b *Business) Create(ctx context.Context, ne NewEntry) (Entry, error) {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.create")
 	defer span.End()
@@ -95,15 +114,27 @@ func (b *Business) Create(ctx context.Context, ne NewEntry) (Entry, error) {
 		return Entry{}, ErrUserDisabled
 	}
 
+	if ne.Data.KeyGeneration() != ne.KeyGeneration {
+		return Entry{}, ErrKeyGeneration
+	}
+
+	if !ne.Type.SupportsSchemaVersion(ne.SchemaVersion) {
+		return Entry{}, ErrSchemaVersion
+	}
+
 	now := time.Now()
 
 	e := Entry{
-		ID:          uuid.New(),
-		Data:        ne.Data,
-		UserID:      ne.UserID,
-		BundleID:    ne.BundleID,
-		DateCreated: now,
-		DateUpdated: now,
+		ID:            uuid.New(),
+		Data:          ne.Data,
+		UserID:        ne.UserID,
+		BundleID:      ne.BundleID,
+		Type:          ne.Type,
+		SchemaVersion: ne.SchemaVersion,
+		KeyGeneration: ne.KeyGeneration,
+		Revision:      1,
+		DateCreated:   now,
+		DateUpdated:   now,
 	}
 
 	if err := b.storer.Create(ctx, e); err != nil {
@@ -113,11 +144,23 @@ func (b *Business) Create(ctx context.Context, ne NewEntry) (Entry, error) {
 	return e, nil
 }
 
-// Update modifies information about a entry.
+// Update modifies information about a entry. The current state of the entry
+// is kept as a version. The update is rejected if the entry has been written
+// since the specified entry was read.
 func (b *Business) Update(ctx context.Context, e Entry, ue UpdateEntry) (Entry, error) {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.update")
 	defer span.End()
 
+	prev := e
+
+	if ue.Type != nil {
+		e.Type = *ue.Type
+	}
+
+	if ue.SchemaVersion != nil {
+		e.SchemaVersion = *ue.SchemaVersion
+	}
+
 	if ue.Data != nil {
 		e.Data = *ue.Data
 	}
@@ -126,28 +169,187 @@ func (b *Business) Update(ctx context.Context, e Entry, ue UpdateEntry) (Entry,
 		e.UserID = *ue.UserID
 	}
 
+	if ue.KeyGeneration != nil {
+		e.KeyGeneration = *ue.KeyGeneration
+	}
+
+	if e.Data.KeyGeneration() != e.KeyGeneration {
+		return Entry{}, ErrKeyGeneration
+	}
+
+	if !e.Type.SupportsSchemaVersion(e.SchemaVersion) {
+		return Entry{}, ErrSchemaVersion
+	}
+
+	e.Revision++
 	e.DateUpdated = time.Now()
 
 	if err := b.storer.Update(ctx, e); err != nil {
 		return Entry{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := b.archive(ctx, prev); err != nil {
+		return Entry{}, err
+	}
+
 	return e, nil
 }
 
-// Delete removes the specified entry.
+// Delete moves the specified entry to the trash. The current state of the
+// entry is kept as a version.
 func (b *Business) Delete(ctx context.Context, e Entry) error {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.delete")
 	defer span.End()
 
-	if err := b.storer.Delete(ctx, e); err != nil {
-		return fmt.Errorf("delete: %w", err)
+	if err := b.archive(ctx, e); err != nil {
+		return err
+	}
+
+	e.DateDeleted = time.Now()
+
+	if err := b.storer.SetDeleted(ctx, e); err != nil {
+		return fmt.Errorf("setdeleted: %w", err)
 	}
 
 	return nil
 }
 
-// Query retrieves a list of existing entries.
+// Bulk applies the operations to the entries of the specified bundle on behalf
+// of the user. Every operation is checked before anything is written, if one
+// or more fail the results hold the reason and ErrBulkFailed is returned so
+// the caller can roll back. New entries and versions are written with batch
+// inserts.
+func (b *Business) Bulk(ctx context.Context, userID uuid.UUID, bundleID uuid.UUID, keyGeneration int, ops []BulkOperation) ([]BulkResult, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.bulk")
+	defer span.End()
+
+	usr, err := b.userBus.QueryByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user.querybyid: %s: %w", userID, err)
+	}
+
+	if !usr.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	// -------------------------------------------------------------------------
+	// Load the entries being updated or deleted.
+
+	var ids []uuid.UUID
+	for _, op := range ops {
+		if op.Action == BulkUpdate || op.Action == BulkDelete {
+			ids = append(ids, op.EntryID)
+		}
+	}
+
+	existing := make(map[uuid.UUID]Entry, len(ids))
+	if len(ids) > 0 {
+		entries, err := b.storer.QueryByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("querybyids: %w", err)
+		}
+
+		for _, e := range entries {
+			// Entries of other bundles and entries in the trash are treated
+			// as not existing.
+			if e.BundleID == bundleID && e.DateDeleted.IsZero() {
+				existing[e.ID] = e
+			}
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Check every operation.
+
+	now := time.Now()
+	results := make([]BulkResult, len(ops))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	failed := false
+
+	for i, op := range ops {
+		results[i] = checkBulkOperation(op, userID, bundleID, keyGeneration, now, existing, seen)
+		if results[i].Err != nil {
+			failed = true
+		}
+	}
+
+	if failed {
+		return results, ErrBulkFailed
+	}
+
+	// -------------------------------------------------------------------------
+	// Apply the operations.
+
+	var creates []Entry
+	var vers []Version
+
+	for i, res := range results {
+		switch res.Action {
+		case BulkCreate:
+			creates = append(creates, res.Entry)
+
+		case BulkUpdate:
+			if err := b.storer.Update(ctx, res.Entry); err != nil {
+				if errors.Is(err, ErrConflict) {
+					results[i].Err = ErrConflict
+					return results, ErrBulkFailed
+				}
+				return nil, fmt.Errorf("update: entryID[%s]: %w", res.Entry.ID, err)
+			}
+			vers = append(vers, newVersion(existing[res.Entry.ID], now))
+
+		case BulkDelete:
+			if err := b.storer.SetDeleted(ctx, res.Entry); err != nil {
+				return nil, fmt.Errorf("setdeleted: entryID[%s]: %w", res.Entry.ID, err)
+			}
+			vers = append(vers, newVersion(existing[res.Entry.ID], now))
+		}
+	}
+
+	if len(creates) > 0 {
+		if err := b.storer.CreateMany(ctx, creates); err != nil {
+			return nil, fmt.Errorf("createmany: %w", err)
+		}
+	}
+
+	if len(vers) > 0 {
+		if err := b.storer.CreateVersions(ctx, vers); err != nil {
+			return nil, fmt.Errorf("createversions: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// Undelete moves the specified entry out of the trash.
+func (b *Business) Undelete(ctx context.Context, e Entry) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.undelete")
+	defer span.End()
+
+	e.DateDeleted = time.Time{}
+
+	if err := b.storer.SetDeleted(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("setdeleted: %w", err)
+	}
+
+	return e, nil
+}
+
+// PurgeDeleted permanently removes the entries that have been in the trash
+// for longer than the retention period, along with their versions.
+func (b *Business) PurgeDeleted(ctx context.Context, retention time.Duration) error {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.purgedeleted")
+	defer span.End()
+
+	if err := b.storer.Purge(ctx, time.Now().Add(-retention)); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing entries. Entries in the trash are only
+// returned when the filter asks for them.
 func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Entry, error) {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.query")
 	defer span.End()
@@ -168,7 +370,8 @@ func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return b.storer.Count(ctx, filter)
 }
 
-// QueryByID finds the entry by the specified ID.
+// QueryByID finds the entry by the specified ID. An entry in the trash is
+// treated as not existing.
 func (b *Business) QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error) {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.querybyid")
 	defer span.End()
@@ -178,10 +381,32 @@ func (b *Business) QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, err
 		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, err)
 	}
 
+	if !e.DateDeleted.IsZero() {
+		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, ErrNotFound)
+	}
+
 	return e, nil
 }
 
-// QueryByUserID finds the entries by a specified User ID.
+// QueryDeletedByID finds the entry in the trash by the specified ID.
+func (b *Business) QueryDeletedByID(ctx context.Context, entryID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.querydeletedbyid")
+	defer span.End()
+
+	e, err := b.storer.QueryByID(ctx, entryID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, err)
+	}
+
+	if e.DateDeleted.IsZero() {
+		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, ErrNotFound)
+	}
+
+	return e, nil
+}
+
+// QueryByUserID finds the entries by a specified User ID, excluding the
+// entries in the trash.
 func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Entry, error) {
 	ctx, span := otel.AddSpan(ctx, "business.entrybus.querybyuserid")
 	defer span.End()
@@ -193,3 +418,191 @@ func (b *Business) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Entry
 
 	return entries, nil
 }
+
+// QueryByBundleID finds the entries in the specified bundle. Entries in the
+// trash are included since they are still encrypted under the bundle key.
+func (b *Business) QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.querybybundleid")
+	defer span.End()
+
+	entries, err := b.storer.QueryByBundleID(ctx, bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return entries, nil
+}
+
+// QueryVersions retrieves the versions of the specified entry, newest first.
+func (b *Business) QueryVersions(ctx context.Context, entryID uuid.UUID, page page.Page) ([]Version, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.queryversions")
+	defer span.End()
+
+	vers, err := b.storer.QueryVersions(ctx, entryID, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: entryID[%s]: %w", entryID, err)
+	}
+
+	return vers, nil
+}
+
+// CountVersions returns the total number of versions of the specified entry.
+func (b *Business) CountVersions(ctx context.Context, entryID uuid.UUID) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.countversions")
+	defer span.End()
+
+	return b.storer.CountVersions(ctx, entryID)
+}
+
+// QueryVersionByID finds the version of the entry by the specified ID.
+func (b *Business) QueryVersionByID(ctx context.Context, e Entry, versionID uuid.UUID) (Version, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.queryversionbyid")
+	defer span.End()
+
+	v, err := b.storer.QueryVersionByID(ctx, versionID)
+	if err != nil {
+		return Version{}, fmt.Errorf("query: versionID[%s]: %w", versionID, err)
+	}
+
+	// A version of another entry is treated as not existing.
+	if v.EntryID != e.ID {
+		return Version{}, fmt.Errorf("query: versionID[%s]: %w", versionID, ErrVersionNotFound)
+	}
+
+	return v, nil
+}
+
+// Restore replaces the data and item type of the entry with those of the
+// specified version. The data being replaced is kept as a new version.
+func (b *Business) Restore(ctx context.Context, e Entry, v Version, userID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.restore")
+	defer span.End()
+
+	if v.EntryID != e.ID {
+		return Entry{}, ErrVersionNotFound
+	}
+
+	ue := UpdateEntry{
+		Type:          &v.Type,
+		SchemaVersion: &v.SchemaVersion,
+		Data:          &v.Data,
+		UserID:        &userID,
+		KeyGeneration: &v.KeyGeneration,
+	}
+
+	return b.Update(ctx, e, ue)
+}
+
+// PurgeVersions removes the versions that fall outside the retention policy.
+func (b *Business) PurgeVersions(ctx context.Context, vr VersionRetention) error {
+	ctx, span := otel.AddSpan(ctx, "business.entrybus.purgeversions")
+	defer span.End()
+
+	var before time.Time
+	if vr.MaxAge > 0 {
+		before = time.Now().Add(-vr.MaxAge)
+	}
+
+	if err := b.storer.DeleteVersions(ctx, vr.MaxCount, before); err != nil {
+		return fmt.Errorf("deleteversions: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// archive writes the current state of the entry as a version.
+func (b *Business) archive(ctx context.Context, e Entry) error {
+	if err := b.storer.CreateVersion(ctx, newVersion(e, time.Now())); err != nil {
+		return fmt.Errorf("createversion: %w", err)
+	}
+
+	return nil
+}
+
+// newVersion returns the current state of the entry as a version archived at
+// the specified time.
+func newVersion(e Entry, archived time.Time) Version {
+	return Version{
+		ID:            uuid.New(),
+		EntryID:       e.ID,
+		BundleID:      e.BundleID,
+		UserID:        e.UserID,
+		Type:          e.Type,
+		SchemaVersion: e.SchemaVersion,
+		Data:          e.Data,
+		KeyGeneration: e.KeyGeneration,
+		DateCreated:   e.DateUpdated,
+		DateArchived:  archived,
+	}
+}
+
+// checkBulkOperation validates the operation and returns the state of the
+// entry once the operation is applied.
+func checkBulkOperation(op BulkOperation, userID uuid.UUID, bundleID uuid.UUID, keyGeneration int, now time.Time, existing map[uuid.UUID]Entry, seen map[uuid.UUID]bool) BulkResult {
+	res := BulkResult{
+		Action: op.Action,
+	}
+
+	switch op.Action {
+	case BulkCreate:
+		res.Entry = Entry{
+			ID:            uuid.New(),
+			UserID:        userID,
+			BundleID:      bundleID,
+			Type:          op.Type,
+			SchemaVersion: op.SchemaVersion,
+			Data:          op.Data,
+			KeyGeneration: keyGeneration,
+			Revision:      1,
+			DateCreated:   now,
+			DateUpdated:   now,
+		}
+
+	case BulkUpdate, BulkDelete:
+		e, exists := existing[op.EntryID]
+		switch {
+		case !exists:
+			res.Err = ErrNotFound
+			return res
+		case seen[op.EntryID]:
+			res.Err = ErrBulkDuplicate
+			return res
+		case op.Revision != 0 && op.Revision != e.Revision:
+			res.Err = ErrConflict
+			return res
+		}
+		seen[op.EntryID] = true
+
+		if op.Action == BulkDelete {
+			e.DateDeleted = now
+			res.Entry = e
+			return res
+		}
+
+		e.UserID = userID
+		e.Type = op.Type
+		e.SchemaVersion = op.SchemaVersion
+		e.Data = op.Data
+		e.KeyGeneration = keyGeneration
+		e.Revision++
+		e.DateUpdated = now
+		res.Entry = e
+
+	default:
+		res.Err = ErrBulkAction
+		return res
+	}
+
+	if res.Entry.Data.KeyGeneration() != res.Entry.KeyGeneration {
+		res.Err = ErrKeyGeneration
+		return res
+	}
+
+	if !res.Entry.Type.SupportsSchemaVersion(res.Entry.SchemaVersion) {
+		res.Err = ErrSchemaVersion
+	}
+
+	return res
+}
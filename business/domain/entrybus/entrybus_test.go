@@ -2,6 +2,7 @@ package entrybus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -15,7 +16,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
-	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 	"github.com/gradientsearch/pwmanager/business/types/role"
 )
 
@@ -35,6 +36,9 @@ func Test_Entry(t *testing.T) {
 	unitest.Run(t, create(db.BusDomain, sd), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
+	unitest.Run(t, versions(db.BusDomain, sd), "versions")
+	unitest.Run(t, trash(db.BusDomain, sd), "trash")
+	unitest.Run(t, bulk(db.BusDomain, sd), "bulk")
 }
 
 // =============================================================================
@@ -117,6 +121,13 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		return entries[i].ID.String() <= entries[j].ID.String()
 	})
 
+	var logins []entrybus.Entry
+	for _, e := range entries {
+		if e.Type == itemtype.Login {
+			logins = append(logins, e)
+		}
+	}
+
 	table := []unitest.Table{
 		{
 			Name:    "all",
@@ -154,6 +165,43 @@ func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "bytype",
+			ExpResp: logins,
+			ExcFunc: func(ctx context.Context) any {
+				filter := entrybus.QueryFilter{
+					UserID: &sd.Users[0].ID,
+					Type:   &itemtype.Login,
+				}
+
+				resp, err := busDomain.Entry.Query(ctx, filter, entrybus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]entrybus.Entry)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]entrybus.Entry)
+
+				for i := range gotResp {
+					if gotResp[i].DateCreated.Format(time.RFC3339) == expResp[i].DateCreated.Format(time.RFC3339) {
+						expResp[i].DateCreated = gotResp[i].DateCreated
+					}
+
+					if gotResp[i].DateUpdated.Format(time.RFC3339) == expResp[i].DateUpdated.Format(time.RFC3339) {
+						expResp[i].DateUpdated = gotResp[i].DateUpdated
+					}
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
 		{
 			Name:    "byid",
 			ExpResp: sd.Users[0].Entries[0],
@@ -194,15 +242,22 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: entrybus.Entry{
-				UserID:   sd.Users[0].ID,
-				BundleID: sd.Users[0].Bundles[2].ID,
-				Data:     entry.MustParse("Guitar"),
+				UserID:        sd.Users[0].ID,
+				BundleID:      sd.Users[0].Bundles[2].ID,
+				Type:          itemtype.Card,
+				SchemaVersion: 1,
+				Data:          entrybus.TestNewData("Guitar", 1),
+				KeyGeneration: 1,
+				Revision:      1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nk := entrybus.NewEntry{
-					UserID:   sd.Users[0].ID,
-					BundleID: sd.Users[0].Bundles[2].ID,
-					Data:     entry.MustParse("Guitar"),
+					UserID:        sd.Users[0].ID,
+					BundleID:      sd.Users[0].Bundles[2].ID,
+					Type:          itemtype.Card,
+					SchemaVersion: 1,
+					Data:          entrybus.TestNewData("Guitar", 1),
+					KeyGeneration: 1,
 				}
 
 				resp, err := busDomain.Entry.Create(ctx, nk)
@@ -227,6 +282,42 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "key-generation",
+			ExpResp: entrybus.ErrKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				nk := entrybus.NewEntry{
+					UserID:        sd.Users[0].ID,
+					BundleID:      sd.Users[0].Bundles[2].ID,
+					Type:          itemtype.Login,
+					SchemaVersion: 1,
+					Data:          entrybus.TestNewData("Guitar", 2),
+					KeyGeneration: 1,
+				}
+
+				_, err := busDomain.Entry.Create(ctx, nk)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "schema-version",
+			ExpResp: entrybus.ErrSchemaVersion,
+			ExcFunc: func(ctx context.Context) any {
+				nk := entrybus.NewEntry{
+					UserID:        sd.Users[0].ID,
+					BundleID:      sd.Users[0].Bundles[2].ID,
+					Type:          itemtype.SecureNote,
+					SchemaVersion: itemtype.SecureNote.SchemaVersion() + 1,
+					Data:          entrybus.TestNewData("Guitar", 1),
+					KeyGeneration: 1,
+				}
+
+				_, err := busDomain.Entry.Create(ctx, nk)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
 	}
 
 	return table
@@ -237,16 +328,21 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 		{
 			Name: "basic",
 			ExpResp: entrybus.Entry{
-				ID:          sd.Users[0].Entries[0].ID,
-				BundleID:    sd.Users[0].Bundles[0].ID,
-				UserID:      sd.Users[0].ID,
-				Data:        entry.MustParse("Guitar"),
-				DateCreated: sd.Users[0].Entries[0].DateCreated,
-				DateUpdated: sd.Users[0].Entries[0].DateCreated,
+				ID:            sd.Users[0].Entries[0].ID,
+				BundleID:      sd.Users[0].Bundles[0].ID,
+				UserID:        sd.Users[0].ID,
+				Type:          itemtype.SecureNote,
+				SchemaVersion: 1,
+				Data:          entrybus.TestNewData("Guitar", 1),
+				KeyGeneration: 1,
+				Revision:      2,
+				DateCreated:   sd.Users[0].Entries[0].DateCreated,
+				DateUpdated:   sd.Users[0].Entries[0].DateCreated,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				uk := entrybus.UpdateEntry{
-					Data: dbtest.EntryPointer("Guitar"),
+					Type: dbtest.ItemTypePointer("SECURE_NOTE"),
+					Data: dbtest.EntryPointer(entrybus.TestNewData("Guitar", 1).String()),
 				}
 
 				resp, err := busDomain.Entry.Update(ctx, sd.Users[0].Entries[0], uk)
@@ -269,6 +365,20 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "stale-revision",
+			ExpResp: entrybus.ErrConflict,
+			ExcFunc: func(ctx context.Context) any {
+				ue := entrybus.UpdateEntry{
+					Data: dbtest.EntryPointer(entrybus.TestNewData("Stale", 1).String()),
+				}
+
+				// The seeded entry was read before the basic update above.
+				_, err := busDomain.Entry.Update(ctx, sd.Users[0].Entries[0], ue)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
 	}
 
 	return table
@@ -308,3 +418,377 @@ func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func versions(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	seeded := sd.Users[0].Entries[0]
+
+	table := []unitest.Table{
+		{
+			Name: "update",
+			ExpResp: []entrybus.Version{
+				{
+					EntryID:       seeded.ID,
+					BundleID:      seeded.BundleID,
+					UserID:        seeded.UserID,
+					Type:          seeded.Type,
+					SchemaVersion: seeded.SchemaVersion,
+					Data:          seeded.Data,
+					KeyGeneration: seeded.KeyGeneration,
+					DateCreated:   seeded.DateUpdated,
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Entry.QueryVersions(ctx, seeded.ID, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]entrybus.Version)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.([]entrybus.Version)
+
+				for i := range gotResp {
+					if i < len(expResp) {
+						expResp[i].ID = gotResp[i].ID
+						expResp[i].DateArchived = gotResp[i].DateArchived
+					}
+
+					// Timestamps lose precision in the database.
+					gotResp[i].DateCreated = gotResp[i].DateCreated.Truncate(time.Second)
+				}
+
+				for i := range expResp {
+					expResp[i].DateCreated = expResp[i].DateCreated.Truncate(time.Second)
+				}
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "delete",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				count, err := busDomain.Entry.CountVersions(ctx, sd.Users[0].Entries[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "restore",
+			ExpResp: seeded.Data,
+			ExcFunc: func(ctx context.Context) any {
+				e, err := busDomain.Entry.QueryByID(ctx, seeded.ID)
+				if err != nil {
+					return err
+				}
+
+				vers, err := busDomain.Entry.QueryVersions(ctx, seeded.ID, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Entry.Restore(ctx, e, vers[0], sd.Users[0].ID); err != nil {
+					return err
+				}
+
+				e, err = busDomain.Entry.QueryByID(ctx, seeded.ID)
+				if err != nil {
+					return err
+				}
+
+				return e.Data
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "other-entry",
+			ExpResp: entrybus.ErrVersionNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				vers, err := busDomain.Entry.QueryVersions(ctx, seeded.ID, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Entry.QueryVersionByID(ctx, sd.Admins[0].Entries[0], vers[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, exists := got.(error)
+				if !exists || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("expected error %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Entry.PurgeVersions(ctx, entrybus.VersionRetention{MaxCount: 1}); err != nil {
+					return err
+				}
+
+				count, err := busDomain.Entry.CountVersions(ctx, seeded.ID)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func trash(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "excluded",
+			ExpResp: entrybus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Entry.QueryByID(ctx, sd.Users[0].Entries[1].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "count",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				deleted := true
+				filter := entrybus.QueryFilter{
+					UserID:  &sd.Users[0].ID,
+					Deleted: &deleted,
+				}
+
+				count, err := busDomain.Entry.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "undelete",
+			ExpResp: sd.Users[0].Entries[1].ID,
+			ExcFunc: func(ctx context.Context) any {
+				e, err := busDomain.Entry.QueryDeletedByID(ctx, sd.Users[0].Entries[1].ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Entry.Undelete(ctx, e); err != nil {
+					return err
+				}
+
+				e, err = busDomain.Entry.QueryByID(ctx, sd.Users[0].Entries[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return e.ID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "purge",
+			ExpResp: entrybus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Entry.PurgeDeleted(ctx, 0); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Entry.QueryDeletedByID(ctx, sd.Admins[0].Entries[1].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "purge-versions",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				count, err := busDomain.Entry.CountVersions(ctx, sd.Admins[0].Entries[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func bulk(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	type result struct {
+		Action   string
+		Revision int
+		Err      error
+	}
+
+	toResults := func(results []entrybus.BulkResult) []result {
+		res := make([]result, len(results))
+		for i, r := range results {
+			res[i] = result{Action: r.Action, Revision: r.Entry.Revision, Err: r.Err}
+		}
+		return res
+	}
+
+	cmpResults := func(got any, exp any) string {
+		return cmp.Diff(got, exp, cmp.Comparer(func(x, y error) bool {
+			return errors.Is(x, y)
+		}))
+	}
+
+	usrID := sd.Users[0].ID
+	bdlID := sd.Users[0].Bundles[2].ID
+
+	table := []unitest.Table{
+		{
+			Name: "apply",
+			ExpResp: []result{
+				{Action: entrybus.BulkUpdate, Revision: 2},
+				{Action: entrybus.BulkDelete, Revision: 1},
+				{Action: entrybus.BulkCreate, Revision: 1},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				ops := []entrybus.BulkOperation{
+					{Action: entrybus.BulkCreate, Type: itemtype.Login, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk0", 1)},
+					{Action: entrybus.BulkCreate, Type: itemtype.Login, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk1", 1)},
+				}
+
+				created, err := busDomain.Entry.Bulk(ctx, usrID, bdlID, 1, ops)
+				if err != nil {
+					return err
+				}
+
+				ops = []entrybus.BulkOperation{
+					{Action: entrybus.BulkUpdate, EntryID: created[0].Entry.ID, Revision: 1, Type: itemtype.SecureNote, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk0-Updated", 1)},
+					{Action: entrybus.BulkDelete, EntryID: created[1].Entry.ID, Revision: 1},
+					{Action: entrybus.BulkCreate, Type: itemtype.Card, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk2", 1)},
+				}
+
+				results, err := busDomain.Entry.Bulk(ctx, usrID, bdlID, 1, ops)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Entry.QueryByID(ctx, created[1].Entry.ID); !errors.Is(err, entrybus.ErrNotFound) {
+					return fmt.Errorf("deleted entry should be in the trash: %w", err)
+				}
+
+				count, err := busDomain.Entry.CountVersions(ctx, created[0].Entry.ID)
+				if err != nil {
+					return err
+				}
+
+				if count != 1 {
+					return fmt.Errorf("updated entry should have 1 version, got %d", count)
+				}
+
+				return toResults(results)
+			},
+			CmpFunc: cmpResults,
+		},
+		{
+			Name: "failed",
+			ExpResp: []result{
+				{Action: entrybus.BulkCreate, Err: entrybus.ErrKeyGeneration},
+				{Action: entrybus.BulkUpdate, Err: entrybus.ErrNotFound},
+				{Action: entrybus.BulkDelete},
+				{Action: entrybus.BulkDelete, Err: entrybus.ErrBulkDuplicate},
+				{Action: "move", Err: entrybus.ErrBulkAction},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				e := sd.Users[0].Entries[0]
+
+				ops := []entrybus.BulkOperation{
+					{Action: entrybus.BulkCreate, Type: itemtype.Login, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk", 2)},
+					{Action: entrybus.BulkUpdate, EntryID: uuid.New(), Type: itemtype.Login, SchemaVersion: 1, Data: entrybus.TestNewData("Bulk", 1)},
+					{Action: entrybus.BulkDelete, EntryID: e.ID},
+					{Action: entrybus.BulkDelete, EntryID: e.ID},
+					{Action: "move", EntryID: e.ID},
+				}
+
+				results, err := busDomain.Entry.Bulk(ctx, usrID, e.BundleID, 1, ops)
+				if !errors.Is(err, entrybus.ErrBulkFailed) {
+					return fmt.Errorf("expected error %v, got %v", entrybus.ErrBulkFailed, err)
+				}
+
+				// Nothing is written when an operation fails.
+				if _, err := busDomain.Entry.QueryByID(ctx, e.ID); err != nil {
+					return err
+				}
+
+				// Only the outcome of each operation matters here.
+				res := toResults(results)
+				for i := range res {
+					res[i].Revision = 0
+				}
+
+				return res
+			},
+			CmpFunc: cmpResults,
+		},
+		{
+			Name:    "stale-revision",
+			ExpResp: entrybus.ErrConflict,
+			ExcFunc: func(ctx context.Context) any {
+				e := sd.Users[0].Entries[0]
+
+				// The seeded entry has been updated since it was read.
+				ops := []entrybus.BulkOperation{
+					{Action: entrybus.BulkDelete, EntryID: e.ID, Revision: e.Revision},
+				}
+
+				results, err := busDomain.Entry.Bulk(ctx, usrID, e.BundleID, 1, ops)
+				if !errors.Is(err, entrybus.ErrBulkFailed) {
+					return fmt.Errorf("expected error %v, got %v", entrybus.ErrBulkFailed, err)
+				}
+
+				return results[0].Err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
@@ -1,12 +1,26 @@
 package entrybus
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// Entries in the trash are excluded unless Deleted is true, in which case only
+// entries in the trash are included. MemberID limits the entries to the
+// bundles the user holds a key for, excluding bundles in the trash.
 type QueryFilter struct {
-	ID     *uuid.UUID
-	UserID *uuid.UUID
+	ID               *uuid.UUID
+	UserID           *uuid.UUID
+	BundleID         *uuid.UUID
+	MemberID         *uuid.UUID
+	Type             *itemtype.ItemType
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+	StartUpdatedDate *time.Time
+	EndUpdatedDate   *time.Time
+	Deleted          *bool
 }
@@ -5,23 +5,36 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
-// Entry represents an individual entry.
+// Entry represents an individual entry. KeyGeneration is the bundle key
+// generation the data is encrypted under. Type and SchemaVersion are not
+// secret and describe how clients read the decrypted data. Revision counts the
+// writes to the entry, starting at 1, and is used to detect concurrent
+// updates. DateDeleted is set while the entry is in the trash.
 type Entry struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	BundleID    uuid.UUID
-	Data        entry.Entry
-	DateCreated time.Time
-	DateUpdated time.Time
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	BundleID      uuid.UUID
+	Type          itemtype.ItemType
+	SchemaVersion int
+	Data          entry.Entry
+	KeyGeneration int
+	Revision      int
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	DateDeleted   time.Time
 }
 
 // NewEntry is what we require from clients when adding a Entry.
 type NewEntry struct {
-	UserID   uuid.UUID
-	BundleID uuid.UUID
-	Data     entry.Entry
+	UserID        uuid.UUID
+	BundleID      uuid.UUID
+	Type          itemtype.ItemType
+	SchemaVersion int
+	Data          entry.Entry
+	KeyGeneration int
 }
 
 // UpdateEntry defines what information may be provided to modify an
@@ -31,6 +44,60 @@ type NewEntry struct {
 // explicitly blank. Normally we do not want to use pointers to basic types but
 // we make exceptions around marshalling/unmarshalling.
 type UpdateEntry struct {
-	Data   *entry.Entry
-	UserID *uuid.UUID
+	Type          *itemtype.ItemType
+	SchemaVersion *int
+	Data          *entry.Entry
+	UserID        *uuid.UUID
+	KeyGeneration *int
+}
+
+// Version represents a prior state of an entry. A version is written every
+// time an entry is updated or deleted. DateCreated is when the versioned data
+// was written and DateArchived is when it was replaced.
+type Version struct {
+	ID            uuid.UUID
+	EntryID       uuid.UUID
+	BundleID      uuid.UUID
+	UserID        uuid.UUID
+	Type          itemtype.ItemType
+	SchemaVersion int
+	Data          entry.Entry
+	KeyGeneration int
+	DateCreated   time.Time
+	DateArchived  time.Time
+}
+
+// VersionRetention defines how many versions are kept per entry and for how
+// long. A zero value disables that limit.
+type VersionRetention struct {
+	MaxCount int
+	MaxAge   time.Duration
+}
+
+// Set of actions a bulk operation can perform.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkOperation is a single write of a bulk request. EntryID identifies the
+// entry to update or delete and Revision, when not zero, is the revision of
+// the entry the client read. Type, SchemaVersion and Data are the content of
+// the entry to create or the new content of the entry to update.
+type BulkOperation struct {
+	Action        string
+	EntryID       uuid.UUID
+	Revision      int
+	Type          itemtype.ItemType
+	SchemaVersion int
+	Data          entry.Entry
+}
+
+// BulkResult is the outcome of a bulk operation. Entry is the state of the
+// entry after the operation and Err is set when the operation failed.
+type BulkResult struct {
+	Action string
+	Entry  Entry
+	Err    error
 }
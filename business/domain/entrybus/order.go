@@ -7,6 +7,9 @@ var DefaultOrderBy = order.NewBy(OrderByEntryID, order.ASC)
 
 // Set of fields that the results can be ordered by.
 const (
-	OrderByEntryID = "entry_id"
-	OrderByUserID  = "user_id"
+	OrderByEntryID     = "entry_id"
+	OrderByUserID      = "user_id"
+	OrderByType        = "item_type"
+	OrderByDateCreated = "date_created"
+	OrderByDateUpdated = "date_updated"
 )
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
@@ -16,6 +17,10 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// batchSize is the number of rows written by a single batch insert, which
+// keeps the bind parameters well below the limit of postgres.
+const batchSize = 1000
+
 // Store manages the set of APIs for entry database access.
 type Store struct {
 	log *logger.Logger
@@ -51,9 +56,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (entrybus.Storer, error) {
 func (s *Store) Create(ctx context.Context, k entrybus.Entry) error {
 	const q = `
 	INSERT INTO entries
-		(entry_id, user_id, bundle_id, data, date_created, date_updated)
+		(entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated)
 	VALUES
-		(:entry_id, :user_id, :bundle_id, :data, :date_created, :date_updated)`
+		(:entry_id, :user_id, :bundle_id, :item_type, :schema_version, :data, :key_generation, :revision, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(k)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -62,16 +67,61 @@ func (s *Store) Create(ctx context.Context, k entrybus.Entry) error {
 	return nil
 }
 
-// Update modifies data about a entrybus. It will error if the specified ID is
-// invalid or does not reference an existing entrybus.
+// CreateMany adds the entries to the sqldb using batch inserts.
+func (s *Store) CreateMany(ctx context.Context, entries []entrybus.Entry) error {
+	const q = `
+	INSERT INTO entries
+		(entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated)
+	VALUES
+		(:entry_id, :user_id, :bundle_id, :item_type, :schema_version, :data, :key_generation, :revision, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecBatchContext(ctx, s.log, s.db, q, toDBEntries(entries), batchSize); err != nil {
+		return fmt.Errorf("namedexecbatchcontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update modifies data about a entrybus. The update only applies while the
+// entry is still at the revision before the one of the specified entry.
 func (s *Store) Update(ctx context.Context, k entrybus.Entry) error {
 	const q = `
 	UPDATE
 		entries
 	SET
+		"item_type" = :item_type,
+		"schema_version" = :schema_version,
 		"data" = :data,
 		"user_id" = :user_id,
+		"key_generation" = :key_generation,
+		"revision" = :revision,
 		"date_updated" = :date_updated
+	WHERE
+		entry_id = :entry_id
+		AND revision = :revision - 1
+	RETURNING
+		entry_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"entry_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBEntry(k), &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", entrybus.ErrConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// SetDeleted moves an entry in or out of the trash.
+func (s *Store) SetDeleted(ctx context.Context, k entrybus.Entry) error {
+	const q = `
+	UPDATE
+		entries
+	SET
+		"date_deleted" = :date_deleted
 	WHERE
 		entry_id = :entry_id`
 
@@ -82,19 +132,30 @@ func (s *Store) Update(ctx context.Context, k entrybus.Entry) error {
 	return nil
 }
 
-// Delete removes the entry identified by a given ID.
-func (s *Store) Delete(ctx context.Context, k entrybus.Entry) error {
+// Purge removes the entries moved to the trash before the specified time from
+// the database along with their versions.
+func (s *Store) Purge(ctx context.Context, before time.Time) error {
 	data := struct {
-		ID string `db:"entry_id"`
+		Before time.Time `db:"before"`
 	}{
-		ID: k.ID.String(),
+		Before: before.UTC(),
 	}
 
+	// Both deletes run in one statement so the versions of a purged entry are
+	// never left behind, or removed while the entry survives.
 	const q = `
+	WITH purged AS (
+		DELETE FROM
+			entries
+		WHERE
+			date_deleted < :before
+		RETURNING
+			entry_id
+	)
 	DELETE FROM
-		entries
+		entry_versions
 	WHERE
-		entry_id = :entry_id`
+		entry_id IN (SELECT entry_id FROM purged)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -112,7 +173,7 @@ func (s *Store) Query(ctx context.Context, filter entrybus.QueryFilter, orderBy
 
 	const q = `
 	SELECT
-	    entry_id, user_id,  bundle_id, data, date_created, date_updated
+	    entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated, date_deleted
 	FROM
 		entries`
 
@@ -170,7 +231,7 @@ func (s *Store) QueryByID(ctx context.Context, entryID uuid.UUID) (entrybus.Entr
 
 	const q = `
 	SELECT
-	    entry_id, user_id,  bundle_id, data, date_created, date_updated
+	    entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated, date_deleted
 	FROM
 		entries
 	WHERE
@@ -187,6 +248,36 @@ func (s *Store) QueryByID(ctx context.Context, entryID uuid.UUID) (entrybus.Entr
 	return toBusEntry(dbEntry)
 }
 
+// QueryByIDs finds the entries identified by the given IDs, including the
+// entries in the trash.
+func (s *Store) QueryByIDs(ctx context.Context, entryIDs []uuid.UUID) ([]entrybus.Entry, error) {
+	ids := make([]string, len(entryIDs))
+	for i, id := range entryIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		IDs []string `db:"entry_ids"`
+	}{
+		IDs: ids,
+	}
+
+	const q = `
+	SELECT
+	    entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated, date_deleted
+	FROM
+		entries
+	WHERE
+		entry_id IN (:entry_ids)`
+
+	var dbEntries []entry
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedquerysliceusingin: %w", err)
+	}
+
+	return toBusEntries(dbEntries)
+}
+
 // QueryByUserID finds the entry identified by a given User ID.
 func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]entrybus.Entry, error) {
 	data := struct {
@@ -197,11 +288,35 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]entrybus
 
 	const q = `
 	SELECT
-	    entry_id, user_id, date_created, date_updated
+	    entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated, date_deleted
+	FROM
+		entries
+	WHERE
+		user_id = :user_id AND date_deleted IS NULL`
+
+	var dbEntries []entry
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusEntries(dbEntries)
+}
+
+// QueryByBundleID finds the entries in the specified bundle.
+func (s *Store) QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]entrybus.Entry, error) {
+	data := struct {
+		ID string `db:"bundle_id"`
+	}{
+		ID: bundleID.String(),
+	}
+
+	const q = `
+	SELECT
+	    entry_id, user_id, bundle_id, item_type, schema_version, data, key_generation, revision, date_created, date_updated, date_deleted
 	FROM
 		entries
 	WHERE
-		user_id = :user_id`
+		bundle_id = :bundle_id`
 
 	var dbEntries []entry
 	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
@@ -210,3 +325,150 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]entrybus
 
 	return toBusEntries(dbEntries)
 }
+
+// CreateVersion adds a version of an entry to the sqldb.
+func (s *Store) CreateVersion(ctx context.Context, v entrybus.Version) error {
+	const q = `
+	INSERT INTO entry_versions
+		(version_id, entry_id, bundle_id, user_id, item_type, schema_version, data, key_generation, date_created, date_archived)
+	VALUES
+		(:version_id, :entry_id, :bundle_id, :user_id, :item_type, :schema_version, :data, :key_generation, :date_created, :date_archived)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBVersion(v)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// CreateVersions adds the versions to the sqldb using batch inserts.
+func (s *Store) CreateVersions(ctx context.Context, vers []entrybus.Version) error {
+	const q = `
+	INSERT INTO entry_versions
+		(version_id, entry_id, bundle_id, user_id, item_type, schema_version, data, key_generation, date_created, date_archived)
+	VALUES
+		(:version_id, :entry_id, :bundle_id, :user_id, :item_type, :schema_version, :data, :key_generation, :date_created, :date_archived)`
+
+	if err := sqldb.NamedExecBatchContext(ctx, s.log, s.db, q, toDBVersions(vers), batchSize); err != nil {
+		return fmt.Errorf("namedexecbatchcontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryVersions gets the versions of the specified entry, newest first.
+func (s *Store) QueryVersions(ctx context.Context, entryID uuid.UUID, page page.Page) ([]entrybus.Version, error) {
+	data := map[string]any{
+		"entry_id":      entryID.String(),
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+	    version_id, entry_id, bundle_id, user_id, item_type, schema_version, data, key_generation, date_created, date_archived
+	FROM
+		entry_versions
+	WHERE
+		entry_id = :entry_id
+	ORDER BY
+		date_archived DESC
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbVers []version
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbVers); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusVersions(dbVers)
+}
+
+// CountVersions returns the total number of versions of the specified entry.
+func (s *Store) CountVersions(ctx context.Context, entryID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"entry_id"`
+	}{
+		ID: entryID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		entry_versions
+	WHERE
+		entry_id = :entry_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryVersionByID finds the entry version identified by a given ID.
+func (s *Store) QueryVersionByID(ctx context.Context, versionID uuid.UUID) (entrybus.Version, error) {
+	data := struct {
+		ID string `db:"version_id"`
+	}{
+		ID: versionID.String(),
+	}
+
+	const q = `
+	SELECT
+	    version_id, entry_id, bundle_id, user_id, item_type, schema_version, data, key_generation, date_created, date_archived
+	FROM
+		entry_versions
+	WHERE
+		version_id = :version_id`
+
+	var dbVer version
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbVer); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return entrybus.Version{}, fmt.Errorf("db: %w", entrybus.ErrVersionNotFound)
+		}
+		return entrybus.Version{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusVersion(dbVer)
+}
+
+// DeleteVersions removes the versions archived before the specified time and
+// the versions beyond the newest maxCount of each entry. A maxCount of zero
+// keeps any number of versions.
+func (s *Store) DeleteVersions(ctx context.Context, maxCount int, before time.Time) error {
+	data := struct {
+		MaxCount int       `db:"max_count"`
+		Before   time.Time `db:"before"`
+	}{
+		MaxCount: maxCount,
+		Before:   before.UTC(),
+	}
+
+	const q = `
+	DELETE FROM
+		entry_versions
+	WHERE
+		version_id IN (
+			SELECT
+				version_id
+			FROM (
+				SELECT
+					version_id, date_archived,
+					ROW_NUMBER() OVER (PARTITION BY entry_id ORDER BY date_archived DESC) AS position
+				FROM
+					entry_versions
+			) AS v
+			WHERE
+				(:max_count > 0 AND v.position > :max_count) OR v.date_archived < :before
+		)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
@@ -20,8 +20,50 @@ func (s *Store) applyFilter(filter entrybus.QueryFilter, data map[string]any, bu
 		wc = append(wc, "user_id = :user_id")
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if filter.BundleID != nil {
+		data["bundle_id"] = *filter.BundleID
+		wc = append(wc, "bundle_id = :bundle_id")
 	}
+
+	if filter.MemberID != nil {
+		data["member_id"] = *filter.MemberID
+		wc = append(wc, `bundle_id IN (
+		SELECT k.bundle_id FROM keys k JOIN bundles b ON b.bundle_id = k.bundle_id
+		WHERE k.user_id = :member_id AND b.date_deleted IS NULL)`)
+	}
+
+	if filter.Type != nil {
+		data["item_type"] = filter.Type.String()
+		wc = append(wc, "item_type = :item_type")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if filter.StartUpdatedDate != nil {
+		data["start_date_updated"] = filter.StartUpdatedDate.UTC()
+		wc = append(wc, "date_updated >= :start_date_updated")
+	}
+
+	if filter.EndUpdatedDate != nil {
+		data["end_date_updated"] = filter.EndUpdatedDate.UTC()
+		wc = append(wc, "date_updated <= :end_date_updated")
+	}
+
+	switch {
+	case filter.Deleted != nil && *filter.Deleted:
+		wc = append(wc, "date_deleted IS NOT NULL")
+	default:
+		wc = append(wc, "date_deleted IS NULL")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
 }
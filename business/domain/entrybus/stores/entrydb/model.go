@@ -1,49 +1,86 @@
 package entrydb
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	kt "github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
 type entry struct {
-	ID          uuid.UUID `db:"entry_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	BundleID    uuid.UUID `db:"bundle_id"`
-	Data        string    `db:"data"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID            uuid.UUID    `db:"entry_id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	BundleID      uuid.UUID    `db:"bundle_id"`
+	Type          string       `db:"item_type"`
+	SchemaVersion int          `db:"schema_version"`
+	Data          string       `db:"data"`
+	KeyGeneration int          `db:"key_generation"`
+	Revision      int          `db:"revision"`
+	DateCreated   time.Time    `db:"date_created"`
+	DateUpdated   time.Time    `db:"date_updated"`
+	DateDeleted   sql.NullTime `db:"date_deleted"`
 }
 
 func toDBEntry(bus entrybus.Entry) entry {
 	db := entry{
-		ID:          bus.ID,
-		UserID:      bus.UserID,
-		BundleID:    bus.BundleID,
-		Data:        bus.Data.String(),
-		DateCreated: bus.DateCreated.UTC(),
-		DateUpdated: bus.DateUpdated.UTC(),
+		ID:            bus.ID,
+		UserID:        bus.UserID,
+		BundleID:      bus.BundleID,
+		Type:          bus.Type.String(),
+		SchemaVersion: bus.SchemaVersion,
+		Data:          bus.Data.String(),
+		KeyGeneration: bus.KeyGeneration,
+		Revision:      bus.Revision,
+		DateCreated:   bus.DateCreated.UTC(),
+		DateUpdated:   bus.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  bus.DateDeleted.UTC(),
+			Valid: !bus.DateDeleted.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toDBEntries(bus []entrybus.Entry) []entry {
+	db := make([]entry, len(bus))
+	for i, e := range bus {
+		db[i] = toDBEntry(e)
 	}
 
 	return db
 }
 
 func toBusEntry(db entry) (entrybus.Entry, error) {
+	typ, err := itemtype.Parse(db.Type)
+	if err != nil {
+		return entrybus.Entry{}, fmt.Errorf("parse type: %w", err)
+	}
+
 	entry, err := kt.Parse(db.Data)
 	if err != nil {
 		return entrybus.Entry{}, fmt.Errorf("parse entry: %w", err)
 	}
 
 	bus := entrybus.Entry{
-		ID:          db.ID,
-		UserID:      db.UserID,
-		BundleID:    db.BundleID,
-		Data:        entry,
-		DateCreated: db.DateCreated.In(time.Local),
-		DateUpdated: db.DateUpdated.In(time.Local),
+		ID:            db.ID,
+		UserID:        db.UserID,
+		BundleID:      db.BundleID,
+		Type:          typ,
+		SchemaVersion: db.SchemaVersion,
+		Data:          entry,
+		KeyGeneration: db.KeyGeneration,
+		Revision:      db.Revision,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
@@ -62,3 +99,85 @@ func toBusEntries(dbs []entry) ([]entrybus.Entry, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type version struct {
+	ID            uuid.UUID `db:"version_id"`
+	EntryID       uuid.UUID `db:"entry_id"`
+	BundleID      uuid.UUID `db:"bundle_id"`
+	UserID        uuid.UUID `db:"user_id"`
+	Type          string    `db:"item_type"`
+	SchemaVersion int       `db:"schema_version"`
+	Data          string    `db:"data"`
+	KeyGeneration int       `db:"key_generation"`
+	DateCreated   time.Time `db:"date_created"`
+	DateArchived  time.Time `db:"date_archived"`
+}
+
+func toDBVersion(bus entrybus.Version) version {
+	db := version{
+		ID:            bus.ID,
+		EntryID:       bus.EntryID,
+		BundleID:      bus.BundleID,
+		UserID:        bus.UserID,
+		Type:          bus.Type.String(),
+		SchemaVersion: bus.SchemaVersion,
+		Data:          bus.Data.String(),
+		KeyGeneration: bus.KeyGeneration,
+		DateCreated:   bus.DateCreated.UTC(),
+		DateArchived:  bus.DateArchived.UTC(),
+	}
+
+	return db
+}
+
+func toDBVersions(bus []entrybus.Version) []version {
+	db := make([]version, len(bus))
+	for i, v := range bus {
+		db[i] = toDBVersion(v)
+	}
+
+	return db
+}
+
+func toBusVersion(db version) (entrybus.Version, error) {
+	typ, err := itemtype.Parse(db.Type)
+	if err != nil {
+		return entrybus.Version{}, fmt.Errorf("parse type: %w", err)
+	}
+
+	entry, err := kt.Parse(db.Data)
+	if err != nil {
+		return entrybus.Version{}, fmt.Errorf("parse entry: %w", err)
+	}
+
+	bus := entrybus.Version{
+		ID:            db.ID,
+		EntryID:       db.EntryID,
+		BundleID:      db.BundleID,
+		UserID:        db.UserID,
+		Type:          typ,
+		SchemaVersion: db.SchemaVersion,
+		Data:          entry,
+		KeyGeneration: db.KeyGeneration,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateArchived:  db.DateArchived.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusVersions(dbs []version) ([]entrybus.Version, error) {
+	bus := make([]entrybus.Version, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusVersion(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -8,8 +8,11 @@ import (
 )
 
 var orderByFields = map[string]string{
-	entrybus.OrderByEntryID: "entry_id",
-	entrybus.OrderByUserID:  "user_id",
+	entrybus.OrderByEntryID:     "entry_id",
+	entrybus.OrderByUserID:      "user_id",
+	entrybus.OrderByType:        "item_type",
+	entrybus.OrderByDateCreated: "date_created",
+	entrybus.OrderByDateUpdated: "date_updated",
 }
 
 func orderByClause(orderBy order.By) (string, error) {
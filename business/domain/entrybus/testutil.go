@@ -7,9 +7,23 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 )
 
-const ()
+// testItemTypes is the set of item types the generated entries cycle through.
+var testItemTypes = []itemtype.ItemType{
+	itemtype.Login,
+	itemtype.SecureNote,
+	itemtype.Card,
+	itemtype.Identity,
+	itemtype.SSHKey,
+}
+
+// TestNewData is a helper method for testing. It returns entry data encrypted
+// under the key generation with the value standing in for the ciphertext.
+func TestNewData(value string, keyGeneration int) entry.Entry {
+	return entry.MustNew(entry.EncA256GCM, keyGeneration, make([]byte, 12), []byte(fmt.Sprintf("%-16s", value)))
+}
 
 // TestGenerateNewEntries is a helper method for testing.
 // n specifies how many entries to add to each bundle.
@@ -23,10 +37,15 @@ func TestGenerateNewEntries(n int, userID uuid.UUID, bids []uuid.UUID) []NewEntr
 	for i := range len(bids) {
 		idx++
 		for b := range n {
+			typ := testItemTypes[b%len(testItemTypes)]
+
 			ne := NewEntry{
-				Data:     entry.MustParse(fmt.Sprintf("Name%d", idx)),
-				BundleID: bids[i],
-				UserID:   userID,
+				Type:          typ,
+				SchemaVersion: typ.SchemaVersion(),
+				Data:          TestNewData(fmt.Sprintf("Name%d", idx), 1),
+				BundleID:      bids[i],
+				UserID:        userID,
+				KeyGeneration: 1,
 			}
 
 			newEntries[(i*n)+b] = ne
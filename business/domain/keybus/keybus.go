@@ -13,14 +13,18 @@ import (
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/otel"
 )
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound     = errors.New("key not found")
-	ErrUserDisabled = errors.New("user disabled")
+	ErrNotFound       = errors.New("key not found")
+	ErrUserDisabled   = errors.New("user disabled")
+	ErrConflict       = errors.New("key has been modified since it was read")
+	ErrBundleNotFound = errors.New("bundle not found")
+	ErrPersonalBundle = errors.New("personal bundle keys can only be created for the bundle owner")
 )
 
 // Storer interface declares the behavior this package needs to persist and
@@ -35,6 +39,8 @@ type Storer interface {
 	QueryByID(ctx context.Context, keyID uuid.UUID) (Key, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Key, error)
 	QueryByUserIDBundleID(ctx context.Context, bundleID uuid.UUID, userID uuid.UUID) (Key, error)
+	QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]Key, error)
+	QueryBundleOwner(ctx context.Context, bundleID uuid.UUID) (BundleOwner, error)
 }
 
 // Business manages the set of APIs for key access.
@@ -96,6 +102,15 @@ func (b *Business) Create(ctx context.Context, nk NewKey) (Key, error) {
 		return Key{}, ErrUserDisabled
 	}
 
+	owner, err := b.storer.QueryBundleOwner(ctx, nk.BundleID)
+	if err != nil {
+		return Key{}, fmt.Errorf("querybundleowner: bundleID[%s]: %w", nk.BundleID, err)
+	}
+
+	if owner.Type.Equal(bundletype.Personal) && owner.UserID != nk.UserID {
+		return Key{}, ErrPersonalBundle
+	}
+
 	now := time.Now()
 
 	k := Key{
@@ -104,6 +119,7 @@ func (b *Business) Create(ctx context.Context, nk NewKey) (Key, error) {
 		BundleID:    nk.BundleID,
 		Data:        nk.Data,
 		Roles:       nk.Roles,
+		Revision:    1,
 		DateCreated: now,
 		DateUpdated: now,
 	}
@@ -115,7 +131,8 @@ func (b *Business) Create(ctx context.Context, nk NewKey) (Key, error) {
 	return k, nil
 }
 
-// Update modifies information about a key.
+// Update modifies information about a key. The update is rejected if the key
+// has been written since the specified key was read.
 func (b *Business) Update(ctx context.Context, k Key, uk UpdateKey) (Key, error) {
 	ctx, span := otel.AddSpan(ctx, "business.keybus.update")
 	defer span.End()
@@ -128,6 +145,7 @@ func (b *Business) Update(ctx context.Context, k Key, uk UpdateKey) (Key, error)
 		k.Roles = uk.Roles
 	}
 
+	k.Revision++
 	k.DateUpdated = time.Now()
 
 	if err := b.storer.Update(ctx, k); err != nil {
@@ -208,3 +226,16 @@ func (b *Business) QueryByUserIDBundleID(ctx context.Context, userID uuid.UUID,
 
 	return k, nil
 }
+
+// QueryByBundleID finds the keys of every member of the specified bundle.
+func (b *Business) QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]Key, error) {
+	ctx, span := otel.AddSpan(ctx, "business.keybus.querybybundleid")
+	defer span.End()
+
+	keys, err := b.storer.QueryByBundleID(ctx, bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return keys, nil
+}
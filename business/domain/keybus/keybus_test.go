@@ -2,6 +2,7 @@ package keybus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -16,6 +17,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 	"github.com/gradientsearch/pwmanager/business/types/key"
 	"github.com/gradientsearch/pwmanager/business/types/role"
 )
@@ -200,6 +202,7 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				UserID:   sd.Users[0].ID,
 				BundleID: sd.Users[0].Bundles[2].ID,
 				Data:     key.MustParse("Guitar"),
+				Revision: 1,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nk := keybus.NewKey{
@@ -230,6 +233,38 @@ func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "personal-other-user",
+			ExpResp: keybus.ErrPersonalBundle,
+			ExcFunc: func(ctx context.Context) any {
+				nb := bundlebus.NewBundle{
+					UserID: sd.Users[0].ID,
+					Type:   bundletype.Personal,
+				}
+
+				bdl, err := busDomain.Bundle.Create(ctx, nb)
+				if err != nil {
+					return err
+				}
+
+				nk := keybus.NewKey{
+					UserID:   sd.Admins[0].ID,
+					BundleID: bdl.ID,
+					Data:     key.MustParse("Guitar"),
+				}
+
+				_, err = busDomain.Key.Create(ctx, nk)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, exists := got.(error)
+				if !exists || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("expected error %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
@@ -245,6 +280,7 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				UserID:      sd.Users[0].ID,
 				Data:        key.MustParse("Guitar"),
 				Roles:       []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write},
+				Revision:    2,
 				DateCreated: sd.Users[0].Keys[0].DateCreated,
 				DateUpdated: sd.Users[0].Keys[0].DateCreated,
 			},
@@ -273,6 +309,27 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "stale-revision",
+			ExpResp: keybus.ErrConflict,
+			ExcFunc: func(ctx context.Context) any {
+				uk := keybus.UpdateKey{
+					Data: dbtest.KeyPointer("Stale"),
+				}
+
+				// The seeded key was read before the basic update above.
+				_, err := busDomain.Key.Update(ctx, sd.Users[0].Keys[0], uk)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, exists := got.(error)
+				if !exists || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("expected error %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
 	}
 
 	return table
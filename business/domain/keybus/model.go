@@ -5,20 +5,30 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 	"github.com/gradientsearch/pwmanager/business/types/key"
 )
 
-// Key represents an individual key.
+// Key represents an individual key. Revision counts the writes to the key,
+// starting at 1, and is used to detect concurrent updates.
 type Key struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	BundleID    uuid.UUID
 	Data        key.Key
 	Roles       []bundlerole.Role
+	Revision    int
 	DateCreated time.Time
 	DateUpdated time.Time
 }
 
+// BundleOwner represents the owner and type of the bundle a key is created
+// for. Keys for a personal bundle may only be created for its owner.
+type BundleOwner struct {
+	UserID uuid.UUID
+	Type   bundletype.BundleType
+}
+
 // NewKey is what we require from clients when adding a Key.
 type NewKey struct {
 	UserID   uuid.UUID
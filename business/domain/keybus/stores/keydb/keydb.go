@@ -51,9 +51,9 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (keybus.Storer, error) {
 func (s *Store) Create(ctx context.Context, k keybus.Key) error {
 	const q = `
 	INSERT INTO keys
-		(key_id, user_id, bundle_id, data, roles, date_created, date_updated)
+		(key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated)
 	VALUES
-		(:key_id, :user_id, :bundle_id, :data, :roles, :date_created, :date_updated)`
+		(:key_id, :user_id, :bundle_id, :data, :roles, :revision, :date_created, :date_updated)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBKey(k)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -62,20 +62,30 @@ func (s *Store) Create(ctx context.Context, k keybus.Key) error {
 	return nil
 }
 
-// Update modifies data about a keybus. It will error if the specified ID is
-// invalid or does not reference an existing keybus.
+// Update modifies data about a keybus. The update only applies while the key
+// is still at the revision before the one of the specified key.
 func (s *Store) Update(ctx context.Context, k keybus.Key) error {
 	const q = `
 	UPDATE
 		keys
 	SET
 		"data" = :data,
+		"revision" = :revision,
 		"date_updated" = :date_updated
 	WHERE
-		key_id = :key_id`
+		key_id = :key_id
+		AND revision = :revision - 1
+	RETURNING
+		key_id`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBKey(k)); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	var dest struct {
+		ID uuid.UUID `db:"key_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBKey(k), &dest); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", keybus.ErrConflict)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
 	}
 
 	return nil
@@ -111,7 +121,7 @@ func (s *Store) Query(ctx context.Context, filter keybus.QueryFilter, orderBy or
 
 	const q = `
 	SELECT
-	    key_id, user_id,  bundle_id, data, roles, date_created, date_updated
+	    key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
 	FROM
 		keys`
 
@@ -169,7 +179,7 @@ func (s *Store) QueryByID(ctx context.Context, keyID uuid.UUID) (keybus.Key, err
 
 	const q = `
 	SELECT
-	    key_id, user_id, bundle_id, data, roles, date_created, date_updated
+	    key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
 	FROM
 		keys
 	WHERE
@@ -196,7 +206,7 @@ func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]keybus.K
 
 	const q = `
 	SELECT
-	    key_id, user_id, bundle_id, data, roles, date_created, date_updated
+	    key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
 	FROM
 		keys
 	WHERE
@@ -222,7 +232,7 @@ func (s *Store) QueryByUserIDBundleID(ctx context.Context, userID uuid.UUID, bun
 
 	const q = `
 	SELECT
-	    key_id, user_id, bundle_id, data, roles, date_created, date_updated
+	    key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
 	FROM
 		keys
 	WHERE
@@ -240,3 +250,55 @@ func (s *Store) QueryByUserIDBundleID(ctx context.Context, userID uuid.UUID, bun
 
 	return toBusKey(dbKey)
 }
+
+// QueryByBundleID finds the keys identified by a given Bundle ID.
+func (s *Store) QueryByBundleID(ctx context.Context, bundleID uuid.UUID) ([]keybus.Key, error) {
+	data := struct {
+		ID string `db:"bundle_id"`
+	}{
+		ID: bundleID.String(),
+	}
+
+	const q = `
+	SELECT
+	    key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
+	FROM
+		keys
+	WHERE
+		bundle_id = :bundle_id`
+
+	var dbKeys []key
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbKeys); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusKeys(dbKeys)
+}
+
+// QueryBundleOwner finds the owner and type of the bundle with the specified
+// ID.
+func (s *Store) QueryBundleOwner(ctx context.Context, bundleID uuid.UUID) (keybus.BundleOwner, error) {
+	data := struct {
+		BundleID string `db:"bundle_id"`
+	}{
+		BundleID: bundleID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, type
+	FROM
+		bundles
+	WHERE
+		bundle_id = :bundle_id`
+
+	var dbOwner bundleOwner
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbOwner); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return keybus.BundleOwner{}, fmt.Errorf("db: %w", keybus.ErrBundleNotFound)
+		}
+		return keybus.BundleOwner{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusBundleOwner(dbOwner)
+}
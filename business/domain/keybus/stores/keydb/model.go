@@ -8,6 +8,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb/dbarray"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 	kt "github.com/gradientsearch/pwmanager/business/types/key"
 )
 
@@ -17,6 +18,7 @@ type key struct {
 	BundleID    uuid.UUID      `db:"bundle_id"`
 	Data        string         `db:"data"`
 	Roles       dbarray.String `db:"roles"`
+	Revision    int            `db:"revision"`
 	DateCreated time.Time      `db:"date_created"`
 	DateUpdated time.Time      `db:"date_updated"`
 }
@@ -28,6 +30,7 @@ func toDBKey(bus keybus.Key) key {
 		BundleID:    bus.BundleID,
 		Data:        bus.Data.String(),
 		Roles:       bundlerole.ParseToString(bus.Roles),
+		Revision:    bus.Revision,
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
 	}
@@ -52,6 +55,7 @@ func toBusKey(db key) (keybus.Key, error) {
 		BundleID:    db.BundleID,
 		Data:        key,
 		Roles:       roles,
+		Revision:    db.Revision,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
 	}
@@ -72,3 +76,24 @@ func toBusKeys(dbs []key) ([]keybus.Key, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type bundleOwner struct {
+	UserID uuid.UUID `db:"user_id"`
+	Type   string    `db:"type"`
+}
+
+func toBusBundleOwner(db bundleOwner) (keybus.BundleOwner, error) {
+	typ, err := bundletype.Parse(db.Type)
+	if err != nil {
+		return keybus.BundleOwner{}, fmt.Errorf("parse type: %w", err)
+	}
+
+	bus := keybus.BundleOwner{
+		UserID: db.UserID,
+		Type:   typ,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,15 @@
+package memberbus
+
+import (
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID        *uuid.UUID
+	BundleID  *uuid.UUID
+	InviteeID *uuid.UUID
+	Status    *invitestatus.Status
+}
@@ -0,0 +1,282 @@
+// Package memberbus provides business access to bundle membership.
+package memberbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound       = errors.New("invite not found")
+	ErrUserDisabled   = errors.New("user disabled")
+	ErrNotShareable   = errors.New("personal bundles can not be shared")
+	ErrAlreadyMember  = errors.New("user is already a member of the bundle")
+	ErrAlreadyInvited = errors.New("user already has a pending invite for the bundle")
+	ErrNotPending     = errors.New("invite is not pending")
+	ErrRevokeOwner    = errors.New("bundle owner can not be revoked")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, inv Invite) error
+	Update(ctx context.Context, inv Invite) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Invite, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, inviteID uuid.UUID) (Invite, error)
+}
+
+// Business manages the set of APIs for bundle membership access.
+type Business struct {
+	log       *logger.Logger
+	userBus   *userbus.Business
+	bundleBus *bundlebus.Business
+	keyBus    *keybus.Business
+	storer    Storer
+}
+
+// NewBusiness constructs a membership business API for use.
+func NewBusiness(log *logger.Logger, userBus *userbus.Business, bundleBus *bundlebus.Business, keyBus *keybus.Business, storer Storer) *Business {
+	return &Business{
+		log:       log,
+		userBus:   userBus,
+		bundleBus: bundleBus,
+		keyBus:    keyBus,
+		storer:    storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleBus, err := b.bundleBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBus, err := b.keyBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:       b.log,
+		userBus:   userBus,
+		bundleBus: bundleBus,
+		keyBus:    keyBus,
+		storer:    storer,
+	}
+
+	return &bus, nil
+}
+
+// Invite creates a pending invite for a user to join a shareable bundle.
+func (b *Business) Invite(ctx context.Context, ni NewInvite) (Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.invite")
+	defer span.End()
+
+	bdl, err := b.checkShareable(ctx, ni.BundleID)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	// The wrapped key must be the bundle's current key.
+	if ni.KeyGeneration != bdl.KeyGeneration {
+		return Invite{}, bundlebus.ErrStaleKeyGeneration
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, ni.InviteeID)
+	if err != nil {
+		return Invite{}, fmt.Errorf("user.querybyid: %s: %w", ni.InviteeID, err)
+	}
+
+	if !usr.Enabled {
+		return Invite{}, ErrUserDisabled
+	}
+
+	_, err = b.keyBus.QueryByUserIDBundleID(ctx, ni.InviteeID, ni.BundleID)
+	switch {
+	case err == nil:
+		return Invite{}, ErrAlreadyMember
+	case !errors.Is(err, keybus.ErrNotFound):
+		return Invite{}, fmt.Errorf("key.querybyuseridbundleid: %w", err)
+	}
+
+	now := time.Now()
+
+	inv := Invite{
+		ID:            uuid.New(),
+		BundleID:      ni.BundleID,
+		InviterID:     ni.InviterID,
+		InviteeID:     ni.InviteeID,
+		Data:          ni.Data,
+		Roles:         ni.Roles,
+		KeyGeneration: ni.KeyGeneration,
+		Status:        invitestatus.Pending,
+		DateCreated:   now,
+		DateUpdated:   now,
+	}
+
+	if err := b.storer.Create(ctx, inv); err != nil {
+		return Invite{}, fmt.Errorf("create: %w", err)
+	}
+
+	return inv, nil
+}
+
+// Accept adds the invitee as a member of the bundle by creating their key
+// from the invite.
+func (b *Business) Accept(ctx context.Context, inv Invite) (Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.accept")
+	defer span.End()
+
+	if !inv.Status.Equal(invitestatus.Pending) {
+		return Invite{}, ErrNotPending
+	}
+
+	// The bundle type can change after the invite was sent.
+	bdl, err := b.checkShareable(ctx, inv.BundleID)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	// The bundle key can be rotated after the invite was sent, leaving the
+	// invite with a key that no longer decrypts the bundle.
+	if inv.KeyGeneration != bdl.KeyGeneration {
+		return Invite{}, bundlebus.ErrStaleKeyGeneration
+	}
+
+	nk := keybus.NewKey{
+		UserID:   inv.InviteeID,
+		BundleID: inv.BundleID,
+		Data:     inv.Data,
+		Roles:    inv.Roles,
+	}
+
+	if _, err := b.keyBus.Create(ctx, nk); err != nil {
+		return Invite{}, fmt.Errorf("key.create: %w", err)
+	}
+
+	return b.setStatus(ctx, inv, invitestatus.Accepted)
+}
+
+// Decline marks the invite as declined without creating a key.
+func (b *Business) Decline(ctx context.Context, inv Invite) (Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.decline")
+	defer span.End()
+
+	if !inv.Status.Equal(invitestatus.Pending) {
+		return Invite{}, ErrNotPending
+	}
+
+	return b.setStatus(ctx, inv, invitestatus.Declined)
+}
+
+// Revoke removes the user as a member of the bundle by deleting their key.
+func (b *Business) Revoke(ctx context.Context, bdl bundlebus.Bundle, userID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.revoke")
+	defer span.End()
+
+	if bdl.UserID == userID {
+		return ErrRevokeOwner
+	}
+
+	k, err := b.keyBus.QueryByUserIDBundleID(ctx, userID, bdl.ID)
+	if err != nil {
+		return fmt.Errorf("key.querybyuseridbundleid: %w", err)
+	}
+
+	if err := b.keyBus.Delete(ctx, k); err != nil {
+		return fmt.Errorf("key.delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing invites.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.query")
+	defer span.End()
+
+	invs, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return invs, nil
+}
+
+// Count returns the total number of invites.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the invite by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, inviteID uuid.UUID) (Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.memberbus.querybyid")
+	defer span.End()
+
+	inv, err := b.storer.QueryByID(ctx, inviteID)
+	if err != nil {
+		return Invite{}, fmt.Errorf("query: inviteID[%s]: %w", inviteID, err)
+	}
+
+	return inv, nil
+}
+
+// =============================================================================
+
+// checkShareable returns the bundle, or ErrNotShareable if the bundle is a
+// personal bundle.
+func (b *Business) checkShareable(ctx context.Context, bundleID uuid.UUID) (bundlebus.Bundle, error) {
+	bdl, err := b.bundleBus.QueryByID(ctx, bundleID)
+	if err != nil {
+		return bundlebus.Bundle{}, fmt.Errorf("bundle.querybyid: %s: %w", bundleID, err)
+	}
+
+	if bdl.Type.Equal(bundletype.Personal) {
+		return bundlebus.Bundle{}, ErrNotShareable
+	}
+
+	return bdl, nil
+}
+
+func (b *Business) setStatus(ctx context.Context, inv Invite, status invitestatus.Status) (Invite, error) {
+	inv.Status = status
+	inv.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, inv); err != nil {
+		return Invite{}, fmt.Errorf("update: %w", err)
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,327 @@
+package memberbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+func Test_Member(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Member")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, invite(db.BusDomain, sd), "invite")
+	unitest.Run(t, accept(db.BusDomain, sd), "accept")
+	unitest.Run(t, decline(db.BusDomain, sd), "decline")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 3, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	// The owner gets a shareable bundle followed by a personal bundle.
+	var bdls []bundlebus.Bundle
+	var keys []keybus.Key
+	for _, typ := range []bundletype.BundleType{bundletype.Shareable, bundletype.Personal} {
+		bdl, err := busDomain.Bundle.Create(ctx, bundlebus.NewBundle{UserID: usrs[0].ID, Type: typ})
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
+		}
+
+		k, err := busDomain.Key.Create(ctx, keybus.NewKey{
+			UserID:   usrs[0].ID,
+			BundleID: bdl.ID,
+			Data:     key.MustParse("OwnerKey"),
+			Roles:    []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write},
+		})
+		if err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+		}
+
+		bdls = append(bdls, bdl)
+		keys = append(keys, k)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{
+			{User: usrs[0], Bundles: bdls, Keys: keys},
+			{User: usrs[1]},
+			{User: usrs[2]},
+		},
+	}
+
+	return sd, nil
+}
+
+func newInvite(sd unitest.SeedData, bdl bundlebus.Bundle, invitee unitest.User) memberbus.NewInvite {
+	return memberbus.NewInvite{
+		BundleID:      bdl.ID,
+		InviterID:     sd.Users[0].ID,
+		InviteeID:     invitee.ID,
+		Data:          key.MustParse("WrappedKey"),
+		Roles:         []bundlerole.Role{bundlerole.Read},
+		KeyGeneration: bdl.KeyGeneration,
+	}
+}
+
+func pendingInvite(ctx context.Context, busDomain dbtest.BusDomain, usr unitest.User) (memberbus.Invite, error) {
+	filter := memberbus.QueryFilter{
+		InviteeID: &usr.ID,
+		Status:    &invitestatus.Pending,
+	}
+
+	invs, err := busDomain.Member.Query(ctx, filter, memberbus.DefaultOrderBy, page.MustParse("1", "10"))
+	if err != nil {
+		return memberbus.Invite{}, err
+	}
+
+	if len(invs) != 1 {
+		return memberbus.Invite{}, fmt.Errorf("expected 1 pending invite, got %d", len(invs))
+	}
+
+	return invs[0], nil
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
+
+// =============================================================================
+
+func invite(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: memberbus.Invite{
+				BundleID:      sd.Users[0].Bundles[0].ID,
+				InviterID:     sd.Users[0].ID,
+				InviteeID:     sd.Users[1].ID,
+				Data:          key.MustParse("WrappedKey"),
+				Roles:         []bundlerole.Role{bundlerole.Read},
+				KeyGeneration: 1,
+				Status:        invitestatus.Pending,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Member.Invite(ctx, newInvite(sd, sd.Users[0].Bundles[0], sd.Users[1]))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(memberbus.Invite)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(memberbus.Invite)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "pending",
+			ExpResp: memberbus.ErrAlreadyInvited,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Member.Invite(ctx, newInvite(sd, sd.Users[0].Bundles[0], sd.Users[1]))
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "member",
+			ExpResp: memberbus.ErrAlreadyMember,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Member.Invite(ctx, newInvite(sd, sd.Users[0].Bundles[0], sd.Users[0]))
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "stale",
+			ExpResp: bundlebus.ErrStaleKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				ni := newInvite(sd, sd.Users[0].Bundles[0], sd.Users[2])
+				ni.KeyGeneration++
+
+				_, err := busDomain.Member.Invite(ctx, ni)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "personal",
+			ExpResp: memberbus.ErrNotShareable,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Member.Invite(ctx, newInvite(sd, sd.Users[0].Bundles[1], sd.Users[1]))
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func accept(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: keybus.Key{
+				UserID:   sd.Users[1].ID,
+				BundleID: sd.Users[0].Bundles[0].ID,
+				Data:     key.MustParse("WrappedKey"),
+				Roles:    []bundlerole.Role{bundlerole.Read},
+				Revision: 1,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				inv, err := pendingInvite(ctx, busDomain, sd.Users[1])
+				if err != nil {
+					return err
+				}
+
+				inv, err = busDomain.Member.Accept(ctx, inv)
+				if err != nil {
+					return err
+				}
+
+				if !inv.Status.Equal(invitestatus.Accepted) {
+					return fmt.Errorf("expected status %s, got %s", invitestatus.Accepted, inv.Status)
+				}
+
+				resp, err := busDomain.Key.QueryByUserIDBundleID(ctx, sd.Users[1].ID, sd.Users[0].Bundles[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(keybus.Key)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(keybus.Key)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func decline(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: invitestatus.Declined,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.Member.Invite(ctx, newInvite(sd, sd.Users[0].Bundles[0], sd.Users[2])); err != nil {
+					return err
+				}
+
+				inv, err := pendingInvite(ctx, busDomain, sd.Users[2])
+				if err != nil {
+					return err
+				}
+
+				inv, err = busDomain.Member.Decline(ctx, inv)
+				if err != nil {
+					return err
+				}
+
+				return inv.Status
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "not-pending",
+			ExpResp: memberbus.ErrNotPending,
+			ExcFunc: func(ctx context.Context) any {
+				inv := memberbus.Invite{Status: invitestatus.Declined}
+
+				_, err := busDomain.Member.Accept(ctx, inv)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: keybus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Member.Revoke(ctx, sd.Users[0].Bundles[0], sd.Users[1].ID); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Key.QueryByUserIDBundleID(ctx, sd.Users[1].ID, sd.Users[0].Bundles[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "owner",
+			ExpResp: memberbus.ErrRevokeOwner,
+			ExcFunc: func(ctx context.Context) any {
+				return busDomain.Member.Revoke(ctx, sd.Users[0].Bundles[0], sd.Users[0].ID)
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
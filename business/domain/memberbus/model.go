@@ -0,0 +1,37 @@
+package memberbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+// Invite represents an invitation for a user to become a member of a bundle.
+// Data holds the bundle key wrapped with the invitee's public key and becomes
+// the invitee's key when the invite is accepted. KeyGeneration is the bundle
+// key generation the wrapped key belongs to.
+type Invite struct {
+	ID            uuid.UUID
+	BundleID      uuid.UUID
+	InviterID     uuid.UUID
+	InviteeID     uuid.UUID
+	Data          key.Key
+	Roles         []bundlerole.Role
+	KeyGeneration int
+	Status        invitestatus.Status
+	DateCreated   time.Time
+	DateUpdated   time.Time
+}
+
+// NewInvite is what we require from clients when inviting a user to a bundle.
+type NewInvite struct {
+	BundleID      uuid.UUID
+	InviterID     uuid.UUID
+	InviteeID     uuid.UUID
+	Data          key.Key
+	Roles         []bundlerole.Role
+	KeyGeneration int
+}
@@ -0,0 +1,14 @@
+package memberbus
+
+import "github.com/gradientsearch/pwmanager/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByInviteID    = "invite_id"
+	OrderByBundleID    = "bundle_id"
+	OrderByStatus      = "status"
+	OrderByDateCreated = "date_created"
+)
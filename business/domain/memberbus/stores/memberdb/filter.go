@@ -0,0 +1,37 @@
+package memberdb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+)
+
+func (s *Store) applyFilter(filter memberbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ID != nil {
+		data["invite_id"] = *filter.ID
+		wc = append(wc, "invite_id = :invite_id")
+	}
+
+	if filter.BundleID != nil {
+		data["bundle_id"] = *filter.BundleID
+		wc = append(wc, "bundle_id = :bundle_id")
+	}
+
+	if filter.InviteeID != nil {
+		data["invitee_id"] = *filter.InviteeID
+		wc = append(wc, "invitee_id = :invitee_id")
+	}
+
+	if filter.Status != nil {
+		data["status"] = filter.Status.String()
+		wc = append(wc, "status = :status")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,165 @@
+// Package memberdb contains bundle membership related CRUD functionality.
+package memberdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for invite database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (memberbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new invite into the database.
+func (s *Store) Create(ctx context.Context, inv memberbus.Invite) error {
+	const q = `
+	INSERT INTO bundle_invites
+		(invite_id, bundle_id, inviter_id, invitee_id, data, roles, key_generation, status, date_created, date_updated)
+	VALUES
+		(:invite_id, :bundle_id, :inviter_id, :invitee_id, :data, :roles, :key_generation, :status, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBInvite(inv)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", memberbus.ErrAlreadyInvited)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the status of an invite in the database.
+func (s *Store) Update(ctx context.Context, inv memberbus.Invite) error {
+	const q = `
+	UPDATE
+		bundle_invites
+	SET
+		"status" = :status,
+		"date_updated" = :date_updated
+	WHERE
+		invite_id = :invite_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBInvite(inv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing invites from the database.
+func (s *Store) Query(ctx context.Context, filter memberbus.QueryFilter, orderBy order.By, page page.Page) ([]memberbus.Invite, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		invite_id, bundle_id, inviter_id, invitee_id, data, roles, key_generation, status, date_created, date_updated
+	FROM
+		bundle_invites`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbInvs []invite
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbInvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusInvites(dbInvs)
+}
+
+// Count returns the total number of invites in the DB.
+func (s *Store) Count(ctx context.Context, filter memberbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		bundle_invites`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID finds the invite identified by a given ID.
+func (s *Store) QueryByID(ctx context.Context, inviteID uuid.UUID) (memberbus.Invite, error) {
+	data := struct {
+		ID string `db:"invite_id"`
+	}{
+		ID: inviteID.String(),
+	}
+
+	const q = `
+	SELECT
+		invite_id, bundle_id, inviter_id, invitee_id, data, roles, key_generation, status, date_created, date_updated
+	FROM
+		bundle_invites
+	WHERE
+		invite_id = :invite_id`
+
+	var dbInv invite
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbInv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return memberbus.Invite{}, fmt.Errorf("db: %w", memberbus.ErrNotFound)
+		}
+		return memberbus.Invite{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusInvite(dbInv)
+}
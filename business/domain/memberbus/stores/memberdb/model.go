@@ -0,0 +1,89 @@
+package memberdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb/dbarray"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/invitestatus"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+type invite struct {
+	ID            uuid.UUID      `db:"invite_id"`
+	BundleID      uuid.UUID      `db:"bundle_id"`
+	InviterID     uuid.UUID      `db:"inviter_id"`
+	InviteeID     uuid.UUID      `db:"invitee_id"`
+	Data          string         `db:"data"`
+	Roles         dbarray.String `db:"roles"`
+	KeyGeneration int            `db:"key_generation"`
+	Status        string         `db:"status"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+}
+
+func toDBInvite(bus memberbus.Invite) invite {
+	db := invite{
+		ID:            bus.ID,
+		BundleID:      bus.BundleID,
+		InviterID:     bus.InviterID,
+		InviteeID:     bus.InviteeID,
+		Data:          bus.Data.String(),
+		Roles:         bundlerole.ParseToString(bus.Roles),
+		KeyGeneration: bus.KeyGeneration,
+		Status:        bus.Status.String(),
+		DateCreated:   bus.DateCreated.UTC(),
+		DateUpdated:   bus.DateUpdated.UTC(),
+	}
+
+	return db
+}
+
+func toBusInvite(db invite) (memberbus.Invite, error) {
+	data, err := key.Parse(db.Data)
+	if err != nil {
+		return memberbus.Invite{}, fmt.Errorf("parse data: %w", err)
+	}
+
+	roles, err := bundlerole.ParseMany(db.Roles)
+	if err != nil {
+		return memberbus.Invite{}, fmt.Errorf("parse roles: %w", err)
+	}
+
+	status, err := invitestatus.Parse(db.Status)
+	if err != nil {
+		return memberbus.Invite{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	bus := memberbus.Invite{
+		ID:            db.ID,
+		BundleID:      db.BundleID,
+		InviterID:     db.InviterID,
+		InviteeID:     db.InviteeID,
+		Data:          data,
+		Roles:         roles,
+		KeyGeneration: db.KeyGeneration,
+		Status:        status,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusInvites(dbs []invite) ([]memberbus.Invite, error) {
+	bus := make([]memberbus.Invite, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusInvite(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,24 @@
+package memberdb
+
+import (
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	memberbus.OrderByInviteID:    "invite_id",
+	memberbus.OrderByBundleID:    "bundle_id",
+	memberbus.OrderByStatus:      "status",
+	memberbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,42 @@
+package sessionbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the delegate
+// system. If the business was constructed for query only, there won't be a
+// delegate provided.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionUpdated, b.actionUserUpdated)
+	}
+}
+
+// actionUserUpdated is executed by the user domain indirectly when a user is
+// updated. The sessions of a disabled user are revoked so their tokens stop
+// working right away instead of when they expire.
+func (b *Business) actionUserUpdated(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionUpdatedParms
+	err := json.Unmarshal(data.RawParams, &params)
+	if err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	if params.Enabled == nil || *params.Enabled {
+		return nil
+	}
+
+	b.log.Info(ctx, "action-userupdate", "user_id", params.UserID, "enabled", false)
+
+	if err := b.RevokeAll(ctx, params.UserID); err != nil {
+		return fmt.Errorf("revokeall: %w", err)
+	}
+
+	return nil
+}
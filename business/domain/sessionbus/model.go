@@ -0,0 +1,53 @@
+package sessionbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+// Session represents a login of a user on a device. AccessID is the ID (jti)
+// of the latest access token issued for the session, which is valid until
+// AccessExpires. The session can be refreshed until DateExpires and
+// DateRevoked is set once the session is revoked. Roles are granted to the
+// session by the identity provider the user logged in with, on top of the
+// roles of the user.
+type Session struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Roles         []role.Role
+	Device        string
+	IPAddress     string
+	AccessID      uuid.UUID
+	AccessExpires time.Time
+	DateCreated   time.Time
+	DateLastUsed  time.Time
+	DateExpires   time.Time
+	DateRevoked   time.Time
+}
+
+// NewSession contains information needed to create a new session.
+type NewSession struct {
+	UserID    uuid.UUID
+	Roles     []role.Role
+	Device    string
+	IPAddress string
+}
+
+// RefreshToken represents a refresh token issued for a session. Only the
+// hash of the token is stored. DateUsed is set once the token was exchanged,
+// a token that is presented again after that has been stolen.
+type RefreshToken struct {
+	TokenHash   []byte
+	SessionID   uuid.UUID
+	DateCreated time.Time
+	DateUsed    time.Time
+}
+
+// RevokedToken represents an access token that can no longer be used even
+// though it has not expired. It is kept until DateExpires.
+type RevokedToken struct {
+	TokenID     string
+	DateExpires time.Time
+}
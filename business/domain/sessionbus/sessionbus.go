@@ -0,0 +1,386 @@
+// Package sessionbus provides business access to the sessions of users, the
+// refresh tokens that keep them alive and the revocation of access tokens.
+package sessionbus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound            = errors.New("session not found")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+	ErrRevoked             = errors.New("session has been revoked")
+	ErrExpired             = errors.New("session has expired")
+)
+
+// AccessTTL is how long an access token issued for a session is valid.
+// RefreshTTL is how long a session can go without being refreshed before it
+// expires.
+const (
+	AccessTTL  = 15 * time.Minute
+	RefreshTTL = 30 * 24 * time.Hour
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, sess Session) error
+	Update(ctx context.Context, sess Session) error
+	QueryByID(ctx context.Context, sessionID uuid.UUID) (Session, error)
+	Lock(ctx context.Context, sessionID uuid.UUID, fn func(storer Storer, sess Session) error) error
+	QueryActiveByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]Session, error)
+	CreateRefreshToken(ctx context.Context, rt RefreshToken) error
+	QueryRefreshToken(ctx context.Context, tokenHash []byte) (RefreshToken, error)
+	UseRefreshToken(ctx context.Context, tokenHash []byte, now time.Time) error
+	CreateRevokedToken(ctx context.Context, rt RevokedToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	Purge(ctx context.Context, now time.Time) error
+}
+
+// Business manages the set of APIs for session access. Inside of a
+// transaction, reuseStorer is the store outside of it which sessions whose
+// refresh token was reused are revoked with.
+type Business struct {
+	log         *logger.Logger
+	delegate    *delegate.Delegate
+	storer      Storer
+	reuseStorer Storer
+}
+
+// NewBusiness constructs a session business API for use.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	b := Business{
+		log:         log,
+		delegate:    delegate,
+		storer:      storer,
+		reuseStorer: storer,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls. The revocation of a
+// session whose refresh token was reused is the exception, it is kept when
+// the transaction is rolled back.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:         b.log,
+		delegate:    b.delegate,
+		storer:      storer,
+		reuseStorer: b.reuseStorer,
+	}
+
+	return &bus, nil
+}
+
+// Create starts a new session and returns it with its first refresh token.
+// The access token for the session must be issued with the session AccessID
+// as its ID and expire at AccessExpires.
+func (b *Business) Create(ctx context.Context, ns NewSession) (Session, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.create")
+	defer span.End()
+
+	now := time.Now()
+
+	sess := Session{
+		ID:            uuid.New(),
+		UserID:        ns.UserID,
+		Roles:         ns.Roles,
+		Device:        ns.Device,
+		IPAddress:     ns.IPAddress,
+		AccessID:      uuid.New(),
+		AccessExpires: now.Add(AccessTTL),
+		DateCreated:   now,
+		DateLastUsed:  now,
+		DateExpires:   now.Add(RefreshTTL),
+	}
+
+	if err := b.storer.Create(ctx, sess); err != nil {
+		return Session{}, "", fmt.Errorf("create: %w", err)
+	}
+
+	token, err := b.newRefreshToken(ctx, sess, now)
+	if err != nil {
+		return Session{}, "", err
+	}
+
+	return sess, token, nil
+}
+
+// Refresh exchanges the refresh token for a new one and moves the session to
+// a new access token, the previous access token is revoked. Every refresh
+// token can only be exchanged once. Presenting a token that was already
+// exchanged means it has been stolen, so the session is revoked.
+func (b *Business) Refresh(ctx context.Context, refreshToken string) (Session, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.refresh")
+	defer span.End()
+
+	tokenHash := hashRefreshToken(refreshToken)
+
+	rt, err := b.storer.QueryRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return Session{}, "", fmt.Errorf("queryrefreshtoken: %w", err)
+	}
+
+	if !rt.DateUsed.IsZero() {
+		return Session{}, "", b.revokeReused(ctx, rt.SessionID)
+	}
+
+	now := time.Now()
+
+	// The token is marked as used in a single statement so of two concurrent
+	// exchanges of the same token only one succeeds. It is marked before the
+	// session is locked so the exchange that lost never holds the lock the
+	// revocation of the session needs.
+	if err := b.storer.UseRefreshToken(ctx, tokenHash, now); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			return Session{}, "", b.revokeReused(ctx, rt.SessionID)
+		}
+		return Session{}, "", fmt.Errorf("userefreshtoken: %w", err)
+	}
+
+	var sess Session
+	var token string
+
+	// The session is locked so a revocation for a reused token can't be
+	// overwritten by the rotation.
+	f := func(storer Storer, locked Session) error {
+		lb := Business{
+			log:         b.log,
+			delegate:    b.delegate,
+			storer:      storer,
+			reuseStorer: b.reuseStorer,
+		}
+
+		sess, token, err = lb.rotate(ctx, locked, now)
+		return err
+	}
+
+	if err := b.storer.Lock(ctx, rt.SessionID, f); err != nil {
+		return Session{}, "", fmt.Errorf("lock: sessionID[%s]: %w", rt.SessionID, err)
+	}
+
+	return sess, token, nil
+}
+
+// Revoke ends the session and revokes its access token.
+func (b *Business) Revoke(ctx context.Context, sess Session) (Session, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.revoke")
+	defer span.End()
+
+	if !sess.DateRevoked.IsZero() {
+		return sess, nil
+	}
+
+	if err := b.revokeAccess(ctx, sess); err != nil {
+		return Session{}, err
+	}
+
+	sess.DateRevoked = time.Now()
+
+	if err := b.storer.Update(ctx, sess); err != nil {
+		return Session{}, fmt.Errorf("update: %w", err)
+	}
+
+	return sess, nil
+}
+
+// RevokeAll ends every active session of the user.
+func (b *Business) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.revokeall")
+	defer span.End()
+
+	sessions, err := b.storer.QueryActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	for _, sess := range sessions {
+		if _, err := b.Revoke(ctx, sess); err != nil {
+			return fmt.Errorf("revoke: sessionID[%s]: %w", sess.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// QueryByID finds the session by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, sessionID uuid.UUID) (Session, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.querybyid")
+	defer span.End()
+
+	sess, err := b.storer.QueryByID(ctx, sessionID)
+	if err != nil {
+		return Session{}, fmt.Errorf("query: sessionID[%s]: %w", sessionID, err)
+	}
+
+	return sess, nil
+}
+
+// QueryActiveByUserID retrieves the sessions of the user that are neither
+// revoked nor expired, the most recently used first.
+func (b *Business) QueryActiveByUserID(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.queryactivebyuserid")
+	defer span.End()
+
+	sessions, err := b.storer.QueryActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return sessions, nil
+}
+
+// IsTokenRevoked reports whether the access token with the specified ID
+// has been revoked.
+func (b *Business) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.istokenrevoked")
+	defer span.End()
+
+	revoked, err := b.storer.IsTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("istokenrevoked: tokenID[%s]: %w", tokenID, err)
+	}
+
+	return revoked, nil
+}
+
+// PurgeExpired permanently removes the sessions that expired or were
+// revoked and the revoked access tokens that expired since.
+func (b *Business) PurgeExpired(ctx context.Context) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessionbus.purgeexpired")
+	defer span.End()
+
+	if err := b.storer.Purge(ctx, time.Now()); err != nil {
+		return fmt.Errorf("purge: %w", err)
+	}
+
+	return nil
+}
+
+// newRefreshToken mints a refresh token for the session.
+func (b *Business) newRefreshToken(ctx context.Context, sess Session, now time.Time) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	rt := RefreshToken{
+		TokenHash:   hashRefreshToken(token),
+		SessionID:   sess.ID,
+		DateCreated: now,
+	}
+
+	if err := b.storer.CreateRefreshToken(ctx, rt); err != nil {
+		return "", fmt.Errorf("createrefreshtoken: %w", err)
+	}
+
+	return token, nil
+}
+
+// revokeAccess revokes the current access token of the session until it
+// would have expired.
+func (b *Business) revokeAccess(ctx context.Context, sess Session) error {
+	rt := RevokedToken{
+		TokenID:     sess.AccessID.String(),
+		DateExpires: sess.AccessExpires,
+	}
+
+	if err := b.storer.CreateRevokedToken(ctx, rt); err != nil {
+		return fmt.Errorf("createrevokedtoken: %w", err)
+	}
+
+	return nil
+}
+
+// rotate moves the locked session to a new access token and mints the
+// refresh token that replaces the one exchanged.
+func (b *Business) rotate(ctx context.Context, sess Session, now time.Time) (Session, string, error) {
+	if !sess.DateRevoked.IsZero() {
+		return Session{}, "", fmt.Errorf("refresh: sessionID[%s]: %w", sess.ID, ErrRevoked)
+	}
+
+	if now.After(sess.DateExpires) {
+		return Session{}, "", fmt.Errorf("refresh: sessionID[%s]: %w", sess.ID, ErrExpired)
+	}
+
+	if err := b.revokeAccess(ctx, sess); err != nil {
+		return Session{}, "", err
+	}
+
+	sess.AccessID = uuid.New()
+	sess.AccessExpires = now.Add(AccessTTL)
+	sess.DateLastUsed = now
+	sess.DateExpires = now.Add(RefreshTTL)
+
+	if err := b.storer.Update(ctx, sess); err != nil {
+		return Session{}, "", fmt.Errorf("update: %w", err)
+	}
+
+	token, err := b.newRefreshToken(ctx, sess, now)
+	if err != nil {
+		return Session{}, "", err
+	}
+
+	return sess, token, nil
+}
+
+// revokeReused revokes the session a reused refresh token belongs to and
+// returns the error reporting the reuse.
+func (b *Business) revokeReused(ctx context.Context, sessionID uuid.UUID) error {
+	// The refresh that found the reuse holds no lock on the session, so the
+	// session is revoked outside of its transaction, where the revocation is
+	// kept when that transaction is rolled back. The session is read again
+	// under a lock so the access token revoked is the one issued last, even
+	// when the exchange that won is still rotating it.
+	f := func(storer Storer, sess Session) error {
+		b.log.Info(ctx, "refresh token reused", "sessionID", sess.ID, "userID", sess.UserID)
+
+		rb := Business{
+			log:      b.log,
+			delegate: b.delegate,
+			storer:   storer,
+		}
+
+		_, err := rb.Revoke(ctx, sess)
+		return err
+	}
+
+	if err := b.reuseStorer.Lock(ctx, sessionID, f); err != nil {
+		return fmt.Errorf("revoke: sessionID[%s]: %w", sessionID, err)
+	}
+
+	return fmt.Errorf("refresh: sessionID[%s]: %w", sessionID, ErrRefreshTokenReused)
+}
+
+// hashRefreshToken returns the hash of the token that is persisted so
+// plaintext refresh tokens are never stored.
+func hashRefreshToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
@@ -0,0 +1,369 @@
+package sessionbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+func Test_Session(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Session")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+	unitest.Run(t, refresh(db.BusDomain, sd), "refresh")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+	unitest.Run(t, event(db.BusDomain, sd), "event")
+}
+
+// =============================================================================
+
+type seedData struct {
+	unitest.SeedData
+	Sessions [][]sessionbus.Session
+	Tokens   [][]string
+}
+
+func insertSeedData(busDomain dbtest.BusDomain) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 3, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := seedData{
+		Sessions: make([][]sessionbus.Session, len(usrs)),
+		Tokens:   make([][]string, len(usrs)),
+	}
+
+	for i, usr := range usrs {
+		sessions, tokens, err := sessionbus.TestGenerateSeedSessions(ctx, 2, busDomain.Session, usr.ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding sessions : %w", err)
+		}
+
+		sd.Users = append(sd.Users, unitest.User{User: usr})
+		sd.Sessions[i] = sessions
+		sd.Tokens[i] = tokens
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func query(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	usr := sd.Users[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "active",
+			ExpResp: []uuid.UUID{sd.Sessions[0][1].ID, sd.Sessions[0][0].ID},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Session.QueryActiveByUserID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				ids := make([]uuid.UUID, len(resp))
+				for i, sess := range resp {
+					ids[i] = sess.ID
+				}
+
+				return ids
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]uuid.UUID)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				return cmp.Diff(gotResp, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func refresh(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	sess := sd.Sessions[1][0]
+	token := sd.Tokens[1][0]
+
+	var rotated string
+
+	table := []unitest.Table{
+		{
+			Name:    "rotate",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				resp, newToken, err := busDomain.Session.Refresh(ctx, token)
+				if err != nil {
+					return err
+				}
+
+				rotated = newToken
+
+				if resp.ID != sess.ID || resp.AccessID == sess.AccessID || newToken == token {
+					return fmt.Errorf("should move the session to a new access token and refresh token")
+				}
+
+				revoked, err := busDomain.Session.IsTokenRevoked(ctx, sess.AccessID.String())
+				if err != nil {
+					return err
+				}
+
+				return revoked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "invalid",
+			ExpResp: sessionbus.ErrInvalidRefreshToken,
+			ExcFunc: func(ctx context.Context) any {
+				_, _, err := busDomain.Session.Refresh(ctx, "not-a-refresh-token")
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "reuse",
+			ExpResp: sessionbus.ErrRefreshTokenReused,
+			ExcFunc: func(ctx context.Context) any {
+				_, _, err := busDomain.Session.Refresh(ctx, token)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "reuse-revoked",
+			ExpResp: sessionbus.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				_, _, err := busDomain.Session.Refresh(ctx, rotated)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "roles",
+			ExpResp: []role.Role{role.Admin},
+			ExcFunc: func(ctx context.Context) any {
+				ns := sessionbus.NewSession{
+					UserID: sess.UserID,
+					Roles:  []role.Role{role.Admin},
+				}
+
+				_, token, err := busDomain.Session.Create(ctx, ns)
+				if err != nil {
+					return err
+				}
+
+				resp, _, err := busDomain.Session.Refresh(ctx, token)
+				if err != nil {
+					return err
+				}
+
+				return resp.Roles
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "concurrent-reuse",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				created, token, err := busDomain.Session.Create(ctx, sessionbus.NewSession{UserID: sess.UserID})
+				if err != nil {
+					return err
+				}
+
+				type result struct {
+					sess sessionbus.Session
+					err  error
+				}
+
+				results := make(chan result, 2)
+				for range 2 {
+					go func() {
+						resp, _, err := busDomain.Session.Refresh(ctx, token)
+						results <- result{sess: resp, err: err}
+					}()
+				}
+
+				accessIDs := []uuid.UUID{created.AccessID}
+				var reused bool
+
+				for range 2 {
+					r := <-results
+					switch {
+					case r.err == nil:
+						accessIDs = append(accessIDs, r.sess.AccessID)
+					case errors.Is(r.err, sessionbus.ErrRefreshTokenReused):
+						reused = true
+					case !errors.Is(r.err, sessionbus.ErrRevoked):
+						return r.err
+					}
+				}
+
+				if !reused {
+					return fmt.Errorf("should report the reuse of the refresh token")
+				}
+
+				resp, err := busDomain.Session.QueryByID(ctx, created.ID)
+				if err != nil {
+					return err
+				}
+
+				if resp.DateRevoked.IsZero() {
+					return fmt.Errorf("should revoke the session")
+				}
+
+				for _, id := range append(accessIDs, resp.AccessID) {
+					revoked, err := busDomain.Session.IsTokenRevoked(ctx, id.String())
+					if err != nil {
+						return err
+					}
+
+					if !revoked {
+						return fmt.Errorf("should revoke access token %s", id)
+					}
+				}
+
+				return true
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	sess := sd.Sessions[1][1]
+	token := sd.Tokens[1][1]
+
+	table := []unitest.Table{
+		{
+			Name:    "session",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Session.Revoke(ctx, sess)
+				if err != nil {
+					return err
+				}
+
+				if resp.DateRevoked.IsZero() {
+					return fmt.Errorf("should set the revoked date")
+				}
+
+				revoked, err := busDomain.Session.IsTokenRevoked(ctx, sess.AccessID.String())
+				if err != nil {
+					return err
+				}
+
+				return revoked
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "refresh",
+			ExpResp: sessionbus.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				_, _, err := busDomain.Session.Refresh(ctx, token)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "active",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Session.QueryActiveByUserID(ctx, sd.Users[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func event(busDomain dbtest.BusDomain, sd seedData) []unitest.Table {
+	usr := sd.Users[2]
+
+	table := []unitest.Table{
+		{
+			Name:    "user-disabled",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				enabled := false
+				if _, err := busDomain.User.Update(ctx, usr.User, userbus.UpdateUser{Enabled: &enabled}); err != nil {
+					return err
+				}
+
+				for _, sess := range sd.Sessions[2] {
+					revoked, err := busDomain.Session.IsTokenRevoked(ctx, sess.AccessID.String())
+					if err != nil {
+						return err
+					}
+
+					if !revoked {
+						return fmt.Errorf("should revoke the access token of session %s", sess.ID)
+					}
+				}
+
+				resp, err := busDomain.Session.QueryActiveByUserID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return len(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
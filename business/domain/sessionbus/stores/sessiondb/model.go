@@ -0,0 +1,142 @@
+package sessiondb
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb/dbarray"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+type session struct {
+	ID            uuid.UUID      `db:"session_id"`
+	UserID        uuid.UUID      `db:"user_id"`
+	Roles         dbarray.String `db:"roles"`
+	Device        string         `db:"device"`
+	IPAddress     string         `db:"ip_address"`
+	AccessID      uuid.UUID      `db:"access_id"`
+	AccessExpires time.Time      `db:"access_expires"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateLastUsed  time.Time      `db:"date_last_used"`
+	DateExpires   time.Time      `db:"date_expires"`
+	DateRevoked   sql.NullTime   `db:"date_revoked"`
+}
+
+func toDBSession(bus sessionbus.Session) session {
+	db := session{
+		ID:            bus.ID,
+		UserID:        bus.UserID,
+		Roles:         role.ParseToString(bus.Roles),
+		Device:        bus.Device,
+		IPAddress:     bus.IPAddress,
+		AccessID:      bus.AccessID,
+		AccessExpires: bus.AccessExpires.UTC(),
+		DateCreated:   bus.DateCreated.UTC(),
+		DateLastUsed:  bus.DateLastUsed.UTC(),
+		DateExpires:   bus.DateExpires.UTC(),
+		DateRevoked: sql.NullTime{
+			Time:  bus.DateRevoked.UTC(),
+			Valid: !bus.DateRevoked.IsZero(),
+		},
+	}
+
+	return db
+}
+
+func toBusSession(db session) (sessionbus.Session, error) {
+	roles, err := role.ParseMany(db.Roles)
+	if err != nil {
+		return sessionbus.Session{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := sessionbus.Session{
+		ID:            db.ID,
+		UserID:        db.UserID,
+		Roles:         roles,
+		Device:        db.Device,
+		IPAddress:     db.IPAddress,
+		AccessID:      db.AccessID,
+		AccessExpires: db.AccessExpires.In(time.Local),
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateLastUsed:  db.DateLastUsed.In(time.Local),
+		DateExpires:   db.DateExpires.In(time.Local),
+	}
+
+	if db.DateRevoked.Valid {
+		bus.DateRevoked = db.DateRevoked.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusSessions(dbs []session) ([]sessionbus.Session, error) {
+	bus := make([]sessionbus.Session, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusSession(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type refreshToken struct {
+	TokenHash   string       `db:"token_hash"`
+	SessionID   uuid.UUID    `db:"session_id"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUsed    sql.NullTime `db:"date_used"`
+}
+
+func toDBRefreshToken(bus sessionbus.RefreshToken) refreshToken {
+	return refreshToken{
+		TokenHash:   hex.EncodeToString(bus.TokenHash),
+		SessionID:   bus.SessionID,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  bus.DateUsed.UTC(),
+			Valid: !bus.DateUsed.IsZero(),
+		},
+	}
+}
+
+func toBusRefreshToken(db refreshToken) (sessionbus.RefreshToken, error) {
+	hash, err := hex.DecodeString(db.TokenHash)
+	if err != nil {
+		return sessionbus.RefreshToken{}, fmt.Errorf("decode token hash: %w", err)
+	}
+
+	bus := sessionbus.RefreshToken{
+		TokenHash:   hash,
+		SessionID:   db.SessionID,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.DateUsed.Valid {
+		bus.DateUsed = db.DateUsed.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type revokedToken struct {
+	TokenID     string    `db:"token_id"`
+	DateExpires time.Time `db:"date_expires"`
+}
+
+func toDBRevokedToken(bus sessionbus.RevokedToken) revokedToken {
+	return revokedToken{
+		TokenID:     bus.TokenID,
+		DateExpires: bus.DateExpires.UTC(),
+	}
+}
@@ -0,0 +1,359 @@
+// Package sessiondb contains session related CRUD functionality.
+package sessiondb
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for session database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (sessionbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new session into the database.
+func (s *Store) Create(ctx context.Context, sess sessionbus.Session) error {
+	const q = `
+	INSERT INTO sessions
+		(session_id, user_id, roles, device, ip_address, access_id, access_expires, date_created, date_last_used, date_expires, date_revoked)
+	VALUES
+		(:session_id, :user_id, :roles, :device, :ip_address, :access_id, :access_expires, :date_created, :date_last_used, :date_expires, :date_revoked)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSession(sess)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a session document in the database.
+func (s *Store) Update(ctx context.Context, sess sessionbus.Session) error {
+	const q = `
+	UPDATE
+		sessions
+	SET
+		"access_id" = :access_id,
+		"access_expires" = :access_expires,
+		"date_last_used" = :date_last_used,
+		"date_expires" = :date_expires,
+		"date_revoked" = :date_revoked
+	WHERE
+		session_id = :session_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBSession(sess)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified session from the database.
+func (s *Store) QueryByID(ctx context.Context, sessionID uuid.UUID) (sessionbus.Session, error) {
+	data := struct {
+		ID string `db:"session_id"`
+	}{
+		ID: sessionID.String(),
+	}
+
+	const q = `
+	SELECT
+		session_id, user_id, roles, device, ip_address, access_id, access_expires, date_created, date_last_used, date_expires, date_revoked
+	FROM
+		sessions
+	WHERE
+		session_id = :session_id`
+
+	var dbSess session
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSess); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return sessionbus.Session{}, fmt.Errorf("db: %w", sessionbus.ErrNotFound)
+		}
+		return sessionbus.Session{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusSession(dbSess)
+}
+
+// Lock reads the specified session with a row lock held until the
+// transaction ends and calls fn with it and a store in that transaction, so
+// fn never acts on a session another transaction is changing. Outside of a
+// transaction the lock is taken in one of its own that commits when fn
+// succeeds.
+func (s *Store) Lock(ctx context.Context, sessionID uuid.UUID, fn func(storer sessionbus.Storer, sess sessionbus.Session) error) error {
+	db, ok := s.db.(*sqlx.DB)
+	if !ok {
+		return s.lock(ctx, sessionID, fn)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	store := Store{
+		log: s.log,
+		db:  tx,
+	}
+
+	if err := store.lock(ctx, sessionID, fn); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) lock(ctx context.Context, sessionID uuid.UUID, fn func(storer sessionbus.Storer, sess sessionbus.Session) error) error {
+	data := struct {
+		ID string `db:"session_id"`
+	}{
+		ID: sessionID.String(),
+	}
+
+	const q = `
+	SELECT
+		session_id, user_id, roles, device, ip_address, access_id, access_expires, date_created, date_last_used, date_expires, date_revoked
+	FROM
+		sessions
+	WHERE
+		session_id = :session_id
+	FOR UPDATE`
+
+	var dbSess session
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSess); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", sessionbus.ErrNotFound)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	sess, err := toBusSession(dbSess)
+	if err != nil {
+		return err
+	}
+
+	return fn(s, sess)
+}
+
+// QueryActiveByUserID retrieves the sessions of the user that are neither
+// revoked nor expired at the specified time.
+func (s *Store) QueryActiveByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]sessionbus.Session, error) {
+	data := struct {
+		UserID string    `db:"user_id"`
+		Now    time.Time `db:"now"`
+	}{
+		UserID: userID.String(),
+		Now:    now.UTC(),
+	}
+
+	const q = `
+	SELECT
+		session_id, user_id, roles, device, ip_address, access_id, access_expires, date_created, date_last_used, date_expires, date_revoked
+	FROM
+		sessions
+	WHERE
+		user_id = :user_id AND
+		date_revoked IS NULL AND
+		date_expires > :now
+	ORDER BY
+		date_last_used DESC`
+
+	var dbSessions []session
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbSessions); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusSessions(dbSessions)
+}
+
+// CreateRefreshToken inserts a refresh token into the database.
+func (s *Store) CreateRefreshToken(ctx context.Context, rt sessionbus.RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_hash, session_id, date_created, date_used)
+	VALUES
+		(:token_hash, :session_id, :date_created, :date_used)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRefreshToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRefreshToken gets the refresh token with the specified hash.
+func (s *Store) QueryRefreshToken(ctx context.Context, tokenHash []byte) (sessionbus.RefreshToken, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+	}
+
+	const q = `
+	SELECT
+		token_hash, session_id, date_created, date_used
+	FROM
+		refresh_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbRT refreshToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRT); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return sessionbus.RefreshToken{}, fmt.Errorf("db: %w", sessionbus.ErrInvalidRefreshToken)
+		}
+		return sessionbus.RefreshToken{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRefreshToken(dbRT)
+}
+
+// UseRefreshToken marks the refresh token with the specified hash as used.
+// Marking the token only while it is unused in a single statement guarantees
+// a token can only be exchanged once.
+func (s *Store) UseRefreshToken(ctx context.Context, tokenHash []byte, now time.Time) error {
+	data := struct {
+		TokenHash string    `db:"token_hash"`
+		Now       time.Time `db:"now"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+		Now:       now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		date_used = :now
+	WHERE
+		token_hash = :token_hash AND
+		date_used IS NULL
+	RETURNING
+		token_hash, session_id, date_created, date_used`
+
+	var dbRT refreshToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRT); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", sessionbus.ErrRefreshTokenReused)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRevokedToken adds an access token to the revoked tokens.
+func (s *Store) CreateRevokedToken(ctx context.Context, rt sessionbus.RevokedToken) error {
+	const q = `
+	INSERT INTO revoked_tokens
+		(token_id, date_expires)
+	VALUES
+		(:token_id, :date_expires)
+	ON CONFLICT (token_id) DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRevokedToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// IsTokenRevoked reports whether the access token with the specified ID is
+// in the revoked tokens.
+func (s *Store) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	data := struct {
+		TokenID string `db:"token_id"`
+	}{
+		TokenID: tokenID,
+	}
+
+	const q = `
+	SELECT
+		token_id, date_expires
+	FROM
+		revoked_tokens
+	WHERE
+		token_id = :token_id`
+
+	var dbRT revokedToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRT); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("db: %w", err)
+	}
+
+	return true, nil
+}
+
+// Purge removes the sessions that expired or were revoked before the
+// specified time along with their refresh tokens, and the revoked access
+// tokens that expired before it.
+func (s *Store) Purge(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now.UTC(),
+	}
+
+	const qs = `
+	DELETE FROM
+		sessions
+	WHERE
+		date_expires < :now OR
+		date_revoked < :now`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, qs, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	const qt = `
+	DELETE FROM
+		revoked_tokens
+	WHERE
+		date_expires < :now`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, qt, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
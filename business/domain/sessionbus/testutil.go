@@ -0,0 +1,45 @@
+package sessionbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// TestGenerateNewSessions is a helper method for testing.
+func TestGenerateNewSessions(n int, userID uuid.UUID) []NewSession {
+	newSessions := make([]NewSession, n)
+
+	for i := range n {
+		ns := NewSession{
+			UserID:    userID,
+			Device:    fmt.Sprintf("pwmanager-test-%d", i),
+			IPAddress: "127.0.0.1",
+		}
+
+		newSessions[i] = ns
+	}
+
+	return newSessions
+}
+
+// TestGenerateSeedSessions is a helper method for testing. The refresh token
+// of each session is returned at the same index.
+func TestGenerateSeedSessions(ctx context.Context, n int, api *Business, userID uuid.UUID) ([]Session, []string, error) {
+	newSessions := TestGenerateNewSessions(n, userID)
+
+	sessions := make([]Session, len(newSessions))
+	tokens := make([]string, len(newSessions))
+	for i, ns := range newSessions {
+		sess, token, err := api.Create(ctx, ns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("seeding session: idx: %d : %w", i, err)
+		}
+
+		sessions[i] = sess
+		tokens[i] = token
+	}
+
+	return sessions, tokens, nil
+}
@@ -0,0 +1,54 @@
+package syncbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/types/changekind"
+)
+
+// Tombstone records that a row was permanently removed. BundleID is the bundle
+// the row belonged to.
+type Tombstone struct {
+	Kind        changekind.Kind
+	ID          uuid.UUID
+	BundleID    uuid.UUID
+	DateDeleted time.Time
+}
+
+// Cursor is a position in the stream of changes. XID is the id of the
+// transaction that made a change and Seq orders the changes it made.
+type Cursor struct {
+	XID int64
+	Seq int64
+}
+
+// IsZero reports whether the cursor is the start of the stream.
+func (c Cursor) IsZero() bool {
+	return c.XID == 0 && c.Seq == 0
+}
+
+// After reports whether the cursor is further along the stream than other.
+func (c Cursor) After(other Cursor) bool {
+	if c.XID != other.XID {
+		return c.XID > other.XID
+	}
+
+	return c.Seq > other.Seq
+}
+
+// Changes represents the rows a user can access that changed after a cursor.
+// Bundles and entries in the trash are included with DateDeleted set. Cursor
+// is the value to pass on the next sync and HasMore reports that the limit
+// was reached before the changes ran out.
+type Changes struct {
+	Cursor     Cursor
+	HasMore    bool
+	Bundles    []bundlebus.Bundle
+	Keys       []keybus.Key
+	Entries    []entrybus.Entry
+	Tombstones []Tombstone
+}
@@ -0,0 +1,245 @@
+package syncdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb/dbarray"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/changekind"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+type bundle struct {
+	ID            uuid.UUID    `db:"bundle_id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	Type          string       `db:"type"`
+	Metadata      string       `db:"metadata"`
+	KeyGeneration int          `db:"key_generation"`
+	Revision      int          `db:"revision"`
+	DateCreated   time.Time    `db:"date_created"`
+	DateUpdated   time.Time    `db:"date_updated"`
+	DateDeleted   sql.NullTime `db:"date_deleted"`
+}
+
+func toBusBundle(db bundle) (bundlebus.Bundle, error) {
+	typ, err := bundletype.Parse(db.Type)
+	if err != nil {
+		return bundlebus.Bundle{}, fmt.Errorf("parse type: %w", err)
+	}
+
+	bus := bundlebus.Bundle{
+		ID:            db.ID,
+		UserID:        db.UserID,
+		Type:          typ,
+		Metadata:      db.Metadata,
+		KeyGeneration: db.KeyGeneration,
+		Revision:      db.Revision,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusBundles(dbs []bundle) ([]bundlebus.Bundle, error) {
+	bus := make([]bundlebus.Bundle, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusBundle(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type bundleKey struct {
+	ID          uuid.UUID      `db:"key_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	BundleID    uuid.UUID      `db:"bundle_id"`
+	Data        string         `db:"data"`
+	Roles       dbarray.String `db:"roles"`
+	Revision    int            `db:"revision"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toBusKey(db bundleKey) (keybus.Key, error) {
+	data, err := key.Parse(db.Data)
+	if err != nil {
+		return keybus.Key{}, fmt.Errorf("parse key: %w", err)
+	}
+
+	roles, err := bundlerole.ParseMany(db.Roles)
+	if err != nil {
+		return keybus.Key{}, fmt.Errorf("parse roles: %w", err)
+	}
+
+	bus := keybus.Key{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		BundleID:    db.BundleID,
+		Data:        data,
+		Roles:       roles,
+		Revision:    db.Revision,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusKeys(dbs []bundleKey) ([]keybus.Key, error) {
+	bus := make([]keybus.Key, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusKey(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type bundleEntry struct {
+	ID            uuid.UUID    `db:"entry_id"`
+	UserID        uuid.UUID    `db:"user_id"`
+	BundleID      uuid.UUID    `db:"bundle_id"`
+	Type          string       `db:"item_type"`
+	SchemaVersion int          `db:"schema_version"`
+	Data          string       `db:"data"`
+	KeyGeneration int          `db:"key_generation"`
+	Revision      int          `db:"revision"`
+	DateCreated   time.Time    `db:"date_created"`
+	DateUpdated   time.Time    `db:"date_updated"`
+	DateDeleted   sql.NullTime `db:"date_deleted"`
+}
+
+func toBusEntry(db bundleEntry) (entrybus.Entry, error) {
+	typ, err := itemtype.Parse(db.Type)
+	if err != nil {
+		return entrybus.Entry{}, fmt.Errorf("parse type: %w", err)
+	}
+
+	data, err := entry.Parse(db.Data)
+	if err != nil {
+		return entrybus.Entry{}, fmt.Errorf("parse entry: %w", err)
+	}
+
+	bus := entrybus.Entry{
+		ID:            db.ID,
+		UserID:        db.UserID,
+		BundleID:      db.BundleID,
+		Type:          typ,
+		SchemaVersion: db.SchemaVersion,
+		Data:          data,
+		KeyGeneration: db.KeyGeneration,
+		Revision:      db.Revision,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateDeleted.Valid {
+		bus.DateDeleted = db.DateDeleted.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusEntries(dbs []bundleEntry) ([]entrybus.Entry, error) {
+	bus := make([]entrybus.Entry, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusEntry(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type tombstone struct {
+	Kind        string    `db:"kind"`
+	ID          uuid.UUID `db:"id"`
+	BundleID    uuid.UUID `db:"bundle_id"`
+	DateDeleted time.Time `db:"date_deleted"`
+}
+
+func toBusTombstone(db tombstone) (syncbus.Tombstone, error) {
+	kind, err := changekind.Parse(db.Kind)
+	if err != nil {
+		return syncbus.Tombstone{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	bus := syncbus.Tombstone{
+		Kind:        kind,
+		ID:          db.ID,
+		BundleID:    db.BundleID,
+		DateDeleted: db.DateDeleted.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusTombstones(dbs []tombstone) ([]syncbus.Tombstone, error) {
+	bus := make([]syncbus.Tombstone, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusTombstone(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type position struct {
+	ChangeXID int64 `db:"change_xid"`
+	ChangeSeq int64 `db:"change_seq"`
+}
+
+func toBusCursor(db position) syncbus.Cursor {
+	return syncbus.Cursor{
+		XID: db.ChangeXID,
+		Seq: db.ChangeSeq,
+	}
+}
+
+func toBusCursors(dbs []position) []syncbus.Cursor {
+	cursors := make([]syncbus.Cursor, len(dbs))
+	for i, db := range dbs {
+		cursors[i] = toBusCursor(db)
+	}
+
+	return cursors
+}
@@ -0,0 +1,259 @@
+// Package syncdb contains sync related database access.
+package syncdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for sync database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// QueryCursor returns the position before the oldest transaction still
+// running. Every change before it is committed and no change can be made
+// before it anymore.
+func (s *Store) QueryCursor(ctx context.Context) (syncbus.Cursor, error) {
+	const q = `
+	SELECT
+		CAST(CAST(pg_snapshot_xmin(pg_current_snapshot()) AS TEXT) AS BIGINT) AS change_xid,
+		0 AS change_seq`
+
+	var dest position
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dest); err != nil {
+		return syncbus.Cursor{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusCursor(dest), nil
+}
+
+// QueryHorizon returns the highest position of the purged tombstones.
+func (s *Store) QueryHorizon(ctx context.Context) (syncbus.Cursor, error) {
+	const q = `
+	SELECT
+		change_xid, change_seq
+	FROM
+		sync_horizon`
+
+	var dest position
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, map[string]any{}, &dest); err != nil {
+		return syncbus.Cursor{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusCursor(dest), nil
+}
+
+// QueryPositions gets the positions of the changes to the bundles, keys,
+// entries and tombstones the user can access between the cursors, in order
+// and up to the limit.
+func (s *Store) QueryPositions(ctx context.Context, userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor, limit int) ([]syncbus.Cursor, error) {
+	data := window(userID, since, until)
+	data["limit"] = limit
+
+	const q = `
+	SELECT
+		change_xid, change_seq
+	FROM (
+		SELECT b.change_xid, b.change_seq FROM bundles b
+		JOIN keys k ON k.bundle_id = b.bundle_id AND k.user_id = :user_id
+		UNION ALL
+		SELECT change_xid, change_seq FROM keys
+		WHERE user_id = :user_id
+		UNION ALL
+		SELECT e.change_xid, e.change_seq FROM entries e
+		JOIN keys k ON k.bundle_id = e.bundle_id AND k.user_id = :user_id
+		UNION ALL
+		SELECT change_xid, change_seq FROM tombstones
+		WHERE user_id = :user_id OR bundle_id IN (SELECT bundle_id FROM keys WHERE user_id = :user_id)
+	) AS changes
+	WHERE
+		(change_xid, change_seq) > (:since_xid, :since_seq) AND
+		(change_xid, change_seq) <= (:until_xid, :until_seq)
+	ORDER BY
+		change_xid, change_seq
+	FETCH NEXT :limit ROWS ONLY`
+
+	var dbPositions []position
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPositions); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusCursors(dbPositions), nil
+}
+
+// QueryBundles gets the bundles the user holds a key for that changed, or
+// whose key changed, between the cursors.
+func (s *Store) QueryBundles(ctx context.Context, userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor) ([]bundlebus.Bundle, error) {
+	data := window(userID, since, until)
+
+	const q = `
+	SELECT
+		b.bundle_id, b.user_id, b.type, b.metadata, b.key_generation, b.revision, b.date_created, b.date_updated, b.date_deleted
+	FROM
+		bundles b
+	JOIN
+		keys k ON k.bundle_id = b.bundle_id AND k.user_id = :user_id
+	WHERE
+		((b.change_xid, b.change_seq) > (:since_xid, :since_seq) AND (b.change_xid, b.change_seq) <= (:until_xid, :until_seq)) OR
+		((k.change_xid, k.change_seq) > (:since_xid, :since_seq) AND (k.change_xid, k.change_seq) <= (:until_xid, :until_seq))
+	ORDER BY
+		b.change_xid, b.change_seq`
+
+	var dbBdls []bundle
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbBdls); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusBundles(dbBdls)
+}
+
+// QueryKeys gets the keys of the user that changed between the cursors.
+func (s *Store) QueryKeys(ctx context.Context, userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor) ([]keybus.Key, error) {
+	data := window(userID, since, until)
+
+	const q = `
+	SELECT
+		key_id, user_id, bundle_id, data, roles, revision, date_created, date_updated
+	FROM
+		keys
+	WHERE
+		user_id = :user_id AND
+		(change_xid, change_seq) > (:since_xid, :since_seq) AND
+		(change_xid, change_seq) <= (:until_xid, :until_seq)
+	ORDER BY
+		change_xid, change_seq`
+
+	var dbKeys []bundleKey
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbKeys); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusKeys(dbKeys)
+}
+
+// QueryEntries gets the entries in the bundles the user holds a key for that
+// changed, or whose bundle key changed, between the cursors.
+func (s *Store) QueryEntries(ctx context.Context, userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor) ([]entrybus.Entry, error) {
+	data := window(userID, since, until)
+
+	const q = `
+	SELECT
+		e.entry_id, e.user_id, e.bundle_id, e.item_type, e.schema_version, e.data, e.key_generation, e.revision,
+		e.date_created, e.date_updated, e.date_deleted
+	FROM
+		entries e
+	JOIN
+		keys k ON k.bundle_id = e.bundle_id AND k.user_id = :user_id
+	WHERE
+		((e.change_xid, e.change_seq) > (:since_xid, :since_seq) AND (e.change_xid, e.change_seq) <= (:until_xid, :until_seq)) OR
+		((k.change_xid, k.change_seq) > (:since_xid, :since_seq) AND (k.change_xid, k.change_seq) <= (:until_xid, :until_seq))
+	ORDER BY
+		e.change_xid, e.change_seq`
+
+	var dbEntries []bundleEntry
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEntries(dbEntries)
+}
+
+// QueryTombstones gets the tombstones written between the cursors for the
+// bundles the user holds a key for and for the keys of the user.
+func (s *Store) QueryTombstones(ctx context.Context, userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor) ([]syncbus.Tombstone, error) {
+	data := window(userID, since, until)
+
+	const q = `
+	SELECT
+		kind, id, bundle_id, date_deleted
+	FROM
+		tombstones
+	WHERE
+		(change_xid, change_seq) > (:since_xid, :since_seq) AND
+		(change_xid, change_seq) <= (:until_xid, :until_seq) AND
+		(user_id = :user_id OR bundle_id IN (SELECT bundle_id FROM keys WHERE user_id = :user_id))
+	ORDER BY
+		change_xid, change_seq`
+
+	var dbTombs []tombstone
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbTombs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusTombstones(dbTombs)
+}
+
+// PurgeTombstones removes the tombstones written before the specified time and
+// moves the horizon past them.
+func (s *Store) PurgeTombstones(ctx context.Context, before time.Time) error {
+	data := struct {
+		Before time.Time `db:"before"`
+	}{
+		Before: before.UTC(),
+	}
+
+	const q = `
+	WITH purged AS (
+		DELETE FROM
+			tombstones
+		WHERE
+			date_deleted < :before
+		RETURNING
+			change_xid, change_seq
+	), last AS (
+		SELECT
+			change_xid, change_seq
+		FROM
+			purged
+		ORDER BY
+			change_xid DESC, change_seq DESC
+		FETCH NEXT 1 ROWS ONLY
+	)
+	UPDATE
+		sync_horizon h
+	SET
+		change_xid = last.change_xid,
+		change_seq = last.change_seq
+	FROM
+		last
+	WHERE
+		(last.change_xid, last.change_seq) > (h.change_xid, h.change_seq)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// window returns the query parameters bounding the changes of the user to
+// the ones between the cursors.
+func window(userID uuid.UUID, since syncbus.Cursor, until syncbus.Cursor) map[string]any {
+	return map[string]any{
+		"user_id":   userID,
+		"since_xid": since.XID,
+		"since_seq": since.Seq,
+		"until_xid": until.XID,
+		"until_seq": until.Seq,
+	}
+}
@@ -0,0 +1,139 @@
+// Package syncbus provides business access to the changes of the bundles,
+// keys and entries a user can access so clients can keep a local copy in sync.
+package syncbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of error variables for sync operations.
+var (
+	ErrCursorExpired = errors.New("cursor expired, a full sync is required")
+)
+
+// Storer interface declares the behavior this package needs to retrieve
+// changes. The since and until cursors bound the changes, since is exclusive
+// and until is inclusive.
+type Storer interface {
+	QueryCursor(ctx context.Context) (Cursor, error)
+	QueryHorizon(ctx context.Context) (Cursor, error)
+	QueryPositions(ctx context.Context, userID uuid.UUID, since Cursor, until Cursor, limit int) ([]Cursor, error)
+	QueryBundles(ctx context.Context, userID uuid.UUID, since Cursor, until Cursor) ([]bundlebus.Bundle, error)
+	QueryKeys(ctx context.Context, userID uuid.UUID, since Cursor, until Cursor) ([]keybus.Key, error)
+	QueryEntries(ctx context.Context, userID uuid.UUID, since Cursor, until Cursor) ([]entrybus.Entry, error)
+	QueryTombstones(ctx context.Context, userID uuid.UUID, since Cursor, until Cursor) ([]Tombstone, error)
+	PurgeTombstones(ctx context.Context, before time.Time) error
+}
+
+// Business manages the set of APIs for sync access.
+type Business struct {
+	storer Storer
+}
+
+// NewBusiness constructs a sync business API for use.
+func NewBusiness(storer Storer) *Business {
+	return &Business{
+		storer: storer,
+	}
+}
+
+// Query retrieves up to limit changes the user can access after the since
+// cursor. A zero since cursor retrieves everything the user can access.
+// Bundles and entries are included in full when the user was given a key to
+// the bundle after the since cursor, they come along with the key and do not
+// count towards the limit.
+func (b *Business) Query(ctx context.Context, userID uuid.UUID, since Cursor, limit int) (Changes, error) {
+	ctx, span := otel.AddSpan(ctx, "business.syncbus.query")
+	defer span.End()
+
+	if limit < 1 {
+		return Changes{}, fmt.Errorf("limit must be positive: %d", limit)
+	}
+
+	horizon, err := b.storer.QueryHorizon(ctx)
+	if err != nil {
+		return Changes{}, fmt.Errorf("queryhorizon: %w", err)
+	}
+
+	// The cursor has to be read before the changes. Every transaction before
+	// the cursor has finished by then and changes made by later ones are
+	// picked up by the next sync.
+	cursor, err := b.storer.QueryCursor(ctx)
+	if err != nil {
+		return Changes{}, fmt.Errorf("querycursor: %w", err)
+	}
+
+	if since.XID < 0 || since.Seq < 0 || since.After(cursor) || (!since.IsZero() && horizon.After(since)) {
+		return Changes{}, ErrCursorExpired
+	}
+
+	// One position past the limit tells whether there is more to come. The
+	// page then ends on the last position within the limit.
+	positions, err := b.storer.QueryPositions(ctx, userID, since, cursor, limit+1)
+	if err != nil {
+		return Changes{}, fmt.Errorf("querypositions: %w", err)
+	}
+
+	hasMore := len(positions) > limit
+	if hasMore {
+		cursor = positions[limit-1]
+	}
+
+	bdls, err := b.storer.QueryBundles(ctx, userID, since, cursor)
+	if err != nil {
+		return Changes{}, fmt.Errorf("querybundles: %w", err)
+	}
+
+	keys, err := b.storer.QueryKeys(ctx, userID, since, cursor)
+	if err != nil {
+		return Changes{}, fmt.Errorf("querykeys: %w", err)
+	}
+
+	entries, err := b.storer.QueryEntries(ctx, userID, since, cursor)
+	if err != nil {
+		return Changes{}, fmt.Errorf("queryentries: %w", err)
+	}
+
+	// A full sync has nothing to remove.
+	var tombstones []Tombstone
+	if !since.IsZero() {
+		tombstones, err = b.storer.QueryTombstones(ctx, userID, since, cursor)
+		if err != nil {
+			return Changes{}, fmt.Errorf("querytombstones: %w", err)
+		}
+	}
+
+	changes := Changes{
+		Cursor:     cursor,
+		HasMore:    hasMore,
+		Bundles:    bdls,
+		Keys:       keys,
+		Entries:    entries,
+		Tombstones: tombstones,
+	}
+
+	return changes, nil
+}
+
+// PurgeTombstones removes the tombstones older than the retention period.
+// Clients holding a cursor from before the oldest remaining tombstone have to
+// run a full sync.
+func (b *Business) PurgeTombstones(ctx context.Context, retention time.Duration) error {
+	ctx, span := otel.AddSpan(ctx, "business.syncbus.purgetombstones")
+	defer span.End()
+
+	if err := b.storer.PurgeTombstones(ctx, time.Now().Add(-retention)); err != nil {
+		return fmt.Errorf("purgetombstones: %w", err)
+	}
+
+	return nil
+}
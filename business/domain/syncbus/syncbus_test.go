@@ -0,0 +1,364 @@
+package syncbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+func Test_Sync(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Sync")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, query(db.BusDomain, sd), "query")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	bdls, err := bundlebus.TestGenerateSeedShareableBundles(ctx, 2, busDomain.Bundle, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
+	}
+
+	bids := []uuid.UUID{}
+	for _, v := range bdls {
+		bids = append(bids, v.ID)
+	}
+
+	roles := []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write}
+	keys, err := keybus.TestGenerateSeedKeys(ctx, 2, busDomain.Key, usrs[0].ID, bids, roles)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	entries, err := entrybus.TestGenerateSeedEntries(ctx, 2, busDomain.Entry, usrs[0].ID, bids)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding entries : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User:    usrs[0],
+		Bundles: bdls,
+		Keys:    keys,
+		Entries: entries,
+	}
+
+	tu2 := unitest.User{
+		User: usrs[1],
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{tu1, tu2},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+// summary holds the ids of the changes in the order they were returned.
+type summary struct {
+	Bundles    []uuid.UUID
+	Keys       []uuid.UUID
+	Entries    []uuid.UUID
+	Tombstones []uuid.UUID
+}
+
+func summarize(c syncbus.Changes) summary {
+	s := summary{
+		Bundles:    make([]uuid.UUID, len(c.Bundles)),
+		Keys:       make([]uuid.UUID, len(c.Keys)),
+		Entries:    make([]uuid.UUID, len(c.Entries)),
+		Tombstones: make([]uuid.UUID, len(c.Tombstones)),
+	}
+
+	for i, b := range c.Bundles {
+		s.Bundles[i] = b.ID
+	}
+
+	for i, k := range c.Keys {
+		s.Keys[i] = k.ID
+	}
+
+	for i, e := range c.Entries {
+		s.Entries[i] = e.ID
+	}
+
+	for i, t := range c.Tombstones {
+		s.Tombstones[i] = t.ID
+	}
+
+	return s
+}
+
+func ids[T any](items []T, id func(T) uuid.UUID) []uuid.UUID {
+	s := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		s[i] = id(item)
+	}
+
+	return s
+}
+
+func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	owner := sd.Users[0]
+	member := sd.Users[1]
+
+	// The cursors are carried from one case to the next like a client would.
+	var ownerCursor syncbus.Cursor
+	var memberCursor syncbus.Cursor
+
+	const limit = 100
+
+	cmpSummary := func(got any, exp any) string {
+		gotResp, exists := got.(summary)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp, exp)
+	}
+
+	table := []unitest.Table{
+		{
+			Name: "full",
+			ExpResp: summary{
+				Bundles:    ids(owner.Bundles, func(b bundlebus.Bundle) uuid.UUID { return b.ID }),
+				Keys:       ids(owner.Keys, func(k keybus.Key) uuid.UUID { return k.ID }),
+				Entries:    ids(owner.Entries, func(e entrybus.Entry) uuid.UUID { return e.ID }),
+				Tombstones: []uuid.UUID{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Sync.Query(ctx, owner.ID, syncbus.Cursor{}, limit)
+				if err != nil {
+					return err
+				}
+
+				ownerCursor = resp.Cursor
+
+				return summarize(resp)
+			},
+			CmpFunc: cmpSummary,
+		},
+		{
+			Name:    "paged",
+			ExpResp: len(owner.Bundles) + len(owner.Keys) + len(owner.Entries),
+			ExcFunc: func(ctx context.Context) any {
+				var cursor syncbus.Cursor
+				seen := make(map[uuid.UUID]struct{})
+
+				// Paging one change at a time has to deliver everything the
+				// full sync did. Bundles and entries may come again along
+				// with a key on a later page.
+				for {
+					resp, err := busDomain.Sync.Query(ctx, owner.ID, cursor, 1)
+					if err != nil {
+						return err
+					}
+
+					if resp.HasMore && !resp.Cursor.After(cursor) {
+						return errors.New("cursor did not move forward")
+					}
+
+					s := summarize(resp)
+					for _, id := range slices.Concat(s.Bundles, s.Keys, s.Entries) {
+						seen[id] = struct{}{}
+					}
+
+					cursor = resp.Cursor
+
+					if !resp.HasMore {
+						break
+					}
+				}
+
+				return len(seen)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "unchanged",
+			ExpResp: summary{
+				Bundles:    []uuid.UUID{},
+				Keys:       []uuid.UUID{},
+				Entries:    []uuid.UUID{},
+				Tombstones: []uuid.UUID{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Sync.Query(ctx, owner.ID, ownerCursor, limit)
+				if err != nil {
+					return err
+				}
+
+				return summarize(resp)
+			},
+			CmpFunc: cmpSummary,
+		},
+		{
+			Name: "update",
+			ExpResp: summary{
+				Bundles:    []uuid.UUID{},
+				Keys:       []uuid.UUID{},
+				Entries:    []uuid.UUID{owner.Entries[0].ID},
+				Tombstones: []uuid.UUID{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				data := entrybus.TestNewData("Synced", 1)
+				ue := entrybus.UpdateEntry{
+					Data: &data,
+				}
+
+				if _, err := busDomain.Entry.Update(ctx, owner.Entries[0], ue); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Sync.Query(ctx, owner.ID, ownerCursor, limit)
+				if err != nil {
+					return err
+				}
+
+				ownerCursor = resp.Cursor
+
+				return summarize(resp)
+			},
+			CmpFunc: cmpSummary,
+		},
+		{
+			Name: "no-access",
+			ExpResp: summary{
+				Bundles:    []uuid.UUID{},
+				Keys:       []uuid.UUID{},
+				Entries:    []uuid.UUID{},
+				Tombstones: []uuid.UUID{},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Sync.Query(ctx, member.ID, syncbus.Cursor{}, limit)
+				if err != nil {
+					return err
+				}
+
+				memberCursor = resp.Cursor
+
+				return summarize(resp)
+			},
+			CmpFunc: cmpSummary,
+		},
+		{
+			Name:    "new-member",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				nk := keybus.TestGenerateNewKeys(1, member.ID, []uuid.UUID{owner.Bundles[1].ID}, []bundlerole.Role{bundlerole.Read})
+				k, err := busDomain.Key.Create(ctx, nk[0])
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Sync.Query(ctx, member.ID, memberCursor, limit)
+				if err != nil {
+					return err
+				}
+
+				// The bundle and its entries are older than the cursor but
+				// the member only got access now.
+				exp := summary{
+					Bundles:    []uuid.UUID{owner.Bundles[1].ID},
+					Keys:       []uuid.UUID{k.ID},
+					Entries:    []uuid.UUID{owner.Entries[2].ID, owner.Entries[3].ID},
+					Tombstones: []uuid.UUID{},
+				}
+
+				if diff := cmp.Diff(summarize(resp), exp); diff != "" {
+					return errors.New(diff)
+				}
+
+				return len(resp.Entries)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name: "purge",
+			ExpResp: summary{
+				Bundles:    []uuid.UUID{},
+				Keys:       []uuid.UUID{},
+				Entries:    []uuid.UUID{},
+				Tombstones: []uuid.UUID{owner.Entries[1].ID},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Entry.Delete(ctx, owner.Entries[1]); err != nil {
+					return err
+				}
+
+				if err := busDomain.Entry.PurgeDeleted(ctx, 0); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Sync.Query(ctx, owner.ID, ownerCursor, limit)
+				if err != nil {
+					return err
+				}
+
+				return summarize(resp)
+			},
+			CmpFunc: cmpSummary,
+		},
+		{
+			Name:    "expired",
+			ExpResp: syncbus.ErrCursorExpired,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Sync.PurgeTombstones(ctx, -time.Hour); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Sync.Query(ctx, owner.ID, ownerCursor, limit)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, exists := got.(error)
+				if !exists || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("expected error %v, got %v", exp, got)
+				}
+
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -43,6 +43,29 @@ type RegisterUser struct {
 	UUK      uuk.UUK
 }
 
+// RegistrationToken represents a one-time token minted by an admin that allows
+// a new user to complete registration. Only the hash of the token is stored,
+// the plaintext Token is returned once when the token is minted.
+type RegistrationToken struct {
+	UserID      uuid.UUID
+	Token       string
+	TokenHash   []byte
+	DateExpires time.Time
+	DateCreated time.Time
+}
+
+// LinkCode represents a short-lived, single-use code a logged in user mints to
+// start linking an identity from a browser redirect, so the user's token is
+// never put in a URL. Only the hash of the code is stored, the plaintext Code
+// is returned once when the code is minted.
+type LinkCode struct {
+	UserID      uuid.UUID
+	Code        string
+	CodeHash    []byte
+	DateExpires time.Time
+	DateCreated time.Time
+}
+
 // UpdateUser contains information needed to update a user.
 type UpdateUser struct {
 	Name       *name.Name
@@ -54,6 +77,103 @@ type UpdateUser struct {
 }
 
 // UpdateUserPassword contains information needed to update user password.
+// UUK is the users existing UUK with the EncSymKey re-wrapped under the new
+// password, the key pair it protects must not change.
 type UpdateUserPassword struct {
-	Password *string
+	OldPassword string
+	Password    string
+	UUK         uuk.UUK
+}
+
+// Set of recovery methods recorded in the recovery audit trail.
+const (
+	RecoveryMethodEnable = "enable"
+	RecoveryMethodKit    = "kit"
+	RecoveryMethodAdmin  = "admin"
+)
+
+// EnableRecovery contains information needed to turn on account recovery.
+// UUK is the users existing UUK with recovery keys added. Verifier is derived
+// from the users recovery kit and is empty when only recovery admins are used.
+type EnableRecovery struct {
+	UUK      uuk.UUK
+	Verifier string
+}
+
+// RecoverUser contains information needed for a user to recover their
+// account with their recovery kit.
+type RecoverUser struct {
+	Email    mail.Address
+	Verifier string
+	Password string
+	UUK      uuk.UUK
+}
+
+// AdminRecoverUser contains information needed for a recovery admin to
+// recover the account of a user.
+type AdminRecoverUser struct {
+	Password string
+	UUK      uuk.UUK
+}
+
+// RecoveryVerifier represents the hash of the verifier derived from a
+// users recovery kit.
+type RecoveryVerifier struct {
+	UserID       uuid.UUID
+	VerifierHash []byte
+	DateCreated  time.Time
+	DateUpdated  time.Time
+}
+
+// RecoveryEvent represents an entry in the recovery audit trail.
+type RecoveryEvent struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	ActorID     uuid.UUID
+	Method      string
+	DateCreated time.Time
+}
+
+// Identity represents an identity at an external provider, such as a Google
+// account, that is linked to a user. Subject is the provider's stable ID for
+// the account.
+type Identity struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Provider    string
+	Subject     string
+	Email       mail.Address
+	DateCreated time.Time
+}
+
+// ExternalIdentity contains the identity asserted by an external provider
+// after a user completes the provider's login.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         mail.Address
+	EmailVerified bool
+}
+
+// MFA represents the TOTP second factor of a user. The factor is only
+// enforced once it is Enabled, after the user proved their authenticator app
+// generates valid codes. LastStep is the time step of the last accepted code
+// so a code can't be used twice. FailedAttempts counts the invalid codes since
+// the last valid one and no code is accepted before LockedUntil.
+type MFA struct {
+	UserID         uuid.UUID
+	Secret         string
+	Enabled        bool
+	LastStep       int64
+	FailedAttempts int
+	LockedUntil    time.Time
+	DateCreated    time.Time
+	DateUpdated    time.Time
+}
+
+// MFAEnrollment contains what a user needs to add their account to an
+// authenticator app. URI is the provisioning URI to render as a QR code.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
 }
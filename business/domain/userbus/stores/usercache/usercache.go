@@ -10,6 +10,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/google/uuid"
 	"github.com/viccon/sturdyc"
@@ -20,6 +21,7 @@ type Store struct {
 	log    *logger.Logger
 	storer userbus.Storer
 	cache  *sturdyc.Client[userbus.User]
+	inTx   bool
 }
 
 // NewStore constructs the api for data and caching access.
@@ -37,8 +39,22 @@ func NewStore(log *logger.Logger, storer userbus.Storer, ttl time.Duration) *Sto
 
 // NewWithTx constructs a new Store value replacing the sqlx DB
 // value with a sqlx DB value that is currently inside a transaction.
+// Writes made inside the transaction evict the user from the cache
+// instead of caching data that may still be rolled back.
 func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (userbus.Storer, error) {
-	return s.storer.NewWithTx(tx)
+	storer, err := s.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log:    s.log,
+		storer: storer,
+		cache:  s.cache,
+		inTx:   true,
+	}
+
+	return &store, nil
 }
 
 // Create inserts a new user into the database.
@@ -118,6 +134,126 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 	return usr, nil
 }
 
+// CreateRegistrationToken inserts a registration token into the database.
+func (s *Store) CreateRegistrationToken(ctx context.Context, rt userbus.RegistrationToken) error {
+	return s.storer.CreateRegistrationToken(ctx, rt)
+}
+
+// ConsumeRegistrationToken deletes and returns the specified registration token.
+func (s *Store) ConsumeRegistrationToken(ctx context.Context, tokenHash []byte) (userbus.RegistrationToken, error) {
+	return s.storer.ConsumeRegistrationToken(ctx, tokenHash)
+}
+
+// CreateLinkCode inserts a link code into the database.
+func (s *Store) CreateLinkCode(ctx context.Context, lc userbus.LinkCode) error {
+	return s.storer.CreateLinkCode(ctx, lc)
+}
+
+// ConsumeLinkCode deletes and returns the specified link code.
+func (s *Store) ConsumeLinkCode(ctx context.Context, codeHash []byte) (userbus.LinkCode, error) {
+	return s.storer.ConsumeLinkCode(ctx, codeHash)
+}
+
+// SetRecoveryVerifier inserts or replaces the recovery kit verifier hash of a user.
+func (s *Store) SetRecoveryVerifier(ctx context.Context, rv userbus.RecoveryVerifier) error {
+	return s.storer.SetRecoveryVerifier(ctx, rv)
+}
+
+// QueryRecoveryVerifier gets the recovery kit verifier hash of a user.
+func (s *Store) QueryRecoveryVerifier(ctx context.Context, userID uuid.UUID) (userbus.RecoveryVerifier, error) {
+	return s.storer.QueryRecoveryVerifier(ctx, userID)
+}
+
+// CreateRecoveryEvent inserts an entry into the recovery audit trail.
+func (s *Store) CreateRecoveryEvent(ctx context.Context, re userbus.RecoveryEvent) error {
+	return s.storer.CreateRecoveryEvent(ctx, re)
+}
+
+// QueryRecoveryEvents retrieves the recovery audit trail of a user.
+func (s *Store) QueryRecoveryEvents(ctx context.Context, userID uuid.UUID) ([]userbus.RecoveryEvent, error) {
+	return s.storer.QueryRecoveryEvents(ctx, userID)
+}
+
+// CreateIdentity inserts a linked external identity into the database.
+func (s *Store) CreateIdentity(ctx context.Context, idn userbus.Identity) error {
+	return s.storer.CreateIdentity(ctx, idn)
+}
+
+// DeleteIdentity removes a linked external identity from the database.
+func (s *Store) DeleteIdentity(ctx context.Context, idn userbus.Identity) error {
+	return s.storer.DeleteIdentity(ctx, idn)
+}
+
+// QueryIdentity gets the linked external identity for the provider subject.
+func (s *Store) QueryIdentity(ctx context.Context, provider string, subject string) (userbus.Identity, error) {
+	return s.storer.QueryIdentity(ctx, provider, subject)
+}
+
+// QueryIdentityByID gets the specified linked external identity.
+func (s *Store) QueryIdentityByID(ctx context.Context, identityID uuid.UUID) (userbus.Identity, error) {
+	return s.storer.QueryIdentityByID(ctx, identityID)
+}
+
+// QueryIdentities retrieves the external identities linked to a user.
+func (s *Store) QueryIdentities(ctx context.Context, userID uuid.UUID) ([]userbus.Identity, error) {
+	return s.storer.QueryIdentities(ctx, userID)
+}
+
+// SetMFA inserts or replaces the two-factor authentication settings of a user.
+func (s *Store) SetMFA(ctx context.Context, mfa userbus.MFA) error {
+	return s.storer.SetMFA(ctx, mfa)
+}
+
+// DeleteMFA removes the two-factor authentication settings of a user.
+func (s *Store) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	return s.storer.DeleteMFA(ctx, userID)
+}
+
+// QueryMFA gets the two-factor authentication settings of a user.
+func (s *Store) QueryMFA(ctx context.Context, userID uuid.UUID) (userbus.MFA, error) {
+	return s.storer.QueryMFA(ctx, userID)
+}
+
+// UseMFAStep records the time step of an accepted TOTP code.
+func (s *Store) UseMFAStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	return s.storer.UseMFAStep(ctx, userID, step)
+}
+
+// FailMFAAttempt counts an invalid two-factor code of a user.
+func (s *Store) FailMFAAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockedUntil time.Time) error {
+	return s.storer.FailMFAAttempt(ctx, userID, maxAttempts, lockedUntil)
+}
+
+// ResetMFAAttempts clears the invalid two-factor codes counted for a user.
+func (s *Store) ResetMFAAttempts(ctx context.Context, userID uuid.UUID) error {
+	return s.storer.ResetMFAAttempts(ctx, userID)
+}
+
+// ReplaceMFARecoveryCodes replaces the recovery codes of a user.
+func (s *Store) ReplaceMFARecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes [][]byte) error {
+	return s.storer.ReplaceMFARecoveryCodes(ctx, userID, codeHashes)
+}
+
+// ConsumeMFARecoveryCode deletes the recovery code with the specified hash.
+func (s *Store) ConsumeMFARecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error {
+	return s.storer.ConsumeMFARecoveryCode(ctx, userID, codeHash)
+}
+
+// CreateMFARole adds a role to the roles that require two-factor authentication.
+func (s *Store) CreateMFARole(ctx context.Context, r role.Role) error {
+	return s.storer.CreateMFARole(ctx, r)
+}
+
+// DeleteMFARole removes a role from the roles that require two-factor authentication.
+func (s *Store) DeleteMFARole(ctx context.Context, r role.Role) error {
+	return s.storer.DeleteMFARole(ctx, r)
+}
+
+// QueryMFARoles retrieves the roles that require two-factor authentication.
+func (s *Store) QueryMFARoles(ctx context.Context) ([]role.Role, error) {
+	return s.storer.QueryMFARoles(ctx)
+}
+
 // readCache performs a safe search in the cache for the specified key.
 func (s *Store) readCache(key string) (userbus.User, bool) {
 	usr, exists := s.cache.Get(key)
@@ -130,6 +266,11 @@ func (s *Store) readCache(key string) (userbus.User, bool) {
 
 // writeCache performs a safe write to the cache for the specified userbus.
 func (s *Store) writeCache(bus userbus.User) {
+	if s.inTx {
+		s.deleteCache(bus)
+		return
+	}
+
 	s.cache.Set(bus.ID.String(), bus)
 	s.cache.Set(bus.Email.Address, bus)
 }
@@ -2,12 +2,15 @@ package userdb
 
 import (
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/mail"
 	"time"
 
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb/dbarray"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
 	"github.com/gradientsearch/pwmanager/business/types/name"
 	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/google/uuid"
@@ -23,10 +26,23 @@ type user struct {
 	Enabled      bool           `db:"enabled"`
 	DateCreated  time.Time      `db:"date_created"`
 	DateUpdated  time.Time      `db:"date_updated"`
+	UUK          sql.NullString `db:"uuk"`
 }
 
-func toDBUser(bus userbus.User) user {
-	return user{
+func toDBUser(bus userbus.User) (user, error) {
+	var dbUUK sql.NullString
+	if bus.UUK.UUID != uuid.Nil {
+		data, err := json.Marshal(bus.UUK)
+		if err != nil {
+			return user{}, fmt.Errorf("marshal uuk: %w", err)
+		}
+		dbUUK = sql.NullString{
+			String: string(data),
+			Valid:  true,
+		}
+	}
+
+	db := user{
 		ID:           bus.ID,
 		Name:         bus.Name.String(),
 		Email:        bus.Email.Address,
@@ -39,7 +55,10 @@ func toDBUser(bus userbus.User) user {
 		Enabled:     bus.Enabled,
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
+		UUK:         dbUUK,
 	}
+
+	return db, nil
 }
 
 func toBusUser(db user) (userbus.User, error) {
@@ -62,6 +81,13 @@ func toBusUser(db user) (userbus.User, error) {
 		return userbus.User{}, fmt.Errorf("parse department: %w", err)
 	}
 
+	var usrUUK uuk.UUK
+	if db.UUK.Valid {
+		if err := json.Unmarshal([]byte(db.UUK.String), &usrUUK); err != nil {
+			return userbus.User{}, fmt.Errorf("unmarshal uuk: %w", err)
+		}
+	}
+
 	bus := userbus.User{
 		ID:           db.ID,
 		Name:         nme,
@@ -72,6 +98,7 @@ func toBusUser(db user) (userbus.User, error) {
 		Department:   department,
 		DateCreated:  db.DateCreated.In(time.Local),
 		DateUpdated:  db.DateUpdated.In(time.Local),
+		UUK:          usrUUK,
 	}
 
 	return bus, nil
@@ -90,3 +117,268 @@ func toBusUsers(dbs []user) ([]userbus.User, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type registrationToken struct {
+	UserID      uuid.UUID `db:"user_id"`
+	TokenHash   string    `db:"token_hash"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBRegistrationToken(bus userbus.RegistrationToken) registrationToken {
+	return registrationToken{
+		UserID:      bus.UserID,
+		TokenHash:   hex.EncodeToString(bus.TokenHash),
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusRegistrationToken(db registrationToken) (userbus.RegistrationToken, error) {
+	hash, err := hex.DecodeString(db.TokenHash)
+	if err != nil {
+		return userbus.RegistrationToken{}, fmt.Errorf("decode token hash: %w", err)
+	}
+
+	bus := userbus.RegistrationToken{
+		UserID:      db.UserID,
+		TokenHash:   hash,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type linkCode struct {
+	UserID      uuid.UUID `db:"user_id"`
+	CodeHash    string    `db:"code_hash"`
+	DateExpires time.Time `db:"date_expires"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBLinkCode(bus userbus.LinkCode) linkCode {
+	return linkCode{
+		UserID:      bus.UserID,
+		CodeHash:    hex.EncodeToString(bus.CodeHash),
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusLinkCode(db linkCode) (userbus.LinkCode, error) {
+	hash, err := hex.DecodeString(db.CodeHash)
+	if err != nil {
+		return userbus.LinkCode{}, fmt.Errorf("decode code hash: %w", err)
+	}
+
+	bus := userbus.LinkCode{
+		UserID:      db.UserID,
+		CodeHash:    hash,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type recoveryVerifier struct {
+	UserID       uuid.UUID `db:"user_id"`
+	VerifierHash string    `db:"verifier_hash"`
+	DateCreated  time.Time `db:"date_created"`
+	DateUpdated  time.Time `db:"date_updated"`
+}
+
+func toDBRecoveryVerifier(bus userbus.RecoveryVerifier) recoveryVerifier {
+	return recoveryVerifier{
+		UserID:       bus.UserID,
+		VerifierHash: hex.EncodeToString(bus.VerifierHash),
+		DateCreated:  bus.DateCreated.UTC(),
+		DateUpdated:  bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusRecoveryVerifier(db recoveryVerifier) (userbus.RecoveryVerifier, error) {
+	hash, err := hex.DecodeString(db.VerifierHash)
+	if err != nil {
+		return userbus.RecoveryVerifier{}, fmt.Errorf("decode verifier hash: %w", err)
+	}
+
+	bus := userbus.RecoveryVerifier{
+		UserID:       db.UserID,
+		VerifierHash: hash,
+		DateCreated:  db.DateCreated.In(time.Local),
+		DateUpdated:  db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type recoveryEvent struct {
+	ID          uuid.UUID `db:"event_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	ActorID     uuid.UUID `db:"actor_id"`
+	Method      string    `db:"method"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBRecoveryEvent(bus userbus.RecoveryEvent) recoveryEvent {
+	return recoveryEvent{
+		ID:          bus.ID,
+		UserID:      bus.UserID,
+		ActorID:     bus.ActorID,
+		Method:      bus.Method,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusRecoveryEvents(dbs []recoveryEvent) []userbus.RecoveryEvent {
+	bus := make([]userbus.RecoveryEvent, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = userbus.RecoveryEvent{
+			ID:          db.ID,
+			UserID:      db.UserID,
+			ActorID:     db.ActorID,
+			Method:      db.Method,
+			DateCreated: db.DateCreated.In(time.Local),
+		}
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type identity struct {
+	ID          uuid.UUID `db:"identity_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Provider    string    `db:"provider"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBIdentity(bus userbus.Identity) identity {
+	return identity{
+		ID:          bus.ID,
+		UserID:      bus.UserID,
+		Provider:    bus.Provider,
+		Subject:     bus.Subject,
+		Email:       bus.Email.Address,
+		DateCreated: bus.DateCreated.UTC(),
+	}
+}
+
+func toBusIdentity(db identity) userbus.Identity {
+	return userbus.Identity{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		Provider:    db.Provider,
+		Subject:     db.Subject,
+		Email:       mail.Address{Address: db.Email},
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+}
+
+func toBusIdentities(dbs []identity) []userbus.Identity {
+	bus := make([]userbus.Identity, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusIdentity(db)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type mfa struct {
+	UserID         uuid.UUID    `db:"user_id"`
+	Secret         string       `db:"secret"`
+	Enabled        bool         `db:"enabled"`
+	LastStep       int64        `db:"last_step"`
+	FailedAttempts int          `db:"failed_attempts"`
+	LockedUntil    sql.NullTime `db:"locked_until"`
+	DateCreated    time.Time    `db:"date_created"`
+	DateUpdated    time.Time    `db:"date_updated"`
+}
+
+func toDBMFA(bus userbus.MFA) mfa {
+	return mfa{
+		UserID:      bus.UserID,
+		Secret:      bus.Secret,
+		Enabled:     bus.Enabled,
+		LastStep:    bus.LastStep,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusMFA(db mfa) userbus.MFA {
+	bus := userbus.MFA{
+		UserID:         db.UserID,
+		Secret:         db.Secret,
+		Enabled:        db.Enabled,
+		LastStep:       db.LastStep,
+		FailedAttempts: db.FailedAttempts,
+		DateCreated:    db.DateCreated.In(time.Local),
+		DateUpdated:    db.DateUpdated.In(time.Local),
+	}
+
+	if db.LockedUntil.Valid {
+		bus.LockedUntil = db.LockedUntil.Time.In(time.Local)
+	}
+
+	return bus
+}
+
+// =============================================================================
+
+type mfaRecoveryCode struct {
+	UserID   uuid.UUID `db:"user_id"`
+	CodeHash string    `db:"code_hash"`
+}
+
+func toDBMFARecoveryCodes(userID uuid.UUID, codeHashes [][]byte) []mfaRecoveryCode {
+	db := make([]mfaRecoveryCode, len(codeHashes))
+
+	for i, hash := range codeHashes {
+		db[i] = mfaRecoveryCode{
+			UserID:   userID,
+			CodeHash: hex.EncodeToString(hash),
+		}
+	}
+
+	return db
+}
+
+// =============================================================================
+
+type mfaRole struct {
+	Role        string    `db:"role"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toBusMFARoles(dbs []mfaRole) ([]role.Role, error) {
+	bus := make([]role.Role, len(dbs))
+
+	for i, db := range dbs {
+		r, err := role.Parse(db.Role)
+		if err != nil {
+			return nil, fmt.Errorf("parse role: %w", err)
+		}
+
+		bus[i] = r
+	}
+
+	return bus, nil
+}
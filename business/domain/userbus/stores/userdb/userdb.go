@@ -4,14 +4,17 @@ package userdb
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/mail"
+	"time"
 
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -51,11 +54,16 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (userbus.Storer, error) {
 func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated)
+		(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, uuk)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated)`
+		(:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated, :uuk)`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	dbUsr, err := toDBUser(usr)
+	if err != nil {
+		return err
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbUsr); err != nil {
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
 			return fmt.Errorf("namedexeccontext: %w", userbus.ErrUniqueEmail)
 		}
@@ -77,11 +85,17 @@ func (s *Store) Update(ctx context.Context, usr userbus.User) error {
 		"password_hash" = :password_hash,
 		"department" = :department,
 		"enabled" = :enabled,
-		"date_updated" = :date_updated
+		"date_updated" = :date_updated,
+		"uuk" = :uuk
 	WHERE
 		user_id = :user_id`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	dbUsr, err := toDBUser(usr)
+	if err != nil {
+		return err
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbUsr); err != nil {
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
 			return userbus.ErrUniqueEmail
 		}
@@ -99,7 +113,13 @@ func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	WHERE
 		user_id = :user_id`
 
-	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: usr.ID.String(),
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -115,7 +135,7 @@ func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy o
 
 	const q = `
 	SELECT
-		user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+		user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, uuk
 	FROM
 		users`
 
@@ -171,7 +191,7 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User,
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, uuk
 	FROM
 		users
 	WHERE 
@@ -198,7 +218,7 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, uuk
 	FROM
 		users
 	WHERE
@@ -214,3 +234,587 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	return toBusUser(dbUsr)
 }
+
+// CreateRegistrationToken inserts a registration token into the database,
+// replacing any token previously minted for the user.
+func (s *Store) CreateRegistrationToken(ctx context.Context, rt userbus.RegistrationToken) error {
+	const q = `
+	INSERT INTO registration_tokens
+		(user_id, token_hash, date_expires, date_created)
+	VALUES
+		(:user_id, :token_hash, :date_expires, :date_created)
+	ON CONFLICT (user_id) DO UPDATE SET
+		token_hash = EXCLUDED.token_hash,
+		date_expires = EXCLUDED.date_expires,
+		date_created = EXCLUDED.date_created`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRegistrationToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeRegistrationToken deletes the registration token with the specified
+// hash and returns it. Deleting and returning the row in a single statement
+// guarantees a token can only be consumed once.
+func (s *Store) ConsumeRegistrationToken(ctx context.Context, tokenHash []byte) (userbus.RegistrationToken, error) {
+	data := struct {
+		TokenHash string `db:"token_hash"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+	}
+
+	const q = `
+	DELETE FROM
+		registration_tokens
+	WHERE
+		token_hash = :token_hash
+	RETURNING
+		user_id, token_hash, date_expires, date_created`
+
+	var dbRT registrationToken
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRT); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.RegistrationToken{}, fmt.Errorf("db: %w", userbus.ErrInvalidRegistrationToken)
+		}
+		return userbus.RegistrationToken{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRegistrationToken(dbRT)
+}
+
+// CreateLinkCode inserts a link code into the database, replacing any code
+// previously minted for the user.
+func (s *Store) CreateLinkCode(ctx context.Context, lc userbus.LinkCode) error {
+	const q = `
+	INSERT INTO link_codes
+		(user_id, code_hash, date_expires, date_created)
+	VALUES
+		(:user_id, :code_hash, :date_expires, :date_created)
+	ON CONFLICT (user_id) DO UPDATE SET
+		code_hash = EXCLUDED.code_hash,
+		date_expires = EXCLUDED.date_expires,
+		date_created = EXCLUDED.date_created`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBLinkCode(lc)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLinkCode deletes the link code with the specified hash and returns
+// it. Deleting and returning the row in a single statement guarantees a code
+// can only be consumed once.
+func (s *Store) ConsumeLinkCode(ctx context.Context, codeHash []byte) (userbus.LinkCode, error) {
+	data := struct {
+		CodeHash string `db:"code_hash"`
+	}{
+		CodeHash: hex.EncodeToString(codeHash),
+	}
+
+	const q = `
+	DELETE FROM
+		link_codes
+	WHERE
+		code_hash = :code_hash
+	RETURNING
+		user_id, code_hash, date_expires, date_created`
+
+	var dbLC linkCode
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbLC); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.LinkCode{}, fmt.Errorf("db: %w", userbus.ErrInvalidLinkCode)
+		}
+		return userbus.LinkCode{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusLinkCode(dbLC)
+}
+
+// SetRecoveryVerifier inserts or replaces the recovery kit verifier hash of a user.
+func (s *Store) SetRecoveryVerifier(ctx context.Context, rv userbus.RecoveryVerifier) error {
+	const q = `
+	INSERT INTO user_recovery
+		(user_id, verifier_hash, date_created, date_updated)
+	VALUES
+		(:user_id, :verifier_hash, :date_created, :date_updated)
+	ON CONFLICT (user_id) DO UPDATE SET
+		verifier_hash = EXCLUDED.verifier_hash,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRecoveryVerifier(rv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRecoveryVerifier gets the recovery kit verifier hash of a user.
+func (s *Store) QueryRecoveryVerifier(ctx context.Context, userID uuid.UUID) (userbus.RecoveryVerifier, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, verifier_hash, date_created, date_updated
+	FROM
+		user_recovery
+	WHERE
+		user_id = :user_id AND verifier_hash IS NOT NULL`
+
+	var dbRV recoveryVerifier
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRV); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.RecoveryVerifier{}, fmt.Errorf("db: %w", userbus.ErrRecoveryNotEnabled)
+		}
+		return userbus.RecoveryVerifier{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRecoveryVerifier(dbRV)
+}
+
+// CreateRecoveryEvent inserts an entry into the recovery audit trail.
+func (s *Store) CreateRecoveryEvent(ctx context.Context, re userbus.RecoveryEvent) error {
+	const q = `
+	INSERT INTO recovery_events
+		(event_id, user_id, actor_id, method, date_created)
+	VALUES
+		(:event_id, :user_id, :actor_id, :method, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRecoveryEvent(re)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryRecoveryEvents retrieves the recovery audit trail of a user.
+func (s *Store) QueryRecoveryEvents(ctx context.Context, userID uuid.UUID) ([]userbus.RecoveryEvent, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		event_id, user_id, actor_id, method, date_created
+	FROM
+		recovery_events
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created DESC`
+
+	var dbEvents []recoveryEvent
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvents); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusRecoveryEvents(dbEvents), nil
+}
+
+// CreateIdentity inserts a linked external identity into the database.
+func (s *Store) CreateIdentity(ctx context.Context, idn userbus.Identity) error {
+	const q = `
+	INSERT INTO user_identities
+		(identity_id, user_id, provider, subject, email, date_created)
+	VALUES
+		(:identity_id, :user_id, :provider, :subject, :email, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBIdentity(idn)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", userbus.ErrIdentityLinked)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteIdentity removes a linked external identity from the database.
+func (s *Store) DeleteIdentity(ctx context.Context, idn userbus.Identity) error {
+	const q = `
+	DELETE FROM
+		user_identities
+	WHERE
+		identity_id = :identity_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBIdentity(idn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryIdentity gets the linked external identity for the provider subject.
+func (s *Store) QueryIdentity(ctx context.Context, provider string, subject string) (userbus.Identity, error) {
+	data := struct {
+		Provider string `db:"provider"`
+		Subject  string `db:"subject"`
+	}{
+		Provider: provider,
+		Subject:  subject,
+	}
+
+	const q = `
+	SELECT
+		identity_id, user_id, provider, subject, email, date_created
+	FROM
+		user_identities
+	WHERE
+		provider = :provider AND subject = :subject`
+
+	var dbIdn identity
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbIdn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.Identity{}, fmt.Errorf("db: %w", userbus.ErrIdentityNotFound)
+		}
+		return userbus.Identity{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusIdentity(dbIdn), nil
+}
+
+// QueryIdentityByID gets the specified linked external identity.
+func (s *Store) QueryIdentityByID(ctx context.Context, identityID uuid.UUID) (userbus.Identity, error) {
+	data := struct {
+		ID string `db:"identity_id"`
+	}{
+		ID: identityID.String(),
+	}
+
+	const q = `
+	SELECT
+		identity_id, user_id, provider, subject, email, date_created
+	FROM
+		user_identities
+	WHERE
+		identity_id = :identity_id`
+
+	var dbIdn identity
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbIdn); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.Identity{}, fmt.Errorf("db: %w", userbus.ErrIdentityNotFound)
+		}
+		return userbus.Identity{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusIdentity(dbIdn), nil
+}
+
+// QueryIdentities retrieves the external identities linked to a user.
+func (s *Store) QueryIdentities(ctx context.Context, userID uuid.UUID) ([]userbus.Identity, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		identity_id, user_id, provider, subject, email, date_created
+	FROM
+		user_identities
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created`
+
+	var dbIdns []identity
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbIdns); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusIdentities(dbIdns), nil
+}
+
+// SetMFA inserts or replaces the two-factor authentication settings of a user.
+func (s *Store) SetMFA(ctx context.Context, m userbus.MFA) error {
+	const q = `
+	INSERT INTO user_mfa
+		(user_id, secret, enabled, last_step, date_created, date_updated)
+	VALUES
+		(:user_id, :secret, :enabled, :last_step, :date_created, :date_updated)
+	ON CONFLICT (user_id) DO UPDATE SET
+		secret = EXCLUDED.secret,
+		enabled = EXCLUDED.enabled,
+		last_step = EXCLUDED.last_step,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBMFA(m)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMFA removes the two-factor authentication settings of a user along
+// with their recovery codes.
+func (s *Store) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		user_mfa
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMFA gets the two-factor authentication settings of a user.
+func (s *Store) QueryMFA(ctx context.Context, userID uuid.UUID) (userbus.MFA, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, secret, enabled, last_step, failed_attempts, locked_until, date_created, date_updated
+	FROM
+		user_mfa
+	WHERE
+		user_id = :user_id`
+
+	var dbMFA mfa
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbMFA); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return userbus.MFA{}, fmt.Errorf("db: %w", userbus.ErrMFANotEnabled)
+		}
+		return userbus.MFA{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusMFA(dbMFA), nil
+}
+
+// UseMFAStep records the time step of an accepted TOTP code. The step is
+// only recorded while it is newer than the last one in a single statement,
+// which guarantees a code can only be used once.
+func (s *Store) UseMFAStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	data := struct {
+		ID   string `db:"user_id"`
+		Step int64  `db:"last_step"`
+	}{
+		ID:   userID.String(),
+		Step: step,
+	}
+
+	const q = `
+	UPDATE
+		user_mfa
+	SET
+		last_step = :last_step
+	WHERE
+		user_id = :user_id AND
+		last_step < :last_step
+	RETURNING
+		user_id, secret, enabled, last_step, failed_attempts, locked_until, date_created, date_updated`
+
+	var dbMFA mfa
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbMFA); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", userbus.ErrInvalidMFACode)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// FailMFAAttempt counts an invalid code in a single statement and locks the
+// second factor until the specified time once the attempts reach the maximum.
+func (s *Store) FailMFAAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockedUntil time.Time) error {
+	data := struct {
+		ID          string    `db:"user_id"`
+		MaxAttempts int       `db:"max_attempts"`
+		LockedUntil time.Time `db:"locked_until"`
+	}{
+		ID:          userID.String(),
+		MaxAttempts: maxAttempts,
+		LockedUntil: lockedUntil.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		user_mfa
+	SET
+		failed_attempts = failed_attempts + 1,
+		locked_until = CASE WHEN failed_attempts + 1 >= :max_attempts THEN :locked_until ELSE locked_until END
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ResetMFAAttempts clears the invalid codes counted for a user.
+func (s *Store) ResetMFAAttempts(ctx context.Context, userID uuid.UUID) error {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	UPDATE
+		user_mfa
+	SET
+		failed_attempts = 0,
+		locked_until = NULL
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceMFARecoveryCodes replaces the recovery codes of a user.
+func (s *Store) ReplaceMFARecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes [][]byte) error {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const qd = `
+	DELETE FROM
+		user_mfa_recovery_codes
+	WHERE
+		user_id = :user_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, qd, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	const qi = `
+	INSERT INTO user_mfa_recovery_codes
+		(user_id, code_hash)
+	VALUES
+		(:user_id, :code_hash)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, qi, toDBMFARecoveryCodes(userID, codeHashes)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeMFARecoveryCode deletes the recovery code with the specified hash.
+// Deleting the row in a single statement guarantees a recovery code can
+// only be used once.
+func (s *Store) ConsumeMFARecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error {
+	data := struct {
+		ID       string `db:"user_id"`
+		CodeHash string `db:"code_hash"`
+	}{
+		ID:       userID.String(),
+		CodeHash: hex.EncodeToString(codeHash),
+	}
+
+	const q = `
+	DELETE FROM
+		user_mfa_recovery_codes
+	WHERE
+		user_id = :user_id AND
+		code_hash = :code_hash
+	RETURNING
+		user_id, code_hash`
+
+	var dbCode mfaRecoveryCode
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCode); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", userbus.ErrInvalidMFACode)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMFARole adds a role to the roles that require two-factor
+// authentication.
+func (s *Store) CreateMFARole(ctx context.Context, r role.Role) error {
+	data := mfaRole{
+		Role:        r.String(),
+		DateCreated: time.Now().UTC(),
+	}
+
+	const q = `
+	INSERT INTO mfa_roles
+		(role, date_created)
+	VALUES
+		(:role, :date_created)
+	ON CONFLICT (role) DO NOTHING`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMFARole removes a role from the roles that require two-factor
+// authentication.
+func (s *Store) DeleteMFARole(ctx context.Context, r role.Role) error {
+	data := struct {
+		Role string `db:"role"`
+	}{
+		Role: r.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		mfa_roles
+	WHERE
+		role = :role`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryMFARoles retrieves the roles that require two-factor authentication.
+func (s *Store) QueryMFARoles(ctx context.Context) ([]role.Role, error) {
+	const q = `
+	SELECT
+		role, date_created
+	FROM
+		mfa_roles
+	ORDER BY
+		role`
+
+	var dbRoles []mfaRole
+	if err := sqldb.QuerySlice(ctx, s.log, s.db, q, &dbRoles); err != nil {
+		return nil, fmt.Errorf("queryslice: %w", err)
+	}
+
+	return toBusMFARoles(dbRoles)
+}
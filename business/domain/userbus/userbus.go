@@ -3,15 +3,25 @@ package userbus
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
 	"github.com/gradientsearch/pwmanager/business/sdk/order"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/sdk/totp"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/gradientsearch/pwmanager/business/types/role"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/gradientsearch/pwmanager/foundation/otel"
 	"github.com/google/uuid"
@@ -20,9 +30,46 @@ import (
 
 // Set of error variables for CRUD operations.
 var (
-	ErrNotFound              = errors.New("user not found")
-	ErrUniqueEmail           = errors.New("email is not unique")
-	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrNotFound                 = errors.New("user not found")
+	ErrUniqueEmail              = errors.New("email is not unique")
+	ErrAuthenticationFailure    = errors.New("authentication failed")
+	ErrInvalidRegistrationToken = errors.New("registration token is invalid")
+	ErrExpiredRegistrationToken = errors.New("registration token has expired")
+	ErrInvalidLinkCode          = errors.New("link code is invalid")
+	ErrInvalidUUK               = errors.New("uuk is invalid")
+	ErrNotRegistered            = errors.New("user has not completed registration")
+	ErrUUKMismatch              = errors.New("uuk does not protect the existing key pair")
+	ErrRecoveryNotEnabled       = errors.New("account recovery is not enabled")
+	ErrIdentityNotFound         = errors.New("identity not found")
+	ErrIdentityLinked           = errors.New("identity is already linked")
+	ErrEmailNotVerified         = errors.New("identity email is not verified")
+	ErrDomainNotAllowed         = errors.New("identity email domain is not allowed")
+	ErrMFANotEnabled            = errors.New("two-factor authentication is not enabled")
+	ErrMFAEnabled               = errors.New("two-factor authentication is already enabled")
+	ErrMFARequired              = errors.New("two-factor authentication is required for the user's roles")
+	ErrMFACodeRequired          = errors.New("two-factor code is required")
+	ErrInvalidMFACode           = errors.New("two-factor code is invalid")
+	ErrMFALocked                = errors.New("two-factor authentication is locked after too many invalid codes")
+)
+
+// RegistrationTokenTTL is how long a registration token minted by an admin
+// remains valid.
+const RegistrationTokenTTL = 72 * time.Hour
+
+// LinkCodeTTL is how long a link code can be exchanged for starting to link
+// an identity.
+const LinkCodeTTL = time.Minute
+
+// MFAIssuer names the account in authenticator apps. MFARecoveryCodes is the
+// number of recovery codes issued when two-factor authentication is enabled,
+// each code can replace a TOTP code once. After MFAMaxAttempts invalid codes
+// in a row no code is accepted for MFALockout, and every invalid code after
+// that locks it again until a valid code is given.
+const (
+	MFAIssuer        = "pwmanager"
+	MFARecoveryCodes = 10
+	MFAMaxAttempts   = 5
+	MFALockout       = 15 * time.Minute
 )
 
 // Storer interface declares the behavior this package needs to persist and
@@ -36,13 +83,39 @@ type Storer interface {
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
+	CreateRegistrationToken(ctx context.Context, rt RegistrationToken) error
+	ConsumeRegistrationToken(ctx context.Context, tokenHash []byte) (RegistrationToken, error)
+	CreateLinkCode(ctx context.Context, lc LinkCode) error
+	ConsumeLinkCode(ctx context.Context, codeHash []byte) (LinkCode, error)
+	SetRecoveryVerifier(ctx context.Context, rv RecoveryVerifier) error
+	QueryRecoveryVerifier(ctx context.Context, userID uuid.UUID) (RecoveryVerifier, error)
+	CreateRecoveryEvent(ctx context.Context, re RecoveryEvent) error
+	QueryRecoveryEvents(ctx context.Context, userID uuid.UUID) ([]RecoveryEvent, error)
+	CreateIdentity(ctx context.Context, idn Identity) error
+	DeleteIdentity(ctx context.Context, idn Identity) error
+	QueryIdentity(ctx context.Context, provider string, subject string) (Identity, error)
+	QueryIdentityByID(ctx context.Context, identityID uuid.UUID) (Identity, error)
+	QueryIdentities(ctx context.Context, userID uuid.UUID) ([]Identity, error)
+	SetMFA(ctx context.Context, mfa MFA) error
+	DeleteMFA(ctx context.Context, userID uuid.UUID) error
+	QueryMFA(ctx context.Context, userID uuid.UUID) (MFA, error)
+	UseMFAStep(ctx context.Context, userID uuid.UUID, step int64) error
+	FailMFAAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockedUntil time.Time) error
+	ResetMFAAttempts(ctx context.Context, userID uuid.UUID) error
+	ReplaceMFARecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes [][]byte) error
+	ConsumeMFARecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error
+	CreateMFARole(ctx context.Context, r role.Role) error
+	DeleteMFARole(ctx context.Context, r role.Role) error
+	QueryMFARoles(ctx context.Context) ([]role.Role, error)
 }
 
-// Business manages the set of APIs for user access.
+// Business manages the set of APIs for user access. tx is set on the values
+// returned by NewWithTx and passed along with delegate calls.
 type Business struct {
 	log      *logger.Logger
 	storer   Storer
 	delegate *delegate.Delegate
+	tx       sqldb.CommitRollbacker
 }
 
 // NewBusiness constructs a user business API for use.
@@ -66,6 +139,7 @@ func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
 		log:      b.log,
 		delegate: b.delegate,
 		storer:   storer,
+		tx:       tx,
 	}
 
 	return &bus, nil
@@ -102,6 +176,125 @@ func (b *Business) Create(ctx context.Context, nu NewUser) (User, error) {
 	return usr, nil
 }
 
+// CreateRegistrationToken mints a one-time registration token for the
+// specified user. Any previously minted token for the user is replaced.
+func (b *Business) CreateRegistrationToken(ctx context.Context, usr User) (RegistrationToken, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.createregistrationtoken")
+	defer span.End()
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return RegistrationToken{}, fmt.Errorf("generate token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+
+	rt := RegistrationToken{
+		UserID:      usr.ID,
+		Token:       token,
+		TokenHash:   hashRegistrationToken(token),
+		DateExpires: now.Add(RegistrationTokenTTL),
+		DateCreated: now,
+	}
+
+	if err := b.storer.CreateRegistrationToken(ctx, rt); err != nil {
+		return RegistrationToken{}, fmt.Errorf("createregistrationtoken: %w", err)
+	}
+
+	return rt, nil
+}
+
+// CreateLinkCode mints a single-use code the specified user exchanges for
+// starting to link an identity. Any previously minted code for the user is
+// replaced.
+func (b *Business) CreateLinkCode(ctx context.Context, usr User) (LinkCode, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.createlinkcode")
+	defer span.End()
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return LinkCode{}, fmt.Errorf("generate code: %w", err)
+	}
+
+	code := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+
+	lc := LinkCode{
+		UserID:      usr.ID,
+		Code:        code,
+		CodeHash:    hashLinkCode(code),
+		DateExpires: now.Add(LinkCodeTTL),
+		DateCreated: now,
+	}
+
+	if err := b.storer.CreateLinkCode(ctx, lc); err != nil {
+		return LinkCode{}, fmt.Errorf("createlinkcode: %w", err)
+	}
+
+	return lc, nil
+}
+
+// ConsumeLinkCode exchanges the link code for the ID of the user that minted
+// it. The code is consumed so it can't be used again.
+func (b *Business) ConsumeLinkCode(ctx context.Context, code string) (uuid.UUID, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.consumelinkcode")
+	defer span.End()
+
+	lc, err := b.storer.ConsumeLinkCode(ctx, hashLinkCode(code))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("consumelinkcode: %w", err)
+	}
+
+	if time.Now().After(lc.DateExpires) {
+		return uuid.Nil, fmt.Errorf("consumelinkcode: userID[%s]: %w", lc.UserID, ErrInvalidLinkCode)
+	}
+
+	return lc.UserID, nil
+}
+
+// Register completes the registration of a user created by an admin. The
+// UUK must carry a public key and an encrypted private key. The registration
+// token is consumed so it can't be used again, the password is replaced and
+// the user's UUK is stored.
+func (b *Business) Register(ctx context.Context, ru RegisterUser) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.register")
+	defer span.End()
+
+	if err := ru.UUK.Validate(); err != nil {
+		return User{}, fmt.Errorf("register: %w: %w", ErrInvalidUUK, err)
+	}
+
+	rt, err := b.storer.ConsumeRegistrationToken(ctx, hashRegistrationToken(ru.Token))
+	if err != nil {
+		return User{}, fmt.Errorf("consumeregistrationtoken: %w", err)
+	}
+
+	if time.Now().After(rt.DateExpires) {
+		return User{}, fmt.Errorf("register: userID[%s]: %w", rt.UserID, ErrExpiredRegistrationToken)
+	}
+
+	usr, err := b.storer.QueryByID(ctx, rt.UserID)
+	if err != nil {
+		return User{}, fmt.Errorf("query: userID[%s]: %w", rt.UserID, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(ru.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("generatefrompassword: %w", err)
+	}
+
+	usr.PasswordHash = hash
+	usr.UUK = ru.UUK
+	usr.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
 // Update modifies information about a user.
 func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, error) {
 	ctx, span := otel.AddSpan(ctx, "business.userbus.update")
@@ -143,13 +336,592 @@ func (b *Business) Update(ctx context.Context, usr User, uu UpdateUser) (User, e
 
 	// Other domains may need to know when a user is updated so business
 	// logic can be applied. This represents a delegate call to other domains.
-	if err := b.delegate.Call(ctx, ActionUpdatedData(uu, usr.ID)); err != nil {
+	data := ActionUpdatedData(uu, usr.ID)
+	data.Tx = b.tx
+
+	if err := b.delegate.Call(ctx, data); err != nil {
 		return User{}, fmt.Errorf("failed to execute `%s` action: %w", ActionUpdated, err)
 	}
 
 	return usr, nil
 }
 
+// UpdatePassword changes the password of a user after verifying the old
+// password. The password hash and the re-wrapped UUK are stored together so
+// the user can never end up with a UUK locked by a different password.
+func (b *Business) UpdatePassword(ctx context.Context, usr User, up UpdateUserPassword) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.updatepassword")
+	defer span.End()
+
+	if err := bcrypt.CompareHashAndPassword(usr.PasswordHash, []byte(up.OldPassword)); err != nil {
+		return User{}, fmt.Errorf("comparehashandpassword: %w", ErrAuthenticationFailure)
+	}
+
+	if err := checkKeyPair(usr, up.UUK); err != nil {
+		return User{}, fmt.Errorf("updatepassword: userID[%s]: %w", usr.ID, err)
+	}
+
+	return b.replacePassword(ctx, usr, up.Password, up.UUK)
+}
+
+// EnableRecovery stores the users UUK with recovery keys added and, when the
+// user printed a recovery kit, the hash of the kit verifier.
+func (b *Business) EnableRecovery(ctx context.Context, usr User, er EnableRecovery) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.enablerecovery")
+	defer span.End()
+
+	if err := checkKeyPair(usr, er.UUK); err != nil {
+		return User{}, fmt.Errorf("enablerecovery: userID[%s]: %w", usr.ID, err)
+	}
+
+	if len(er.UUK.Recovery) == 0 {
+		return User{}, fmt.Errorf("enablerecovery: userID[%s]: %w", usr.ID, ErrRecoveryNotEnabled)
+	}
+
+	now := time.Now()
+
+	if er.Verifier != "" {
+		if !er.UUK.HasRecoveryKit() {
+			return User{}, fmt.Errorf("enablerecovery: userID[%s]: %w", usr.ID, ErrRecoveryNotEnabled)
+		}
+
+		rv := RecoveryVerifier{
+			UserID:       usr.ID,
+			VerifierHash: hashRecoveryVerifier(er.Verifier),
+			DateCreated:  now,
+			DateUpdated:  now,
+		}
+
+		if err := b.storer.SetRecoveryVerifier(ctx, rv); err != nil {
+			return User{}, fmt.Errorf("setrecoveryverifier: %w", err)
+		}
+	}
+
+	usr.UUK = er.UUK
+	usr.DateUpdated = now
+
+	if err := b.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := b.recordRecovery(ctx, usr.ID, usr.ID, RecoveryMethodEnable); err != nil {
+		return User{}, err
+	}
+
+	return usr, nil
+}
+
+// Recover lets a user that lost their master password or secret key set a
+// new password after proving possession of their recovery kit.
+func (b *Business) Recover(ctx context.Context, rr RecoverUser) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.recover")
+	defer span.End()
+
+	usr, err := b.storer.QueryByEmail(ctx, rr.Email)
+	if err != nil {
+		return User{}, fmt.Errorf("query: email[%s]: %w", rr.Email.Address, ErrAuthenticationFailure)
+	}
+
+	rv, err := b.storer.QueryRecoveryVerifier(ctx, usr.ID)
+	if err != nil {
+		return User{}, fmt.Errorf("queryrecoveryverifier: userID[%s]: %w", usr.ID, ErrAuthenticationFailure)
+	}
+
+	if subtle.ConstantTimeCompare(rv.VerifierHash, hashRecoveryVerifier(rr.Verifier)) != 1 {
+		return User{}, fmt.Errorf("recover: userID[%s]: %w", usr.ID, ErrAuthenticationFailure)
+	}
+
+	if err := checkKeyPair(usr, rr.UUK); err != nil {
+		return User{}, fmt.Errorf("recover: userID[%s]: %w", usr.ID, err)
+	}
+
+	usr, err = b.replacePassword(ctx, usr, rr.Password, rr.UUK)
+	if err != nil {
+		return User{}, err
+	}
+
+	if err := b.recordRecovery(ctx, usr.ID, usr.ID, RecoveryMethodKit); err != nil {
+		return User{}, err
+	}
+
+	return usr, nil
+}
+
+// RecoverByAdmin lets a recovery admin the user wrapped their symmetric key
+// to set a new password for the user.
+func (b *Business) RecoverByAdmin(ctx context.Context, admin User, usr User, ar AdminRecoverUser) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.recoverbyadmin")
+	defer span.End()
+
+	if !usr.UUK.HasRecoveryAdmin(admin.ID) {
+		return User{}, fmt.Errorf("recoverbyadmin: userID[%s] adminID[%s]: %w", usr.ID, admin.ID, ErrRecoveryNotEnabled)
+	}
+
+	if err := checkKeyPair(usr, ar.UUK); err != nil {
+		return User{}, fmt.Errorf("recoverbyadmin: userID[%s]: %w", usr.ID, err)
+	}
+
+	usr, err := b.replacePassword(ctx, usr, ar.Password, ar.UUK)
+	if err != nil {
+		return User{}, err
+	}
+
+	if err := b.recordRecovery(ctx, usr.ID, admin.ID, RecoveryMethodAdmin); err != nil {
+		return User{}, err
+	}
+
+	return usr, nil
+}
+
+// QueryRecoveryEvents retrieves the recovery audit trail for the user.
+func (b *Business) QueryRecoveryEvents(ctx context.Context, userID uuid.UUID) ([]RecoveryEvent, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.queryrecoveryevents")
+	defer span.End()
+
+	events, err := b.storer.QueryRecoveryEvents(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return events, nil
+}
+
+// LinkIdentity links the external identity to the user so the user can log
+// in through the provider. An identity can only be linked to one user and a
+// user can only link one identity per provider.
+func (b *Business) LinkIdentity(ctx context.Context, usr User, ei ExternalIdentity) (Identity, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.linkidentity")
+	defer span.End()
+
+	idn := Identity{
+		ID:          uuid.New(),
+		UserID:      usr.ID,
+		Provider:    ei.Provider,
+		Subject:     ei.Subject,
+		Email:       ei.Email,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.CreateIdentity(ctx, idn); err != nil {
+		return Identity{}, fmt.Errorf("createidentity: %w", err)
+	}
+
+	return idn, nil
+}
+
+// UnlinkIdentity removes the link between the external identity and its user.
+func (b *Business) UnlinkIdentity(ctx context.Context, idn Identity) error {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.unlinkidentity")
+	defer span.End()
+
+	if err := b.storer.DeleteIdentity(ctx, idn); err != nil {
+		return fmt.Errorf("deleteidentity: %w", err)
+	}
+
+	return nil
+}
+
+// QueryIdentityByID finds the linked identity by the specified ID.
+func (b *Business) QueryIdentityByID(ctx context.Context, identityID uuid.UUID) (Identity, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.queryidentitybyid")
+	defer span.End()
+
+	idn, err := b.storer.QueryIdentityByID(ctx, identityID)
+	if err != nil {
+		return Identity{}, fmt.Errorf("query: identityID[%s]: %w", identityID, err)
+	}
+
+	return idn, nil
+}
+
+// QueryIdentities retrieves the identities linked to the user.
+func (b *Business) QueryIdentities(ctx context.Context, userID uuid.UUID) ([]Identity, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.queryidentities")
+	defer span.End()
+
+	idns, err := b.storer.QueryIdentities(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return idns, nil
+}
+
+// AuthenticateIdentity finds the user the external identity belongs to. When
+// the identity is not linked yet it is matched to a user by its email, which
+// the provider must have verified and whose domain must be in allowedDomains.
+// The matched identity is linked so later logins use the provider subject.
+// An empty allowedDomains disables matching by email.
+func (b *Business) AuthenticateIdentity(ctx context.Context, ei ExternalIdentity, allowedDomains []string) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.authenticateidentity")
+	defer span.End()
+
+	idn, err := b.storer.QueryIdentity(ctx, ei.Provider, ei.Subject)
+	switch {
+	case err == nil:
+		usr, err := b.storer.QueryByID(ctx, idn.UserID)
+		if err != nil {
+			return User{}, fmt.Errorf("query: userID[%s]: %w", idn.UserID, err)
+		}
+
+		return usr, nil
+
+	case !errors.Is(err, ErrIdentityNotFound):
+		return User{}, fmt.Errorf("queryidentity: provider[%s]: %w", ei.Provider, err)
+	}
+
+	if !ei.EmailVerified {
+		return User{}, fmt.Errorf("authenticateidentity: email[%s]: %w", ei.Email.Address, ErrEmailNotVerified)
+	}
+
+	if !domainAllowed(ei.Email, allowedDomains) {
+		return User{}, fmt.Errorf("authenticateidentity: email[%s]: %w", ei.Email.Address, ErrDomainNotAllowed)
+	}
+
+	usr, err := b.storer.QueryByEmail(ctx, ei.Email)
+	if err != nil {
+		return User{}, fmt.Errorf("query: email[%s]: %w", ei.Email.Address, err)
+	}
+
+	if _, err := b.LinkIdentity(ctx, usr, ei); err != nil {
+		return User{}, err
+	}
+
+	return usr, nil
+}
+
+// EnrollMFA starts enabling two-factor authentication for the user with a
+// new TOTP secret. Codes are only required once the user confirmed the
+// enrollment with ConfirmMFA, enrolling again replaces an unconfirmed secret.
+func (b *Business) EnrollMFA(ctx context.Context, usr User) (MFAEnrollment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.enrollmfa")
+	defer span.End()
+
+	mfa, err := b.storer.QueryMFA(ctx, usr.ID)
+	switch {
+	case err == nil:
+		if mfa.Enabled {
+			return MFAEnrollment{}, fmt.Errorf("enrollmfa: userID[%s]: %w", usr.ID, ErrMFAEnabled)
+		}
+
+	case !errors.Is(err, ErrMFANotEnabled):
+		return MFAEnrollment{}, fmt.Errorf("querymfa: userID[%s]: %w", usr.ID, err)
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return MFAEnrollment{}, err
+	}
+
+	now := time.Now()
+
+	mfa = MFA{
+		UserID:      usr.ID,
+		Secret:      secret,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.SetMFA(ctx, mfa); err != nil {
+		return MFAEnrollment{}, fmt.Errorf("setmfa: %w", err)
+	}
+
+	me := MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(MFAIssuer, usr.Email.Address, secret),
+	}
+
+	return me, nil
+}
+
+// ConfirmMFA enables two-factor authentication for the user once the code
+// shows the authenticator app was set up with the enrolled secret. The
+// recovery codes are returned once, only their hashes are stored.
+func (b *Business) ConfirmMFA(ctx context.Context, usr User, code string) ([]string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.confirmmfa")
+	defer span.End()
+
+	mfa, err := b.storer.QueryMFA(ctx, usr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("querymfa: userID[%s]: %w", usr.ID, err)
+	}
+
+	if mfa.Enabled {
+		return nil, fmt.Errorf("confirmmfa: userID[%s]: %w", usr.ID, ErrMFAEnabled)
+	}
+
+	step, ok, err := totp.Validate(mfa.Secret, code, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("confirmmfa: userID[%s]: %w", usr.ID, ErrInvalidMFACode)
+	}
+
+	codes := make([]string, MFARecoveryCodes)
+	hashes := make([][]byte, MFARecoveryCodes)
+	for i := range codes {
+		rc, err := newMFARecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = rc
+		hashes[i] = hashMFARecoveryCode(rc)
+	}
+
+	if err := b.storer.ReplaceMFARecoveryCodes(ctx, usr.ID, hashes); err != nil {
+		return nil, fmt.Errorf("replacemfarecoverycodes: %w", err)
+	}
+
+	mfa.Enabled = true
+	mfa.LastStep = step
+	mfa.DateUpdated = time.Now()
+
+	if err := b.storer.SetMFA(ctx, mfa); err != nil {
+		return nil, fmt.Errorf("setmfa: %w", err)
+	}
+
+	return codes, nil
+}
+
+// DisableMFA turns off two-factor authentication for the user after checking
+// a current code or a recovery code. It can't be turned off while the roles
+// of the user require it.
+func (b *Business) DisableMFA(ctx context.Context, usr User, code string) error {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.disablemfa")
+	defer span.End()
+
+	required, err := b.MFARequired(ctx, usr)
+	if err != nil {
+		return err
+	}
+
+	if required {
+		return fmt.Errorf("disablemfa: userID[%s]: %w", usr.ID, ErrMFARequired)
+	}
+
+	mfa, err := b.storer.QueryMFA(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("querymfa: userID[%s]: %w", usr.ID, err)
+	}
+
+	if !mfa.Enabled {
+		return fmt.Errorf("disablemfa: userID[%s]: %w", usr.ID, ErrMFANotEnabled)
+	}
+
+	if err := b.verifyMFACode(ctx, mfa, code); err != nil {
+		return err
+	}
+
+	if err := b.storer.DeleteMFA(ctx, usr.ID); err != nil {
+		return fmt.Errorf("deletemfa: %w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateMFA performs the second step of a login. Users with two-factor
+// authentication enabled must provide a current code or a recovery code,
+// users whose roles require it but haven't enabled it can't log in until
+// they do.
+func (b *Business) AuthenticateMFA(ctx context.Context, usr User, code string) error {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.authenticatemfa")
+	defer span.End()
+
+	mfa, err := b.storer.QueryMFA(ctx, usr.ID)
+	if err != nil && !errors.Is(err, ErrMFANotEnabled) {
+		return fmt.Errorf("querymfa: userID[%s]: %w", usr.ID, err)
+	}
+
+	if !mfa.Enabled {
+		required, err := b.MFARequired(ctx, usr)
+		if err != nil {
+			return err
+		}
+
+		if required {
+			return fmt.Errorf("authenticatemfa: userID[%s]: %w", usr.ID, ErrMFARequired)
+		}
+
+		return nil
+	}
+
+	if code == "" {
+		return fmt.Errorf("authenticatemfa: userID[%s]: %w", usr.ID, ErrMFACodeRequired)
+	}
+
+	return b.verifyMFACode(ctx, mfa, code)
+}
+
+// QueryMFA gets the two-factor authentication settings of the user.
+func (b *Business) QueryMFA(ctx context.Context, userID uuid.UUID) (MFA, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.querymfa")
+	defer span.End()
+
+	mfa, err := b.storer.QueryMFA(ctx, userID)
+	if err != nil {
+		return MFA{}, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return mfa, nil
+}
+
+// MFARequired reports whether any of the roles of the user require
+// two-factor authentication.
+func (b *Business) MFARequired(ctx context.Context, usr User) (bool, error) {
+	roles, err := b.QueryMFARoles(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range usr.Roles {
+		if slices.Contains(roles, r) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SetMFARole sets whether users with the role must use two-factor
+// authentication.
+func (b *Business) SetMFARole(ctx context.Context, r role.Role, required bool) error {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.setmfarole")
+	defer span.End()
+
+	if !required {
+		if err := b.storer.DeleteMFARole(ctx, r); err != nil {
+			return fmt.Errorf("deletemfarole: role[%s]: %w", r, err)
+		}
+
+		return nil
+	}
+
+	if err := b.storer.CreateMFARole(ctx, r); err != nil {
+		return fmt.Errorf("createmfarole: role[%s]: %w", r, err)
+	}
+
+	return nil
+}
+
+// QueryMFARoles retrieves the roles that require two-factor authentication.
+func (b *Business) QueryMFARoles(ctx context.Context) ([]role.Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.querymfaroles")
+	defer span.End()
+
+	roles, err := b.storer.QueryMFARoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querymfaroles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// verifyMFACode checks the code is a current TOTP code that wasn't used
+// before or an unused recovery code, which is consumed. Invalid codes are
+// counted and lock the second factor, see MFAMaxAttempts.
+func (b *Business) verifyMFACode(ctx context.Context, mfa MFA, code string) error {
+	now := time.Now()
+
+	if now.Before(mfa.LockedUntil) {
+		return fmt.Errorf("verifymfacode: userID[%s]: %w", mfa.UserID, ErrMFALocked)
+	}
+
+	err := b.checkMFACode(ctx, mfa, code, now)
+	switch {
+	case errors.Is(err, ErrInvalidMFACode):
+		if err := b.storer.FailMFAAttempt(ctx, mfa.UserID, MFAMaxAttempts, now.Add(MFALockout)); err != nil {
+			return fmt.Errorf("failmfaattempt: userID[%s]: %w", mfa.UserID, err)
+		}
+
+		if mfa.FailedAttempts+1 >= MFAMaxAttempts {
+			b.log.Info(ctx, "mfa locked", "userID", mfa.UserID, "attempts", mfa.FailedAttempts+1)
+		}
+
+		return err
+
+	case err != nil:
+		return err
+	}
+
+	if mfa.FailedAttempts > 0 {
+		if err := b.storer.ResetMFAAttempts(ctx, mfa.UserID); err != nil {
+			return fmt.Errorf("resetmfaattempts: userID[%s]: %w", mfa.UserID, err)
+		}
+	}
+
+	return nil
+}
+
+// checkMFACode checks the code without counting invalid ones.
+func (b *Business) checkMFACode(ctx context.Context, mfa MFA, code string, now time.Time) error {
+	if len(code) != totp.Digits {
+		if err := b.storer.ConsumeMFARecoveryCode(ctx, mfa.UserID, hashMFARecoveryCode(code)); err != nil {
+			return fmt.Errorf("consumemfarecoverycode: userID[%s]: %w", mfa.UserID, err)
+		}
+
+		b.log.Info(ctx, "mfa recovery code used", "userID", mfa.UserID)
+
+		return nil
+	}
+
+	step, ok, err := totp.Validate(mfa.Secret, code, now)
+	if err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	if !ok || step <= mfa.LastStep {
+		return fmt.Errorf("verifymfacode: userID[%s]: %w", mfa.UserID, ErrInvalidMFACode)
+	}
+
+	// The step is recorded only if it is newer than the last one in a single
+	// statement so of two concurrent logins with the same code only one wins.
+	if err := b.storer.UseMFAStep(ctx, mfa.UserID, step); err != nil {
+		return fmt.Errorf("usemfastep: userID[%s]: %w", mfa.UserID, err)
+	}
+
+	return nil
+}
+
+// replacePassword stores the new password hash together with the UUK that
+// was re-wrapped under the new password.
+func (b *Business) replacePassword(ctx context.Context, usr User, password string, newUUK uuk.UUK) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("generatefrompassword: %w", err)
+	}
+
+	usr.PasswordHash = hash
+	usr.UUK = newUUK
+	usr.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
+func (b *Business) recordRecovery(ctx context.Context, userID uuid.UUID, actorID uuid.UUID, method string) error {
+	re := RecoveryEvent{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ActorID:     actorID,
+		Method:      method,
+		DateCreated: time.Now(),
+	}
+
+	if err := b.storer.CreateRecoveryEvent(ctx, re); err != nil {
+		return fmt.Errorf("createrecoveryevent: %w", err)
+	}
+
+	b.log.Info(ctx, "account recovery", "userID", userID, "actorID", actorID, "method", method)
+
+	return nil
+}
+
 // Delete removes the specified user.
 func (b *Business) Delete(ctx context.Context, usr User) error {
 	ctx, span := otel.AddSpan(ctx, "business.userbus.delete")
@@ -227,3 +999,82 @@ func (b *Business) Authenticate(ctx context.Context, email mail.Address, passwor
 
 	return usr, nil
 }
+
+// checkKeyPair verifies the user is registered and the new UUK still protects
+// the users existing key pair.
+func checkKeyPair(usr User, newUUK uuk.UUK) error {
+	if usr.UUK.UUID == uuid.Nil {
+		return ErrNotRegistered
+	}
+
+	if newUUK.UUID != usr.UUK.UUID || newUUK.EncPriKey != usr.UUK.EncPriKey {
+		return ErrUUKMismatch
+	}
+
+	if !usr.UUK.SamePublicKey(newUUK) {
+		return ErrUUKMismatch
+	}
+
+	return nil
+}
+
+// domainAllowed reports whether the domain of the email is one of the
+// allowed domains.
+func domainAllowed(email mail.Address, allowedDomains []string) bool {
+	at := strings.LastIndex(email.Address, "@")
+	if at == -1 {
+		return false
+	}
+
+	domain := email.Address[at+1:]
+	for _, allowed := range allowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hashRecoveryVerifier returns the hash of the recovery kit verifier that
+// is persisted.
+func hashRecoveryVerifier(verifier string) []byte {
+	h := sha256.Sum256([]byte(verifier))
+	return h[:]
+}
+
+// hashRegistrationToken returns the hash of the token that is persisted so
+// plaintext registration tokens are never stored.
+func hashRegistrationToken(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+	return h[:]
+}
+
+// hashLinkCode returns the hash of the code that is persisted so plaintext
+// link codes are never stored.
+func hashLinkCode(code string) []byte {
+	h := sha256.Sum256([]byte(code))
+	return h[:]
+}
+
+// newMFARecoveryCode generates a recovery code formatted as two groups of
+// five characters.
+func newMFARecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate recovery code: %w", err)
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))[:10]
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashMFARecoveryCode returns the hash of the recovery code that is
+// persisted. Codes are compared without their formatting.
+func hashMFARecoveryCode(code string) []byte {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	h := sha256.Sum256([]byte(code))
+	return h[:]
+}
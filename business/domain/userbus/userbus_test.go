@@ -2,6 +2,7 @@ package userbus_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/mail"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
 	"github.com/gradientsearch/pwmanager/business/sdk/page"
+	"github.com/gradientsearch/pwmanager/business/sdk/totp"
 	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
 	"github.com/gradientsearch/pwmanager/business/types/name"
 	"github.com/gradientsearch/pwmanager/business/types/role"
@@ -33,6 +35,8 @@ func Test_User(t *testing.T) {
 	unitest.Run(t, query(db.BusDomain, sd), "query")
 	unitest.Run(t, create(db.BusDomain), "create")
 	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, identity(db.BusDomain, sd), "identity")
+	unitest.Run(t, mfa(db.BusDomain, sd), "mfa")
 	unitest.Run(t, delete(db.BusDomain, sd), "delete")
 }
 
@@ -276,6 +280,379 @@ func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	return table
 }
 
+func identity(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0].User
+
+	linked := userbus.ExternalIdentity{
+		Provider:      "google",
+		Subject:       "google-subject-1",
+		Email:         usr.Email,
+		EmailVerified: true,
+	}
+
+	matched := userbus.ExternalIdentity{
+		Provider:      "github",
+		Subject:       "github-subject-1",
+		Email:         usr.Email,
+		EmailVerified: true,
+	}
+
+	unverified := matched
+	unverified.Subject = "github-subject-2"
+	unverified.EmailVerified = false
+
+	userID := func(got any, exp any) string {
+		gotResp, exists := got.(userbus.User)
+		if !exists {
+			return fmt.Sprintf("error occurred: %v", got)
+		}
+
+		return cmp.Diff(gotResp.ID, exp)
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "link",
+			ExpResp: usr.ID,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.User.LinkIdentity(ctx, usr, linked); err != nil {
+					return err
+				}
+
+				resp, err := busDomain.User.AuthenticateIdentity(ctx, linked, nil)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: userID,
+		},
+		{
+			Name:    "link-twice",
+			ExpResp: userbus.ErrIdentityLinked,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.LinkIdentity(ctx, sd.Users[1].User, linked)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "link-code",
+			ExpResp: usr.ID,
+			ExcFunc: func(ctx context.Context) any {
+				lc, err := busDomain.User.CreateLinkCode(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.User.ConsumeLinkCode(ctx, lc.Code)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "link-code-reused",
+			ExpResp: userbus.ErrInvalidLinkCode,
+			ExcFunc: func(ctx context.Context) any {
+				lc, err := busDomain.User.CreateLinkCode(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.ConsumeLinkCode(ctx, lc.Code); err != nil {
+					return err
+				}
+
+				_, err = busDomain.User.ConsumeLinkCode(ctx, lc.Code)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "domain-not-allowed",
+			ExpResp: userbus.ErrDomainNotAllowed,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.AuthenticateIdentity(ctx, matched, []string{"example.com"})
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "email-not-verified",
+			ExpResp: userbus.ErrEmailNotVerified,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.AuthenticateIdentity(ctx, unverified, []string{"gmail.com"})
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "match-email",
+			ExpResp: []string{"google", "github"},
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.User.AuthenticateIdentity(ctx, matched, []string{"GMAIL.com"}); err != nil {
+					return err
+				}
+
+				idns, err := busDomain.User.QueryIdentities(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				providers := make([]string, len(idns))
+				for i, idn := range idns {
+					providers[i] = idn.Provider
+				}
+
+				return providers
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "unlink",
+			ExpResp: userbus.ErrIdentityNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				idns, err := busDomain.User.QueryIdentities(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.User.UnlinkIdentity(ctx, idns[0]); err != nil {
+					return err
+				}
+
+				_, err = busDomain.User.QueryIdentityByID(ctx, idns[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func mfa(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0].User
+	rl := usr.Roles[0]
+
+	var secret string
+	var confirmCode string
+	var recoveryCodes []string
+
+	table := []unitest.Table{
+		{
+			Name:    "not-enabled",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.AuthenticateMFA(ctx, usr, ""); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "enroll",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				me, err := busDomain.User.EnrollMFA(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				secret = me.Secret
+
+				return me.URI == totp.URI(userbus.MFAIssuer, usr.Email.Address, me.Secret)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "confirm-invalid",
+			ExpResp: userbus.ErrInvalidMFACode,
+			ExcFunc: func(ctx context.Context) any {
+				code, err := totp.Code(secret, totp.Step(time.Now())+10)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.User.ConfirmMFA(ctx, usr, code)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "confirm",
+			ExpResp: userbus.MFARecoveryCodes,
+			ExcFunc: func(ctx context.Context) any {
+				code, err := totp.Code(secret, totp.Step(time.Now()))
+				if err != nil {
+					return err
+				}
+
+				confirmCode = code
+
+				codes, err := busDomain.User.ConfirmMFA(ctx, usr, code)
+				if err != nil {
+					return err
+				}
+
+				recoveryCodes = codes
+
+				return len(codes)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "enroll-enabled",
+			ExpResp: userbus.ErrMFAEnabled,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.EnrollMFA(ctx, usr)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "code-required",
+			ExpResp: userbus.ErrMFACodeRequired,
+			ExcFunc: func(ctx context.Context) any {
+				return busDomain.User.AuthenticateMFA(ctx, usr, "")
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "code-replayed",
+			ExpResp: userbus.ErrInvalidMFACode,
+			ExcFunc: func(ctx context.Context) any {
+				return busDomain.User.AuthenticateMFA(ctx, usr, confirmCode)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "recovery-code",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.AuthenticateMFA(ctx, usr, recoveryCodes[0]); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "recovery-code-reused",
+			ExpResp: userbus.ErrInvalidMFACode,
+			ExcFunc: func(ctx context.Context) any {
+				return busDomain.User.AuthenticateMFA(ctx, usr, recoveryCodes[0])
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "disable-required",
+			ExpResp: userbus.ErrMFARequired,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.SetMFARole(ctx, rl, true); err != nil {
+					return err
+				}
+
+				return busDomain.User.DisableMFA(ctx, usr, recoveryCodes[1])
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "disable",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.SetMFARole(ctx, rl, false); err != nil {
+					return err
+				}
+
+				if err := busDomain.User.DisableMFA(ctx, usr, recoveryCodes[1]); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "enrollment-required",
+			ExpResp: userbus.ErrMFARequired,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.SetMFARole(ctx, rl, true); err != nil {
+					return err
+				}
+				defer busDomain.User.SetMFARole(ctx, rl, false)
+
+				return busDomain.User.AuthenticateMFA(ctx, usr, "")
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "locked",
+			ExpResp: userbus.ErrMFALocked,
+			ExcFunc: func(ctx context.Context) any {
+				usr := sd.Users[1].User
+
+				me, err := busDomain.User.EnrollMFA(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				code, err := totp.Code(me.Secret, totp.Step(time.Now()))
+				if err != nil {
+					return err
+				}
+
+				codes, err := busDomain.User.ConfirmMFA(ctx, usr, code)
+				if err != nil {
+					return err
+				}
+
+				for range userbus.MFAMaxAttempts {
+					if err := busDomain.User.AuthenticateMFA(ctx, usr, "not-a-recovery-code"); !errors.Is(err, userbus.ErrInvalidMFACode) {
+						return fmt.Errorf("expected an invalid code: %w", err)
+					}
+				}
+
+				return busDomain.User.AuthenticateMFA(ctx, usr, codes[0])
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
+
 func delete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
 	table := []unitest.Table{
 		{
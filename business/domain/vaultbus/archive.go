@@ -0,0 +1,278 @@
+package vaultbus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+// Format identifies a document as a vault archive.
+const Format = "pwmanager-vault"
+
+// Version is the current version of the archive format.
+const Version = 1
+
+// document is the portable form of an archive. The payload is signed with
+// HMAC-SHA256 together with the format and version so none of them can be
+// changed without the signature failing:
+//
+//	{"format":"pwmanager-vault","version":1,"payload":"<base64url>","signature":"<base64url>"}
+type document struct {
+	Format    string `json:"format"`
+	Version   int    `json:"version"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+type payload struct {
+	UserID       string          `json:"userID"`
+	Bundles      []payloadBundle `json:"bundles"`
+	DateExported string          `json:"dateExported"`
+}
+
+type payloadBundle struct {
+	ID            string         `json:"id"`
+	Type          string         `json:"type"`
+	Metadata      string         `json:"metadata"`
+	KeyGeneration int            `json:"keyGeneration"`
+	Key           payloadKey     `json:"key"`
+	Entries       []payloadEntry `json:"entries"`
+}
+
+type payloadKey struct {
+	Data  string   `json:"data"`
+	Roles []string `json:"roles"`
+}
+
+type payloadEntry struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+	KeyGeneration int             `json:"keyGeneration"`
+	DateCreated   string          `json:"dateCreated"`
+	DateUpdated   string          `json:"dateUpdated"`
+}
+
+// Seal encodes the archive into its portable form and signs it.
+func (b *Business) Seal(a Archive) ([]byte, error) {
+	p := payload{
+		UserID:       a.UserID.String(),
+		Bundles:      make([]payloadBundle, len(a.Bundles)),
+		DateExported: a.DateExported.Format(time.RFC3339),
+	}
+
+	for i, bdl := range a.Bundles {
+		entries := make([]payloadEntry, len(bdl.Entries))
+		for j, e := range bdl.Entries {
+			entries[j] = payloadEntry{
+				ID:            e.ID.String(),
+				Type:          e.Type.String(),
+				SchemaVersion: e.SchemaVersion,
+				Data:          json.RawMessage(e.Data.String()),
+				KeyGeneration: e.KeyGeneration,
+				DateCreated:   e.DateCreated.Format(time.RFC3339),
+				DateUpdated:   e.DateUpdated.Format(time.RFC3339),
+			}
+		}
+
+		p.Bundles[i] = payloadBundle{
+			ID:            bdl.ID.String(),
+			Type:          bdl.Type.String(),
+			Metadata:      bdl.Metadata,
+			KeyGeneration: bdl.KeyGeneration,
+			Key: payloadKey{
+				Data:  bdl.Key.Data.String(),
+				Roles: bundlerole.ParseToString(bdl.Key.Roles),
+			},
+			Entries: entries,
+		}
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	doc := document{
+		Format:  Format,
+		Version: Version,
+		Payload: base64.RawURLEncoding.EncodeToString(data),
+	}
+	doc.Signature = base64.RawURLEncoding.EncodeToString(b.sign(doc))
+
+	return json.Marshal(doc)
+}
+
+// Open verifies the signature of an archive in its portable form and decodes
+// it.
+func (b *Business) Open(data []byte) (Archive, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Archive{}, fmt.Errorf("unmarshal: %w: %w", ErrInvalidArchive, err)
+	}
+
+	if doc.Format != Format {
+		return Archive{}, fmt.Errorf("format[%s]: %w", doc.Format, ErrInvalidArchive)
+	}
+
+	if doc.Version != Version {
+		return Archive{}, fmt.Errorf("version[%d]: %w", doc.Version, ErrUnsupportedVersion)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(doc.Signature)
+	if err != nil || !hmac.Equal(sig, b.sign(doc)) {
+		return Archive{}, ErrInvalidSignature
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(doc.Payload)
+	if err != nil {
+		return Archive{}, fmt.Errorf("decode: %w: %w", ErrInvalidArchive, err)
+	}
+
+	var p payload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return Archive{}, fmt.Errorf("unmarshal: %w: %w", ErrInvalidArchive, err)
+	}
+
+	a, err := toArchive(p)
+	if err != nil {
+		return Archive{}, fmt.Errorf("parse: %w: %w", ErrInvalidArchive, err)
+	}
+
+	a.Version = doc.Version
+
+	return a, nil
+}
+
+// sign computes the signature over the format, version and payload of the
+// document.
+func (b *Business) sign(doc document) []byte {
+	mac := hmac.New(sha256.New, b.signingKey)
+	fmt.Fprintf(mac, "%s.%d.%s", doc.Format, doc.Version, doc.Payload)
+
+	return mac.Sum(nil)
+}
+
+func toArchive(p payload) (Archive, error) {
+	userID, err := uuid.Parse(p.UserID)
+	if err != nil {
+		return Archive{}, fmt.Errorf("userID: %w", err)
+	}
+
+	dateExported, err := time.Parse(time.RFC3339, p.DateExported)
+	if err != nil {
+		return Archive{}, fmt.Errorf("dateExported: %w", err)
+	}
+
+	a := Archive{
+		UserID:       userID,
+		Bundles:      make([]Bundle, len(p.Bundles)),
+		DateExported: dateExported,
+	}
+
+	for i, pb := range p.Bundles {
+		bdl, err := toArchiveBundle(pb)
+		if err != nil {
+			return Archive{}, fmt.Errorf("bundle[%d]: %w", i, err)
+		}
+
+		a.Bundles[i] = bdl
+	}
+
+	return a, nil
+}
+
+func toArchiveBundle(pb payloadBundle) (Bundle, error) {
+	id, err := uuid.Parse(pb.ID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("id: %w", err)
+	}
+
+	typ, err := bundletype.Parse(pb.Type)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("type: %w", err)
+	}
+
+	k, err := key.Parse(pb.Key.Data)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("key: %w", err)
+	}
+
+	roles, err := bundlerole.ParseMany(pb.Key.Roles)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("roles: %w", err)
+	}
+
+	bdl := Bundle{
+		ID:            id,
+		Type:          typ,
+		Metadata:      pb.Metadata,
+		KeyGeneration: pb.KeyGeneration,
+		Key: Key{
+			Data:  k,
+			Roles: roles,
+		},
+		Entries: make([]Entry, len(pb.Entries)),
+	}
+
+	for i, pe := range pb.Entries {
+		e, err := toArchiveEntry(pe)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("entry[%d]: %w", i, err)
+		}
+
+		bdl.Entries[i] = e
+	}
+
+	return bdl, nil
+}
+
+func toArchiveEntry(pe payloadEntry) (Entry, error) {
+	id, err := uuid.Parse(pe.ID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("id: %w", err)
+	}
+
+	typ, err := itemtype.Parse(pe.Type)
+	if err != nil {
+		return Entry{}, fmt.Errorf("type: %w", err)
+	}
+
+	data, err := entry.Parse(string(pe.Data))
+	if err != nil {
+		return Entry{}, fmt.Errorf("data: %w", err)
+	}
+
+	dateCreated, err := time.Parse(time.RFC3339, pe.DateCreated)
+	if err != nil {
+		return Entry{}, fmt.Errorf("dateCreated: %w", err)
+	}
+
+	dateUpdated, err := time.Parse(time.RFC3339, pe.DateUpdated)
+	if err != nil {
+		return Entry{}, fmt.Errorf("dateUpdated: %w", err)
+	}
+
+	e := Entry{
+		ID:            id,
+		Type:          typ,
+		SchemaVersion: pe.SchemaVersion,
+		Data:          data,
+		KeyGeneration: pe.KeyGeneration,
+		DateCreated:   dateCreated,
+		DateUpdated:   dateUpdated,
+	}
+
+	return e, nil
+}
@@ -0,0 +1,74 @@
+package vaultbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
+	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+	"github.com/gradientsearch/pwmanager/business/types/key"
+)
+
+// Archive represents the bundles of a user taken out of the system. The
+// metadata, keys and entries are kept as the ciphertext the clients wrote, the
+// archive is as end-to-end encrypted as the vault itself.
+type Archive struct {
+	Version      int
+	UserID       uuid.UUID
+	Bundles      []Bundle
+	DateExported time.Time
+}
+
+// Bundle represents a bundle in an archive with the key of the user who
+// exported it and the entries encrypted under that key.
+type Bundle struct {
+	ID            uuid.UUID
+	Type          bundletype.BundleType
+	Metadata      string
+	KeyGeneration int
+	Key           Key
+	Entries       []Entry
+}
+
+// Key represents the wrapped bundle key of the user who exported the bundle.
+type Key struct {
+	Data  key.Key
+	Roles []bundlerole.Role
+}
+
+// Entry represents an entry in an archive.
+type Entry struct {
+	ID            uuid.UUID
+	Type          itemtype.ItemType
+	SchemaVersion int
+	Data          entry.Entry
+	KeyGeneration int
+	DateCreated   time.Time
+	DateUpdated   time.Time
+}
+
+// Mapping pairs the id of a bundle or entry in an archive with the id it was
+// given when it was imported.
+type Mapping struct {
+	ArchiveID uuid.UUID
+	ID        uuid.UUID
+}
+
+// BundleImport records the bundle an import recreated from a bundle of an
+// archive, so importing the archive again skips it.
+type BundleImport struct {
+	UserID          uuid.UUID
+	ArchiveBundleID uuid.UUID
+	BundleID        uuid.UUID
+	DateCreated     time.Time
+}
+
+// Result represents what an import added to the system. Bundles the user
+// already has access to are skipped along with their entries.
+type Result struct {
+	Bundles []Mapping
+	Entries []Mapping
+	Skipped []uuid.UUID
+}
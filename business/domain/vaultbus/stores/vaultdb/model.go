@@ -0,0 +1,46 @@
+package vaultdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+)
+
+type bundleImport struct {
+	UserID          uuid.UUID `db:"user_id"`
+	ArchiveBundleID uuid.UUID `db:"archive_bundle_id"`
+	BundleID        uuid.UUID `db:"bundle_id"`
+	DateCreated     time.Time `db:"date_created"`
+}
+
+func toDBBundleImport(bus vaultbus.BundleImport) bundleImport {
+	db := bundleImport{
+		UserID:          bus.UserID,
+		ArchiveBundleID: bus.ArchiveBundleID,
+		BundleID:        bus.BundleID,
+		DateCreated:     bus.DateCreated.UTC(),
+	}
+
+	return db
+}
+
+func toBusBundleImport(db bundleImport) vaultbus.BundleImport {
+	bus := vaultbus.BundleImport{
+		UserID:          db.UserID,
+		ArchiveBundleID: db.ArchiveBundleID,
+		BundleID:        db.BundleID,
+		DateCreated:     db.DateCreated.In(time.Local),
+	}
+
+	return bus
+}
+
+func toBusBundleImports(dbs []bundleImport) []vaultbus.BundleImport {
+	bus := make([]vaultbus.BundleImport, len(dbs))
+	for i, db := range dbs {
+		bus[i] = toBusBundleImport(db)
+	}
+
+	return bus
+}
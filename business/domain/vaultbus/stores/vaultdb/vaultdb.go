@@ -0,0 +1,84 @@
+// Package vaultdb contains vault import related database access.
+package vaultdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for vault database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (vaultbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// CreateImport records the bundle an import recreated from a bundle of an
+// archive.
+func (s *Store) CreateImport(ctx context.Context, bi vaultbus.BundleImport) error {
+	const q = `
+	INSERT INTO bundle_imports
+		(user_id, archive_bundle_id, bundle_id, date_created)
+	VALUES
+		(:user_id, :archive_bundle_id, :bundle_id, :date_created)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBBundleImport(bi)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryImports gets the bundles the user imported. Deleting an imported
+// bundle deletes its record, so it can be imported again.
+func (s *Store) QueryImports(ctx context.Context, userID uuid.UUID) ([]vaultbus.BundleImport, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, archive_bundle_id, bundle_id, date_created
+	FROM
+		bundle_imports
+	WHERE
+		user_id = :user_id`
+
+	var dbImports []bundleImport
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbImports); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusBundleImports(dbImports), nil
+}
@@ -0,0 +1,302 @@
+// Package vaultbus provides business access to exporting the bundles of a user
+// into a signed archive and importing them back.
+package vaultbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/foundation/logger"
+	"github.com/gradientsearch/pwmanager/foundation/otel"
+)
+
+// Set of error variables for vault operations.
+var (
+	ErrInvalidArchive     = errors.New("archive is malformed")
+	ErrInvalidSignature   = errors.New("archive signature is invalid")
+	ErrUnsupportedVersion = errors.New("archive version is not supported")
+	ErrDuplicateID        = errors.New("archive contains a duplicate id")
+	ErrKeyGeneration      = errors.New("archive entry key generation does not match its bundle")
+	ErrUserMismatch       = errors.New("archive was exported by another user")
+)
+
+// ownerRoles are the roles of the key an imported bundle is recreated with,
+// the user who imports a bundle owns the copy.
+var ownerRoles = []bundlerole.Role{bundlerole.Read, bundlerole.Write, bundlerole.Admin}
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	CreateImport(ctx context.Context, bi BundleImport) error
+	QueryImports(ctx context.Context, userID uuid.UUID) ([]BundleImport, error)
+}
+
+// Business manages the set of APIs for vault access.
+type Business struct {
+	log        *logger.Logger
+	bundleBus  *bundlebus.Business
+	keyBus     *keybus.Business
+	entryBus   *entrybus.Business
+	storer     Storer
+	signingKey []byte
+}
+
+// NewBusiness constructs a vault business API for use. Archives are signed
+// with the signing key and only archives signed with the same key can be
+// imported.
+func NewBusiness(log *logger.Logger, bundleBus *bundlebus.Business, keyBus *keybus.Business, entryBus *entrybus.Business, storer Storer, signingKey []byte) *Business {
+	return &Business{
+		log:        log,
+		bundleBus:  bundleBus,
+		keyBus:     keyBus,
+		entryBus:   entryBus,
+		storer:     storer,
+		signingKey: signingKey,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	bundleBus, err := b.bundleBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBus, err := b.keyBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	entryBus, err := b.entryBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:        b.log,
+		bundleBus:  bundleBus,
+		keyBus:     keyBus,
+		entryBus:   entryBus,
+		storer:     storer,
+		signingKey: b.signingKey,
+	}
+
+	return &bus, nil
+}
+
+// Export builds an archive of the bundles the user has a key to along with
+// their entries. Bundles and entries in the trash are left out.
+func (b *Business) Export(ctx context.Context, userID uuid.UUID) (Archive, error) {
+	ctx, span := otel.AddSpan(ctx, "business.vaultbus.export")
+	defer span.End()
+
+	keys, err := b.keyBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return Archive{}, fmt.Errorf("key.querybyuserid: %w", err)
+	}
+
+	a := Archive{
+		Version:      Version,
+		UserID:       userID,
+		Bundles:      make([]Bundle, 0, len(keys)),
+		DateExported: time.Now(),
+	}
+
+	for _, k := range keys {
+		bdl, err := b.bundleBus.QueryByID(ctx, k.BundleID)
+		if err != nil {
+			if errors.Is(err, bundlebus.ErrNotFound) {
+				continue
+			}
+			return Archive{}, fmt.Errorf("bundle.querybyid: %w", err)
+		}
+
+		entries, err := b.entryBus.QueryByBundleID(ctx, bdl.ID)
+		if err != nil {
+			return Archive{}, fmt.Errorf("entry.querybybundleid: %w", err)
+		}
+
+		ab := Bundle{
+			ID:            bdl.ID,
+			Type:          bdl.Type,
+			Metadata:      bdl.Metadata,
+			KeyGeneration: bdl.KeyGeneration,
+			Key: Key{
+				Data:  k.Data,
+				Roles: k.Roles,
+			},
+			Entries: make([]Entry, 0, len(entries)),
+		}
+
+		for _, e := range entries {
+			if !e.DateDeleted.IsZero() {
+				continue
+			}
+
+			ab.Entries = append(ab.Entries, Entry{
+				ID:            e.ID,
+				Type:          e.Type,
+				SchemaVersion: e.SchemaVersion,
+				Data:          e.Data,
+				KeyGeneration: e.KeyGeneration,
+				DateCreated:   e.DateCreated,
+				DateUpdated:   e.DateUpdated,
+			})
+		}
+
+		a.Bundles = append(a.Bundles, ab)
+	}
+
+	return a, nil
+}
+
+// Import recreates the bundles, keys and entries of the archive for the user.
+// Everything is given a new id so an archive can be imported next to the
+// vault it was exported from. The user owns every bundle the import recreates
+// and is given a key with the owner roles to it, whatever roles the key in the
+// archive had. A bundle is skipped as a duplicate when the user already has a
+// key to it or imported it before, the archive id of every recreated bundle is
+// recorded for that. The bundle keys are wrapped for the user who exported
+// the archive, so only that user can import it. Import should run inside a
+// transaction so a failure leaves nothing behind.
+func (b *Business) Import(ctx context.Context, userID uuid.UUID, a Archive) (Result, error) {
+	ctx, span := otel.AddSpan(ctx, "business.vaultbus.import")
+	defer span.End()
+
+	if a.UserID != userID {
+		return Result{}, fmt.Errorf("archive userID[%s]: %w", a.UserID, ErrUserMismatch)
+	}
+
+	if err := validate(a); err != nil {
+		return Result{}, err
+	}
+
+	keys, err := b.keyBus.QueryByUserID(ctx, userID)
+	if err != nil {
+		return Result{}, fmt.Errorf("key.querybyuserid: %w", err)
+	}
+
+	imports, err := b.storer.QueryImports(ctx, userID)
+	if err != nil {
+		return Result{}, fmt.Errorf("queryimports: %w", err)
+	}
+
+	existingIDs := make(map[uuid.UUID]struct{}, len(keys)+len(imports))
+	for _, k := range keys {
+		existingIDs[k.BundleID] = struct{}{}
+	}
+	for _, bi := range imports {
+		existingIDs[bi.ArchiveBundleID] = struct{}{}
+	}
+
+	res := Result{
+		Bundles: []Mapping{},
+		Entries: []Mapping{},
+		Skipped: []uuid.UUID{},
+	}
+
+	for _, ab := range a.Bundles {
+		if _, exists := existingIDs[ab.ID]; exists {
+			res.Skipped = append(res.Skipped, ab.ID)
+			continue
+		}
+
+		nb := bundlebus.NewBundle{
+			UserID:        userID,
+			Type:          ab.Type,
+			Metadata:      ab.Metadata,
+			KeyGeneration: ab.KeyGeneration,
+		}
+
+		bdl, err := b.bundleBus.Create(ctx, nb)
+		if err != nil {
+			return Result{}, fmt.Errorf("bundle.create: archiveID[%s]: %w", ab.ID, err)
+		}
+
+		nk := keybus.NewKey{
+			UserID:   userID,
+			BundleID: bdl.ID,
+			Data:     ab.Key.Data,
+			Roles:    ownerRoles,
+		}
+
+		if _, err := b.keyBus.Create(ctx, nk); err != nil {
+			return Result{}, fmt.Errorf("key.create: archiveID[%s]: %w", ab.ID, err)
+		}
+
+		bi := BundleImport{
+			UserID:          userID,
+			ArchiveBundleID: ab.ID,
+			BundleID:        bdl.ID,
+			DateCreated:     time.Now(),
+		}
+
+		if err := b.storer.CreateImport(ctx, bi); err != nil {
+			return Result{}, fmt.Errorf("createimport: archiveID[%s]: %w", ab.ID, err)
+		}
+
+		res.Bundles = append(res.Bundles, Mapping{ArchiveID: ab.ID, ID: bdl.ID})
+
+		for _, ae := range ab.Entries {
+			ne := entrybus.NewEntry{
+				UserID:        userID,
+				BundleID:      bdl.ID,
+				Type:          ae.Type,
+				SchemaVersion: ae.SchemaVersion,
+				Data:          ae.Data,
+				KeyGeneration: ae.KeyGeneration,
+			}
+
+			e, err := b.entryBus.Create(ctx, ne)
+			if err != nil {
+				return Result{}, fmt.Errorf("entry.create: archiveID[%s]: %w", ae.ID, err)
+			}
+
+			res.Entries = append(res.Entries, Mapping{ArchiveID: ae.ID, ID: e.ID})
+		}
+	}
+
+	return res, nil
+}
+
+// validate checks the archive is consistent before anything is written.
+func validate(a Archive) error {
+	ids := make(map[uuid.UUID]struct{})
+
+	for _, ab := range a.Bundles {
+		if _, exists := ids[ab.ID]; exists {
+			return fmt.Errorf("bundle[%s]: %w", ab.ID, ErrDuplicateID)
+		}
+		ids[ab.ID] = struct{}{}
+
+		for _, ae := range ab.Entries {
+			if _, exists := ids[ae.ID]; exists {
+				return fmt.Errorf("entry[%s]: %w", ae.ID, ErrDuplicateID)
+			}
+			ids[ae.ID] = struct{}{}
+
+			// Rotating a bundle re-encrypts every entry, the entries of an
+			// exported bundle are all under its current key generation.
+			if ae.KeyGeneration != ab.KeyGeneration {
+				return fmt.Errorf("entry[%s]: %w", ae.ID, ErrKeyGeneration)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,485 @@
+package vaultbus_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
+	"github.com/gradientsearch/pwmanager/business/domain/keybus"
+	"github.com/gradientsearch/pwmanager/business/domain/userbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/role"
+)
+
+func Test_Vault(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Vault")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, export(db.BusDomain, sd), "export")
+	unitest.Run(t, seal(db.BusDomain, sd), "seal")
+	unitest.Run(t, importArchive(db.BusDomain, sd), "import")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	bdls, err := bundlebus.TestGenerateSeedBundles(ctx, 2, busDomain.Bundle, usrs[0].ID)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
+	}
+
+	bids := []uuid.UUID{}
+	for _, v := range bdls {
+		bids = append(bids, v.ID)
+	}
+
+	roles := []bundlerole.Role{bundlerole.Admin, bundlerole.Read, bundlerole.Write}
+	keys, err := keybus.TestGenerateSeedKeys(ctx, 2, busDomain.Key, usrs[0].ID, bids, roles)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
+	}
+
+	entries, err := entrybus.TestGenerateSeedEntries(ctx, 2, busDomain.Entry, usrs[0].ID, bids)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding entries : %w", err)
+	}
+
+	tu1 := unitest.User{
+		User:    usrs[0],
+		Bundles: bdls,
+		Keys:    keys,
+		Entries: entries,
+	}
+
+	tu2 := unitest.User{
+		User: usrs[1],
+	}
+
+	// -------------------------------------------------------------------------
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{tu1, tu2},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+// sortIDs ignores the order of ids, bundles and entries are exported in the
+// order the store returns them.
+var sortIDs = cmpopts.SortSlices(func(a, b uuid.UUID) bool { return a.String() < b.String() })
+
+// summary holds the ids of an archive by bundle.
+type summary map[uuid.UUID][]uuid.UUID
+
+func summarize(a vaultbus.Archive) summary {
+	s := make(summary, len(a.Bundles))
+	for _, b := range a.Bundles {
+		s[b.ID] = make([]uuid.UUID, len(b.Entries))
+		for i, e := range b.Entries {
+			s[b.ID][i] = e.ID
+		}
+	}
+
+	return s
+}
+
+func export(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	owner := sd.Users[0]
+
+	exp := summary{}
+	for _, b := range owner.Bundles {
+		exp[b.ID] = []uuid.UUID{}
+		for _, e := range owner.Entries {
+			if e.BundleID == b.ID {
+				exp[b.ID] = append(exp[b.ID], e.ID)
+			}
+		}
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "owner",
+			ExpResp: exp,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				return summarize(resp)
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(summary)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				return cmp.Diff(gotResp, exp, sortIDs)
+			},
+		},
+		{
+			Name:    "no-access",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.Vault.Export(ctx, sd.Users[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return len(resp.Bundles)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func seal(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	owner := sd.Users[0]
+
+	// tamper seals an archive of the owner and changes the document with the
+	// specified function.
+	tamper := func(ctx context.Context, fn func(doc map[string]any)) ([]byte, error) {
+		a, err := busDomain.Vault.Export(ctx, owner.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := busDomain.Vault.Seal(a)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+
+		fn(doc)
+
+		return json.Marshal(doc)
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "roundtrip",
+			ExpResp: "",
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				data, err := busDomain.Vault.Seal(a)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Vault.Open(data)
+				if err != nil {
+					return err
+				}
+
+				// The archive is encoded with a precision of a second.
+				return cmp.Diff(resp, a, cmpopts.EquateApproxTime(time.Second))
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "signature",
+			ExpResp: vaultbus.ErrInvalidSignature,
+			ExcFunc: func(ctx context.Context) any {
+				data, err := tamper(ctx, func(doc map[string]any) {
+					doc["signature"] = "AAAA"
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Vault.Open(data)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "version",
+			ExpResp: vaultbus.ErrUnsupportedVersion,
+			ExcFunc: func(ctx context.Context) any {
+				data, err := tamper(ctx, func(doc map[string]any) {
+					doc["version"] = vaultbus.Version + 1
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Vault.Open(data)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "malformed",
+			ExpResp: vaultbus.ErrInvalidArchive,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Vault.Open(bytes.Repeat([]byte("{"), 2))
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func importArchive(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	owner := sd.Users[0]
+	member := sd.Users[1]
+
+	table := []unitest.Table{
+		{
+			Name: "duplicate",
+			ExpResp: vaultbus.Result{
+				Bundles: []vaultbus.Mapping{},
+				Entries: []vaultbus.Mapping{},
+				Skipped: []uuid.UUID{owner.Bundles[0].ID, owner.Bundles[1].ID},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				resp, err := busDomain.Vault.Import(ctx, owner.ID, a)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp, sortIDs)
+			},
+		},
+		{
+			Name:    "copy",
+			ExpResp: len(owner.Entries),
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				// A copy of the vault under other ids is not a duplicate of
+				// what the owner already has, even with the same metadata.
+				for i := range a.Bundles {
+					a.Bundles[i].ID = uuid.New()
+				}
+
+				res, err := busDomain.Vault.Import(ctx, owner.ID, a)
+				if err != nil {
+					return err
+				}
+
+				// Every bundle and entry is given a new id and the imported
+				// bundles hold the same entries under them.
+				ids := make(map[uuid.UUID]uuid.UUID)
+				for _, m := range append(res.Bundles, res.Entries...) {
+					if m.ArchiveID == m.ID {
+						return fmt.Errorf("id %s was not remapped", m.ID)
+					}
+					ids[m.ArchiveID] = m.ID
+				}
+
+				exp := summary{}
+				for bid, eids := range summarize(a) {
+					exp[ids[bid]] = make([]uuid.UUID, len(eids))
+					for i, eid := range eids {
+						exp[ids[bid]][i] = ids[eid]
+					}
+				}
+
+				exported, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				imported := summary{}
+				for bid, eids := range summarize(exported) {
+					if _, exists := exp[bid]; exists {
+						imported[bid] = eids
+					}
+				}
+
+				if diff := cmp.Diff(imported, exp, sortIDs); diff != "" {
+					return errors.New(diff)
+				}
+
+				return len(res.Entries)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reimport",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				for i := range a.Bundles {
+					a.Bundles[i].ID = uuid.New()
+				}
+
+				if _, err := busDomain.Vault.Import(ctx, owner.ID, a); err != nil {
+					return err
+				}
+
+				// The recreated bundles have other ids, the archive ids
+				// recorded on the first import tell they were imported.
+				res, err := busDomain.Vault.Import(ctx, owner.ID, a)
+				if err != nil {
+					return err
+				}
+
+				exp := make([]uuid.UUID, len(a.Bundles))
+				for i, ab := range a.Bundles {
+					exp[i] = ab.ID
+				}
+
+				if diff := cmp.Diff(res.Skipped, exp, sortIDs); diff != "" {
+					return errors.New(diff)
+				}
+
+				return len(res.Bundles)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "owner-roles",
+			ExpResp: []bundlerole.Role{bundlerole.Read, bundlerole.Write, bundlerole.Admin},
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				// The importer owns the recreated bundle, a key that only
+				// let the user read the bundle is not carried over.
+				a.Bundles = a.Bundles[:1]
+				a.Bundles[0].ID = uuid.New()
+				a.Bundles[0].Key.Roles = []bundlerole.Role{bundlerole.Read}
+
+				res, err := busDomain.Vault.Import(ctx, owner.ID, a)
+				if err != nil {
+					return err
+				}
+
+				k, err := busDomain.Key.QueryByUserIDBundleID(ctx, owner.ID, res.Bundles[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return k.Roles
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp, cmpopts.SortSlices(func(a, b bundlerole.Role) bool { return a.String() < b.String() }))
+			},
+		},
+		{
+			Name:    "other-user",
+			ExpResp: vaultbus.ErrUserMismatch,
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Vault.Import(ctx, member.ID, a)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "key-generation",
+			ExpResp: vaultbus.ErrKeyGeneration,
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				a.Bundles[0].KeyGeneration++
+
+				_, err = busDomain.Vault.Import(ctx, owner.ID, a)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "duplicate-id",
+			ExpResp: vaultbus.ErrDuplicateID,
+			ExcFunc: func(ctx context.Context) any {
+				a, err := busDomain.Vault.Export(ctx, owner.ID)
+				if err != nil {
+					return err
+				}
+
+				a.Bundles[1].ID = a.Bundles[0].ID
+
+				_, err = busDomain.Vault.Import(ctx, owner.ID, a)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, exists := got.(error)
+	if !exists || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("expected error %v, got %v", exp, got)
+	}
+
+	return ""
+}
@@ -2,15 +2,20 @@ package vbundlebus
 
 import (
 	"github.com/google/uuid"
-	"github.com/gradientsearch/pwmanager/business/types/name"
+	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
+	"github.com/gradientsearch/pwmanager/business/types/bundletype"
 )
 
 // QueryFilter holds the available fields a query can be filtered on.
 // We are using pointer semantics because the With API mutates the value.
+// Shared selects the bundles owned by someone else when true and the bundles
+// owned by the user when false. Role selects the bundles the user's key grants
+// the role on.
 type QueryFilter struct {
-	ID       *uuid.UUID
-	Name     *name.Name
-	Cost     *float64
-	Quantity *int
-	UserName *name.Name
+	UserID   *uuid.UUID
+	BundleID *uuid.UUID
+	OwnerID  *uuid.UUID
+	Type     *bundletype.BundleType
+	Shared   *bool
+	Role     *bundlerole.Role
 }
@@ -7,7 +7,7 @@ import (
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 )
 
-// Nested user info inside the "users" JSON array
+// BundleUser is a member of a bundle and the roles their key grants.
 type BundleUser struct {
 	UserID uuid.UUID
 	Name   string
@@ -15,16 +15,23 @@ type BundleUser struct {
 	Roles  []bundlerole.Role
 }
 
-// Main structure for the query result
+// UserBundleKey is a bundle as seen by a user holding a key to it, whether
+// the user owns the bundle or it was shared with them. KeyID, UserID, KeyData
+// and KeyRoles describe the key of that user. EntryCount counts the entries
+// of the bundle that are not in the trash.
 type UserBundleKey struct {
+	KeyID       uuid.UUID
 	UserID      uuid.UUID
-	Name        string
 	BundleID    uuid.UUID
+	OwnerID     uuid.UUID
+	OwnerName   string
+	OwnerEmail  string
 	Type        string
 	Metadata    string
 	DateCreated time.Time
 	DateUpdated time.Time
 	KeyData     string
 	KeyRoles    []bundlerole.Role
+	EntryCount  int
 	Users       []BundleUser
 }
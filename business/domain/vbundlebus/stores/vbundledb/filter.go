@@ -0,0 +1,51 @@
+package vbundledb
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+)
+
+func (s *Store) applyFilter(filter vbundlebus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.BundleID != nil {
+		data["bundle_id"] = *filter.BundleID
+		wc = append(wc, "bundle_id = :bundle_id")
+	}
+
+	if filter.OwnerID != nil {
+		data["owner_id"] = *filter.OwnerID
+		wc = append(wc, "owner_id = :owner_id")
+	}
+
+	if filter.Type != nil {
+		data["type"] = filter.Type.String()
+		wc = append(wc, "type = :type")
+	}
+
+	if filter.Shared != nil {
+		switch *filter.Shared {
+		case true:
+			wc = append(wc, "owner_id <> user_id")
+		default:
+			wc = append(wc, "owner_id = user_id")
+		}
+	}
+
+	if filter.Role != nil {
+		data["role"] = filter.Role.String()
+		wc = append(wc, ":role = ANY(key_roles)")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -47,13 +47,17 @@ func toBusBundle(db userBundleKey) (vbundlebus.UserBundleKey, error) {
 	}
 
 	bus := vbundlebus.UserBundleKey{
+		KeyID:       db.KeyID,
 		UserID:      db.UserID,
-		Name:        db.Name,
 		BundleID:    db.BundleID,
+		OwnerID:     db.OwnerID,
+		OwnerName:   db.OwnerName,
+		OwnerEmail:  db.OwnerEmail,
 		Type:        db.Type,
 		Metadata:    db.Metadata,
 		KeyData:     db.KeyData,
 		KeyRoles:    roles,
+		EntryCount:  db.EntryCount,
 		Users:       users,
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
@@ -86,14 +90,18 @@ type bundleUser struct {
 
 // Main structure for the query result
 type userBundleKey struct {
+	KeyID       uuid.UUID      `db:"key_id"`
 	UserID      uuid.UUID      `db:"user_id"`
-	Name        string         `db:"name"`
 	BundleID    uuid.UUID      `db:"bundle_id"`
+	OwnerID     uuid.UUID      `db:"owner_id"`
+	OwnerName   string         `db:"owner_name"`
+	OwnerEmail  string         `db:"owner_email"`
 	Type        string         `db:"type"`
 	Metadata    string         `db:"metadata"`
 	DateCreated time.Time      `db:"date_created"`
 	DateUpdated time.Time      `db:"date_updated"`
 	KeyData     string         `db:"key_data"`
 	KeyRoles    dbarray.String `db:"key_roles"`
+	EntryCount  int            `db:"entry_count"`
 	Users       string         `db:"users"`
 }
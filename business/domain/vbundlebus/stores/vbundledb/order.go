@@ -0,0 +1,26 @@
+package vbundledb
+
+import (
+	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	vbundlebus.OrderByBundleID:    "bundle_id",
+	vbundlebus.OrderByType:        "type",
+	vbundlebus.OrderByOwnerName:   "owner_name",
+	vbundlebus.OrderByEntryCount:  "entry_count",
+	vbundlebus.OrderByDateCreated: "date_created",
+	vbundlebus.OrderByDateUpdated: "date_updated",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
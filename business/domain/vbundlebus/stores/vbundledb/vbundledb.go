@@ -2,11 +2,13 @@
 package vbundledb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
-	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
 	"github.com/gradientsearch/pwmanager/foundation/logger"
 	"github.com/jmoiron/sqlx"
@@ -26,46 +28,63 @@ func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
 	}
 }
 
-// Query retrieves a list of existing keys from the database.
-func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) ([]vbundlebus.UserBundleKey, error) {
+// Query retrieves a list of the bundles users hold a key to from the database.
+func (s *Store) Query(ctx context.Context, filter vbundlebus.QueryFilter, orderBy order.By, page page.Page) ([]vbundlebus.UserBundleKey, error) {
 	data := map[string]any{
-		"user_id": userID,
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
 	}
 
 	const q = `
-SELECT
-	u.user_id,
-	u.name,
-    b.bundle_id,
-    b.type,
-    b.metadata,
-    b.date_created,
-    b.date_updated,
-    k.data AS key_data,
-    k.roles AS key_roles,
-    (
-        SELECT json_agg(json_build_object('user_id', ku.user_id, 'name', ku.name, 'email', ku.email, 'roles', k2.roles))
-        FROM keys k2
-        JOIN users ku ON k2.user_id = ku.user_id
-        WHERE k2.bundle_id = b.bundle_id
-    ) AS users
-FROM
-    users u
-JOIN
-    bundles b ON b.user_id = u.user_id
-LEFT JOIN
-    keys k ON k.bundle_id = b.bundle_id AND k.user_id = u.user_id
-WHERE b.user_id = :user_id`
-
-	var dnKey []userBundleKey
-	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, q, data, &dnKey); err != nil {
+	SELECT
+		key_id, user_id, bundle_id, owner_id, owner_name, owner_email, type, metadata,
+		date_created, date_updated, key_data, key_roles, entry_count, users
+	FROM
+		view_user_bundles`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbBdls []userBundleKey
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbBdls); err != nil {
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	k, err := toBusBundles(dnKey)
+	bdls, err := toBusBundles(dbBdls)
 	if err != nil {
 		return nil, err
 	}
 
-	return k, nil
+	return bdls, nil
+}
+
+// Count returns the total number of bundles users hold a key to in the DB.
+func (s *Store) Count(ctx context.Context, filter vbundlebus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		view_user_bundles`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
 }
@@ -5,14 +5,16 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/sdk/order"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/foundation/otel"
 )
 
 // Storer interface declares the behavior this package needs to persist and
 // retrieve data.
 type Storer interface {
-	QueryByID(ctx context.Context, userID uuid.UUID) ([]UserBundleKey, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]UserBundleKey, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
 }
 
 // Business manages the set of APIs for view key access.
@@ -27,15 +29,23 @@ func NewBusiness(storer Storer) *Business {
 	}
 }
 
-// Query retrieves a list of existing keys.
-func (b *Business) QueryByID(ctx context.Context, userID uuid.UUID) ([]UserBundleKey, error) {
+// Query retrieves a list of the bundles users hold a key to.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]UserBundleKey, error) {
 	ctx, span := otel.AddSpan(ctx, "business.vbundlebus.query")
 	defer span.End()
 
-	users, err := b.storer.QueryByID(ctx, userID)
+	bdls, err := b.storer.Query(ctx, filter, orderBy, page)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 
-	return users, nil
+	return bdls, nil
+}
+
+// Count returns the total number of bundles users hold a key to.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.vbundlebus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
 }
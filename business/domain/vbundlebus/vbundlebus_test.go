@@ -8,10 +8,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
+	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
 	"github.com/gradientsearch/pwmanager/business/sdk/dbtest"
+	"github.com/gradientsearch/pwmanager/business/sdk/page"
 	"github.com/gradientsearch/pwmanager/business/sdk/unitest"
 	"github.com/gradientsearch/pwmanager/business/types/bundlerole"
 	"github.com/gradientsearch/pwmanager/business/types/role"
@@ -20,7 +22,7 @@ import (
 func Test_VBundle(t *testing.T) {
 	t.Parallel()
 
-	db := dbtest.New(t, "Test_Key")
+	db := dbtest.New(t, "Test_VBundle")
 
 	sd, err := insertSeedData(db.BusDomain)
 	if err != nil {
@@ -42,7 +44,7 @@ func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
 	}
 
-	bdls, err := bundlebus.TestGenerateSeedBundles(ctx, 2, busDomain.Bundle, usrs[0].ID)
+	bdls, err := bundlebus.TestGenerateSeedShareableBundles(ctx, 2, busDomain.Bundle, usrs[0].ID)
 	if err != nil {
 		return unitest.SeedData{}, fmt.Errorf("seeding bundles : %w", err)
 	}
@@ -58,10 +60,16 @@ func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 		return unitest.SeedData{}, fmt.Errorf("seeding keys : %w", err)
 	}
 
+	entries, err := entrybus.TestGenerateSeedEntries(ctx, 2, busDomain.Entry, usrs[0].ID, bids)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding entries : %w", err)
+	}
+
 	tu1 := unitest.User{
 		User:    usrs[0],
 		Bundles: bdls,
 		Keys:    keys,
+		Entries: entries,
 	}
 
 	// -------------------------------------------------------------------------
@@ -88,84 +96,178 @@ func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
 
 // =============================================================================
 
+func toBundleUser(usr unitest.User, k keybus.Key) vbundlebus.BundleUser {
+	return vbundlebus.BundleUser{
+		UserID: usr.ID,
+		Name:   usr.Name.String(),
+		Email:  usr.Email.Address,
+		Roles:  k.Roles,
+	}
+}
+
+// toUserBundleKey builds the expected view of the bundle for the holder of
+// the key. The dates are taken from the response since they are read back
+// from the database.
+func toUserBundleKey(owner unitest.User, bdl bundlebus.Bundle, k keybus.Key, entryCount int, users []vbundlebus.BundleUser, got vbundlebus.UserBundleKey) vbundlebus.UserBundleKey {
+	return vbundlebus.UserBundleKey{
+		KeyID:       k.ID,
+		UserID:      k.UserID,
+		BundleID:    bdl.ID,
+		OwnerID:     owner.ID,
+		OwnerName:   owner.Name.String(),
+		OwnerEmail:  owner.Email.Address,
+		Type:        bdl.Type.String(),
+		Metadata:    bdl.Metadata,
+		DateCreated: got.DateCreated,
+		DateUpdated: got.DateUpdated,
+		KeyData:     k.Data.String(),
+		KeyRoles:    k.Roles,
+		EntryCount:  entryCount,
+		Users:       users,
+	}
+}
+
 func query(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	owner := sd.Users[0]
+	member := sd.Users[1]
+
+	pg := page.MustParse("1", "10")
+
+	search := func(ctx context.Context, filter vbundlebus.QueryFilter) any {
+		resp, err := busDomain.VBundle.Query(ctx, filter, vbundlebus.DefaultOrderBy, pg)
+		if err != nil {
+			return err
+		}
+
+		return resp
+	}
+
+	bundleIDs := func(got any, exp any) string {
+		gotResp, exists := got.([]vbundlebus.UserBundleKey)
+		if !exists {
+			return "error occurred"
+		}
+
+		ids := make([]uuid.UUID, len(gotResp))
+		for i, b := range gotResp {
+			ids[i] = b.BundleID
+		}
+
+		return cmp.Diff(ids, exp)
+	}
+
+	shared := true
+	owned := false
+	admin := bundlerole.Admin
 
 	table := []unitest.Table{
 		{
-			Name:    "all",
-			ExpResp: make([]vbundlebus.UserBundleKey, 2),
+			Name:    "owner",
+			ExpResp: nil,
 			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &owner.ID})
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.([]vbundlebus.UserBundleKey)
+				if !exists || len(gotResp) != 2 {
+					return "should have returned 2 bundles"
+				}
 
-				resp, err := busDomain.VBundle.QueryByID(ctx, sd.Users[0].User.ID)
-				if err != nil {
-					return err
+				expResp := []vbundlebus.UserBundleKey{
+					toUserBundleKey(owner, owner.Bundles[0], owner.Keys[0], 2, []vbundlebus.BundleUser{
+						toBundleUser(owner, owner.Keys[0]),
+						toBundleUser(member, member.Keys[0]),
+					}, gotResp[0]),
+					toUserBundleKey(owner, owner.Bundles[1], owner.Keys[1], 2, []vbundlebus.BundleUser{
+						toBundleUser(owner, owner.Keys[1]),
+					}, gotResp[1]),
 				}
 
-				return resp
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "member",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &member.ID})
 			},
 			CmpFunc: func(got any, exp any) string {
 				gotResp, exists := got.([]vbundlebus.UserBundleKey)
-				if !exists {
-					return "error occurred"
+				if !exists || len(gotResp) != 1 {
+					return "should have returned the shared bundle"
 				}
 
-				tu1 := sd.Users[0]
-				tu2 := sd.Users[1]
-				// =============================================================================
-
-				b1 := gotResp[0]
-				b2 := gotResp[1]
-
 				expResp := []vbundlebus.UserBundleKey{
-					{
-						UserID:      tu1.User.ID,
-						BundleID:    tu1.Bundles[0].ID,
-						Name:        tu1.User.Name.String(),
-						Type:        b1.Type,
-						Metadata:    tu1.Bundles[0].Metadata,
-						DateCreated: b1.DateCreated,
-						DateUpdated: b1.DateUpdated,
-						KeyData:     tu1.Keys[0].Data.String(),
-						KeyRoles:    tu1.Keys[0].Roles,
-						Users: []vbundlebus.BundleUser{
-							{
-								UserID: tu1.User.ID,
-								Name:   tu1.User.Name.String(),
-								Email:  tu1.User.Email.Address,
-								Roles:  tu1.Keys[0].Roles,
-							},
-							{
-								UserID: tu2.User.ID,
-								Name:   tu2.User.Name.String(),
-								Email:  tu2.User.Email.Address,
-								Roles:  tu2.Keys[0].Roles,
-							},
-						},
-					},
-					{
-						UserID:      tu1.User.ID,
-						BundleID:    tu1.Bundles[1].ID,
-						Name:        tu1.User.Name.String(),
-						Type:        b2.Type,
-						Metadata:    tu1.Bundles[1].Metadata,
-						DateCreated: b2.DateCreated,
-						DateUpdated: b2.DateUpdated,
-						KeyData:     tu1.Keys[1].Data.String(),
-						KeyRoles:    tu1.Keys[1].Roles,
-						Users: []vbundlebus.BundleUser{
-							{
-								UserID: tu1.User.ID,
-								Name:   tu1.User.Name.String(),
-								Email:  tu1.User.Email.Address,
-								Roles:  tu1.Keys[1].Roles,
-							},
-						},
-					},
+					toUserBundleKey(owner, owner.Bundles[0], member.Keys[0], 2, []vbundlebus.BundleUser{
+						toBundleUser(owner, owner.Keys[0]),
+						toBundleUser(member, member.Keys[0]),
+					}, gotResp[0]),
 				}
 
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "shared",
+			ExpResp: []uuid.UUID{owner.Bundles[0].ID},
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &member.ID, Shared: &shared})
+			},
+			CmpFunc: bundleIDs,
+		},
+		{
+			Name:    "owned",
+			ExpResp: []uuid.UUID{},
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &member.ID, Shared: &owned})
+			},
+			CmpFunc: bundleIDs,
+		},
+		{
+			Name:    "role",
+			ExpResp: []uuid.UUID{owner.Bundles[0].ID, owner.Bundles[1].ID},
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &owner.ID, Role: &admin})
+			},
+			CmpFunc: bundleIDs,
+		},
+		{
+			Name:    "role-member",
+			ExpResp: []uuid.UUID{},
+			ExcFunc: func(ctx context.Context) any {
+				return search(ctx, vbundlebus.QueryFilter{UserID: &member.ID, Role: &admin})
+			},
+			CmpFunc: bundleIDs,
+		},
+		{
+			Name:    "page",
+			ExpResp: []uuid.UUID{owner.Bundles[1].ID},
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := busDomain.VBundle.Query(ctx, vbundlebus.QueryFilter{UserID: &owner.ID}, vbundlebus.DefaultOrderBy, page.MustParse("2", "1"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: bundleIDs,
+		},
+		{
+			Name:    "count",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				count, err := busDomain.VBundle.Count(ctx, vbundlebus.QueryFilter{UserID: &member.ID})
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
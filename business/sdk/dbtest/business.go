@@ -3,15 +3,25 @@ package dbtest
 import (
 	"time"
 
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus"
+	"github.com/gradientsearch/pwmanager/business/domain/auditbus/stores/auditdb"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/bundlebus/stores/bundledb"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus"
 	"github.com/gradientsearch/pwmanager/business/domain/entrybus/stores/entrydb"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus"
 	"github.com/gradientsearch/pwmanager/business/domain/keybus/stores/keydb"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus"
+	"github.com/gradientsearch/pwmanager/business/domain/memberbus/stores/memberdb"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus"
+	"github.com/gradientsearch/pwmanager/business/domain/sessionbus/stores/sessiondb"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus"
+	"github.com/gradientsearch/pwmanager/business/domain/syncbus/stores/syncdb"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/usercache"
 	"github.com/gradientsearch/pwmanager/business/domain/userbus/stores/userdb"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus"
+	"github.com/gradientsearch/pwmanager/business/domain/vaultbus/stores/vaultdb"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus"
 	"github.com/gradientsearch/pwmanager/business/domain/vbundlebus/stores/vbundledb"
 	"github.com/gradientsearch/pwmanager/business/sdk/delegate"
@@ -25,8 +35,13 @@ type BusDomain struct {
 	Bundle   *bundlebus.Business
 	Key      *keybus.Business
 	Entry    *entrybus.Business
+	Member   *memberbus.Business
 	User     *userbus.Business
 	VBundle  *vbundlebus.Business
+	Sync     *syncbus.Business
+	Vault    *vaultbus.Business
+	Audit    *auditbus.Business
+	Session  *sessionbus.Business
 }
 
 func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
@@ -34,15 +49,25 @@ func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
 	userBus := userbus.NewBusiness(log, delegate, usercache.NewStore(log, userdb.NewStore(log, db), time.Hour))
 	keyBus := keybus.NewBusiness(log, userBus, delegate, keydb.NewStore(log, db))
 	entryBus := entrybus.NewBusiness(log, userBus, delegate, entrydb.NewStore(log, db))
-	bundleBus := bundlebus.NewBusiness(log, userBus, delegate, bundledb.NewStore(log, db))
+	bundleBus := bundlebus.NewBusiness(log, userBus, keyBus, entryBus, delegate, bundledb.NewStore(log, db))
 	vbundleBus := vbundlebus.NewBusiness(vbundledb.NewStore(log, db))
+	memberBus := memberbus.NewBusiness(log, userBus, bundleBus, keyBus, memberdb.NewStore(log, db))
+	syncBus := syncbus.NewBusiness(syncdb.NewStore(log, db))
+	vaultBus := vaultbus.NewBusiness(log, bundleBus, keyBus, entryBus, vaultdb.NewStore(log, db), []byte("test-vault-signing-key"))
+	auditBus := auditbus.NewBusiness(log, delegate, auditdb.NewStore(log, db))
+	sessionBus := sessionbus.NewBusiness(log, delegate, sessiondb.NewStore(log, db))
 
 	return BusDomain{
 		Delegate: delegate,
 		Bundle:   bundleBus,
 		Key:      keyBus,
 		Entry:    entryBus,
+		Member:   memberBus,
 		User:     userBus,
 		VBundle:  vbundleBus,
+		Sync:     syncBus,
+		Vault:    vaultBus,
+		Audit:    auditBus,
+		Session:  sessionBus,
 	}
 }
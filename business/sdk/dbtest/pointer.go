@@ -2,6 +2,7 @@ package dbtest
 
 import (
 	"github.com/gradientsearch/pwmanager/business/types/entry"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
 	"github.com/gradientsearch/pwmanager/business/types/key"
 	"github.com/gradientsearch/pwmanager/business/types/money"
 	"github.com/gradientsearch/pwmanager/business/types/name"
@@ -84,6 +85,14 @@ func EntryNullPointer(value string) *entry.Null {
 	return &entry
 }
 
+// ItemTypePointer is a helper to get a *ItemType from a string. It's in the
+// tests package because we normally don't want to deal with pointers to basic
+// types but it's useful in some tests.
+func ItemTypePointer(value string) *itemtype.ItemType {
+	typ := itemtype.MustParse(value)
+	return &typ
+}
+
 // MoneyPointer is a helper to get a *Money from a float. It's in the tests
 // package because we normally don't want to deal with pointers to basic types
 // but it's useful in some tests.
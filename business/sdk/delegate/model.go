@@ -3,16 +3,22 @@ package delegate
 import (
 	"context"
 	"fmt"
+
+	"github.com/gradientsearch/pwmanager/business/sdk/sqldb"
 )
 
 // Func represents a function that is registered and called by the system.
 type Func func(context.Context, Data) error
 
-// Data represents an event between domains.
+// Data represents an event between domains. Tx is the transaction the event
+// was raised in, it is nil outside of a transaction. Functions that write in
+// response to the event use it so their writes commit or roll back with the
+// change that raised it.
 type Data struct {
 	Domain    string
 	Action    string
 	RawParams []byte
+	Tx        sqldb.CommitRollbacker
 }
 
 // String implements the Stringer interface.
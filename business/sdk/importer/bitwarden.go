@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+)
+
+// Set of Bitwarden item types.
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+	bitwardenSSHKey     = 5
+)
+
+// Set of Bitwarden custom field types.
+const (
+	bitwardenFieldHidden = 1
+)
+
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	Type     int              `json:"type"`
+	FolderID string           `json:"folderId"`
+	Name     string           `json:"name"`
+	Notes    string           `json:"notes"`
+	Favorite bool             `json:"favorite"`
+	Fields   []bitwardenField `json:"fields"`
+	Login    *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity *struct {
+		Identity
+		Address3 string `json:"address3"`
+	} `json:"identity"`
+	SSHKey *struct {
+		PrivateKey     string `json:"privateKey"`
+		PublicKey      string `json:"publicKey"`
+		KeyFingerprint string `json:"keyFingerprint"`
+	} `json:"sshKey"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+// importBitwarden parses an unencrypted Bitwarden JSON export.
+func importBitwarden(r io.Reader) ([]Item, error) {
+	var exp bitwardenExport
+	if err := json.NewDecoder(r).Decode(&exp); err != nil {
+		return nil, fmt.Errorf("decode: %w: %w", ErrInvalidFile, err)
+	}
+
+	if exp.Encrypted {
+		return nil, ErrEncrypted
+	}
+
+	folders := make(map[string]string, len(exp.Folders))
+	for _, f := range exp.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	items := make([]Item, 0, len(exp.Items))
+	for _, bi := range exp.Items {
+		var it Item
+
+		switch bi.Type {
+		case bitwardenLogin:
+			var l Login
+			if bi.Login != nil {
+				l = Login{
+					Username: bi.Login.Username,
+					Password: bi.Login.Password,
+					TOTP:     bi.Login.TOTP,
+				}
+				for _, u := range bi.Login.URIs {
+					if u.URI != "" {
+						l.URLs = append(l.URLs, u.URI)
+					}
+				}
+			}
+			it = Item{Type: itemtype.Login, Login: &l}
+
+		case bitwardenSecureNote:
+			it = Item{Type: itemtype.SecureNote}
+
+		case bitwardenCard:
+			it = Item{Type: itemtype.Card, Card: &Card{}}
+			if bi.Card != nil {
+				it.Card = &Card{
+					Cardholder: bi.Card.CardholderName,
+					Brand:      bi.Card.Brand,
+					Number:     bi.Card.Number,
+					ExpMonth:   bi.Card.ExpMonth,
+					ExpYear:    bi.Card.ExpYear,
+					Code:       bi.Card.Code,
+				}
+			}
+
+		case bitwardenIdentity:
+			it = Item{Type: itemtype.Identity, Identity: &Identity{}}
+			if bi.Identity != nil {
+				id := bi.Identity.Identity
+				if bi.Identity.Address3 != "" {
+					id.Address2 = joinNonEmpty(", ", id.Address2, bi.Identity.Address3)
+				}
+				it.Identity = &id
+			}
+
+		case bitwardenSSHKey:
+			it = Item{Type: itemtype.SSHKey, SSHKey: &SSHKey{}}
+			if bi.SSHKey != nil {
+				it.SSHKey = &SSHKey{
+					PrivateKey:  bi.SSHKey.PrivateKey,
+					PublicKey:   bi.SSHKey.PublicKey,
+					Fingerprint: bi.SSHKey.KeyFingerprint,
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("item[%s] type[%d]: %w", bi.Name, bi.Type, ErrInvalidFile)
+		}
+
+		it.Name = itemName(bi.Name, it.Type)
+		it.Folder = folders[bi.FolderID]
+		it.Favorite = bi.Favorite
+		it.Notes = bi.Notes
+
+		for _, f := range bi.Fields {
+			it.Fields = append(it.Fields, Field{
+				Name:   f.Name,
+				Value:  f.Value,
+				Hidden: f.Type == bitwardenFieldHidden,
+			})
+		}
+
+		items = append(items, it)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Set of item attributes a CSV column can hold.
+const (
+	csvName     = "name"
+	csvUsername = "username"
+	csvPassword = "password"
+	csvURL      = "url"
+	csvNotes    = "notes"
+	csvFolder   = "folder"
+	csvTOTP     = "totp"
+	csvFavorite = "favorite"
+)
+
+// csvColumns maps the lower case column names used by common managers to the
+// item attribute they hold. This covers the CSV exports of browsers, LastPass,
+// Bitwarden and KeePassXC among others.
+var csvColumns = map[string]string{
+	"name":           csvName,
+	"title":          csvName,
+	"username":       csvUsername,
+	"user":           csvUsername,
+	"login":          csvUsername,
+	"login_username": csvUsername,
+	"email":          csvUsername,
+	"password":       csvPassword,
+	"login_password": csvPassword,
+	"url":            csvURL,
+	"uri":            csvURL,
+	"login_uri":      csvURL,
+	"website":        csvURL,
+	"notes":          csvNotes,
+	"note":           csvNotes,
+	"extra":          csvNotes,
+	"folder":         csvFolder,
+	"group":          csvFolder,
+	"grouping":       csvFolder,
+	"totp":           csvTOTP,
+	"otp":            csvTOTP,
+	"login_totp":     csvTOTP,
+	"fav":            csvFavorite,
+	"favorite":       csvFavorite,
+}
+
+// importCSV parses a CSV file with a header row. Columns are matched by name
+// and columns that are not known become custom fields.
+func importCSV(r io.Reader) ([]Item, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("missing header: %w", ErrInvalidFile)
+		}
+		return nil, fmt.Errorf("read header: %w: %w", ErrInvalidFile, err)
+	}
+
+	columns := make([]string, len(header))
+	known := false
+	for i, h := range header {
+		// Spreadsheets write a byte order mark in front of the first column.
+		h = strings.TrimPrefix(h, "\ufeff")
+		header[i] = strings.TrimSpace(h)
+		columns[i] = csvColumns[strings.ToLower(header[i])]
+		if columns[i] != "" {
+			known = true
+		}
+	}
+
+	if !known {
+		return nil, fmt.Errorf("no known columns in header: %w", ErrInvalidFile)
+	}
+
+	var items []Item
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("read line[%d]: %w: %w", line, ErrInvalidFile, err)
+		}
+
+		var name, notes, folder string
+		var favorite bool
+		var l Login
+		var fields []Field
+
+		for i, v := range record {
+			if i >= len(columns) || v == "" {
+				continue
+			}
+
+			switch columns[i] {
+			case csvName:
+				name = v
+			case csvUsername:
+				if l.Username == "" {
+					l.Username = v
+					continue
+				}
+				fields = append(fields, Field{Name: header[i], Value: v})
+			case csvPassword:
+				l.Password = v
+			case csvURL:
+				l.URLs = append(l.URLs, v)
+			case csvNotes:
+				notes = v
+			case csvFolder:
+				folder = v
+			case csvTOTP:
+				l.TOTP = v
+			case csvFavorite:
+				favorite = v == "1" || strings.EqualFold(v, "true")
+			default:
+				fields = append(fields, Field{Name: header[i], Value: v})
+			}
+		}
+
+		it := newLoginItem(name, l)
+		it.Name = itemName(name, it.Type)
+		it.Notes = notes
+		it.Folder = folder
+		it.Favorite = favorite
+		it.Fields = fields
+
+		items = append(items, it)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,208 @@
+// Package importer parses the export files of other password managers into a
+// normalized item model. Parsing happens on the client, the items are
+// plaintext and have to be encrypted under the bundle key before they are sent
+// to the batch entry create endpoint.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+)
+
+// Set of error variables for importing.
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrEncrypted     = errors.New("encrypted exports are not supported, export without a password")
+	ErrInvalidFile   = errors.New("file is not a valid export")
+)
+
+// Importer declares the behavior of a parser for the export files of a
+// password manager.
+type Importer interface {
+	Import(r io.Reader) ([]Item, error)
+}
+
+// ImporterFunc is an adapter to allow the use of an ordinary function as an
+// importer.
+type ImporterFunc func(r io.Reader) ([]Item, error)
+
+// Import implements the Importer interface.
+func (f ImporterFunc) Import(r io.Reader) ([]Item, error) {
+	return f(r)
+}
+
+// Set of formats supported by this package.
+const (
+	FormatBitwardenJSON = "bitwarden-json"
+	Format1PUX          = "1password-1pux"
+	FormatKeePassXML    = "keepass-xml"
+	FormatCSV           = "csv"
+)
+
+var (
+	mu        sync.RWMutex
+	importers = make(map[string]Importer)
+)
+
+func init() {
+	Register(FormatBitwardenJSON, ImporterFunc(importBitwarden))
+	Register(Format1PUX, ImporterFunc(import1PUX))
+	Register(FormatKeePassXML, ImporterFunc(importKeePass))
+	Register(FormatCSV, ImporterFunc(importCSV))
+}
+
+// Register makes an importer available under the format name. Registering a
+// format a second time replaces the importer.
+func Register(format string, imp Importer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	importers[format] = imp
+}
+
+// Formats returns the names of the registered formats in sorted order.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	formats := make([]string, 0, len(importers))
+	for format := range importers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// Import parses the export file with the importer registered for the format.
+func Import(format string, r io.Reader) ([]Item, error) {
+	mu.RLock()
+	imp, exists := importers[format]
+	mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("format[%s]: %w", format, ErrUnknownFormat)
+	}
+
+	items, err := imp.Import(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+
+	return items, nil
+}
+
+// =============================================================================
+
+// Item represents an item of another password manager. Only the part matching
+// the type of the item is set, fields that have no place in the type are kept
+// as custom fields.
+type Item struct {
+	Type     itemtype.ItemType `json:"type"`
+	Name     string            `json:"name"`
+	Folder   string            `json:"folder,omitempty"`
+	Favorite bool              `json:"favorite,omitempty"`
+	Notes    string            `json:"notes,omitempty"`
+	Login    *Login            `json:"login,omitempty"`
+	Card     *Card             `json:"card,omitempty"`
+	Identity *Identity         `json:"identity,omitempty"`
+	SSHKey   *SSHKey           `json:"sshKey,omitempty"`
+	Fields   []Field           `json:"fields,omitempty"`
+}
+
+// SchemaVersion returns the schema version the item is written with.
+func (it Item) SchemaVersion() int {
+	return it.Type.SchemaVersion()
+}
+
+// Login represents the credentials of a login item.
+type Login struct {
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	TOTP     string   `json:"totp,omitempty"`
+	URLs     []string `json:"urls,omitempty"`
+}
+
+// Card represents a payment card.
+type Card struct {
+	Cardholder string `json:"cardholder,omitempty"`
+	Brand      string `json:"brand,omitempty"`
+	Number     string `json:"number,omitempty"`
+	ExpMonth   string `json:"expMonth,omitempty"`
+	ExpYear    string `json:"expYear,omitempty"`
+	Code       string `json:"code,omitempty"`
+}
+
+// Identity represents the personal details of an identity item.
+type Identity struct {
+	Title          string `json:"title,omitempty"`
+	FirstName      string `json:"firstName,omitempty"`
+	MiddleName     string `json:"middleName,omitempty"`
+	LastName       string `json:"lastName,omitempty"`
+	Company        string `json:"company,omitempty"`
+	Email          string `json:"email,omitempty"`
+	Phone          string `json:"phone,omitempty"`
+	Address1       string `json:"address1,omitempty"`
+	Address2       string `json:"address2,omitempty"`
+	City           string `json:"city,omitempty"`
+	State          string `json:"state,omitempty"`
+	PostalCode     string `json:"postalCode,omitempty"`
+	Country        string `json:"country,omitempty"`
+	Username       string `json:"username,omitempty"`
+	SSN            string `json:"ssn,omitempty"`
+	PassportNumber string `json:"passportNumber,omitempty"`
+	LicenseNumber  string `json:"licenseNumber,omitempty"`
+}
+
+// SSHKey represents an SSH key pair.
+type SSHKey struct {
+	PrivateKey  string `json:"privateKey,omitempty"`
+	PublicKey   string `json:"publicKey,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Field represents a custom field. Hidden fields should be masked by clients.
+type Field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// newLoginItem returns a login item, or a secure note when there are no
+// credentials to speak of, which is how notes are stored by managers that only
+// know logins.
+func newLoginItem(name string, l Login) Item {
+	if l.Username == "" && l.Password == "" && l.TOTP == "" && len(l.URLs) == 0 {
+		return Item{Type: itemtype.SecureNote, Name: name}
+	}
+
+	return Item{Type: itemtype.Login, Name: name, Login: &l}
+}
+
+// itemName returns the name of an item, items without one are named after
+// their type so they can still be found.
+func itemName(name string, typ itemtype.ItemType) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+
+	return "Untitled " + strings.ToLower(strings.ReplaceAll(typ.String(), "_", " "))
+}
+
+// joinNonEmpty joins the values that are not empty with the separator.
+func joinNonEmpty(sep string, values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+
+	return strings.Join(nonEmpty, sep)
+}
@@ -0,0 +1,273 @@
+package importer_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gradientsearch/pwmanager/business/sdk/importer"
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+)
+
+const bitwardenExport = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Work"}],
+  "items": [
+    {
+      "type": 1, "folderId": "f1", "name": "Gitlab", "notes": "ci account", "favorite": true,
+      "fields": [{"name": "pin", "value": "1234", "type": 1}],
+      "login": {"username": "gopher", "password": "secret", "totp": "otpauth://totp/x", "uris": [{"uri": "https://gitlab.com"}]}
+    },
+    {"type": 2, "folderId": null, "name": "Wifi", "notes": "password123", "secureNote": {"type": 0}},
+    {
+      "type": 3, "name": "Visa",
+      "card": {"cardholderName": "Bill Kennedy", "brand": "Visa", "number": "4111111111111111", "expMonth": "12", "expYear": "2030", "code": "123"}
+    },
+    {
+      "type": 4, "name": "Me",
+      "identity": {"firstName": "Bill", "lastName": "Kennedy", "address1": "1 Main St", "address2": "Apt 2", "address3": "Floor 3", "email": "bill@example.com"}
+    },
+    {"type": 5, "name": "Server", "sshKey": {"privateKey": "PRIVATE", "publicKey": "ssh-ed25519 AAAA", "keyFingerprint": "SHA256:abc"}}
+  ]
+}`
+
+const onePasswordData = `{
+  "accounts": [{
+    "vaults": [{
+      "attrs": {"name": "Private"},
+      "items": [
+        {
+          "favIndex": 1, "categoryUuid": "001",
+          "overview": {"title": "Github", "url": "https://github.com", "urls": [{"url": "https://github.com"}, {"url": "https://gist.github.com"}]},
+          "details": {
+            "loginFields": [
+              {"value": "gopher", "designation": "username", "fieldType": "T"},
+              {"value": "secret", "designation": "password", "fieldType": "P"}
+            ],
+            "notesPlain": "main account",
+            "sections": [{"fields": [
+              {"title": "one-time password", "id": "TOTP_1", "value": {"totp": "otpauth://totp/y"}},
+              {"title": "recovery", "id": "r1", "value": {"concealed": "codes"}}
+            ]}]
+          }
+        },
+        {
+          "favIndex": 0, "categoryUuid": "002",
+          "overview": {"title": "Amex"},
+          "details": {"sections": [{"fields": [
+            {"title": "cardholder name", "id": "cardholder", "value": {"string": "Bill Kennedy"}},
+            {"title": "type", "id": "type", "value": {"creditCardType": "amex"}},
+            {"title": "number", "id": "ccnum", "value": {"creditCardNumber": "371449635398431"}},
+            {"title": "verification number", "id": "cvv", "value": {"concealed": "1234"}},
+            {"title": "expiry date", "id": "expiry", "value": {"monthYear": 203011}}
+          ]}]}
+        },
+        {
+          "categoryUuid": "114",
+          "overview": {"title": "Deploy key"},
+          "details": {"sections": [{"fields": [
+            {"title": "private key", "id": "private_key", "value": {"sshKey": {"privateKey": "PRIVATE", "metadata": {"publicKey": "ssh-ed25519 BBBB", "fingerprint": "SHA256:def"}}}}
+          ]}]}
+        },
+        {"categoryUuid": "003", "overview": {"title": ""}, "details": {"notesPlain": "remember"}}
+      ]
+    }]
+  }]
+}`
+
+const keePassExport = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta><RecycleBinUUID>bin</RecycleBinUUID></Meta>
+  <Root>
+    <Group>
+      <UUID>root</UUID>
+      <Name>Database</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>Mail</Value></String>
+        <String><Key>UserName</Key><Value>gopher</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+        <String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+        <String><Key>Notes</Key><Value>personal</Value></String>
+        <String><Key>PIN</Key><Value ProtectInMemory="True">0000</Value></String>
+        <History>
+          <Entry><String><Key>Title</Key><Value>Old Mail</Value></String></Entry>
+        </History>
+      </Entry>
+      <Group>
+        <UUID>work</UUID>
+        <Name>Work</Name>
+        <Group>
+          <UUID>servers</UUID>
+          <Name>Servers</Name>
+          <Entry>
+            <String><Key>Title</Key><Value>Note</Value></String>
+            <String><Key>Notes</Key><Value>rack 4</Value></String>
+          </Entry>
+        </Group>
+      </Group>
+      <Group>
+        <UUID>bin</UUID>
+        <Name>Recycle Bin</Name>
+        <Entry><String><Key>Title</Key><Value>Deleted</Value></String></Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+
+const csvExport = "\ufeffname,url,username,password,extra,grouping,fav,pin\n" +
+	"Bank,https://bank.example.com,gopher,secret,,Finance,1,9999\n" +
+	"Alarm,,,,code 4321,,0,\n"
+
+func Test_Import(t *testing.T) {
+	table := []struct {
+		name   string
+		format string
+		data   []byte
+		exp    []importer.Item
+	}{
+		{
+			name:   "bitwarden",
+			format: importer.FormatBitwardenJSON,
+			data:   []byte(bitwardenExport),
+			exp: []importer.Item{
+				{
+					Type: itemtype.Login, Name: "Gitlab", Folder: "Work", Favorite: true, Notes: "ci account",
+					Login:  &importer.Login{Username: "gopher", Password: "secret", TOTP: "otpauth://totp/x", URLs: []string{"https://gitlab.com"}},
+					Fields: []importer.Field{{Name: "pin", Value: "1234", Hidden: true}},
+				},
+				{Type: itemtype.SecureNote, Name: "Wifi", Notes: "password123"},
+				{
+					Type: itemtype.Card, Name: "Visa",
+					Card: &importer.Card{Cardholder: "Bill Kennedy", Brand: "Visa", Number: "4111111111111111", ExpMonth: "12", ExpYear: "2030", Code: "123"},
+				},
+				{
+					Type: itemtype.Identity, Name: "Me",
+					Identity: &importer.Identity{FirstName: "Bill", LastName: "Kennedy", Address1: "1 Main St", Address2: "Apt 2, Floor 3", Email: "bill@example.com"},
+				},
+				{
+					Type: itemtype.SSHKey, Name: "Server",
+					SSHKey: &importer.SSHKey{PrivateKey: "PRIVATE", PublicKey: "ssh-ed25519 AAAA", Fingerprint: "SHA256:abc"},
+				},
+			},
+		},
+		{
+			name:   "1pux",
+			format: importer.Format1PUX,
+			data:   zip1PUX(t, onePasswordData),
+			exp: []importer.Item{
+				{
+					Type: itemtype.Login, Name: "Github", Folder: "Private", Favorite: true, Notes: "main account",
+					Login:  &importer.Login{Username: "gopher", Password: "secret", TOTP: "otpauth://totp/y", URLs: []string{"https://github.com", "https://gist.github.com"}},
+					Fields: []importer.Field{{Name: "recovery", Value: "codes", Hidden: true}},
+				},
+				{
+					Type: itemtype.Card, Name: "Amex", Folder: "Private",
+					Card: &importer.Card{Cardholder: "Bill Kennedy", Brand: "amex", Number: "371449635398431", ExpMonth: "11", ExpYear: "2030", Code: "1234"},
+				},
+				{
+					Type: itemtype.SSHKey, Name: "Deploy key", Folder: "Private",
+					SSHKey: &importer.SSHKey{PrivateKey: "PRIVATE", PublicKey: "ssh-ed25519 BBBB", Fingerprint: "SHA256:def"},
+				},
+				{Type: itemtype.SecureNote, Name: "Untitled secure note", Folder: "Private", Notes: "remember"},
+			},
+		},
+		{
+			name:   "keepass",
+			format: importer.FormatKeePassXML,
+			data:   []byte(keePassExport),
+			exp: []importer.Item{
+				{
+					Type: itemtype.Login, Name: "Mail", Notes: "personal",
+					Login:  &importer.Login{Username: "gopher", Password: "secret", URLs: []string{"https://mail.example.com"}},
+					Fields: []importer.Field{{Name: "PIN", Value: "0000", Hidden: true}},
+				},
+				{Type: itemtype.SecureNote, Name: "Note", Folder: "Work/Servers", Notes: "rack 4"},
+			},
+		},
+		{
+			name:   "csv",
+			format: importer.FormatCSV,
+			data:   []byte(csvExport),
+			exp: []importer.Item{
+				{
+					Type: itemtype.Login, Name: "Bank", Folder: "Finance", Favorite: true,
+					Login:  &importer.Login{Username: "gopher", Password: "secret", URLs: []string{"https://bank.example.com"}},
+					Fields: []importer.Field{{Name: "pin", Value: "9999"}},
+				},
+				{Type: itemtype.SecureNote, Name: "Alarm", Notes: "code 4321"},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := importer.Import(tt.format, bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Should be able to import the file: %s", err)
+			}
+
+			if diff := cmp.Diff(items, tt.exp); diff != "" {
+				t.Fatalf("Should get the expected items:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ImportErrors(t *testing.T) {
+	table := []struct {
+		name   string
+		format string
+		data   string
+		exp    error
+	}{
+		{name: "unknown-format", format: "lastpass-xml", data: "", exp: importer.ErrUnknownFormat},
+		{name: "bitwarden-encrypted", format: importer.FormatBitwardenJSON, data: `{"encrypted": true, "items": []}`, exp: importer.ErrEncrypted},
+		{name: "bitwarden-invalid", format: importer.FormatBitwardenJSON, data: `[`, exp: importer.ErrInvalidFile},
+		{name: "1pux-not-zip", format: importer.Format1PUX, data: onePasswordData, exp: importer.ErrInvalidFile},
+		{name: "keepass-invalid", format: importer.FormatKeePassXML, data: `<KeePassFile>`, exp: importer.ErrInvalidFile},
+		{name: "csv-empty", format: importer.FormatCSV, data: "", exp: importer.ErrInvalidFile},
+		{name: "csv-unknown-columns", format: importer.FormatCSV, data: "a,b\n1,2\n", exp: importer.ErrInvalidFile},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.Import(tt.format, strings.NewReader(tt.data))
+			if !errors.Is(err, tt.exp) {
+				t.Fatalf("Should get error %v, got %v", tt.exp, err)
+			}
+		})
+	}
+}
+
+func Test_Formats(t *testing.T) {
+	exp := []string{importer.Format1PUX, importer.FormatBitwardenJSON, importer.FormatCSV, importer.FormatKeePassXML}
+
+	if diff := cmp.Diff(importer.Formats(), exp); diff != "" {
+		t.Fatalf("Should get the registered formats:\n%s", diff)
+	}
+}
+
+// zip1PUX packs the export data into a 1PUX archive.
+func zip1PUX(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("export.data")
+	if err != nil {
+		t.Fatalf("Should be able to create the export data: %s", err)
+	}
+
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatalf("Should be able to write the export data: %s", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Should be able to close the archive: %s", err)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Set of standard KeePass entry strings, everything else is a custom field.
+const (
+	keePassTitle    = "Title"
+	keePassUserName = "UserName"
+	keePassPassword = "Password"
+	keePassURL      = "URL"
+	keePassNotes    = "Notes"
+	keePassOTP      = "otp"
+)
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+// keePassEntry holds the current state of an entry, the History element of
+// an entry is not decoded so old versions are left out.
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Value     string `xml:",chardata"`
+			Protected string `xml:"ProtectInMemory,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+}
+
+// importKeePass parses an unencrypted KeePass 2.x XML export. The name of the
+// groups below the root group form the folder of an item and the recycle bin
+// is left out.
+func importKeePass(r io.Reader) ([]Item, error) {
+	var kf keePassFile
+	if err := xml.NewDecoder(r).Decode(&kf); err != nil {
+		return nil, fmt.Errorf("decode: %w: %w", ErrInvalidFile, err)
+	}
+
+	var items []Item
+
+	var walk func(g keePassGroup, path []string)
+	walk = func(g keePassGroup, path []string) {
+		if g.UUID != "" && g.UUID == kf.Meta.RecycleBinUUID {
+			return
+		}
+
+		for _, e := range g.Entries {
+			it := toKeePassItem(e)
+			it.Folder = strings.Join(path, "/")
+			items = append(items, it)
+		}
+
+		for _, sub := range g.Groups {
+			walk(sub, append(path[:len(path):len(path)], sub.Name))
+		}
+	}
+
+	for _, root := range kf.Root.Groups {
+		walk(root, nil)
+	}
+
+	return items, nil
+}
+
+func toKeePassItem(e keePassEntry) Item {
+	var name, notes string
+	var l Login
+	var fields []Field
+
+	for _, s := range e.Strings {
+		v := s.Value.Value
+
+		switch s.Key {
+		case keePassTitle:
+			name = v
+		case keePassUserName:
+			l.Username = v
+		case keePassPassword:
+			l.Password = v
+		case keePassURL:
+			if v != "" {
+				l.URLs = append(l.URLs, v)
+			}
+		case keePassNotes:
+			notes = v
+		case keePassOTP:
+			l.TOTP = v
+		default:
+			if v == "" {
+				continue
+			}
+			fields = append(fields, Field{
+				Name:   s.Key,
+				Value:  v,
+				Hidden: strings.EqualFold(s.Value.Protected, "true"),
+			})
+		}
+	}
+
+	it := newLoginItem(name, l)
+	it.Name = itemName(name, it.Type)
+	it.Notes = notes
+	it.Fields = fields
+
+	return it
+}
@@ -0,0 +1,296 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gradientsearch/pwmanager/business/types/itemtype"
+)
+
+// Set of 1Password categories that map to an item type other than a secure
+// note.
+const (
+	onePasswordLogin    = "001"
+	onePasswordCard     = "002"
+	onePasswordIdentity = "004"
+	onePasswordPassword = "005"
+	onePasswordSSHKey   = "114"
+)
+
+// onePasswordDataFile is the file holding the items inside a 1PUX archive.
+const onePasswordDataFile = "export.data"
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	FavIndex     int    `json:"favIndex"`
+	CategoryUUID string `json:"categoryUuid"`
+	Overview     struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+	Details struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			Designation string `json:"designation"`
+			FieldType   string `json:"fieldType"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Fields []struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+	} `json:"details"`
+}
+
+// onePasswordField is a field of a section reduced to its text.
+type onePasswordField struct {
+	id    string
+	title string
+	kind  string
+	value string
+}
+
+// import1PUX parses a 1Password 1PUX export, which is a zip archive holding
+// the items as JSON.
+func import1PUX(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("unzip: %w: %w", ErrInvalidFile, err)
+	}
+
+	f, err := zr.Open(onePasswordDataFile)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w: %w", onePasswordDataFile, ErrInvalidFile, err)
+	}
+	defer f.Close()
+
+	var exp onePasswordExport
+	if err := json.NewDecoder(f).Decode(&exp); err != nil {
+		return nil, fmt.Errorf("decode: %w: %w", ErrInvalidFile, err)
+	}
+
+	var items []Item
+	for _, acc := range exp.Accounts {
+		for _, vault := range acc.Vaults {
+			for _, oi := range vault.Items {
+				it := toOnePasswordItem(oi)
+				it.Folder = vault.Attrs.Name
+				items = append(items, it)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func toOnePasswordItem(oi onePasswordItem) Item {
+	var fields []onePasswordField
+	for _, s := range oi.Details.Sections {
+		for _, f := range s.Fields {
+			kind, value := onePasswordValue(f.Value)
+			if value == "" {
+				continue
+			}
+
+			fields = append(fields, onePasswordField{
+				id:    f.ID,
+				title: f.Title,
+				kind:  kind,
+				value: value,
+			})
+		}
+	}
+
+	// take removes the first field the match function accepts and returns
+	// its value.
+	take := func(match func(f onePasswordField) bool) string {
+		for i, f := range fields {
+			if match(f) {
+				fields = append(fields[:i], fields[i+1:]...)
+				return f.value
+			}
+		}
+		return ""
+	}
+	byID := func(id string) string {
+		return take(func(f onePasswordField) bool { return f.id == id })
+	}
+	byKind := func(kind string) string {
+		return take(func(f onePasswordField) bool { return f.kind == kind })
+	}
+
+	var it Item
+
+	switch oi.CategoryUUID {
+	case onePasswordLogin, onePasswordPassword:
+		var l Login
+		for _, lf := range oi.Details.LoginFields {
+			switch lf.Designation {
+			case "username":
+				l.Username = lf.Value
+			case "password":
+				l.Password = lf.Value
+			}
+		}
+		if l.Password == "" {
+			l.Password = oi.Details.Password
+		}
+		if oi.Overview.URL != "" {
+			l.URLs = append(l.URLs, oi.Overview.URL)
+		}
+		for _, u := range oi.Overview.URLs {
+			if u.URL != "" && u.URL != oi.Overview.URL {
+				l.URLs = append(l.URLs, u.URL)
+			}
+		}
+		l.TOTP = byKind("totp")
+		it = Item{Type: itemtype.Login, Login: &l}
+
+	case onePasswordCard:
+		c := Card{
+			Cardholder: byID("cardholder"),
+			Brand:      byID("type"),
+			Number:     byID("ccnum"),
+			Code:       byID("cvv"),
+		}
+		if expiry := byID("expiry"); len(expiry) == 6 {
+			c.ExpYear, c.ExpMonth = expiry[:4], expiry[4:]
+		}
+		it = Item{Type: itemtype.Card, Card: &c}
+
+	case onePasswordIdentity:
+		id := Identity{
+			FirstName:  byID("firstname"),
+			MiddleName: byID("initial"),
+			LastName:   byID("lastname"),
+			Company:    byID("company"),
+			Email:      byID("email"),
+			Phone:      byID("defphone"),
+			Address1:   byID("address"),
+			Username:   byID("username"),
+		}
+		it = Item{Type: itemtype.Identity, Identity: &id}
+
+	case onePasswordSSHKey:
+		var k SSHKey
+		for _, s := range oi.Details.Sections {
+			for _, f := range s.Fields {
+				if raw, exists := f.Value["sshKey"]; exists {
+					k = onePasswordSSHKeyValue(raw)
+				}
+			}
+		}
+		byKind("sshKey")
+		it = Item{Type: itemtype.SSHKey, SSHKey: &k}
+
+	default:
+		it = Item{Type: itemtype.SecureNote}
+	}
+
+	it.Name = itemName(oi.Overview.Title, it.Type)
+	it.Favorite = oi.FavIndex > 0
+	it.Notes = oi.Details.NotesPlain
+
+	for _, f := range fields {
+		name := f.title
+		if name == "" {
+			name = f.id
+		}
+
+		it.Fields = append(it.Fields, Field{
+			Name:   name,
+			Value:  f.value,
+			Hidden: f.kind == "concealed",
+		})
+	}
+
+	return it
+}
+
+// onePasswordValue returns the kind and the text of a field value. A value
+// is an object with a single key naming its kind, e.g. {"concealed":"secret"}.
+func onePasswordValue(value map[string]json.RawMessage) (string, string) {
+	for kind, raw := range value {
+		switch kind {
+		case "email":
+			var v struct {
+				Address string `json:"email_address"`
+			}
+			json.Unmarshal(raw, &v)
+			return kind, v.Address
+
+		case "address":
+			var v struct {
+				Street  string `json:"street"`
+				City    string `json:"city"`
+				State   string `json:"state"`
+				Zip     string `json:"zip"`
+				Country string `json:"country"`
+			}
+			json.Unmarshal(raw, &v)
+			return kind, joinNonEmpty(", ", v.Street, v.City, v.State, v.Zip, v.Country)
+
+		case "sshKey":
+			return kind, onePasswordSSHKeyValue(raw).PrivateKey
+		}
+
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return kind, s
+		}
+
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err == nil {
+			if i, err := n.Int64(); err == nil {
+				return kind, strconv.FormatInt(i, 10)
+			}
+			return kind, n.String()
+		}
+	}
+
+	return "", ""
+}
+
+func onePasswordSSHKeyValue(raw json.RawMessage) SSHKey {
+	var v struct {
+		PrivateKey string `json:"privateKey"`
+		Metadata   struct {
+			PublicKey   string `json:"publicKey"`
+			Fingerprint string `json:"fingerprint"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(raw, &v)
+
+	return SSHKey{
+		PrivateKey:  v.PrivateKey,
+		PublicKey:   v.Metadata.PublicKey,
+		Fingerprint: v.Metadata.Fingerprint,
+	}
+}
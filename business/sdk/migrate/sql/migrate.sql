@@ -48,3 +48,471 @@ CREATE TABLE entries (
     FOREIGN KEY (user_id) REFERENCES users(user_id),
     FOREIGN KEY (bundle_id) REFERENCES bundles(bundle_id) ON DELETE CASCADE
 );
+
+-- Version: 1.02
+-- Description: Add user registration tokens and user unlock keys
+ALTER TABLE users ADD COLUMN uuk TEXT NULL;
+
+CREATE TABLE registration_tokens (
+    user_id UUID NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    date_expires TIMESTAMP NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.03
+-- Description: Add account recovery
+CREATE TABLE user_recovery (
+    user_id UUID NOT NULL,
+    verifier_hash TEXT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE recovery_events (
+    event_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    actor_id UUID NOT NULL,
+    method TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (event_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.04
+-- Description: Add bundle invites
+CREATE TABLE bundle_invites (
+    invite_id UUID NOT NULL,
+    bundle_id UUID NOT NULL,
+    inviter_id UUID NOT NULL,
+    invitee_id UUID NOT NULL,
+    data TEXT NOT NULL,
+    roles TEXT [] NOT NULL,
+    status TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NOT NULL,
+    PRIMARY KEY (invite_id),
+    FOREIGN KEY (bundle_id) REFERENCES bundles(bundle_id) ON DELETE CASCADE,
+    FOREIGN KEY (inviter_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (invitee_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX bundle_invites_pending_idx ON bundle_invites (bundle_id, invitee_id) WHERE status = 'PENDING';
+
+-- Version: 1.05
+-- Description: Add bundle key generations
+ALTER TABLE bundles ADD COLUMN key_generation INT NOT NULL DEFAULT 1;
+ALTER TABLE entries ADD COLUMN key_generation INT NOT NULL DEFAULT 1;
+ALTER TABLE bundle_invites ADD COLUMN key_generation INT NOT NULL DEFAULT 1;
+
+-- Version: 1.06
+-- Description: Add entry versions
+CREATE TABLE entry_versions (
+    version_id UUID NOT NULL,
+    entry_id UUID NOT NULL,
+    bundle_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    data TEXT NOT NULL,
+    key_generation INT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_archived TIMESTAMP NOT NULL,
+    PRIMARY KEY (version_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id),
+    FOREIGN KEY (bundle_id) REFERENCES bundles(bundle_id) ON DELETE CASCADE
+);
+
+CREATE INDEX entry_versions_entry_idx ON entry_versions (entry_id, date_archived DESC);
+
+-- Version: 1.07
+-- Description: Add soft delete for bundles and entries
+ALTER TABLE bundles ADD COLUMN date_deleted TIMESTAMP NULL;
+ALTER TABLE entries ADD COLUMN date_deleted TIMESTAMP NULL;
+
+-- Version: 1.08
+-- Description: Add item types and schema versions for entries
+ALTER TABLE entries ADD COLUMN item_type TEXT NOT NULL DEFAULT 'LOGIN';
+ALTER TABLE entries ADD COLUMN schema_version INT NOT NULL DEFAULT 1;
+ALTER TABLE entry_versions ADD COLUMN item_type TEXT NOT NULL DEFAULT 'LOGIN';
+ALTER TABLE entry_versions ADD COLUMN schema_version INT NOT NULL DEFAULT 1;
+
+-- Version: 1.09
+-- Description: Add change sequences and tombstones for incremental sync
+CREATE SEQUENCE change_seq;
+
+ALTER TABLE bundles ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('change_seq');
+ALTER TABLE bundles ALTER COLUMN change_seq DROP DEFAULT;
+ALTER TABLE keys ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('change_seq');
+ALTER TABLE keys ALTER COLUMN change_seq DROP DEFAULT;
+ALTER TABLE entries ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('change_seq');
+ALTER TABLE entries ALTER COLUMN change_seq DROP DEFAULT;
+
+CREATE INDEX bundles_change_seq_idx ON bundles (change_seq);
+CREATE INDEX keys_change_seq_idx ON keys (change_seq);
+CREATE INDEX entries_change_seq_idx ON entries (change_seq);
+
+CREATE TABLE tombstones (
+    change_seq BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    id UUID NOT NULL,
+    bundle_id UUID NOT NULL,
+    user_id UUID NULL,
+    date_deleted TIMESTAMP NOT NULL,
+    PRIMARY KEY (change_seq)
+);
+
+CREATE INDEX tombstones_bundle_idx ON tombstones (bundle_id, change_seq);
+CREATE INDEX tombstones_user_idx ON tombstones (user_id, change_seq);
+
+-- The highest change sequence of the tombstones removed by the purge job.
+-- Cursors below it can no longer be synced incrementally.
+CREATE TABLE sync_horizon (
+    change_seq BIGINT NOT NULL
+);
+
+INSERT INTO sync_horizon (change_seq) VALUES (0);
+
+-- Writers take a transaction level lock before drawing from the sequence so
+-- change sequences become visible in the order they are drawn. A reader can
+-- never see a change sequence while a lower one is still uncommitted.
+CREATE FUNCTION next_change_seq() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('change_seq'));
+    RETURN nextval('change_seq');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION set_change_seq() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_seq := next_change_seq();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- TG_ARGV[0] is the kind of the row and TG_ARGV[1] the name of its id column.
+-- Only key tombstones record the user since they revoke that user's access.
+CREATE FUNCTION insert_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tombstones (change_seq, kind, id, bundle_id, user_id, date_deleted)
+    VALUES (
+        next_change_seq(),
+        TG_ARGV[0],
+        (to_jsonb(OLD) ->> TG_ARGV[1])::UUID,
+        OLD.bundle_id,
+        CASE WHEN TG_ARGV[0] = 'KEY' THEN OLD.user_id END,
+        now() AT TIME ZONE 'UTC'
+    );
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bundles_change_seq BEFORE INSERT OR UPDATE ON bundles
+    FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+CREATE TRIGGER keys_change_seq BEFORE INSERT OR UPDATE ON keys
+    FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+CREATE TRIGGER entries_change_seq BEFORE INSERT OR UPDATE ON entries
+    FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+
+CREATE TRIGGER bundles_tombstone AFTER DELETE ON bundles
+    FOR EACH ROW EXECUTE FUNCTION insert_tombstone('BUNDLE', 'bundle_id');
+CREATE TRIGGER keys_tombstone AFTER DELETE ON keys
+    FOR EACH ROW EXECUTE FUNCTION insert_tombstone('KEY', 'key_id');
+CREATE TRIGGER entries_tombstone AFTER DELETE ON entries
+    FOR EACH ROW EXECUTE FUNCTION insert_tombstone('ENTRY', 'entry_id');
+
+-- Version: 1.10
+-- Description: Add revisions for optimistic concurrency control
+ALTER TABLE bundles ADD COLUMN revision INT NOT NULL DEFAULT 1;
+ALTER TABLE keys ADD COLUMN revision INT NOT NULL DEFAULT 1;
+ALTER TABLE entries ADD COLUMN revision INT NOT NULL DEFAULT 1;
+
+-- Version: 1.11
+-- Description: Add audit log
+-- Targets are not foreign keys so the record outlives what it refers to.
+CREATE TABLE audits (
+    audit_id UUID NOT NULL,
+    actor_id UUID NULL,
+    action TEXT NOT NULL,
+    bundle_id UUID NULL,
+    entry_id UUID NULL,
+    key_id UUID NULL,
+    user_id UUID NULL,
+    status INT NOT NULL,
+    ip_address TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    trace_id TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (audit_id)
+);
+
+CREATE INDEX audits_date_created_idx ON audits (date_created DESC);
+CREATE INDEX audits_actor_idx ON audits (actor_id, date_created DESC);
+CREATE INDEX audits_bundle_idx ON audits (bundle_id, date_created DESC);
+
+-- Version: 1.12
+-- Description: Chain audit records and add signed checkpoints
+-- Records written before the chain keep an empty hash, verification accepts
+-- them only ahead of the first chained record.
+ALTER TABLE audits ADD COLUMN seq BIGINT;
+UPDATE audits SET seq = ordered.seq
+FROM (SELECT audit_id, row_number() OVER (ORDER BY date_created, audit_id) AS seq FROM audits) AS ordered
+WHERE audits.audit_id = ordered.audit_id;
+ALTER TABLE audits ALTER COLUMN seq SET NOT NULL;
+ALTER TABLE audits ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audits ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audits ADD CONSTRAINT audits_seq_key UNIQUE (seq);
+
+CREATE TABLE audit_checkpoints (
+    checkpoint_id UUID NOT NULL,
+    seq BIGINT NOT NULL,
+    hash TEXT NOT NULL,
+    kid TEXT NOT NULL,
+    signature BYTEA NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (checkpoint_id)
+);
+
+CREATE INDEX audit_checkpoints_seq_idx ON audit_checkpoints (seq);
+
+-- Version: 1.13
+-- Description: Add view of the bundles each user holds a key to
+CREATE VIEW view_user_bundles AS
+SELECT
+    k.key_id,
+    k.user_id,
+    b.bundle_id,
+    b.user_id AS owner_id,
+    o.name AS owner_name,
+    o.email AS owner_email,
+    b.type,
+    b.metadata,
+    b.date_created,
+    b.date_updated,
+    k.data AS key_data,
+    k.roles AS key_roles,
+    (
+        SELECT count(1)
+        FROM entries e
+        WHERE e.bundle_id = b.bundle_id AND e.date_deleted IS NULL
+    ) AS entry_count,
+    (
+        SELECT json_agg(json_build_object('user_id', mu.user_id, 'name', mu.name, 'email', mu.email, 'roles', mk.roles) ORDER BY mk.date_created)
+        FROM keys mk
+        JOIN users mu ON mu.user_id = mk.user_id
+        WHERE mk.bundle_id = b.bundle_id
+    ) AS users
+FROM
+    keys k
+JOIN
+    bundles b ON b.bundle_id = k.bundle_id
+JOIN
+    users o ON o.user_id = b.user_id
+WHERE
+    b.date_deleted IS NULL;
+
+-- Version: 1.14
+-- Description: Add external identities linked to users
+CREATE TABLE user_identities (
+    identity_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (identity_id),
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.15
+-- Description: Add sessions with refresh tokens and revoked access tokens
+CREATE TABLE sessions (
+    session_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    device TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    access_id UUID NOT NULL,
+    access_expires TIMESTAMP NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_last_used TIMESTAMP NOT NULL,
+    date_expires TIMESTAMP NOT NULL,
+    date_revoked TIMESTAMP NULL,
+    PRIMARY KEY (session_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+
+CREATE TABLE refresh_tokens (
+    token_hash TEXT NOT NULL,
+    session_id UUID NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_used TIMESTAMP NULL,
+    PRIMARY KEY (token_hash),
+    FOREIGN KEY (session_id) REFERENCES sessions(session_id) ON DELETE CASCADE
+);
+
+CREATE TABLE revoked_tokens (
+    token_id TEXT NOT NULL,
+    date_expires TIMESTAMP NOT NULL,
+    PRIMARY KEY (token_id)
+);
+
+-- Version: 1.16
+-- Description: Add TOTP two-factor authentication with recovery codes and roles that require it
+CREATE TABLE user_mfa (
+    user_id UUID NOT NULL,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    last_step BIGINT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_mfa_recovery_codes (
+    user_id UUID NOT NULL,
+    code_hash TEXT NOT NULL,
+    PRIMARY KEY (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES user_mfa(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mfa_roles (
+    role TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (role)
+);
+
+-- Version: 1.17
+-- Description: Wrap alphanumeric entry data stored before the envelope format in a legacy envelope
+UPDATE entries SET
+    data = json_build_object(
+        'v', 0,
+        'enc', '',
+        'kg', key_generation,
+        'iv', '',
+        'data', rtrim(translate(encode(convert_to(data, 'UTF8'), 'base64'), E'+/\n', '-_'), '=')
+    )::TEXT
+WHERE
+    data ~ '^[a-zA-Z0-9]{1,600}$';
+
+UPDATE entry_versions SET
+    data = json_build_object(
+        'v', 0,
+        'enc', '',
+        'kg', key_generation,
+        'iv', '',
+        'data', rtrim(translate(encode(convert_to(data, 'UTF8'), 'base64'), E'+/\n', '-_'), '=')
+    )::TEXT
+WHERE
+    data ~ '^[a-zA-Z0-9]{1,600}$';
+
+-- Version: 1.18
+-- Description: Order changes by transaction instead of a global change lock
+-- A change is positioned by the id of the transaction that made it and then
+-- by its change sequence. Readers stop before the oldest transaction still
+-- running, so writers no longer wait on each other to keep the sequence in
+-- commit order.
+ALTER TABLE bundles ADD COLUMN change_xid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE bundles ALTER COLUMN change_xid DROP DEFAULT;
+ALTER TABLE keys ADD COLUMN change_xid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE keys ALTER COLUMN change_xid DROP DEFAULT;
+ALTER TABLE entries ADD COLUMN change_xid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE entries ALTER COLUMN change_xid DROP DEFAULT;
+ALTER TABLE tombstones ADD COLUMN change_xid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tombstones ALTER COLUMN change_xid DROP DEFAULT;
+ALTER TABLE sync_horizon ADD COLUMN change_xid BIGINT NOT NULL DEFAULT 0;
+
+DROP INDEX bundles_change_seq_idx;
+DROP INDEX keys_change_seq_idx;
+DROP INDEX entries_change_seq_idx;
+DROP INDEX tombstones_bundle_idx;
+DROP INDEX tombstones_user_idx;
+
+CREATE INDEX bundles_change_idx ON bundles (change_xid, change_seq);
+CREATE INDEX keys_change_idx ON keys (change_xid, change_seq);
+CREATE INDEX entries_change_idx ON entries (change_xid, change_seq);
+CREATE INDEX tombstones_bundle_idx ON tombstones (bundle_id, change_xid, change_seq);
+CREATE INDEX tombstones_user_idx ON tombstones (user_id, change_xid, change_seq);
+
+CREATE OR REPLACE FUNCTION next_change_seq() RETURNS BIGINT AS $$
+BEGIN
+    RETURN nextval('change_seq');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION set_change_seq() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_xid := pg_current_xact_id()::TEXT::BIGINT;
+    NEW.change_seq := next_change_seq();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION insert_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tombstones (change_xid, change_seq, kind, id, bundle_id, user_id, date_deleted)
+    VALUES (
+        pg_current_xact_id()::TEXT::BIGINT,
+        next_change_seq(),
+        TG_ARGV[0],
+        (to_jsonb(OLD) ->> TG_ARGV[1])::UUID,
+        OLD.bundle_id,
+        CASE WHEN TG_ARGV[0] = 'KEY' THEN OLD.user_id END,
+        now() AT TIME ZONE 'UTC'
+    );
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Version: 1.19
+-- Description: Record where the audit chain starts
+-- The single row holds the sequence of the first chained record, records
+-- before it were written before the chain was introduced. Appends lock the
+-- row so the chain is extended by one transaction at a time.
+CREATE TABLE audit_chain (
+    start_seq BIGINT NOT NULL
+);
+
+INSERT INTO audit_chain (start_seq)
+SELECT COALESCE(
+    (SELECT MIN(seq) FROM audits WHERE hash <> ''),
+    (SELECT COALESCE(MAX(seq), 0) + 1 FROM audits)
+);
+
+-- Version: 1.20
+-- Description: Keep the roles an identity provider granted with the session
+ALTER TABLE sessions ADD COLUMN roles TEXT [] NOT NULL DEFAULT '{}';
+
+-- Version: 1.21
+-- Description: Lock two-factor authentication after repeated invalid codes
+ALTER TABLE user_mfa ADD COLUMN failed_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE user_mfa ADD COLUMN locked_until TIMESTAMP NULL;
+
+-- Version: 1.22
+-- Description: Add single-use codes that start linking an identity
+CREATE TABLE link_codes (
+    user_id UUID NOT NULL,
+    code_hash TEXT UNIQUE NOT NULL,
+    date_expires TIMESTAMP NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.23
+-- Description: Record the archive bundles an import recreated
+CREATE TABLE bundle_imports (
+    user_id UUID NOT NULL,
+    archive_bundle_id UUID NOT NULL,
+    bundle_id UUID NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, archive_bundle_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (bundle_id) REFERENCES bundles(bundle_id) ON DELETE CASCADE
+);
@@ -151,6 +151,25 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	return nil
 }
 
+// NamedExecBatchContext is a helper function to insert a collection of rows
+// with as few statements as possible. The query must be an INSERT with a
+// single VALUES row, which is repeated for up to batchSize rows per statement.
+// The batch size keeps the number of bind parameters within the limit of the
+// database.
+func NamedExecBatchContext[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data []T, batchSize int) error {
+	for len(data) > 0 {
+		n := min(batchSize, len(data))
+
+		if err := NamedExecContext(ctx, log, db, query, data[:n]); err != nil {
+			return err
+		}
+
+		data = data[n:]
+	}
+
+	return nil
+}
+
 // QuerySlice is a helper function for executing queries that return a
 // collection of data to be unmarshalled into a slice.
 func QuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, dest *[]T) error {
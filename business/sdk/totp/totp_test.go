@@ -0,0 +1,102 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gradientsearch/pwmanager/business/sdk/totp"
+)
+
+// secret is the SHA1 key of the RFC 6238 test vectors.
+var secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// The RFC lists 8 digit codes, the 6 digit codes are their last 6 digits.
+	table := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tt := range table {
+		got, err := totp.Code(secret, totp.Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Should be able to generate the code: %s", err)
+		}
+
+		if got != tt.code {
+			t.Errorf("%d: Should generate the RFC code: got %s, exp %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := totp.Step(now)
+
+	code, err := totp.Code(secret, step)
+	if err != nil {
+		t.Fatalf("Should be able to generate the code: %s", err)
+	}
+
+	table := []struct {
+		name string
+		at   time.Time
+		code string
+		ok   bool
+	}{
+		{name: "current", at: now, code: code, ok: true},
+		{name: "previous", at: now.Add(totp.Period), code: code, ok: true},
+		{name: "next", at: now.Add(-totp.Period), code: code, ok: true},
+		{name: "expired", at: now.Add(2 * totp.Period), code: code, ok: false},
+		{name: "wrong", at: now, code: "000000", ok: false},
+		{name: "length", at: now, code: "0", ok: false},
+	}
+
+	for _, tt := range table {
+		got, ok, err := totp.Validate(secret, tt.code, tt.at)
+		if err != nil {
+			t.Fatalf("%s: Should be able to validate the code: %s", tt.name, err)
+		}
+
+		if ok != tt.ok {
+			t.Errorf("%s: Should validate the code %v: got %v", tt.name, tt.ok, ok)
+		}
+
+		if ok && got != step {
+			t.Errorf("%s: Should return the matched step %d: got %d", tt.name, step, got)
+		}
+	}
+}
+
+func TestURI(t *testing.T) {
+	s, err := totp.NewSecret()
+	if err != nil {
+		t.Fatalf("Should be able to generate a secret: %s", err)
+	}
+
+	u, err := url.Parse(totp.URI("pwmanager", "bill@example.com", s))
+	if err != nil {
+		t.Fatalf("Should be able to parse the uri: %s", err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/pwmanager:bill@example.com" {
+		t.Errorf("Should build an otpauth totp uri: got %s", u)
+	}
+
+	if got := u.Query().Get("secret"); got != s {
+		t.Errorf("Should include the secret: got %s", got)
+	}
+
+	if got := u.Query().Get("issuer"); got != "pwmanager" {
+		t.Errorf("Should include the issuer: got %s", got)
+	}
+}
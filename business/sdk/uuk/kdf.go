@@ -0,0 +1,213 @@
+package uuk
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Set of key derivation algorithms that can be recorded in EncSymKey.Alg.
+const (
+	// AlgPBKDF2SHA1 is the original derivation, kept so existing UUKs can
+	// still be unlocked.
+	AlgPBKDF2SHA1   = "pbkdf2-hkdf"
+	AlgPBKDF2SHA256 = "pbkdf2-sha256-hkdf"
+	AlgArgon2id     = "argon2id-hkdf"
+)
+
+// KDF derives a 32 byte key from the password and salt using the parameters
+// recorded in the EncSymKey.
+type KDF func(password, salt []byte, esk EncSymKey) ([]byte, error)
+
+// KDFParams represents the settings used to derive the password key when
+// building or upgrading a UUK. Iterations is recorded in EncSymKey.P2c and is
+// the time cost for Argon2id, Memory (KiB) and Parallelism only apply to Argon2id.
+type KDFParams struct {
+	Alg         string
+	Iterations  int
+	Memory      uint32
+	Parallelism uint8
+}
+
+// DefaultKDFParams are the settings used for new UUKs and the target for
+// upgrading existing ones.
+var DefaultKDFParams = KDFParams{
+	Alg:         AlgArgon2id,
+	Iterations:  3,
+	Memory:      64 * 1024,
+	Parallelism: 4,
+}
+
+// KDFLimits represents the range of parameters a UUK submitted by a client
+// may record for an alg. The lower bounds keep clients from weakening the
+// derivation and the upper bounds keep a UUK from costing the client more
+// than it can afford to unlock. Memory and Parallelism are only checked when
+// MaxMemory and MaxParallelism are set.
+type KDFLimits struct {
+	MinIterations  int
+	MaxIterations  int
+	MinMemory      uint32
+	MaxMemory      uint32
+	MinParallelism uint8
+	MaxParallelism uint8
+}
+
+type kdfEntry struct {
+	fn     KDF
+	limits KDFLimits
+}
+
+var (
+	kdfsMu sync.RWMutex
+	kdfs   = map[string]kdfEntry{
+		AlgPBKDF2SHA1: {
+			fn:     pbkdf2SHA1,
+			limits: KDFLimits{MinIterations: 100_000, MaxIterations: 10_000_000},
+		},
+		AlgPBKDF2SHA256: {
+			fn:     pbkdf2SHA256,
+			limits: KDFLimits{MinIterations: 100_000, MaxIterations: 10_000_000},
+		},
+		AlgArgon2id: {
+			fn: argon2id,
+			limits: KDFLimits{
+				MinIterations:  1,
+				MaxIterations:  10,
+				MinMemory:      19 * 1024,
+				MaxMemory:      1024 * 1024,
+				MinParallelism: 1,
+				MaxParallelism: 16,
+			},
+		},
+	}
+)
+
+// RegisterKDF adds a key derivation function and the limits its parameters
+// must fall within to the registry so UUKs with the specified alg can be
+// built and unlocked.
+func RegisterKDF(alg string, fn KDF, limits KDFLimits) {
+	kdfsMu.Lock()
+	defer kdfsMu.Unlock()
+
+	kdfs[alg] = kdfEntry{fn: fn, limits: limits}
+}
+
+func lookupKDF(alg string) (KDF, error) {
+	entry, err := lookupKDFEntry(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.fn, nil
+}
+
+func lookupKDFEntry(alg string) (kdfEntry, error) {
+	kdfsMu.RLock()
+	defer kdfsMu.RUnlock()
+
+	entry, exists := kdfs[alg]
+	if !exists {
+		return kdfEntry{}, fmt.Errorf("unknown kdf alg %q", alg)
+	}
+
+	return entry, nil
+}
+
+// ValidateKDF checks the alg recorded in the EncSymKey is registered and
+// its parameters fall within the limits registered for the alg. It is used
+// to vet UUKs submitted by clients.
+func (esk EncSymKey) ValidateKDF() error {
+	if err := esk.checkKDF(); err != nil {
+		return err
+	}
+
+	entry, err := lookupKDFEntry(esk.Alg)
+	if err != nil {
+		return err
+	}
+
+	l := entry.limits
+
+	if esk.P2c < l.MinIterations || esk.P2c > l.MaxIterations {
+		return fmt.Errorf("kdf iterations %d outside [%d, %d]", esk.P2c, l.MinIterations, l.MaxIterations)
+	}
+
+	if l.MaxMemory > 0 && (esk.Mem < l.MinMemory || esk.Mem > l.MaxMemory) {
+		return fmt.Errorf("kdf memory %d outside [%d, %d]", esk.Mem, l.MinMemory, l.MaxMemory)
+	}
+
+	if l.MaxParallelism > 0 && (esk.Par < l.MinParallelism || esk.Par > l.MaxParallelism) {
+		return fmt.Errorf("kdf parallelism %d outside [%d, %d]", esk.Par, l.MinParallelism, l.MaxParallelism)
+	}
+
+	return nil
+}
+
+// checkKDF checks the alg recorded in the EncSymKey is registered and its
+// parameters are usable.
+func (esk EncSymKey) checkKDF() error {
+	if _, err := lookupKDF(esk.Alg); err != nil {
+		return err
+	}
+
+	if esk.P2c <= 0 {
+		return fmt.Errorf("invalid kdf iterations %d", esk.P2c)
+	}
+
+	if esk.Alg == AlgArgon2id && (esk.Mem == 0 || esk.Par == 0) {
+		return fmt.Errorf("invalid argon2id parameters mem[%d] par[%d]", esk.Mem, esk.Par)
+	}
+
+	return nil
+}
+
+// NeedsUpgrade reports whether the UUK was derived with weaker settings than
+// the specified params and should be re-wrapped on the next unlock.
+func (uuk *UUK) NeedsUpgrade(params KDFParams) bool {
+	esk := uuk.EncSymKey
+
+	if esk.Alg != params.Alg {
+		return true
+	}
+
+	if esk.P2c < params.Iterations {
+		return true
+	}
+
+	if params.Alg == AlgArgon2id && (esk.Mem < params.Memory || esk.Par < params.Parallelism) {
+		return true
+	}
+
+	return false
+}
+
+// withKDFParams records the derivation settings in UUK.EncSymKey.
+func (uuk *UUK) withKDFParams(params KDFParams) {
+	uuk.EncSymKey.Alg = params.Alg
+	uuk.EncSymKey.P2c = params.Iterations
+	uuk.EncSymKey.Mem = 0
+	uuk.EncSymKey.Par = 0
+
+	if params.Alg == AlgArgon2id {
+		uuk.EncSymKey.Mem = params.Memory
+		uuk.EncSymKey.Par = params.Parallelism
+	}
+}
+
+// =============================================================================
+
+func pbkdf2SHA1(password, salt []byte, esk EncSymKey) ([]byte, error) {
+	return pbkdf2.Key(sha1.New, string(password), salt, esk.P2c, 32)
+}
+
+func pbkdf2SHA256(password, salt []byte, esk EncSymKey) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, string(password), salt, esk.P2c, 32)
+}
+
+func argon2id(password, salt []byte, esk EncSymKey) ([]byte, error) {
+	return argon2.IDKey(password, salt, uint32(esk.P2c), esk.Mem, esk.Par, 32), nil
+}
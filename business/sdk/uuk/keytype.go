@@ -0,0 +1,54 @@
+package uuk
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+// Set of key pair types a UUK can be built with.
+const (
+	KeyTypeRSA2048 = "RSA-2048"
+	KeyTypeP256    = "P-256"
+	KeyTypeX25519  = "X25519"
+)
+
+// DefaultKeyType is the key pair type used by Build.
+const DefaultKeyType = KeyTypeRSA2048
+
+// generatePrivateKey creates a private key of the specified key type.
+func generatePrivateKey(keyType string) (any, error) {
+	switch keyType {
+	case KeyTypeRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+
+	case KeyTypeP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	case KeyTypeX25519:
+		return ecdh.X25519().GenerateKey(rand.Reader)
+	}
+
+	return nil, fmt.Errorf("unknown key type %q", keyType)
+}
+
+// keyEncryptionAlg returns the JWE algorithm used to wrap content keys for the
+// specified JWK. RSA keys use RSA-OAEP, EC and OKP keys use ECDH-ES with
+// AES key wrap.
+func keyEncryptionAlg(key jwk.Key) (jwa.KeyEncryptionAlgorithm, error) {
+	switch key.KeyType() {
+	case jwa.RSA():
+		return jwa.RSA_OAEP(), nil
+
+	case jwa.EC(), jwa.OKP():
+		return jwa.ECDH_ES_A256KW(), nil
+	}
+
+	return jwa.KeyEncryptionAlgorithm{}, fmt.Errorf("unsupported key type %q", key.KeyType())
+}
@@ -0,0 +1,285 @@
+package uuk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v3/jwe"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Set of recovery key types.
+const (
+	RecoveryTypeKit   = "kit"
+	RecoveryTypeAdmin = "admin"
+)
+
+// RecoveryKey contains the symmetric key of the UUK wrapped under a recovery
+// key so the UUK can be re-opened when the master password or secret key is lost.
+type RecoveryKey struct {
+	// uuid of the UUK for a kit or the user id of the recovery admin
+	Kid uuid.UUID `json:"kid"`
+	// kit or admin
+	Type string `json:"type"`
+	// encoding used to encrypt the data e.g. A256GCM or JWE
+	Enc string `json:"enc"`
+	// initialization vector, only used for kits
+	Iv string `json:"iv,omitempty"`
+	// encrypted symmetric key
+	Data string `json:"data"`
+}
+
+// RecoveryKit is the emergency kit printed once for a user. The Code is the
+// only copy of the recovery key and is never sent to the server, the Verifier
+// derived from it is used to prove possession of the kit.
+type RecoveryKit struct {
+	Code string
+	key  []byte
+}
+
+var kitEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewRecoveryKit generates a random recovery kit.
+func NewRecoveryKit() (RecoveryKit, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return RecoveryKit{}, fmt.Errorf("error generating recovery key: %s", err)
+	}
+
+	encoded := kitEncoding.EncodeToString(key)
+
+	var groups []string
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:min(i+4, len(encoded))])
+	}
+
+	kit := RecoveryKit{
+		Code: strings.Join(groups, "-"),
+		key:  key,
+	}
+
+	return kit, nil
+}
+
+// ParseRecoveryKit parses the code printed on a recovery kit.
+func ParseRecoveryKit(code string) (RecoveryKit, error) {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	key, err := kitEncoding.DecodeString(normalized)
+	if err != nil || len(key) != 32 {
+		return RecoveryKit{}, fmt.Errorf("invalid recovery kit code")
+	}
+
+	kit := RecoveryKit{
+		Code: code,
+		key:  key,
+	}
+
+	return kit, nil
+}
+
+// Verifier returns the value the server stores a hash of to check a user
+// holds the recovery kit.
+func (kit RecoveryKit) Verifier() (string, error) {
+	v, err := kit.derive("pwmanager-recovery-verifier")
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(v), nil
+}
+
+func (kit RecoveryKit) derive(info string) ([]byte, error) {
+	r := hkdf.New(sha256.New, kit.key, nil, []byte(info))
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// =============================================================================
+
+// AddRecoveryKit unlocks the symmetric key with the password and wraps it
+// under the recovery kit. Any existing kit is replaced.
+func (uuk *UUK) AddRecoveryKit(kit RecoveryKit, password, groupID, secretKey, userID []byte) error {
+	symmetricKey, err := uuk.unlockSymKey(password, groupID, secretKey, userID)
+	if err != nil {
+		return err
+	}
+
+	wrapKey, err := kit.derive("pwmanager-recovery-wrap")
+	if err != nil {
+		return err
+	}
+
+	c, err := aes.NewCipher(wrapKey)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return err
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return err
+	}
+
+	rk := RecoveryKey{
+		Kid:  uuk.UUID,
+		Type: RecoveryTypeKit,
+		Enc:  "A256GCM",
+		Iv:   hex.EncodeToString(iv),
+		Data: hex.EncodeToString(gcm.Seal(nil, iv, symmetricKey, nil)),
+	}
+
+	uuk.setRecoveryKey(rk)
+
+	return nil
+}
+
+// AddRecoveryAdmin unlocks the symmetric key with the password and encrypts
+// it to the public key of a recovery admin. Any existing entry for the admin
+// is replaced.
+func (uuk *UUK) AddRecoveryAdmin(adminID uuid.UUID, adminPubKey any, password, groupID, secretKey, userID []byte) error {
+	symmetricKey, err := uuk.unlockSymKey(password, groupID, secretKey, userID)
+	if err != nil {
+		return err
+	}
+
+	admin := UUK{PubKey: adminPubKey}
+
+	encrypted, err := admin.Encrypt(hex.EncodeToString(symmetricKey))
+	if err != nil {
+		return err
+	}
+
+	rk := RecoveryKey{
+		Kid:  adminID,
+		Type: RecoveryTypeAdmin,
+		Enc:  "JWE",
+		Data: string(encrypted),
+	}
+
+	uuk.setRecoveryKey(rk)
+
+	return nil
+}
+
+// HasRecoveryAdmin reports whether the symmetric key is wrapped to the
+// specified recovery admin.
+func (uuk *UUK) HasRecoveryAdmin(adminID uuid.UUID) bool {
+	_, exists := uuk.recoveryKey(RecoveryTypeAdmin, adminID)
+	return exists
+}
+
+// HasRecoveryKit reports whether the symmetric key is wrapped under a kit.
+func (uuk *UUK) HasRecoveryKit() bool {
+	_, exists := uuk.recoveryKey(RecoveryTypeKit, uuk.UUID)
+	return exists
+}
+
+// RecoverWithKit unwraps the symmetric key with the recovery kit and wraps it
+// under a 2SKD derived from the new password and secret key. The key pair and
+// recovery entries are left untouched.
+func (uuk *UUK) RecoverWithKit(kit RecoveryKit, newPassword, groupID, secretKey, userID []byte) error {
+	rk, exists := uuk.recoveryKey(RecoveryTypeKit, uuk.UUID)
+	if !exists {
+		return fmt.Errorf("uuk has no recovery kit")
+	}
+
+	wrapKey, err := kit.derive("pwmanager-recovery-wrap")
+	if err != nil {
+		return err
+	}
+
+	c, err := aes.NewCipher(wrapKey)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return err
+	}
+
+	iv, err := hex.DecodeString(rk.Iv)
+	if err != nil {
+		return fmt.Errorf("error decoding recovery iv: %s", err)
+	}
+
+	data, err := hex.DecodeString(rk.Data)
+	if err != nil {
+		return fmt.Errorf("error decoding recovery data: %s", err)
+	}
+
+	symmetricKey, err := gcm.Open(nil, iv, data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt symmetric key: %s", err)
+	}
+
+	return uuk.rewrapSymKey(DefaultKDFParams, symmetricKey, newPassword, groupID, secretKey, userID)
+}
+
+// RecoverWithAdmin decrypts the symmetric key with the private key of the
+// recovery admin and wraps it under a 2SKD derived from the new password and
+// secret key chosen for the user.
+func (uuk *UUK) RecoverWithAdmin(adminID uuid.UUID, adminPriKey jwk.Key, newPassword, groupID, secretKey, userID []byte) error {
+	rk, exists := uuk.recoveryKey(RecoveryTypeAdmin, adminID)
+	if !exists {
+		return fmt.Errorf("uuk has no recovery key for admin %s", adminID)
+	}
+
+	alg, err := keyEncryptionAlg(adminPriKey)
+	if err != nil {
+		return err
+	}
+
+	decrypted, err := jwe.Decrypt([]byte(rk.Data), jwe.WithKey(alg, adminPriKey))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt symmetric key: %s", err)
+	}
+
+	symmetricKey, err := hex.DecodeString(string(decrypted))
+	if err != nil {
+		return fmt.Errorf("error decoding symmetric key: %s", err)
+	}
+
+	return uuk.rewrapSymKey(DefaultKDFParams, symmetricKey, newPassword, groupID, secretKey, userID)
+}
+
+func (uuk *UUK) recoveryKey(typ string, kid uuid.UUID) (RecoveryKey, bool) {
+	for _, rk := range uuk.Recovery {
+		if rk.Type == typ && rk.Kid == kid {
+			return rk, true
+		}
+	}
+
+	return RecoveryKey{}, false
+}
+
+func (uuk *UUK) setRecoveryKey(rk RecoveryKey) {
+	recovery := make([]RecoveryKey, 0, len(uuk.Recovery)+1)
+	for _, existing := range uuk.Recovery {
+		if existing.Type == rk.Type && existing.Kid == rk.Kid {
+			continue
+		}
+		recovery = append(recovery, existing)
+	}
+
+	uuk.Recovery = append(recovery, rk)
+}
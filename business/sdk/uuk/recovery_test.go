@@ -0,0 +1,115 @@
+package uuk_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+func TestRecovery(t *testing.T) {
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	newSecretKey := make([]byte, 32)
+	for i := range 32 {
+		newSecretKey[i] = byte('B')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	opts := uuk.BuildOptions{
+		KDF:     uuk.KDFParams{Alg: uuk.AlgPBKDF2SHA256, Iterations: 1000},
+		KeyType: uuk.KeyTypeP256,
+	}
+
+	u := uuk.UUK{}
+	if err := u.BuildWithOptions(opts, []byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to build the uuk: %s", err)
+	}
+
+	priKey, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Kit
+
+	kit, err := uuk.NewRecoveryKit()
+	if err != nil {
+		t.Fatalf("Should be able to create a recovery kit: %s", err)
+	}
+
+	if err := u.AddRecoveryKit(kit, []byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to add the recovery kit: %s", err)
+	}
+
+	printed, err := uuk.ParseRecoveryKit(kit.Code)
+	if err != nil {
+		t.Fatalf("Should be able to parse the recovery kit: %s", err)
+	}
+
+	v1, _ := kit.Verifier()
+	v2, _ := printed.Verifier()
+	if v1 != v2 {
+		t.Fatalf("Should derive the same verifier from the printed kit")
+	}
+
+	other, _ := uuk.NewRecoveryKit()
+	if err := u.RecoverWithKit(other, []byte("rustaceans"), groupID, newSecretKey, userID); err == nil {
+		t.Fatalf("Should not be able to recover with a different kit")
+	}
+
+	if err := u.RecoverWithKit(printed, []byte("rustaceans"), groupID, newSecretKey, userID); err != nil {
+		t.Fatalf("Should be able to recover with the kit: %s", err)
+	}
+
+	recovered, err := u.DecryptEncPriKey([]byte("rustaceans"), groupID, newSecretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key after recovery: %s", err)
+	}
+
+	if !jwk.Equal(priKey, recovered) {
+		t.Fatalf("Should decrypt the same private key after recovery")
+	}
+
+	// -------------------------------------------------------------------------
+	// Admin
+
+	adminID := uuid.New()
+	admin := uuk.UUK{}
+	if err := admin.BuildWithOptions(uuk.BuildOptions{KDF: opts.KDF, KeyType: uuk.KeyTypeX25519}, []byte("admin"), groupID, secretKey, []byte(adminID.String())); err != nil {
+		t.Fatalf("Should be able to build the admin uuk: %s", err)
+	}
+
+	adminPriKey, err := admin.DecryptEncPriKey([]byte("admin"), groupID, secretKey, []byte(adminID.String()))
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the admin private key: %s", err)
+	}
+
+	if err := u.AddRecoveryAdmin(adminID, admin.PubKey, []byte("rustaceans"), groupID, newSecretKey, userID); err != nil {
+		t.Fatalf("Should be able to add the recovery admin: %s", err)
+	}
+
+	if !u.HasRecoveryAdmin(adminID) || !u.HasRecoveryKit() {
+		t.Fatalf("Should have both recovery keys")
+	}
+
+	if err := u.RecoverWithAdmin(adminID, adminPriKey, []byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to recover with the admin key: %s", err)
+	}
+
+	recovered, err = u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key after admin recovery: %s", err)
+	}
+
+	if !jwk.Equal(priKey, recovered) {
+		t.Fatalf("Should decrypt the same private key after admin recovery")
+	}
+}
@@ -3,10 +3,7 @@ package uuk
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/pbkdf2"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -14,7 +11,6 @@ import (
 	"io"
 
 	"github.com/google/uuid"
-	"github.com/lestrrat-go/jwx/jwa"
 	"github.com/lestrrat-go/jwx/v3/jwe"
 	"github.com/lestrrat-go/jwx/v3/jwk"
 	"golang.org/x/crypto/hkdf"
@@ -47,9 +43,14 @@ type EncSymKey struct {
 	// content type
 	Cty string `json:"cty" mapstructure:"cty"`
 	// the algorithm used to encrypt the EncSymKey e.g. 2SKD PBDKF2-HKDF
+	// used to look up the KDF in the registry
 	Alg string `json:"alg" mapstructure:"alg"`
-	// PBDKF2 iterations e.g. 650000
+	// PBDKF2 iterations e.g. 650000 or Argon2id time cost e.g. 3
 	P2c int `json:"p2c" mapstructure:"p2c"`
+	// Argon2id memory in KiB e.g. 65536
+	Mem uint32 `json:"mem,omitempty" mapstructure:"mem"`
+	// Argon2id parallelism e.g. 4
+	Par uint8 `json:"par,omitempty" mapstructure:"par"`
 	// initial 16 byte random sequence for secret key derivation.
 	// used in the first hkdf function call
 	P2s string `json:"p2s" mapstructure:"p2s"`
@@ -69,6 +70,8 @@ type UUK struct {
 	EncPriKey EncPriKey `json:"enc_pri_key"`
 	// pub key of the private key
 	PubKey interface{} `json:"pub_key"`
+	// symmetric key wrapped under recovery keys, see AddRecoveryKit
+	Recovery []RecoveryKey `json:"recovery,omitempty"`
 }
 
 // withInitializationSalt generates a random 16 byte salt and stores the result in UUK.EncSymKey.P2s
@@ -85,11 +88,6 @@ func (uuk *UUK) withInitializationSalt() error {
 	return nil
 }
 
-// withPasswordIterations sets the iteration count used in the 2SKD func
-func (uuk *UUK) withPasswordIterations(iterations int) {
-	uuk.EncSymKey.P2c = iterations
-}
-
 // withEncSymKey creates a symmetric key, encrypts it using the 2SKD key and stores the encrypted
 // value in UUK.EncSymKey.Data, sets non secret attributes in UUK.EncSymKey.
 // Returns the plaintext symmetric key to be used for encrypting the UUK.EncPriKey
@@ -100,23 +98,33 @@ func (uuk *UUK) withEncSymKey(twoSKD []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if err := uuk.wrapSymKey(twoSKD, symmetricKey); err != nil {
+		return nil, err
+	}
+
+	return symmetricKey, nil
+}
+
+// wrapSymKey encrypts the symmetric key using the 2SKD key and stores the encrypted
+// value in UUK.EncSymKey.Data, sets non secret attributes in UUK.EncSymKey.
+func (uuk *UUK) wrapSymKey(twoSKD, symmetricKey []byte) error {
 	//16, 24, or 32 bytes to select
 	// AES-128, AES-192, or AES-256.
 	// Since symmetric key is 32 bytes this is AES-256
 	c, err := aes.NewCipher(twoSKD)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	gcm, err := cipher.NewGCM(c)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	iv := make([]byte, gcm.NonceSize())
 	_, err = io.ReadFull(rand.Reader, iv)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	encSymKeyIvPrefix := gcm.Seal(iv, iv, symmetricKey, nil)
@@ -127,19 +135,49 @@ func (uuk *UUK) withEncSymKey(twoSKD []byte) ([]byte, error) {
 	uuk.EncSymKey.Iv = hex.EncodeToString(iv)
 	uuk.EncSymKey.Enc = "A256GCM"
 	uuk.EncSymKey.Kid = uuk.UUID
-	uuk.EncSymKey.Alg = "pbkdf2-hkdf"
+
+	return nil
+}
+
+// unwrapSymKey decrypts UUK.EncSymKey.Data using the 2SKD key and returns
+// the plaintext symmetric key.
+func (uuk *UUK) unwrapSymKey(twoSKD []byte) ([]byte, error) {
+	twoSkdCipher, err := aes.NewCipher(twoSKD)
+	if err != nil {
+		return nil, err
+	}
+
+	twoSkdGcm, err := cipher.NewGCM(twoSkdCipher)
+	if err != nil {
+		return nil, err
+	}
+
+	symIv, err := hex.DecodeString(uuk.EncSymKey.Iv)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding symmetric iv: %s", err)
+	}
+
+	encSymKey, err := hex.DecodeString(uuk.EncSymKey.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding symmetric data: %s", err)
+	}
+
+	symmetricKey, err := twoSkdGcm.Open(nil, symIv, encSymKey, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return symmetricKey, nil
 }
 
 // withEncPriKey encrypts creates a private key and encrypts it using the symmetric
 // key stored in UUK.EncSymKey, stores the encrypted value in UUK.EncPriKey.Data, sets
-// non secret attributes in UUK.EncPriKey, and returns the rsa private key
-func (uuk *UUK) withEncPriKey(symmetricKey []byte) (*rsa.PrivateKey, error) {
+// non secret attributes in UUK.EncPriKey, and returns the private key
+func (uuk *UUK) withEncPriKey(symmetricKey []byte, keyType string) (any, error) {
 	//------------------------------------------------------------
 	// Private Key
-	// Generate an RSA private key
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	// Generate a private key of the requested type
+	privateKey, err := generatePrivateKey(keyType)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +224,7 @@ func (uuk *UUK) withEncPriKey(symmetricKey []byte) (*rsa.PrivateKey, error) {
 }
 
 // withPubKey extracts the pubKey from the private key and assigns it to UUK.PubKey
-func (uuk *UUK) withPubKey(prikey *rsa.PrivateKey) error {
+func (uuk *UUK) withPubKey(prikey any) error {
 	jwkPriKey, err := jwk.Import(prikey)
 	if err != nil {
 		return err
@@ -220,9 +258,19 @@ func (uuk *UUK) twoSkd(password, groupID, secretKey, userID []byte) ([]byte, err
 		return nil, err
 	}
 
-	// pbkdf2
-	keyLen := 32
-	passwordDerivedKey, err := pbkdf2.Key(sha1.New, string(password), saltDerivedKey, uuk.EncSymKey.P2c, keyLen)
+	// pbkdf2 or argon2id depending on the alg. Only the structure is checked
+	// so UUKs derived below the current limits can still be unlocked and
+	// upgraded.
+	if err := uuk.EncSymKey.checkKDF(); err != nil {
+		return nil, err
+	}
+
+	kdf, err := lookupKDF(uuk.EncSymKey.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordDerivedKey, err := kdf(password, saltDerivedKey, uuk.EncSymKey)
 	if err != nil {
 		return nil, err
 	}
@@ -257,9 +305,11 @@ func (uuk *UUK) twoSkd(password, groupID, secretKey, userID []byte) ([]byte, err
 //	      "iv": "05e89fa122ecae403feda8dd",
 //	      "data": "95af1a39e798f9f4bcd401c778f2f4ed40eb9f56da8c8f9a1d7b0601777b37bfd2c369af0076f5b94e4be1622003fa8b",
 //	      "cty": "",
-//	      "alg": "pbkdf2-hkdf",
-//	      "p2c": 650000,
+//	      "alg": "argon2id-hkdf",
+//	      "p2c": 3,
 //	      "p2s": "26d6ee5149c95425a0251651f8b07ac0",
+//	      "mem": 65536,
+//	      "par": 4,
 //	    },
 //	  "encrypted_by": "mp",
 //	  "enc_pri_key":
@@ -279,14 +329,30 @@ func (uuk *UUK) twoSkd(password, groupID, secretKey, userID []byte) ([]byte, err
 //	    },
 //	}
 func (uuk *UUK) Build(password, groupID, secretKey, userID []byte) error {
+	return uuk.BuildWithOptions(BuildOptions{KDF: DefaultKDFParams, KeyType: DefaultKeyType}, password, groupID, secretKey, userID)
+}
+
+// BuildWithKDF fills in uuk like Build using the specified key derivation settings.
+func (uuk *UUK) BuildWithKDF(params KDFParams, password, groupID, secretKey, userID []byte) error {
+	return uuk.BuildWithOptions(BuildOptions{KDF: params, KeyType: DefaultKeyType}, password, groupID, secretKey, userID)
+}
+
+// BuildOptions represents the settings used to build a UUK.
+type BuildOptions struct {
+	KDF     KDFParams
+	KeyType string
+}
 
+// BuildWithOptions fills in uuk like Build using the specified key derivation
+// settings and key pair type.
+func (uuk *UUK) BuildWithOptions(opts BuildOptions, password, groupID, secretKey, userID []byte) error {
 	uuk.UUID = uuid.New()
 
 	if err := uuk.withInitializationSalt(); err != nil {
 		return err
 	}
 
-	uuk.withPasswordIterations(650000)
+	uuk.withKDFParams(opts.KDF)
 
 	twoSKD, err := uuk.twoSkd(password, groupID, secretKey, userID)
 	if err != nil {
@@ -298,7 +364,7 @@ func (uuk *UUK) Build(password, groupID, secretKey, userID []byte) error {
 		return err
 	}
 
-	priKey, err := uuk.withEncPriKey(symmetricKey)
+	priKey, err := uuk.withEncPriKey(symmetricKey, opts.KeyType)
 	if err != nil {
 		return err
 	}
@@ -319,27 +385,8 @@ func (uuk *UUK) DecryptEncPriKey(password, groupID, secretKey, userID []byte) (j
 	if err != nil {
 		return nil, fmt.Errorf("failed to create 2SKD %s", err)
 	}
-	twoSkdCipher, err := aes.NewCipher(twoSKD)
-	if err != nil {
-		return nil, err
-	}
-
-	twoSkdGcm, err := cipher.NewGCM(twoSkdCipher)
-	if err != nil {
-		return nil, err
-	}
-
-	symIv, err := hex.DecodeString(uuk.EncSymKey.Iv)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding symmetric iv: %s", err)
-	}
-
-	encSymKey, err := hex.DecodeString(uuk.EncSymKey.Data)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding symmetric data: %s", err)
-	}
 
-	symmetricKey, err := twoSkdGcm.Open(nil, symIv, encSymKey, nil)
+	symmetricKey, err := uuk.unwrapSymKey(twoSKD)
 	if err != nil {
 		return nil, err
 	}
@@ -379,9 +426,94 @@ func (uuk *UUK) DecryptEncPriKey(password, groupID, secretKey, userID []byte) (j
 	return priKey, nil
 }
 
+// ChangePassword re-wraps the EncSymKey under a 2SKD derived from the new password
+// and a fresh salt. The symmetric key, EncPriKey and PubKey are left untouched so
+// data encrypted with the users key pair, e.g. bundle keys, doesn't need to be
+// re-encrypted. The UUK is only modified when the old password unlocks it.
+// The current KDF settings are kept, use Upgrade to change them.
+func (uuk *UUK) ChangePassword(oldPassword, newPassword, groupID, secretKey, userID []byte) error {
+	params := KDFParams{
+		Alg:         uuk.EncSymKey.Alg,
+		Iterations:  uuk.EncSymKey.P2c,
+		Memory:      uuk.EncSymKey.Mem,
+		Parallelism: uuk.EncSymKey.Par,
+	}
+
+	return uuk.rewrap(params, oldPassword, newPassword, groupID, secretKey, userID)
+}
+
+// Upgrade re-wraps the EncSymKey using the specified KDF settings. It is meant to
+// be called after a successful unlock when NeedsUpgrade reports true.
+func (uuk *UUK) Upgrade(params KDFParams, password, groupID, secretKey, userID []byte) error {
+	return uuk.rewrap(params, password, password, groupID, secretKey, userID)
+}
+
+// rewrap unlocks the symmetric key with the old password and wraps it again
+// under a 2SKD derived from the new password, a fresh salt and the params.
+func (uuk *UUK) rewrap(params KDFParams, oldPassword, newPassword, groupID, secretKey, userID []byte) error {
+	symmetricKey, err := uuk.unlockSymKey(oldPassword, groupID, secretKey, userID)
+	if err != nil {
+		return err
+	}
+
+	return uuk.rewrapSymKey(params, symmetricKey, newPassword, groupID, secretKey, userID)
+}
+
+// unlockSymKey derives the 2SKD from the password and returns the plaintext
+// symmetric key.
+func (uuk *UUK) unlockSymKey(password, groupID, secretKey, userID []byte) ([]byte, error) {
+	twoSKD, err := uuk.twoSkd(password, groupID, secretKey, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create 2SKD %s", err)
+	}
+
+	symmetricKey, err := uuk.unwrapSymKey(twoSKD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt symmetric key: %s", err)
+	}
+
+	return symmetricKey, nil
+}
+
+// rewrapSymKey wraps the symmetric key under a 2SKD derived from the new
+// password, a fresh salt and the params. The UUK is only modified on success.
+func (uuk *UUK) rewrapSymKey(params KDFParams, symmetricKey, newPassword, groupID, secretKey, userID []byte) error {
+	changed := *uuk
+
+	if err := changed.withInitializationSalt(); err != nil {
+		return err
+	}
+
+	changed.withKDFParams(params)
+
+	newTwoSKD, err := changed.twoSkd(newPassword, groupID, secretKey, userID)
+	if err != nil {
+		return fmt.Errorf("failed to create 2SKD %s", err)
+	}
+
+	if err := changed.wrapSymKey(newTwoSKD, symmetricKey); err != nil {
+		return fmt.Errorf("failed to encrypt symmetric key: %s", err)
+	}
+
+	*uuk = changed
+
+	return nil
+}
+
 // Convenience func to encrypt data encrypted with users pubkey
+// the JWE algorithm is chosen from the key type of the pubkey
 func (uuk *UUK) Encrypt(payload string) ([]byte, error) {
-	encrypted, err := jwe.Encrypt([]byte(payload), jwe.WithKey(jwa.RSA_OAEP, uuk.PubKey))
+	pubKey, err := uuk.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := keyEncryptionAlg(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := jwe.Encrypt([]byte(payload), jwe.WithKey(alg, pubKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt payload: %s", err)
 	}
@@ -390,7 +522,12 @@ func (uuk *UUK) Encrypt(payload string) ([]byte, error) {
 
 // Convenience func to decrypt data encrypted with users prikey
 func (uuk *UUK) Decrypt(encrypted []byte, priKey jwk.Key) ([]byte, error) {
-	decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(jwa.RSA_OAEP, priKey))
+	alg, err := keyEncryptionAlg(priKey)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(alg, priKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt payload: %s", err)
 
@@ -421,3 +558,72 @@ func (uuk *UUK) Map() (map[string]interface{}, error) {
 
 	return m, nil
 }
+
+// publicKey returns UUK.PubKey as a JWK. PubKey is a jwk.Key after Build but
+// a generic map when the UUK was decoded from JSON.
+func (uuk *UUK) publicKey() (jwk.Key, error) {
+	if key, ok := uuk.PubKey.(jwk.Key); ok {
+		return key, nil
+	}
+
+	data, err := json.Marshal(uuk.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshal pubkey: %w", err)
+	}
+
+	key, err := jwk.ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse pubkey: %w", err)
+	}
+
+	return key, nil
+}
+
+// SamePublicKey reports whether other carries the same public key as the UUK.
+// Keys are compared by their JWK thumbprint so a key decoded from JSON equals
+// the key it was encoded from.
+func (uuk *UUK) SamePublicKey(other UUK) bool {
+	key, err := uuk.publicKey()
+	if err != nil {
+		return false
+	}
+
+	otherKey, err := other.publicKey()
+	if err != nil {
+		return false
+	}
+
+	return jwk.Equal(key, otherKey)
+}
+
+// Validate checks the UUK carries a public key that parses as a JWK and an
+// encrypted private key.
+func (uuk *UUK) Validate() error {
+	if uuk.UUID == uuid.Nil {
+		return fmt.Errorf("uuk is missing a uuid")
+	}
+
+	key, err := uuk.publicKey()
+	if err != nil {
+		return err
+	}
+
+	private, err := jwk.IsPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("check pubkey: %w", err)
+	}
+
+	if private {
+		return fmt.Errorf("pubkey is not a public key")
+	}
+
+	if uuk.EncPriKey.Data == "" {
+		return fmt.Errorf("uuk is missing an encrypted private key")
+	}
+
+	if _, err := hex.DecodeString(uuk.EncPriKey.Data); err != nil {
+		return fmt.Errorf("decode encrypted private key: %w", err)
+	}
+
+	return nil
+}
@@ -1,11 +1,13 @@
 package uuk_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/gradientsearch/pwmanager/business/sdk/uuk"
+	"github.com/lestrrat-go/jwx/v3/jwk"
 )
 
 type TestUsersSecrets struct {
@@ -34,3 +36,267 @@ func TestUUK(t *testing.T) {
 	uuk.Build([]byte(userSecrets.Password), []byte(groupID), userSecrets.SecretKey, []byte(userID))
 	fmt.Printf("%+v", uuk)
 }
+
+func TestChangePassword(t *testing.T) {
+	u := uuk.UUK{}
+
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	if err := u.Build([]byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to build the uuk: %s", err)
+	}
+
+	priKey, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key: %s", err)
+	}
+
+	encPriKey := u.EncPriKey
+
+	if err := u.ChangePassword([]byte("wrong"), []byte("rustaceans"), groupID, secretKey, userID); err == nil {
+		t.Fatalf("Should not be able to change the password with the wrong old password")
+	}
+
+	if err := u.ChangePassword([]byte("gophers"), []byte("rustaceans"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to change the password: %s", err)
+	}
+
+	if u.EncPriKey != encPriKey {
+		t.Fatalf("Should not modify the encrypted private key")
+	}
+
+	if _, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID); err == nil {
+		t.Fatalf("Should not be able to decrypt the private key with the old password")
+	}
+
+	newPriKey, err := u.DecryptEncPriKey([]byte("rustaceans"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key with the new password: %s", err)
+	}
+
+	if !jwk.Equal(priKey, newPriKey) {
+		t.Fatalf("Should decrypt the same private key")
+	}
+}
+
+func TestKDF(t *testing.T) {
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	params := []uuk.KDFParams{
+		{Alg: uuk.AlgPBKDF2SHA1, Iterations: 1000},
+		{Alg: uuk.AlgPBKDF2SHA256, Iterations: 1000},
+		{Alg: uuk.AlgArgon2id, Iterations: 1, Memory: 8 * 1024, Parallelism: 1},
+	}
+
+	for _, p := range params {
+		t.Run(p.Alg, func(t *testing.T) {
+			u := uuk.UUK{}
+			if err := u.BuildWithKDF(p, []byte("gophers"), groupID, secretKey, userID); err != nil {
+				t.Fatalf("Should be able to build the uuk: %s", err)
+			}
+
+			if u.EncSymKey.Alg != p.Alg {
+				t.Fatalf("Should record the alg, got %q exp %q", u.EncSymKey.Alg, p.Alg)
+			}
+
+			if _, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID); err != nil {
+				t.Fatalf("Should be able to decrypt the private key: %s", err)
+			}
+		})
+	}
+
+	u := uuk.UUK{}
+	if err := u.BuildWithKDF(uuk.KDFParams{Alg: "scrypt", Iterations: 1}, []byte("gophers"), groupID, secretKey, userID); err == nil {
+		t.Fatalf("Should not be able to build the uuk with an unknown alg")
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	u := uuk.UUK{}
+	if err := u.BuildWithKDF(uuk.KDFParams{Alg: uuk.AlgPBKDF2SHA1, Iterations: 1000}, []byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to build the uuk: %s", err)
+	}
+
+	priKey, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key: %s", err)
+	}
+
+	target := uuk.KDFParams{Alg: uuk.AlgArgon2id, Iterations: 1, Memory: 8 * 1024, Parallelism: 1}
+
+	if !u.NeedsUpgrade(target) {
+		t.Fatalf("Should need an upgrade")
+	}
+
+	if err := u.Upgrade(target, []byte("gophers"), groupID, secretKey, userID); err != nil {
+		t.Fatalf("Should be able to upgrade the uuk: %s", err)
+	}
+
+	if u.NeedsUpgrade(target) {
+		t.Fatalf("Should not need an upgrade")
+	}
+
+	newPriKey, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+	if err != nil {
+		t.Fatalf("Should be able to decrypt the private key after the upgrade: %s", err)
+	}
+
+	if !jwk.Equal(priKey, newPriKey) {
+		t.Fatalf("Should decrypt the same private key")
+	}
+}
+
+func TestKeyType(t *testing.T) {
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	kdf := uuk.KDFParams{Alg: uuk.AlgPBKDF2SHA256, Iterations: 1000}
+
+	for _, keyType := range []string{uuk.KeyTypeRSA2048, uuk.KeyTypeP256, uuk.KeyTypeX25519} {
+		t.Run(keyType, func(t *testing.T) {
+			u := uuk.UUK{}
+			if err := u.BuildWithOptions(uuk.BuildOptions{KDF: kdf, KeyType: keyType}, []byte("gophers"), groupID, secretKey, userID); err != nil {
+				t.Fatalf("Should be able to build the uuk: %s", err)
+			}
+
+			priKey, err := u.DecryptEncPriKey([]byte("gophers"), groupID, secretKey, userID)
+			if err != nil {
+				t.Fatalf("Should be able to decrypt the private key: %s", err)
+			}
+
+			// Round trip the uuk through JSON like a stored uuk.
+			data, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("Should be able to marshal the uuk: %s", err)
+			}
+
+			var stored uuk.UUK
+			if err := json.Unmarshal(data, &stored); err != nil {
+				t.Fatalf("Should be able to unmarshal the uuk: %s", err)
+			}
+
+			encrypted, err := stored.Encrypt("bundle key")
+			if err != nil {
+				t.Fatalf("Should be able to encrypt with the public key: %s", err)
+			}
+
+			decrypted, err := stored.Decrypt(encrypted, priKey)
+			if err != nil {
+				t.Fatalf("Should be able to decrypt with the private key: %s", err)
+			}
+
+			if string(decrypted) != "bundle key" {
+				t.Fatalf("Should decrypt the payload, got %q", decrypted)
+			}
+		})
+	}
+}
+
+func TestSamePublicKey(t *testing.T) {
+	secretKey := make([]byte, 32)
+	for i := range 32 {
+		secretKey[i] = byte('A')
+	}
+
+	groupID := []byte(uuid.New().String())
+	userID := []byte(uuid.New().String())
+
+	kdf := uuk.KDFParams{Alg: uuk.AlgPBKDF2SHA256, Iterations: 1000}
+
+	for _, keyType := range []string{uuk.KeyTypeRSA2048, uuk.KeyTypeP256, uuk.KeyTypeX25519} {
+		t.Run(keyType, func(t *testing.T) {
+			opts := uuk.BuildOptions{KDF: kdf, KeyType: keyType}
+
+			u := uuk.UUK{}
+			if err := u.BuildWithOptions(opts, []byte("gophers"), groupID, secretKey, userID); err != nil {
+				t.Fatalf("Should be able to build the uuk: %s", err)
+			}
+
+			data, err := json.Marshal(u)
+			if err != nil {
+				t.Fatalf("Should be able to marshal the uuk: %s", err)
+			}
+
+			var stored uuk.UUK
+			if err := json.Unmarshal(data, &stored); err != nil {
+				t.Fatalf("Should be able to unmarshal the uuk: %s", err)
+			}
+
+			if !u.SamePublicKey(stored) {
+				t.Fatalf("Should match the public key decoded from JSON")
+			}
+
+			if err := stored.Validate(); err != nil {
+				t.Fatalf("Should validate the uuk decoded from JSON: %s", err)
+			}
+
+			other := uuk.UUK{}
+			if err := other.BuildWithOptions(opts, []byte("gophers"), groupID, secretKey, userID); err != nil {
+				t.Fatalf("Should be able to build the other uuk: %s", err)
+			}
+
+			swapped := stored
+			swapped.PubKey = other.PubKey
+
+			if stored.SamePublicKey(swapped) {
+				t.Fatalf("Should not match a different public key")
+			}
+		})
+	}
+}
+
+func TestValidateKDF(t *testing.T) {
+	tests := []struct {
+		name  string
+		esk   uuk.EncSymKey
+		valid bool
+	}{
+		{"default", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 3, Mem: 64 * 1024, Par: 4}, true},
+		{"pbkdf2", uuk.EncSymKey{Alg: uuk.AlgPBKDF2SHA256, P2c: 650000}, true},
+		{"unknown-alg", uuk.EncSymKey{Alg: "scrypt", P2c: 1}, false},
+		{"pbkdf2-few-iterations", uuk.EncSymKey{Alg: uuk.AlgPBKDF2SHA256, P2c: 1000}, false},
+		{"pbkdf2-many-iterations", uuk.EncSymKey{Alg: uuk.AlgPBKDF2SHA1, P2c: 100_000_000}, false},
+		{"argon2id-low-memory", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 3, Mem: 1024, Par: 4}, false},
+		{"argon2id-high-memory", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 3, Mem: 4 * 1024 * 1024, Par: 4}, false},
+		{"argon2id-no-parallelism", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 3, Mem: 64 * 1024, Par: 0}, false},
+		{"argon2id-high-parallelism", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 3, Mem: 64 * 1024, Par: 255}, false},
+		{"argon2id-many-iterations", uuk.EncSymKey{Alg: uuk.AlgArgon2id, P2c: 1000, Mem: 64 * 1024, Par: 4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.esk.ValidateKDF()
+			if tt.valid && err != nil {
+				t.Fatalf("Should accept the kdf parameters: %s", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatalf("Should reject the kdf parameters")
+			}
+		})
+	}
+}
@@ -1,14 +1,63 @@
-// Package entry represents a entry in the system.
+// Package entry represents an encrypted entry in the system.
 package entry
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"regexp"
 )
 
-// Entry represents a entry in the system.
+// Set of supported content encryption algorithms.
+const (
+	EncA256GCM = "A256GCM"
+	EncXC20P   = "XC20P"
+)
+
+// nonceSizes maps the supported content encryption algorithms to the size of
+// their initialization vector in bytes.
+var nonceSizes = map[string]int{
+	EncA256GCM: 12,
+	EncXC20P:   24,
+}
+
+// tagSize is the size of the authentication tag in bytes both algorithms
+// append to the ciphertext.
+const tagSize = 16
+
+// Version is the current version of the envelope.
+const Version = 1
+
+// LegacyVersion marks an envelope wrapping alphanumeric data stored before
+// the envelope format existed. Legacy entries can be read but not written by
+// clients, who are expected to re-encrypt them into the current version.
+const LegacyVersion = 0
+
+// DefaultMaxSize is the default limit of an encoded entry in bytes.
+const DefaultMaxSize = 64 << 10
+
+// =============================================================================
+
+// envelope is the encoded form of an entry. The iv and data are base64url
+// encoded without padding, as in JWE.
+type envelope struct {
+	Version       int    `json:"v"`
+	Enc           string `json:"enc"`
+	KeyGeneration int    `json:"kg"`
+	IV            string `json:"iv"`
+	Data          string `json:"data"`
+}
+
+// Entry represents an encrypted entry in the system. The value is a versioned
+// JSON envelope holding the ciphertext and what is needed to decrypt it:
+//
+//	{"v":1,"enc":"A256GCM","kg":1,"iv":"<base64url>","data":"<base64url>"}
+//
+// Only the structure of the envelope is validated, the plaintext is never
+// seen by the system.
 type Entry struct {
 	value string
+	env   envelope
 }
 
 // String returns the value of the entry.
@@ -16,6 +65,23 @@ func (n Entry) String() string {
 	return n.value
 }
 
+// Enc returns the content encryption algorithm of the entry.
+func (n Entry) Enc() string {
+	return n.env.Enc
+}
+
+// Legacy reports whether the entry wraps data stored before the envelope
+// format existed.
+func (n Entry) Legacy() bool {
+	return n.env.Version == LegacyVersion
+}
+
+// KeyGeneration returns the bundle key generation the entry is encrypted
+// under.
+func (n Entry) KeyGeneration() int {
+	return n.env.KeyGeneration
+}
+
 // Equal provides support for the go-cmp package and testing.
 func (n Entry) Equal(n2 Entry) bool {
 	return n.value == n2.value
@@ -28,16 +94,71 @@ func (n Entry) MarshalText() ([]byte, error) {
 
 // =============================================================================
 
-var entryRegEx = regexp.MustCompile("^[a-zA-Z0-9]{1,600}$")
+// New constructs an entry from the ciphertext of the data encrypted with the
+// specified algorithm under the bundle key generation.
+func New(enc string, keyGeneration int, iv []byte, data []byte) (Entry, error) {
+	env := envelope{
+		Version:       Version,
+		Enc:           enc,
+		KeyGeneration: keyGeneration,
+		IV:            base64.RawURLEncoding.EncodeToString(iv),
+		Data:          base64.RawURLEncoding.EncodeToString(data),
+	}
+
+	return fromEnvelope(env)
+}
+
+// MustNew constructs an entry from the ciphertext of the data. If an error
+// occurs the function panics.
+func MustNew(enc string, keyGeneration int, iv []byte, data []byte) Entry {
+	entry, err := New(enc, keyGeneration, iv, data)
+	if err != nil {
+		panic(err)
+	}
+
+	return entry
+}
 
 // Parse parses the string value and returns a entry if the value complies
-// with the rules for a entry.
+// with the rules for a entry. Only the structure of the envelope is checked,
+// so entries stored under a larger limit, or before the envelope format
+// existed, can always be read back. Values received from clients are parsed
+// with ParseLimit.
 func Parse(value string) (Entry, error) {
-	if !entryRegEx.MatchString(value) {
-		return Entry{}, fmt.Errorf("invalid entry %q", value)
+	env, err := decode(value)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if env.Version == LegacyVersion {
+		return fromLegacyEnvelope(env)
+	}
+
+	return fromEnvelope(env)
+}
+
+// ParseLimit parses the string value and returns a entry if the value is a
+// current version envelope no larger than maxSize bytes.
+func ParseLimit(value string, maxSize int) (Entry, error) {
+	if len(value) > maxSize {
+		return Entry{}, fmt.Errorf("invalid entry: size %d exceeds %d bytes", len(value), maxSize)
+	}
+
+	env, err := decode(value)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry, err := fromEnvelope(env)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if len(entry.value) > maxSize {
+		return Entry{}, fmt.Errorf("invalid entry: size %d exceeds %d bytes", len(entry.value), maxSize)
 	}
 
-	return Entry{value}, nil
+	return entry, nil
 }
 
 // MustParse parses the string value and returns a entry if the value
@@ -51,6 +172,93 @@ func MustParse(value string) Entry {
 	return entry
 }
 
+// decode decodes the string value into an envelope.
+func decode(value string) (envelope, error) {
+	d := json.NewDecoder(bytes.NewReader([]byte(value)))
+	d.DisallowUnknownFields()
+
+	var env envelope
+	if err := d.Decode(&env); err != nil {
+		return envelope{}, fmt.Errorf("invalid entry: %w", err)
+	}
+
+	if d.More() {
+		return envelope{}, fmt.Errorf("invalid entry: unexpected data after envelope")
+	}
+
+	return env, nil
+}
+
+// fromLegacyEnvelope validates an envelope wrapping alphanumeric data and
+// returns the entry holding its canonical encoding.
+func fromLegacyEnvelope(env envelope) (Entry, error) {
+	if env.Enc != "" || env.IV != "" {
+		return Entry{}, fmt.Errorf("invalid entry: legacy entry can not have enc or iv")
+	}
+
+	if env.KeyGeneration < 1 {
+		return Entry{}, fmt.Errorf("invalid entry: key generation %d must be at least 1", env.KeyGeneration)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(env.Data)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid entry: decode data: %w", err)
+	}
+
+	if len(data) == 0 {
+		return Entry{}, fmt.Errorf("invalid entry: legacy entry has no data")
+	}
+
+	value, err := json.Marshal(env)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid entry: %w", err)
+	}
+
+	return Entry{string(value), env}, nil
+}
+
+// fromEnvelope validates the envelope and returns the entry holding its
+// canonical encoding.
+func fromEnvelope(env envelope) (Entry, error) {
+	if env.Version != Version {
+		return Entry{}, fmt.Errorf("invalid entry: unsupported version %d", env.Version)
+	}
+
+	nonceSize, exists := nonceSizes[env.Enc]
+	if !exists {
+		return Entry{}, fmt.Errorf("invalid entry: unsupported enc %q", env.Enc)
+	}
+
+	if env.KeyGeneration < 1 {
+		return Entry{}, fmt.Errorf("invalid entry: key generation %d must be at least 1", env.KeyGeneration)
+	}
+
+	iv, err := base64.RawURLEncoding.DecodeString(env.IV)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid entry: decode iv: %w", err)
+	}
+
+	if len(iv) != nonceSize {
+		return Entry{}, fmt.Errorf("invalid entry: iv is %d bytes, %s requires %d", len(iv), env.Enc, nonceSize)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(env.Data)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid entry: decode data: %w", err)
+	}
+
+	if len(data) < tagSize {
+		return Entry{}, fmt.Errorf("invalid entry: data is %d bytes, must be at least %d", len(data), tagSize)
+	}
+
+	value, err := json.Marshal(env)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid entry: %w", err)
+	}
+
+	return Entry{string(value), env}, nil
+}
+
 // =============================================================================
 
 // Null represents a entry in the system that can be empty.
@@ -87,11 +295,12 @@ func ParseNull(value string) (Null, error) {
 		return Null{}, nil
 	}
 
-	if !entryRegEx.MatchString(value) {
-		return Null{}, fmt.Errorf("invalid entry %q", value)
+	entry, err := Parse(value)
+	if err != nil {
+		return Null{}, err
 	}
 
-	return Null{value, true}, nil
+	return Null{entry.value, true}, nil
 }
 
 // MustParseNull parses the string value and returns a entry if the value
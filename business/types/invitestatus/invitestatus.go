@@ -0,0 +1,65 @@
+// Package invitestatus represents the status of a bundle invite in the system.
+package invitestatus
+
+import "fmt"
+
+// The set of statuses that can be used.
+var (
+	Pending  = newStatus("PENDING")
+	Accepted = newStatus("ACCEPTED")
+	Declined = newStatus("DECLINED")
+)
+
+// =============================================================================
+
+// Set of known invite statuses.
+var statuses = make(map[string]Status)
+
+// Status represents an invite status in the system.
+type Status struct {
+	value string
+}
+
+func newStatus(status string) Status {
+	s := Status{status}
+	statuses[status] = s
+	return s
+}
+
+// String returns the name of the status.
+func (s Status) String() string {
+	return s.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Status) Equal(s2 Status) bool {
+	return s.value == s2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.value), nil
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns a status if one exists.
+func Parse(value string) (Status, error) {
+	s, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid invite status %q", value)
+	}
+
+	return s, nil
+}
+
+// MustParse parses the string value and returns a status if one exists. If
+// an error occurs the function panics.
+func MustParse(value string) Status {
+	s, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
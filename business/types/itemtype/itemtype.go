@@ -0,0 +1,80 @@
+// Package itemtype represents the kind of item an entry holds.
+package itemtype
+
+import "fmt"
+
+// The set of types that can be used. Each type carries the latest schema
+// version of its plaintext that clients may write.
+var (
+	Login      = newType("LOGIN", 1)
+	SecureNote = newType("SECURE_NOTE", 1)
+	Card       = newType("CARD", 1)
+	Identity   = newType("IDENTITY", 1)
+	SSHKey     = newType("SSH_KEY", 1)
+)
+
+// =============================================================================
+
+// Set of known item types.
+var itemTypes = make(map[string]ItemType)
+
+// ItemType represents a type in the system.
+type ItemType struct {
+	value         string
+	schemaVersion int
+}
+
+func newType(itemType string, schemaVersion int) ItemType {
+	it := ItemType{itemType, schemaVersion}
+	itemTypes[itemType] = it
+	return it
+}
+
+// String returns the name of the type.
+func (it ItemType) String() string {
+	return it.value
+}
+
+// SchemaVersion returns the latest schema version of the type.
+func (it ItemType) SchemaVersion() int {
+	return it.schemaVersion
+}
+
+// SupportsSchemaVersion reports whether items of the type may be written with
+// the specified schema version.
+func (it ItemType) SupportsSchemaVersion(version int) bool {
+	return version >= 1 && version <= it.schemaVersion
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (it ItemType) Equal(it2 ItemType) bool {
+	return it.value == it2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (it ItemType) MarshalText() ([]byte, error) {
+	return []byte(it.value), nil
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns an item type if one exists.
+func Parse(value string) (ItemType, error) {
+	typ, exists := itemTypes[value]
+	if !exists {
+		return ItemType{}, fmt.Errorf("invalid item type %q", value)
+	}
+
+	return typ, nil
+}
+
+// MustParse parses the string value and returns an item type if one exists.
+// If an error occurs the function panics.
+func MustParse(value string) ItemType {
+	typ, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return typ
+}
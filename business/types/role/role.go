@@ -1,7 +1,10 @@
 // Package role represents the role type in the system.
 package role
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // The set of roles that can be used.
 var (
@@ -88,3 +91,15 @@ func ParseMany(roles []string) ([]Role, error) {
 
 	return usrRoles, nil
 }
+
+// Merge returns the roles found in either collection, each of them once.
+func Merge(roles []Role, more []Role) []Role {
+	merged := slices.Clone(roles)
+	for _, role := range more {
+		if !slices.Contains(merged, role) {
+			merged = append(merged, role)
+		}
+	}
+
+	return merged
+}
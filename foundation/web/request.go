@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // Param returns the web call parameters from the request.
@@ -11,6 +12,30 @@ func Param(r *http.Request, key string) string {
 	return r.PathValue(key)
 }
 
+// IfMatch reports whether the entity tag of the resource satisfies the
+// If-Match header of the request. A request without the header always matches
+// so clients that do not track entity tags keep working. Weak tags never match
+// since If-Match uses the strong comparison.
+func IfMatch(r *http.Request, etag string) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+
+	quoted := `"` + etag + `"`
+
+	for _, v := range values {
+		for _, tag := range strings.Split(v, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || tag == quoted {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // Decoder represents data that can be decoded.
 type Decoder interface {
 	Decode(data []byte) error
@@ -25,6 +25,16 @@ func (NoResponse) Encode() ([]byte, string, error) {
 
 // =============================================================================
 
+// SetETag sets the entity tag of the resource in the response so clients can
+// send it back in the If-Match header of a later update.
+func SetETag(ctx context.Context, etag string) {
+	if w := GetWriter(ctx); w != nil {
+		w.Header().Set("ETag", `"`+etag+`"`)
+	}
+}
+
+// =============================================================================
+
 type httpStatus interface {
 	HTTPStatus() int
 }